@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	pflag "github.com/spf13/pflag"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command_response"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	"github.com/anoriar/gophkeeper/internal/client/shared/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/output"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/prompt"
+)
+
+// errNoCommand корневая команда без подкоманды
+var errNoCommand = errors.New("not valid command")
+
+// maxFlagSuggestionDistance на сколько правок неизвестный флаг может отличаться от подсказки
+const maxFlagSuggestionDistance = 2
+
+// globalOptions флаги, общие для всех команд: client --profile work --server localhost:8080 list -t login
+type globalOptions struct {
+	profile       string
+	output        string
+	field         string
+	serverAddress string
+	// explicit - хотя бы один глобальный флаг указан явно
+	explicit bool
+}
+
+// entryLister записи локального хранилища для автодополнения id: trash - записи из корзины
+type entryLister func(options globalOptions, entryType enum.EntryType, trash bool) ([]command_response.ListEntryCommandResponse, error)
+
+// commandParser дерево команд клиента. Команда только разбирается в CommandInterface, выполняет ее CommandExecutor.
+// Значения флагов хранятся в командах дерева, поэтому дерево строится заново для каждого разбора
+type commandParser struct {
+	secrets     *prompt.SecretReader
+	listEntries entryLister
+	options     globalOptions
+	parsed      command.CommandInterface
+}
+
+// ParseCommand разбирает команду с аргументами без имени программы: args[0] - имя команды.
+// Секреты, не переданные флагами, читаются через secrets. listEntries нужен только автодополнению, nil - без id записей.
+// Если команда не разобрана, а выведены help или скрипт автодополнения, возвращается nil без ошибки
+func ParseCommand(args []string, out io.Writer, secrets *prompt.SecretReader, listEntries entryLister) (command.CommandInterface, globalOptions, error) {
+	p := &commandParser{secrets: secrets, listEntries: listEntries}
+	root := p.newRootCommand()
+	root.SetArgs(args)
+	root.SetOut(out)
+	root.SetErr(out)
+	err := root.Execute()
+	if err != nil {
+		return nil, p.options, err
+	}
+	root.PersistentFlags().Visit(func(flag *pflag.Flag) {
+		p.options.explicit = true
+	})
+	return p.parsed, p.options, nil
+}
+
+// commandNames команды клиента для автодополнения и help в shell
+func commandNames() []string {
+	root := (&commandParser{}).newRootCommand()
+	names := make([]string, 0, len(root.Commands()))
+	for _, cmd := range root.Commands() {
+		if cmd.IsAvailableCommand() && cmd.Name() != "completion" {
+			names = append(names, cmd.Name())
+		}
+	}
+	return names
+}
+
+func (p *commandParser) newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "gophkeeper",
+		Short: "Password manager client: encrypted local vault synchronized with the gophkeeper server",
+		// ошибки выводит вызывающий: main завершает процесс, shell продолжает работу
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fmt.Errorf("%w\nrun '%s --help' for usage", errNoCommand, cmd.CommandPath())
+		},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			_, err := output.NewFormatter(p.options.output, p.options.field)
+			return err
+		},
+	}
+	flags := root.PersistentFlags()
+	flags.StringVar(&p.options.profile, "profile", "", "profile name, empty - PROFILE or the default profile")
+	flags.StringVar(&p.options.output, "output", output.FormatJSON, "output format: json, table, plain")
+	flags.StringVar(&p.options.field, "field", "", "print only this field of the payload, e.g. data.password")
+	flags.StringVar(&p.options.serverAddress, "server", "", "server address, empty - address of the profile or SERVER_ADDRESS")
+	_ = root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(output.Formats, cobra.ShellCompDirectiveNoFileComp))
+	root.SetFlagErrorFunc(suggestFlag)
+
+	root.AddCommand(
+		p.newRegisterCommand(),
+		p.newLoginCommand(),
+		p.newUnlockCommand(),
+		p.newLockCommand(),
+		p.newAddEntryCommand(),
+		p.newEditEntryCommand(),
+		p.newDeleteEntryCommand(),
+		p.newRestoreCommand(),
+		p.newListEntryCommand(),
+		p.newFindEntryCommand(),
+		p.newTrashEntryCommand(),
+		p.newDetailEntryCommand(),
+		p.newHistoryEntryCommand(),
+		p.newRevertEntryCommand(),
+		p.newSyncEntryCommand(),
+		p.newRunCommand(),
+		p.newInjectCommand(),
+		p.newRekeyCommand(),
+		p.newBackupCommand(),
+		p.newProfilesCommand(),
+		p.newShellCommand(),
+		p.newAgentCommand(),
+	)
+	return root
+}
+
+// registerEntryTypeFlag флаг -t с автодополнением типов записей
+func registerEntryTypeFlag(cmd *cobra.Command, entryTypeStr *string) {
+	cmd.Flags().StringVarP(entryTypeStr, "type", "t", "", "entry type: login, card, text, bin")
+	_ = cmd.RegisterFlagCompletionFunc("type", completeEntryTypes)
+}
+
+// registerEntryIdFlag флаг -i с автодополнением id записей локального хранилища выбранного типа
+func (p *commandParser) registerEntryIdFlag(cmd *cobra.Command, id *string, trash bool) {
+	cmd.Flags().StringVarP(id, "id", "i", "", "entry id")
+	_ = cmd.RegisterFlagCompletionFunc("id", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return p.completeEntryIds(cmd, toComplete, trash)
+	})
+}
+
+func completeEntryTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	entryTypes := make([]string, 0, len(enum.AllEntryTypes))
+	for _, entryType := range enum.AllEntryTypes {
+		entryTypes = append(entryTypes, string(entryType))
+	}
+	return entryTypes, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeEntryIds варианты - id с названием в описании. Если тип не указан, предлагаются записи всех типов.
+// Заблокированное хранилище - не ошибка автодополнения: вариантов просто нет
+func (p *commandParser) completeEntryIds(cmd *cobra.Command, toComplete string, trash bool) ([]string, cobra.ShellCompDirective) {
+	if p.listEntries == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	entryTypes := enum.AllEntryTypes
+	entryTypeStr, _ := cmd.Flags().GetString("type")
+	if entryTypeStr != "" {
+		entryType, err := parseEntryType(entryTypeStr)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		entryTypes = []enum.EntryType{entryType}
+	}
+
+	var ids []string
+	for _, entryType := range entryTypes {
+		entries, err := p.listEntries(p.options, entryType, trash)
+		if err != nil {
+			cobra.CompDebugln(fmt.Sprintf("list %s entries error: %v", entryType, err), false)
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		for _, entry := range entries {
+			if strings.HasPrefix(entry.Id, toComplete) {
+				ids = append(ids, entry.Id+"\t"+entry.Title)
+			}
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// suggestFlag дополняет ошибку неизвестного флага ближайшим по написанию флагом команды
+func suggestFlag(cmd *cobra.Command, err error) error {
+	const unknownFlagPrefix = "unknown flag: --"
+	message := err.Error()
+	usageHint := fmt.Sprintf("run '%s --help' for usage", cmd.CommandPath())
+	if !strings.HasPrefix(message, unknownFlagPrefix) {
+		return fmt.Errorf("%w\n%s", err, usageHint)
+	}
+	unknownName := strings.TrimPrefix(message, unknownFlagPrefix)
+
+	suggestion := ""
+	bestDistance := maxFlagSuggestionDistance + 1
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Hidden {
+			return
+		}
+		distance := levenshteinDistance(unknownName, flag.Name)
+		if distance < bestDistance || (strings.HasPrefix(flag.Name, unknownName) && suggestion == "") {
+			suggestion = flag.Name
+			bestDistance = distance
+		}
+	})
+	if suggestion == "" {
+		return fmt.Errorf("%w\n%s", err, usageHint)
+	}
+	return fmt.Errorf("%w\n\nDid you mean this?\n\t--%s\n\n%s", err, suggestion, usageHint)
+}
+
+// levenshteinDistance минимальное число вставок, удалений и замен символов, переводящих a в b
+func levenshteinDistance(a string, b string) int {
+	ar, br := []rune(a), []rune(b)
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(br)]
+}
@@ -4,182 +4,678 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
-	"flag"
 	"fmt"
-	"os"
+	"strings"
 
-	pflag "github.com/spf13/pflag"
+	"github.com/spf13/cobra"
 
 	"github.com/anoriar/gophkeeper/internal/client/entry/dto"
 	entryCommands "github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
 	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	profileCommands "github.com/anoriar/gophkeeper/internal/client/profile/dto/command"
 	"github.com/anoriar/gophkeeper/internal/client/shared/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/prompt"
 	userCommands "github.com/anoriar/gophkeeper/internal/client/user/dto/command"
+	vaultCommands "github.com/anoriar/gophkeeper/internal/client/vault/dto/command"
 )
 
-func ParseFlags() (command.CommandInterface, error) {
-	registerFlags := pflag.NewFlagSet("register", pflag.ExitOnError)
-	loginFlags := pflag.NewFlagSet("login", pflag.ExitOnError)
-	addFlags := pflag.NewFlagSet("add", pflag.ExitOnError)
-	editFlags := pflag.NewFlagSet("edit", pflag.ExitOnError)
-	deleteFlags := pflag.NewFlagSet("delete", pflag.ExitOnError)
-	listFlags := pflag.NewFlagSet("list", pflag.ExitOnError)
-	detailFlags := pflag.NewFlagSet("detail", pflag.ExitOnError)
-	syncFlags := pflag.NewFlagSet("sync", pflag.ExitOnError)
+func (p *commandParser) newRegisterCommand() *cobra.Command {
+	registerCommand := &userCommands.RegisterCommand{}
+	var fromStdin bool
+	cmd := &cobra.Command{
+		Use:   "register",
+		Short: "Register a new user on the server and create the vault",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := readSecretFlag(p.secrets, &registerCommand.Password, "password: ", fromStdin)
+			if err != nil {
+				return err
+			}
+			if registerCommand.MasterPassword == "" {
+				registerCommand.MasterPassword, err = p.secrets.ReadNewSecret("master password: ", "repeat master password: ", fromStdin)
+				if err != nil {
+					return err
+				}
+			}
+			errs := registerCommand.Validate()
+			if errs != nil {
+				return fmt.Errorf("validation error:\n%s", errs.String())
+			}
+			p.parsed = registerCommand
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&registerCommand.UserName, "user", "u", "", "Username")
+	flags.StringVarP(&registerCommand.Password, "pass", "p", "", "Password, omitted - prompt")
+	flags.StringVarP(&registerCommand.MasterPassword, "masterpass", "m", "", "Master password, omitted - prompt with confirmation")
+	flags.BoolVar(&fromStdin, "stdin", false, "read omitted password and master password from stdin, one per line")
+	return cmd
+}
 
-	if len(os.Args) <= 1 {
-		exitWithError(fmt.Errorf("not valid command"))
+func (p *commandParser) newLoginCommand() *cobra.Command {
+	loginCommand := &userCommands.LoginCommand{}
+	var fromStdin bool
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in on the server and unlock the vault",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := readSecretFlag(p.secrets, &loginCommand.Password, "password: ", fromStdin)
+			if err != nil {
+				return err
+			}
+			err = readSecretFlag(p.secrets, &loginCommand.MasterPassword, "master password: ", fromStdin)
+			if err != nil {
+				return err
+			}
+			errs := loginCommand.Validate()
+			if errs != nil {
+				return fmt.Errorf("validation error:\n%s", errs.String())
+			}
+			p.parsed = loginCommand
+			return nil
+		},
 	}
+	flags := cmd.Flags()
+	flags.StringVarP(&loginCommand.UserName, "user", "u", "", "Username")
+	flags.StringVarP(&loginCommand.Password, "pass", "p", "", "Password, omitted - prompt")
+	flags.StringVarP(&loginCommand.MasterPassword, "masterpass", "m", "", "Master password, omitted - prompt")
+	flags.BoolVar(&fromStdin, "stdin", false, "read omitted password and master password from stdin, one per line")
+	return cmd
+}
 
-	switch os.Args[1] {
-	case "register":
-		reg, err := parseRegisterCommand(registerFlags)
-		if err != nil {
-			return nil, fmt.Errorf("register command: %v", err)
-		}
-		return reg, nil
-	case "login":
-		login, err := parseLoginCommand(loginFlags)
-		if err != nil {
-			return nil, fmt.Errorf("login command: %v", err)
-		}
-		return login, nil
-	case "add":
-		add, err := parseAddEntryCommand(addFlags)
-		if err != nil {
-			return nil, fmt.Errorf("add command: %v", err)
-		}
-		return add, err
-	case "edit":
-		edit, err := parseEditEntryCommand(editFlags)
-		if err != nil {
-			return nil, fmt.Errorf("edit command: %v", err)
-		}
-		return edit, err
-	case "delete":
-		deleteCommand, err := parseDeleteEntryCommand(deleteFlags)
-		if err != nil {
-			return nil, fmt.Errorf("delete command: %v", err)
-		}
-		return deleteCommand, err
-	case "list":
-		listCommand, err := parseListEntryCommand(listFlags)
-		if err != nil {
-			return nil, fmt.Errorf("list command: %v", err)
-		}
-		return listCommand, err
-	case "detail":
-		detailCommand, err := parseDetailEntryCommand(detailFlags)
-		if err != nil {
-			return nil, fmt.Errorf("detail command: %v", err)
-		}
-		return detailCommand, err
-	case "sync":
-		syncCommand, err := parseSyncEntryCommand(syncFlags)
-		if err != nil {
-			return nil, fmt.Errorf("sync command: %v", err)
-		}
-		return syncCommand, nil
-	default:
-		return nil, fmt.Errorf("not valid command")
+func (p *commandParser) newUnlockCommand() *cobra.Command {
+	unlockCommand := &userCommands.UnlockCommand{}
+	var fromStdin bool
+	cmd := &cobra.Command{
+		Use:   "unlock",
+		Short: "Unlock the vault without the server",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := readSecretFlag(p.secrets, &unlockCommand.MasterPassword, "master password: ", fromStdin)
+			if err != nil {
+				return err
+			}
+			p.parsed = unlockCommand
+			return nil
+		},
 	}
+	flags := cmd.Flags()
+	flags.StringVarP(&unlockCommand.MasterPassword, "masterpass", "m", "", "Master password, omitted - prompt")
+	flags.BoolVar(&fromStdin, "stdin", false, "read omitted master password from stdin")
+	return cmd
 }
 
-func exitWithError(err error) {
-	fmt.Printf("Error: %s\n", err.Error())
-	flag.Usage()
-	os.Exit(1)
+func (p *commandParser) newLockCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lock",
+		Short: "Lock the vault until the next login or unlock",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p.parsed = &userCommands.LockCommand{}
+			return nil
+		},
+	}
 }
 
-func parseRegisterCommand(flags *pflag.FlagSet) (*userCommands.RegisterCommand, error) {
-	registerCommand := &userCommands.RegisterCommand{}
-	flags.StringVarP(&registerCommand.UserName, "user", "u", "", "Username")
-	flags.StringVarP(&registerCommand.Password, "pass", "p", "", "Password")
-	flags.StringVarP(&registerCommand.MasterPassword, "masterpass", "m", "", "Master password")
+func (p *commandParser) newShellCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell",
+		Short: "Interactive mode: unlock the vault once and run client commands",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p.parsed = &command.ShellCommand{}
+			return nil
+		},
+	}
+}
 
-	err := flags.Parse(os.Args[2:])
-	if err != nil {
-		return nil, err
+func (p *commandParser) newAgentCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "agent",
+		Short: "Run the agent that keeps vault keys in memory",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p.parsed = &vaultCommands.AgentCommand{}
+			return nil
+		},
 	}
-	errs := registerCommand.Validate()
-	if errs != nil {
-		return nil, fmt.Errorf("validation error:\n%s", errs.String())
+}
+
+func (p *commandParser) newRekeyCommand() *cobra.Command {
+	rekeyCommand := &vaultCommands.RekeyCommand{}
+	var fromStdin bool
+	cmd := &cobra.Command{
+		Use:   "rekey",
+		Short: "Change the master password and re-encrypt all entries",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := readSecretFlag(p.secrets, &rekeyCommand.OldMasterPassword, "old master password: ", fromStdin)
+			if err != nil {
+				return err
+			}
+			if rekeyCommand.NewMasterPassword == "" {
+				rekeyCommand.NewMasterPassword, err = p.secrets.ReadNewSecret("new master password: ", "repeat new master password: ", fromStdin)
+				if err != nil {
+					return err
+				}
+			}
+			errs := rekeyCommand.Validate()
+			if errs != nil {
+				return fmt.Errorf("validation error:\n%s", errs.String())
+			}
+			p.parsed = rekeyCommand
+			return nil
+		},
 	}
-	return registerCommand, nil
+	flags := cmd.Flags()
+	flags.StringVarP(&rekeyCommand.OldMasterPassword, "old", "o", "", "Old master password, omitted - prompt")
+	flags.StringVarP(&rekeyCommand.NewMasterPassword, "new", "n", "", "New master password, omitted - prompt with confirmation")
+	flags.BoolVar(&fromStdin, "stdin", false, "read omitted old and new master passwords from stdin, one per line")
+	return cmd
 }
 
-func parseLoginCommand(flags *pflag.FlagSet) (*userCommands.LoginCommand, error) {
-	loginCommand := &userCommands.LoginCommand{}
-	flags.StringVarP(&loginCommand.UserName, "user", "u", "", "Username")
-	flags.StringVarP(&loginCommand.Password, "pass", "p", "", "Password")
-	flags.StringVarP(&loginCommand.MasterPassword, "masterpass", "m", "", "Master password")
+func (p *commandParser) newBackupCommand() *cobra.Command {
+	backupCommand := &vaultCommands.BackupCommand{}
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Write an encrypted backup of the local vault",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			errs := backupCommand.Validate()
+			if errs != nil {
+				return fmt.Errorf("validation error:\n%s", errs.String())
+			}
+			p.parsed = backupCommand
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&backupCommand.FileName, "out", "", "Backup file")
+	return cmd
+}
+
+// newRestoreCommand restore --in восстанавливает хранилище из резервной копии, без --in - запись из корзины
+func (p *commandParser) newRestoreCommand() *cobra.Command {
+	restoreBackupCommand := &vaultCommands.RestoreBackupCommand{}
+	var fromStdin bool
+	var id string
+	var entryTypeStr string
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore an entry from the trash or, with --in, the local vault from a backup",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("in") {
+				err := readSecretFlag(p.secrets, &restoreBackupCommand.MasterPassword, "backup master password: ", fromStdin)
+				if err != nil {
+					return err
+				}
+				errs := restoreBackupCommand.Validate()
+				if errs != nil {
+					return fmt.Errorf("validation error:\n%s", errs.String())
+				}
+				p.parsed = restoreBackupCommand
+				return nil
+			}
+
+			entryType, err := parseEntryType(entryTypeStr)
+			if err != nil {
+				return err
+			}
+			p.parsed = &entryCommands.RestoreEntryCommand{Id: id, EntryType: entryType}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	registerEntryTypeFlag(cmd, &entryTypeStr)
+	p.registerEntryIdFlag(cmd, &id, true)
+	flags.StringVar(&restoreBackupCommand.FileName, "in", "", "Backup file")
+	flags.StringVarP(&restoreBackupCommand.MasterPassword, "masterpass", "m", "", "Master password of the backup, omitted - prompt")
+	flags.BoolVar(&fromStdin, "stdin", false, "read omitted master password of the backup from stdin")
+	return cmd
+}
+
+// entryDataFlags флаги данных записи, общие для add и edit
+type entryDataFlags struct {
+	entryTypeStr  string
+	dataStr       string
+	metaStr       string
+	publicMetaStr string
+	title         string
+	fromStdin     bool
+	dataFile      string
+}
+
+func (f *entryDataFlags) register(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	registerEntryTypeFlag(cmd, &f.entryTypeStr)
+	flags.StringVarP(&f.dataStr, "data", "d", "", "data, omitted - prompt")
+	flags.StringVarP(&f.metaStr, "meta", "m", "", "meta")
+	flags.StringVar(&f.publicMetaStr, "public-meta", "", "public meta, not encrypted")
+	flags.StringVar(&f.title, "title", "", "title, encrypted")
+	flags.BoolVar(&f.fromStdin, "stdin", false, "read data from stdin")
+	flags.StringVar(&f.dataFile, "data-file", "", "read data from file")
+}
 
-	err := flags.Parse(os.Args[2:])
+func (f *entryDataFlags) parse(secrets *prompt.SecretReader) (enum.EntryType, interface{}, json.RawMessage, json.RawMessage, error) {
+	dataStr, err := readEntryData(secrets, f.entryTypeStr, f.dataStr, f.dataFile, f.fromStdin)
 	if err != nil {
-		return nil, err
+		return "", nil, nil, nil, err
+	}
+	entryType, data, meta, err := parseDataAndEntryType(f.entryTypeStr, dataStr, f.metaStr)
+	if err != nil {
+		return "", nil, nil, nil, err
+	}
+	publicMeta, err := parsePublicMeta(f.publicMetaStr)
+	if err != nil {
+		return "", nil, nil, nil, err
 	}
-	errs := loginCommand.Validate()
-	if errs != nil {
-		return nil, fmt.Errorf("validation error:\n%s", errs.String())
+	return entryType, data, meta, publicMeta, nil
+}
+
+func (p *commandParser) newAddEntryCommand() *cobra.Command {
+	entryFlags := &entryDataFlags{}
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add an entry to the local vault",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, data, meta, publicMeta, err := entryFlags.parse(p.secrets)
+			if err != nil {
+				return err
+			}
+
+			entryCommand := &entryCommands.AddEntryCommand{}
+
+			entryCommand.EntryType = entryType
+			entryCommand.Data = data
+			entryCommand.Meta = meta
+			entryCommand.PublicMeta = publicMeta
+			entryCommand.Title = entryFlags.title
+
+			p.parsed = entryCommand
+			return nil
+		},
+	}
+	entryFlags.register(cmd)
+	return cmd
+}
+
+func (p *commandParser) newEditEntryCommand() *cobra.Command {
+	var id string
+	entryFlags := &entryDataFlags{}
+	cmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Replace data and meta of an entry, and its title if --title is set",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, data, meta, publicMeta, err := entryFlags.parse(p.secrets)
+			if err != nil {
+				return err
+			}
+
+			entryCommand := &entryCommands.EditEntryCommand{}
+
+			entryCommand.Id = id
+			entryCommand.EntryType = entryType
+			entryCommand.Data = data
+			entryCommand.Meta = meta
+			entryCommand.PublicMeta = publicMeta
+			// без --title название не меняется, --title "" удаляет его
+			if cmd.Flags().Changed("title") {
+				entryCommand.Title = &entryFlags.title
+			}
+
+			p.parsed = entryCommand
+			return nil
+		},
 	}
-	return loginCommand, nil
+	p.registerEntryIdFlag(cmd, &id, false)
+	entryFlags.register(cmd)
+	return cmd
 }
 
-func parseAddEntryCommand(flags *pflag.FlagSet) (*entryCommands.AddEntryCommand, error) {
+func (p *commandParser) newListEntryCommand() *cobra.Command {
 	var entryTypeStr string
-	var dataStr string
-	var metaStr string
+	var sortBy string
+	var desc bool
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List entries of a type with titles, without data",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, err := parseEntryType(entryTypeStr)
+			if err != nil {
+				return err
+			}
+
+			entryCommand := &entryCommands.ListEntryCommand{}
+			entryCommand.EntryType = entryType
+			entryCommand.SortBy = sortBy
+			entryCommand.Desc = desc
+			entryCommand.Limit = limit
+
+			p.parsed = entryCommand
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	registerEntryTypeFlag(cmd, &entryTypeStr)
+	flags.StringVar(&sortBy, "sort", entryCommands.ListSortTitle, "sort by: title, updatedAt")
+	flags.BoolVar(&desc, "desc", false, "descending order")
+	flags.IntVar(&limit, "limit", 0, "max entries count, 0 - all")
+	_ = cmd.RegisterFlagCompletionFunc("sort", cobra.FixedCompletions(
+		[]string{entryCommands.ListSortTitle, entryCommands.ListSortUpdatedAt}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
 
-	flags.StringVarP(&entryTypeStr, "type", "t", "", "type")
-	flags.StringVarP(&dataStr, "data", "d", "", "data")
-	flags.StringVarP(&metaStr, "meta", "m", "", "meta")
-	err := flags.Parse(os.Args[2:])
-	if err != nil {
-		return nil, err
+// newFindEntryCommand запрос - позиционные аргументы: find mail work ищет "mail work"
+func (p *commandParser) newFindEntryCommand() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "find [query...]",
+		Short: "Fuzzy search entries of all types by title, meta, login and card holder",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryCommand := &entryCommands.FindEntryCommand{}
+			entryCommand.Query = strings.Join(args, " ")
+			entryCommand.Limit = limit
+
+			p.parsed = entryCommand
+			return nil
+		},
 	}
+	cmd.Flags().IntVar(&limit, "limit", 0, "max entries count, 0 - all")
+	return cmd
+}
 
-	entryType, data, meta, err := parseDataAndEntryType(entryTypeStr, dataStr, metaStr)
-	if err != nil {
-		return nil, err
+func (p *commandParser) newTrashEntryCommand() *cobra.Command {
+	var entryTypeStr string
+
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "List deleted entries of a type",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, err := parseEntryType(entryTypeStr)
+			if err != nil {
+				return err
+			}
+			p.parsed = &entryCommands.TrashEntryCommand{EntryType: entryType}
+			return nil
+		},
 	}
+	registerEntryTypeFlag(cmd, &entryTypeStr)
+	return cmd
+}
 
-	entryCommand := &entryCommands.AddEntryCommand{}
+func (p *commandParser) newDeleteEntryCommand() *cobra.Command {
+	var id string
+	var entryTypeStr string
+
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Move an entry to the trash",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, err := parseEntryType(entryTypeStr)
+			if err != nil {
+				return err
+			}
+			p.parsed = &entryCommands.DeleteEntryCommand{Id: id, EntryType: entryType}
+			return nil
+		},
+	}
+	registerEntryTypeFlag(cmd, &entryTypeStr)
+	p.registerEntryIdFlag(cmd, &id, false)
+	return cmd
+}
 
-	entryCommand.EntryType = entryType
-	entryCommand.Data = data
-	entryCommand.Meta = meta
+func (p *commandParser) newDetailEntryCommand() *cobra.Command {
+	var id string
+	var entryTypeStr string
 
-	return entryCommand, nil
+	cmd := &cobra.Command{
+		Use:     "detail",
+		Aliases: []string{"get"},
+		Short:   "Show a decrypted entry: detail -t login -i [id] --output plain --field data.password",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, err := parseEntryType(entryTypeStr)
+			if err != nil {
+				return err
+			}
+			p.parsed = &entryCommands.DetailEntryCommand{Id: id, EntryType: entryType}
+			return nil
+		},
+	}
+	registerEntryTypeFlag(cmd, &entryTypeStr)
+	p.registerEntryIdFlag(cmd, &id, false)
+	return cmd
 }
 
-func parseEditEntryCommand(flags *pflag.FlagSet) (*entryCommands.EditEntryCommand, error) {
+func (p *commandParser) newHistoryEntryCommand() *cobra.Command {
 	var id string
 	var entryTypeStr string
-	var dataStr string
-	var metaStr string
-
-	flags.StringVarP(&id, "id", "i", "", "id")
-	flags.StringVarP(&entryTypeStr, "type", "t", "", "type")
-	flags.StringVarP(&dataStr, "data", "d", "", "data")
-	flags.StringVarP(&metaStr, "meta", "m", "", "meta")
-	err := flags.Parse(os.Args[2:])
-	if err != nil {
-		return nil, err
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List previous revisions of an entry, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, err := parseEntryType(entryTypeStr)
+			if err != nil {
+				return err
+			}
+			p.parsed = &entryCommands.HistoryEntryCommand{Id: id, EntryType: entryType}
+			return nil
+		},
+	}
+	registerEntryTypeFlag(cmd, &entryTypeStr)
+	p.registerEntryIdFlag(cmd, &id, false)
+	return cmd
+}
+
+func (p *commandParser) newRevertEntryCommand() *cobra.Command {
+	var id string
+	var entryTypeStr string
+	var revision int
+
+	cmd := &cobra.Command{
+		Use:   "revert",
+		Short: "Restore a revision of an entry from history",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, err := parseEntryType(entryTypeStr)
+			if err != nil {
+				return err
+			}
+			p.parsed = &entryCommands.RevertEntryCommand{Id: id, EntryType: entryType, Revision: revision}
+			return nil
+		},
 	}
+	registerEntryTypeFlag(cmd, &entryTypeStr)
+	p.registerEntryIdFlag(cmd, &id, false)
+	cmd.Flags().IntVarP(&revision, "revision", "r", 1, "revision number from history, 1 - the latest")
+	return cmd
+}
 
-	entryType, data, meta, err := parseDataAndEntryType(entryTypeStr, dataStr, metaStr)
-	if err != nil {
-		return nil, err
+func (p *commandParser) newSyncEntryCommand() *cobra.Command {
+	var entryTypeStr string
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Synchronize entries of a type with the server",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entryType, err := parseEntryType(entryTypeStr)
+			if err != nil {
+				return err
+			}
+			p.parsed = &entryCommands.SyncEntryCommand{EntryType: entryType}
+			return nil
+		},
+	}
+	registerEntryTypeFlag(cmd, &entryTypeStr)
+	return cmd
+}
+
+// newRunCommand программа и ее аргументы - после --: run --env DB_PASS=login/<id>/password -- ./deploy.sh.
+// Флаги после имени программы принадлежат ей, а не run
+func (p *commandParser) newRunCommand() *cobra.Command {
+	var envs []string
+
+	cmd := &cobra.Command{
+		Use:   "run --env NAME=type/id/field... -- program [args...]",
+		Short: "Run a program with secrets of the local vault in its environment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runCommand := &entryCommands.RunCommand{Args: args}
+			for _, env := range envs {
+				envReference, err := parseEnvReference(env)
+				if err != nil {
+					return err
+				}
+				runCommand.Env = append(runCommand.Env, envReference)
+			}
+			p.parsed = runCommand
+			return nil
+		},
+	}
+	cmd.Flags().SetInterspersed(false)
+	cmd.Flags().StringArrayVarP(&envs, "env", "e", nil, "env variable with entry field value: NAME=type/id/field, field may be omitted for text and bin")
+	return cmd
+}
+
+// newInjectCommand inject -i config.tmpl -o config.yaml, с --check выходной файл не нужен
+func (p *commandParser) newInjectCommand() *cobra.Command {
+	injectCommand := &entryCommands.InjectCommand{}
+	cmd := &cobra.Command{
+		Use:   "inject",
+		Short: "Replace secret references in a template with values of the local vault",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p.parsed = injectCommand
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&injectCommand.In, "in", "i", "", "template file with {{ gk \"type/id/field\" }} or gk://type/id/field references")
+	cmd.Flags().StringVarP(&injectCommand.Out, "out", "o", "", "output file, written with 0600 permissions")
+	cmd.Flags().BoolVar(&injectCommand.Check, "check", false, "only check that every reference resolves, do not write the output file")
+	return cmd
+}
+
+// newProfilesCommand profiles без подкоманды - список профилей
+func (p *commandParser) newProfilesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "Manage profiles: separate vaults, sessions and servers",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p.parsed = &profileCommands.ListProfilesCommand{}
+			return nil
+		},
 	}
 
-	entryCommand := &entryCommands.EditEntryCommand{}
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List profiles",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p.parsed = &profileCommands.ListProfilesCommand{}
+			return nil
+		},
+	}
 
-	entryCommand.Id = id
-	entryCommand.EntryType = entryType
-	entryCommand.Data = data
-	entryCommand.Meta = meta
+	addCommand := &profileCommands.AddProfileCommand{}
+	addCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a profile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p.parsed = addCommand
+			return nil
+		},
+	}
+	addCmd.Flags().StringVarP(&addCommand.Name, "name", "n", "", "profile name")
+	addCmd.Flags().StringVarP(&addCommand.ServerAddress, "server", "s", "", "server address, empty - SERVER_ADDRESS")
+
+	removeCommand := &profileCommands.RemoveProfileCommand{}
+	removeCmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove a profile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p.parsed = removeCommand
+			return nil
+		},
+	}
+	removeCmd.Flags().StringVarP(&removeCommand.Name, "name", "n", "", "profile name")
+	removeCmd.Flags().BoolVar(&removeCommand.Purge, "purge", false, "remove token, vault files and keys of the profile")
+
+	defaultCommand := &profileCommands.DefaultProfileCommand{}
+	defaultCmd := &cobra.Command{
+		Use:   "default",
+		Short: "Change the default profile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p.parsed = defaultCommand
+			return nil
+		},
+	}
+	defaultCmd.Flags().StringVarP(&defaultCommand.Name, "name", "n", "", "profile name")
 
-	return entryCommand, nil
+	cmd.AddCommand(listCmd, addCmd, removeCmd, defaultCmd)
+	return cmd
+}
+
+// readSecretFlag значение, не переданное флагом, вводится без эха или читается строкой из stdin
+func readSecretFlag(secrets *prompt.SecretReader, value *string, promptStr string, fromStdin bool) error {
+	if *value != "" {
+		return nil
+	}
+	secret, err := secrets.ReadSecret(promptStr, fromStdin)
+	if err != nil {
+		return err
+	}
+	*value = secret
+	return nil
+}
+
+// readEntryData данные записи из -d, --data-file, --stdin или ввода без эха. Файл и stdin для bin - сами байты, без base64
+func readEntryData(secrets *prompt.SecretReader, entryType string, data string, dataFile string, fromStdin bool) (string, error) {
+	raw, ok, err := secrets.ReadData(dataFile, fromStdin)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		if data != "" {
+			return data, nil
+		}
+		return secrets.ReadSecret("data: ", false)
+	}
+	if data != "" {
+		return "", prompt.ErrDataSourceConflict
+	}
+	if entryType == string(enum.Bin) {
+		return base64.StdEncoding.EncodeToString(raw), nil
+	}
+	return string(raw), nil
+}
+
+// parseEnvReference NAME=type/id/field: имя переменной - до первого =
+func parseEnvReference(value string) (entryCommands.EnvReference, error) {
+	name, referenceStr, found := strings.Cut(value, "=")
+	if !found {
+		return entryCommands.EnvReference{}, fmt.Errorf("not valid env %q, expected NAME=type/id/field", value)
+	}
+	reference, err := dto.ParseSecretReference(referenceStr)
+	if err != nil {
+		return entryCommands.EnvReference{}, err
+	}
+	return entryCommands.EnvReference{Name: name, Reference: reference}, nil
 }
 
 func parseEntryType(entryType string) (enum.EntryType, error) {
@@ -197,6 +693,17 @@ func parseEntryType(entryType string) (enum.EntryType, error) {
 	}
 }
 
+func parsePublicMeta(publicMetaStr string) (json.RawMessage, error) {
+	if publicMetaStr == "" {
+		return nil, nil
+	}
+	var publicMeta json.RawMessage
+	if err := json.Unmarshal([]byte(publicMetaStr), &publicMeta); err != nil {
+		return nil, err
+	}
+	return publicMeta, nil
+}
+
 func parseDataAndEntryType(entryType string, data string, metaStr string) (enum.EntryType, interface{}, json.RawMessage, error) {
 	var meta json.RawMessage
 	if err := json.Unmarshal([]byte(metaStr), &meta); err != nil {
@@ -228,91 +735,3 @@ func parseDataAndEntryType(entryType string, data string, metaStr string) (enum.
 		return "", nil, json.RawMessage{}, errors.New("not valid entry type")
 	}
 }
-
-func parseListEntryCommand(flags *pflag.FlagSet) (*entryCommands.ListEntryCommand, error) {
-	var entryTypeStr string
-
-	flags.StringVarP(&entryTypeStr, "type", "t", "", "type")
-	err := flags.Parse(os.Args[2:])
-	if err != nil {
-		return nil, err
-	}
-
-	entryType, err := parseEntryType(entryTypeStr)
-	if err != nil {
-		return nil, err
-	}
-
-	entryCommand := &entryCommands.ListEntryCommand{}
-	entryCommand.EntryType = entryType
-
-	return entryCommand, nil
-}
-
-func parseDeleteEntryCommand(flags *pflag.FlagSet) (*entryCommands.DeleteEntryCommand, error) {
-	var id string
-	var entryTypeStr string
-
-	flags.StringVarP(&id, "id", "i", "", "id")
-	flags.StringVarP(&entryTypeStr, "type", "t", "", "type")
-	err := flags.Parse(os.Args[2:])
-	if err != nil {
-		return nil, err
-	}
-
-	entryType, err := parseEntryType(entryTypeStr)
-	if err != nil {
-		return nil, err
-	}
-
-	entryCommand := &entryCommands.DeleteEntryCommand{}
-
-	entryCommand.Id = id
-	entryCommand.EntryType = entryType
-
-	return entryCommand, nil
-}
-
-func parseDetailEntryCommand(flags *pflag.FlagSet) (*entryCommands.DetailEntryCommand, error) {
-	var id string
-	var entryTypeStr string
-
-	flags.StringVarP(&id, "id", "i", "", "id")
-	flags.StringVarP(&entryTypeStr, "type", "t", "", "type")
-	err := flags.Parse(os.Args[2:])
-	if err != nil {
-		return nil, err
-	}
-
-	entryType, err := parseEntryType(entryTypeStr)
-	if err != nil {
-		return nil, err
-	}
-
-	entryCommand := &entryCommands.DetailEntryCommand{}
-
-	entryCommand.Id = id
-	entryCommand.EntryType = entryType
-
-	return entryCommand, nil
-}
-
-func parseSyncEntryCommand(flags *pflag.FlagSet) (*entryCommands.SyncEntryCommand, error) {
-	var entryTypeStr string
-
-	flags.StringVarP(&entryTypeStr, "type", "t", "", "type")
-	err := flags.Parse(os.Args[2:])
-	if err != nil {
-		return nil, err
-	}
-
-	entryType, err := parseEntryType(entryTypeStr)
-	if err != nil {
-		return nil, err
-	}
-
-	entryCommand := &entryCommands.SyncEntryCommand{}
-	entryCommand.EntryType = entryType
-
-	return entryCommand, nil
-}
@@ -2,19 +2,25 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
+	entryCommands "github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command_response"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
 	appPkg "github.com/anoriar/gophkeeper/internal/client/shared/app"
 	"github.com/anoriar/gophkeeper/internal/client/shared/config"
+	sharedCommand "github.com/anoriar/gophkeeper/internal/client/shared/dto/command"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
 	commandPkg "github.com/anoriar/gophkeeper/internal/client/shared/services/command"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/output"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/prompt"
 )
 
-const (
-	SuccessMessage = "status: success"
-	FailMessage    = "status: failed\n%v\n"
-)
+const FailMessage = "status: failed\n%v\n"
 
 func main() {
 	defer func() {
@@ -22,33 +28,101 @@ func main() {
 			log.Fatalf("recovering from panic: %v", r)
 		}
 	}()
+	os.Exit(run())
+}
 
-	cfg, err := config.LoadConfig()
+// run возвращает код завершения: отложенные вызовы должны выполниться до os.Exit
+func run() int {
+	secrets := prompt.NewSecretReader(prompt.NewTerminalPasswordReader(os.Stdin, os.Stderr), os.Stdin)
+	command, options, err := ParseCommand(os.Args[1:], os.Stdout, secrets, listEntries)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "parse command error: %v\n", err)
+		return sharedErrors.ExitCodeUsage
+	}
+	// выведены help или скрипт автодополнения
+	if command == nil {
+		return sharedErrors.ExitCodeSuccess
+	}
+	errs := command.Validate()
+	if len(errs) > 0 {
+		fmt.Fprintf(os.Stderr, "validation error:\n%s\n", errs.String())
+		return sharedErrors.ExitCodeUsage
+	}
+	formatter, err := output.NewFormatter(options.output, options.field)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "parse command error: %v\n", err)
+		return sharedErrors.ExitCodeUsage
+	}
+
+	cfg, err := loadConfig(options)
 	if err != nil {
-		log.Fatalf("load config error %v", err.Error())
+		fmt.Fprintf(os.Stderr, "load config error: %v\n", err)
+		return sharedErrors.ExitCodeError
 	}
+	_, isShell := command.(*sharedCommand.ShellCommand)
+	// ключи, разблокированные в shell, не попадают в агента
+	cfg.InMemoryKeys = isShell
 
 	app, err := appPkg.NewApp(cfg)
 	if err != nil {
-		log.Fatalf("init app error %v", err.Error())
+		fmt.Fprintf(os.Stderr, "init app error: %v\n", err)
+		return sharedErrors.ExitCode(err)
 	}
 	defer app.Close()
 
-	command, err := ParseFlags()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cmdExecutor := commandPkg.NewCommandExecutor(app)
+	if isShell {
+		err = runShell(ctx, app, cmdExecutor, formatter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, FailMessage, err.Error())
+			return sharedErrors.ExitCodeError
+		}
+		return sharedErrors.ExitCodeSuccess
+	}
+	response := cmdExecutor.ExecuteCommand(ctx, command)
+	// вывод запущенной программы не дополняется ответом клиента
+	if _, isRun := command.(*entryCommands.RunCommand); isRun && response.Status == sharedCommand.StatusSuccess {
+		return response.ExitCode
+	}
+	err = formatter.Write(os.Stdout, os.Stderr, response)
 	if err != nil {
-		log.Fatalf("parse command error %v", err.Error())
+		fmt.Fprintf(os.Stderr, "output error: %v\n", err)
+		return sharedErrors.ExitCodeError
 	}
-	errs := command.Validate()
-	if len(errs) > 0 {
-		log.Fatalf("validation error %v", err.Error())
+	return response.ExitCode
+}
+
+// loadConfig конфигурация из окружения с глобальными флагами команды
+func loadConfig(options globalOptions) (*config.Config, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if options.profile != "" {
+		cfg.Profile = options.profile
 	}
+	cfg.ServerAddressOverride = options.serverAddress
+	return cfg, nil
+}
 
-	cmdExecutor := commandPkg.NewCommandExecutor(app)
-	response := cmdExecutor.ExecuteCommand(context.Background(), command)
-	responseStr, err := json.MarshalIndent(response, "", "    ")
+// listEntries записи для автодополнения id. Ключи берутся у агента, поэтому для заблокированного хранилища
+// вариантов нет. Хранилище только читается: автодополнение не должно менять файлы
+func listEntries(options globalOptions, entryType enum.EntryType, trash bool) ([]command_response.ListEntryCommandResponse, error) {
+	cfg, err := loadConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	app, err := appPkg.NewCompletionApp(cfg)
 	if err != nil {
-		fmt.Printf("%s %s", FailMessage, err.Error())
-	} else {
-		fmt.Printf("%s", responseStr)
+		return nil, err
+	}
+	defer app.Close()
+
+	if trash {
+		return app.EntryServiceProvider.GetTrash(context.Background(), entryCommands.TrashEntryCommand{EntryType: entryType})
 	}
+	return app.EntryServiceProvider.GetList(context.Background(), entryCommands.ListEntryCommand{EntryType: entryType})
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	appPkg "github.com/anoriar/gophkeeper/internal/client/shared/app"
+	sharedCommand "github.com/anoriar/gophkeeper/internal/client/shared/dto/command"
+	commandPkg "github.com/anoriar/gophkeeper/internal/client/shared/services/command"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/output"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/prompt"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/shell"
+)
+
+// errShellGlobalOptions профиль, сервер и формат вывода у всех команд shell одни: те, с которыми shell запущен
+var errShellGlobalOptions = errors.New("global flags are set when shell starts: --profile [name] --server [address] --output [format] shell")
+
+// runShell команды shell разбираются тем же деревом команд, что и командная строка, но ошибка разбора не завершает процесс
+func runShell(ctx context.Context, app *appPkg.App, executor *commandPkg.CommandExecutor, formatter *output.Formatter) error {
+	reader, restore, err := shell.NewLineReader(os.Stdin, os.Stdout, fmt.Sprintf("gophkeeper[%s]> ", app.Config.Profile), shell.NewCompleter(commandNames()))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := restore()
+		if err != nil {
+			log.Printf("restore terminal error %v", err.Error())
+		}
+	}()
+
+	// stdin занят вводом команд: секреты, не переданные флагами, вводятся без эха в строке shell
+	secrets := prompt.NewSecretReader(reader, nil)
+	parser := func(args []string) (sharedCommand.CommandInterface, error) {
+		cmd, options, err := ParseCommand(args, reader, secrets, nil)
+		if err != nil {
+			return nil, err
+		}
+		if options.explicit {
+			return nil, errShellGlobalOptions
+		}
+		return cmd, nil
+	}
+	return shell.NewShell(executor, parser, formatter, commandNames(), app.Config.ShellIdleTimeout).Run(ctx, reader)
+}
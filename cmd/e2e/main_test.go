@@ -191,9 +191,34 @@ func (suite *GophkeeperSuite) TestGophkeeper() {
 		assert.Equal(suite.T(), SuccessMessage, commandResponse.Status)
 	})
 
-	suite.Run("detail login entry", func() {
-		commandResponse := suite.execCommand("detail", "-t", "login", "-i", addedId)
-		assert.Equal(suite.T(), FailMessage, commandResponse.Status)
+	suite.Run("list login entry", func() {
+		commandResponse := suite.execCommand("list", "-t", "login")
+		assert.Equal(suite.T(), SuccessMessage, commandResponse.Status)
+		var loginListPayload []response.LoginListEntryResponse
+		err := json.Unmarshal(commandResponse.Payload, &loginListPayload)
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), 0, len(loginListPayload))
+	})
+
+	suite.Run("trash login entry", func() {
+		commandResponse := suite.execCommand("trash", "-t", "login")
+		assert.Equal(suite.T(), SuccessMessage, commandResponse.Status)
+		var loginListPayload []response.LoginListEntryResponse
+		err := json.Unmarshal(commandResponse.Payload, &loginListPayload)
+		require.NoError(suite.T(), err)
+		require.Equal(suite.T(), 1, len(loginListPayload))
+		assert.Equal(suite.T(), addedId, loginListPayload[0].Id)
+		assert.True(suite.T(), loginListPayload[0].IsDeleted)
+	})
+
+	suite.Run("restore login entry", func() {
+		commandResponse := suite.execCommand("restore", "-t", "login", "-i", addedId)
+		assert.Equal(suite.T(), SuccessMessage, commandResponse.Status)
+	})
+
+	suite.Run("sync login entry", func() {
+		commandResponse := suite.execCommand("sync", "-t", "login")
+		assert.Equal(suite.T(), SuccessMessage, commandResponse.Status)
 	})
 
 	suite.Run("list login entry", func() {
@@ -202,7 +227,8 @@ func (suite *GophkeeperSuite) TestGophkeeper() {
 		var loginListPayload []response.LoginListEntryResponse
 		err := json.Unmarshal(commandResponse.Payload, &loginListPayload)
 		require.NoError(suite.T(), err)
-		assert.Equal(suite.T(), 0, len(loginListPayload))
+		require.Equal(suite.T(), 1, len(loginListPayload))
+		assert.Equal(suite.T(), addedId, loginListPayload[0].Id)
 	})
 }
 
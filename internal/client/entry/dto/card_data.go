@@ -2,6 +2,7 @@ package dto
 
 import (
 	"fmt"
+	"strings"
 
 	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
 )
@@ -30,3 +31,19 @@ func (data *CardData) Validate() validation.ValidationErrors {
 
 	return validationErrors
 }
+
+// Field значение поля по имени из JSON данных: number, expireDate, holder, cvv. Регистр не важен
+func (data *CardData) Field(name string) (string, bool) {
+	switch strings.ToLower(name) {
+	case "number":
+		return data.Number, true
+	case "expiredate":
+		return data.ExpireDate, true
+	case "holder":
+		return data.Holder, true
+	case "cvv":
+		return data.CVV, true
+	default:
+		return "", false
+	}
+}
@@ -13,7 +13,12 @@ import (
 type AddEntryCommand struct {
 	EntryType enum.EntryType
 	Data      interface{}
-	Meta      json.RawMessage
+	// Title название записи, шифруется отдельно от данных, чтобы list не расшифровывал данные
+	Title string
+	// Meta метаданные, шифруются вместе с данными
+	Meta json.RawMessage
+	// PublicMeta метаданные, которые передаются на сервер в открытом виде
+	PublicMeta json.RawMessage
 }
 
 func (command *AddEntryCommand) Validate() validation.ValidationErrors {
@@ -14,7 +14,12 @@ type EditEntryCommand struct {
 	Id        string
 	EntryType enum.EntryType
 	Data      interface{}
-	Meta      json.RawMessage
+	// Title название записи, шифруется отдельно от данных, чтобы list не расшифровывал данные. nil - название не меняется
+	Title *string
+	// Meta метаданные, шифруются вместе с данными
+	Meta json.RawMessage
+	// PublicMeta метаданные, которые передаются на сервер в открытом виде
+	PublicMeta json.RawMessage
 }
 
 func (command *EditEntryCommand) Validate() validation.ValidationErrors {
@@ -0,0 +1,26 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+)
+
+// FindEntryCommand поиск по записям всех типов
+type FindEntryCommand struct {
+	Query string
+	// Limit сколько лучших совпадений вернуть, 0 - все
+	Limit int
+}
+
+func (l FindEntryCommand) Validate() validation.ValidationErrors {
+	var validationErrors validation.ValidationErrors
+	if strings.TrimSpace(l.Query) == "" {
+		validationErrors = append(validationErrors, fmt.Errorf("query required"))
+	}
+	if l.Limit < 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("limit must not be negative"))
+	}
+	return validationErrors
+}
@@ -0,0 +1,21 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+)
+
+type HistoryEntryCommand struct {
+	Id        string
+	EntryType enum.EntryType
+}
+
+func (command *HistoryEntryCommand) Validate() validation.ValidationErrors {
+	var validationErrors validation.ValidationErrors
+	if command.Id == "" {
+		validationErrors = append(validationErrors, fmt.Errorf("id required"))
+	}
+	return validationErrors
+}
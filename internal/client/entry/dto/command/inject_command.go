@@ -0,0 +1,30 @@
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+
+	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+)
+
+// InjectCommand подстановка значений записей в шаблон: {{ gk "login/<id>/password" }} или gk://login/<id>/password.
+// Check - только проверить, что все ссылки шаблона разрешаются, без записи Out
+type InjectCommand struct {
+	In    string
+	Out   string
+	Check bool
+}
+
+func (command *InjectCommand) Validate() validation.ValidationErrors {
+	var validationErrors validation.ValidationErrors
+	if command.In == "" {
+		validationErrors = append(validationErrors, fmt.Errorf("in file required"))
+	}
+	if command.Out == "" && !command.Check {
+		validationErrors = append(validationErrors, fmt.Errorf("out file required"))
+	}
+	if command.Out != "" && filepath.Clean(command.Out) == filepath.Clean(command.In) {
+		validationErrors = append(validationErrors, fmt.Errorf("out file must differ from in file"))
+	}
+	return validationErrors
+}
@@ -1,14 +1,38 @@
 package command
 
 import (
+	"fmt"
+
 	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
 	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
 )
 
+const (
+	// ListSortTitle - сортировка по названию без учета регистра, по умолчанию
+	ListSortTitle = "title"
+	// ListSortUpdatedAt - сортировка по дате изменения
+	ListSortUpdatedAt = "updatedAt"
+)
+
 type ListEntryCommand struct {
 	EntryType enum.EntryType
+	// SortBy ListSortTitle или ListSortUpdatedAt, пустое - ListSortTitle
+	SortBy string
+	// Desc сортировка по убыванию
+	Desc bool
+	// Limit сколько записей вернуть после сортировки, 0 - все
+	Limit int
 }
 
 func (l ListEntryCommand) Validate() validation.ValidationErrors {
-	return nil
+	var validationErrors validation.ValidationErrors
+	switch l.SortBy {
+	case "", ListSortTitle, ListSortUpdatedAt:
+	default:
+		validationErrors = append(validationErrors, fmt.Errorf("sort must be %s or %s", ListSortTitle, ListSortUpdatedAt))
+	}
+	if l.Limit < 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("limit must not be negative"))
+	}
+	return validationErrors
 }
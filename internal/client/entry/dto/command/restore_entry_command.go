@@ -0,0 +1,21 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+)
+
+type RestoreEntryCommand struct {
+	Id        string
+	EntryType enum.EntryType
+}
+
+func (command *RestoreEntryCommand) Validate() validation.ValidationErrors {
+	var validationErrors validation.ValidationErrors
+	if command.Id == "" {
+		validationErrors = append(validationErrors, fmt.Errorf("id required"))
+	}
+	return validationErrors
+}
@@ -0,0 +1,26 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+)
+
+type RevertEntryCommand struct {
+	Id        string
+	EntryType enum.EntryType
+	// Revision номер ревизии из history, 1 - последняя
+	Revision int
+}
+
+func (command *RevertEntryCommand) Validate() validation.ValidationErrors {
+	var validationErrors validation.ValidationErrors
+	if command.Id == "" {
+		validationErrors = append(validationErrors, fmt.Errorf("id required"))
+	}
+	if command.Revision < 1 {
+		validationErrors = append(validationErrors, fmt.Errorf("revision must be positive"))
+	}
+	return validationErrors
+}
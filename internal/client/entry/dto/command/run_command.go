@@ -0,0 +1,44 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto"
+	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+)
+
+var envNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// EnvReference переменная окружения программы со значением из записи: DB_PASS=login/<id>/password
+type EnvReference struct {
+	Name      string
+	Reference dto.SecretReference
+}
+
+// RunCommand запуск программы с секретами в переменных окружения. Args - программа и ее аргументы
+type RunCommand struct {
+	Env  []EnvReference
+	Args []string
+}
+
+func (command *RunCommand) Validate() validation.ValidationErrors {
+	var validationErrors validation.ValidationErrors
+	if len(command.Args) == 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("program required: run --env NAME=type/id/field -- program [args]"))
+	}
+	if len(command.Env) == 0 {
+		validationErrors = append(validationErrors, fmt.Errorf("env required"))
+	}
+	names := make(map[string]bool, len(command.Env))
+	for _, env := range command.Env {
+		if !envNameRegexp.MatchString(env.Name) {
+			validationErrors = append(validationErrors, fmt.Errorf("not valid env name %q", env.Name))
+		}
+		if names[env.Name] {
+			validationErrors = append(validationErrors, fmt.Errorf("env %s set twice", env.Name))
+		}
+		names[env.Name] = true
+	}
+	return validationErrors
+}
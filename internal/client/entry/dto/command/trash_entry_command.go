@@ -0,0 +1,14 @@
+package command
+
+import (
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+)
+
+type TrashEntryCommand struct {
+	EntryType enum.EntryType
+}
+
+func (l TrashEntryCommand) Validate() validation.ValidationErrors {
+	return nil
+}
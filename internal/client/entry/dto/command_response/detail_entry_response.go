@@ -8,10 +8,12 @@ import (
 )
 
 type DetailEntryResponse struct {
-	Id        string          `json:"id"`
-	EntryType enum.EntryType  `json:"type"`
-	UpdatedAt time.Time       `json:"updatedAt"`
-	IsDeleted bool            `json:"isDeleted"`
-	Data      interface{}     `json:"data"`
-	Meta      json.RawMessage `json:"meta"`
+	Id         string          `json:"id"`
+	EntryType  enum.EntryType  `json:"type"`
+	Title      string          `json:"title"`
+	UpdatedAt  time.Time       `json:"updatedAt"`
+	IsDeleted  bool            `json:"isDeleted"`
+	Data       interface{}     `json:"data"`
+	Meta       json.RawMessage `json:"meta"`
+	PublicMeta json.RawMessage `json:"publicMeta,omitempty"`
 }
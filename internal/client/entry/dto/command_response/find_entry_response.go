@@ -0,0 +1,17 @@
+package command_response
+
+import (
+	"time"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+)
+
+type FindEntryResponse struct {
+	Id        string         `json:"id"`
+	EntryType enum.EntryType `json:"type"`
+	Title     string         `json:"title"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	// MatchedField поле, в котором найдено лучшее совпадение. Само значение не выводится: оно может быть из зашифрованных метаданных
+	MatchedField string `json:"matchedField"`
+	Score        int    `json:"score"`
+}
@@ -0,0 +1,11 @@
+package command_response
+
+import (
+	"time"
+)
+
+type HistoryEntryResponse struct {
+	Revision  int       `json:"revision"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	IsDeleted bool      `json:"isDeleted"`
+}
@@ -0,0 +1,7 @@
+package command_response
+
+// InjectResponse ссылки шаблона без значений, в порядке первого появления. FileName пуст при --check
+type InjectResponse struct {
+	FileName   string   `json:"fileName,omitempty"`
+	References []string `json:"references"`
+}
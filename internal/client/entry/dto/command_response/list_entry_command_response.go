@@ -1,14 +1,17 @@
 package command_response
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
 )
 
 type ListEntryCommandResponse struct {
-	Id        string         `json:"id"`
-	EntryType enum.EntryType `json:"type"`
-	UpdatedAt time.Time      `json:"updatedAt"`
-	IsDeleted bool           `json:"isDeleted"`
+	Id         string          `json:"id"`
+	EntryType  enum.EntryType  `json:"type"`
+	Title      string          `json:"title"`
+	UpdatedAt  time.Time       `json:"updatedAt"`
+	IsDeleted  bool            `json:"isDeleted"`
+	PublicMeta json.RawMessage `json:"publicMeta,omitempty"`
 }
@@ -2,6 +2,7 @@ package dto
 
 import (
 	"fmt"
+	"strings"
 
 	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
 )
@@ -22,3 +23,15 @@ func (data *LoginData) Validate() validation.ValidationErrors {
 
 	return validationErrors
 }
+
+// Field значение поля по имени из JSON данных: login, password. Регистр не важен
+func (data *LoginData) Field(name string) (string, bool) {
+	switch strings.ToLower(name) {
+	case "login":
+		return data.Login, true
+	case "password":
+		return data.Password, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,39 @@
+package entry_ext
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+const syncMetaVersion = 1
+
+// SyncMeta метаданные записи на сервере: открытая часть, зашифрованная часть и зашифрованное название в base64.
+// Для сервера meta непрозрачна, поэтому название синхронизируется без изменения его схемы
+type SyncMeta struct {
+	Version   int             `json:"v"`
+	Public    json.RawMessage `json:"public,omitempty"`
+	Encrypted string          `json:"encrypted,omitempty"`
+	Title     string          `json:"title,omitempty"`
+}
+
+func NewSyncMeta(public json.RawMessage, encrypted []byte, encryptedTitle []byte) SyncMeta {
+	syncMeta := SyncMeta{Version: syncMetaVersion, Public: public}
+	if len(encrypted) > 0 {
+		syncMeta.Encrypted = base64.StdEncoding.EncodeToString(encrypted)
+	}
+	if len(encryptedTitle) > 0 {
+		syncMeta.Title = base64.StdEncoding.EncodeToString(encryptedTitle)
+	}
+	return syncMeta
+}
+
+// ParseSyncMeta ok=false - метаданные записаны клиентом до появления шифрования meta
+func ParseSyncMeta(raw json.RawMessage) (syncMeta SyncMeta, ok bool) {
+	if len(raw) == 0 {
+		return SyncMeta{}, false
+	}
+	if err := json.Unmarshal(raw, &syncMeta); err != nil || syncMeta.Version != syncMetaVersion {
+		return SyncMeta{}, false
+	}
+	return syncMeta, true
+}
@@ -5,4 +5,6 @@ import "github.com/anoriar/gophkeeper/internal/client/entry/enum"
 type SyncRequest struct {
 	Items    []SyncRequestItem `json:"items"`
 	SyncType enum.EntryType    `json:"syncType"`
+	// WithTrash сервер присылает записи из корзины с isDeleted=true
+	WithTrash bool `json:"withTrash"`
 }
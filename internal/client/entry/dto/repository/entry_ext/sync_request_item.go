@@ -1,7 +1,6 @@
 package entry_ext
 
 import (
-	"encoding/json"
 	"time"
 )
 
@@ -14,6 +13,6 @@ type SyncRequestItem struct {
 	IsDeleted bool `json:"isDeleted"`
 	// Data - зашифрованные данные в base64
 	Data string `json:"data"`
-	// Meta - метаданные
-	Meta json.RawMessage `json:"meta"`
+	// Meta - открытые и зашифрованные метаданные
+	Meta SyncMeta `json:"meta"`
 }
@@ -14,6 +14,8 @@ type SyncResponseItem struct {
 	Data string
 	// Meta - метаданные
 	Meta json.RawMessage
+	// IsDeleted - запись в корзине на сервере
+	IsDeleted bool
 }
 
 func (s *SyncResponseItem) UnmarshalJSON(data []byte) error {
@@ -22,12 +24,14 @@ func (s *SyncResponseItem) UnmarshalJSON(data []byte) error {
 		UpdatedAt  string          `json:"updatedAt"`
 		Data       string          `json:"data"`
 		Meta       json.RawMessage `json:"meta"`
+		IsDeleted  bool            `json:"isDeleted"`
 	}
 	if err := json.Unmarshal(data, &alias); err != nil {
 		return err
 	}
 
 	s.OriginalId = alias.OriginalId
+	s.IsDeleted = alias.IsDeleted
 
 	updatedAt, err := time.Parse(time.RFC3339, alias.UpdatedAt)
 	if err != nil {
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	entryErrors "github.com/anoriar/gophkeeper/internal/client/entry/errors"
+)
+
+const (
+	// SecretFieldData данные text и bin, поле по умолчанию для них
+	SecretFieldData = "data"
+	// SecretFieldTitle название записи любого типа
+	SecretFieldTitle = "title"
+)
+
+// SecretReference ссылка на значение записи локального хранилища: login/<id>/password.
+// Для text и bin поле можно не указывать: text/<id> - данные записи
+type SecretReference struct {
+	EntryType enum.EntryType
+	Id        string
+	Field     string
+}
+
+func ParseSecretReference(value string) (SecretReference, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) < 2 || len(parts) > 3 || !enum.IsEntryType(parts[0]) || parts[1] == "" {
+		return SecretReference{}, fmt.Errorf("%w: %q", entryErrors.ErrSecretReferenceNotValid, value)
+	}
+	reference := SecretReference{EntryType: enum.EntryType(parts[0]), Id: parts[1]}
+	if len(parts) == 3 {
+		reference.Field = parts[2]
+	}
+	switch {
+	case reference.Field == "" && (reference.EntryType == enum.Text || reference.EntryType == enum.Bin):
+		reference.Field = SecretFieldData
+	case reference.Field == "":
+		return SecretReference{}, fmt.Errorf("%w: %q, field required for %s", entryErrors.ErrSecretReferenceNotValid, value, reference.EntryType)
+	}
+	return reference, nil
+}
+
+func (r SecretReference) String() string {
+	return string(r.EntryType) + "/" + r.Id + "/" + r.Field
+}
@@ -8,10 +8,17 @@ import (
 )
 
 type Entry struct {
-	Id        string          `json:"id"`
-	EntryType enum.EntryType  `json:"type"`
-	UpdatedAt time.Time       `json:"updatedAt"`
-	IsDeleted bool            `json:"isDeleted"`
-	Data      []byte          `json:"data"`
-	Meta      json.RawMessage `json:"meta"`
+	Id        string         `json:"id"`
+	EntryType enum.EntryType `json:"type"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	IsDeleted bool           `json:"isDeleted"`
+	Data      []byte         `json:"data"`
+	// Title зашифрованное название записи, показывается в list
+	Title []byte `json:"encryptedTitle,omitempty"`
+	// Meta зашифрованные метаданные
+	Meta []byte `json:"encryptedMeta,omitempty"`
+	// PublicMeta метаданные, открытые серверу
+	PublicMeta json.RawMessage `json:"publicMeta,omitempty"`
+	// PlainMeta метаданные, сохраненные до появления шифрования meta. Шифруются при следующем login
+	PlainMeta json.RawMessage `json:"meta,omitempty"`
 }
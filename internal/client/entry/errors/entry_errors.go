@@ -2,4 +2,9 @@ package errors
 
 import "errors"
 
-var ErrSyncConflict = errors.New("sync entries conflict")
+var ErrRevisionNotFound = errors.New("entry revision not found")
+var ErrEntryNotInTrash = errors.New("entry is not in trash")
+var ErrSecretReferenceNotValid = errors.New("not valid secret reference, expected type/id/field")
+var ErrSecretFieldNotFound = errors.New("entry has no such field")
+var ErrProgramNotStarted = errors.New("program not started")
+var ErrPlainMetaRejected = errors.New("entry has plaintext meta, but vault is already upgraded")
@@ -44,25 +44,44 @@ func (f *EntryResponseFactory) CreateDetailResponseFromEntity(entry entity.Entry
 	}
 
 	return command_response.DetailEntryResponse{
-		Id:        entry.Id,
-		EntryType: entry.EntryType,
-		UpdatedAt: entry.UpdatedAt,
-		IsDeleted: entry.IsDeleted,
-		Data:      data,
-		Meta:      entry.Meta,
+		Id:         entry.Id,
+		EntryType:  entry.EntryType,
+		Title:      string(entry.Title),
+		UpdatedAt:  entry.UpdatedAt,
+		IsDeleted:  entry.IsDeleted,
+		Data:       data,
+		Meta:       entry.Meta,
+		PublicMeta: entry.PublicMeta,
 	}, nil
 }
 
+// CreateListResponseFromEntity названия записей должны быть уже расшифрованы
 func (f *EntryResponseFactory) CreateListResponseFromEntity(entries []entity.Entry) []command_response.ListEntryCommandResponse {
 	responseEntries := make([]command_response.ListEntryCommandResponse, 0, len(entries))
 
 	for _, entryEntity := range entries {
 		responseEntries = append(responseEntries, command_response.ListEntryCommandResponse{
-			Id:        entryEntity.Id,
-			EntryType: entryEntity.EntryType,
-			UpdatedAt: entryEntity.UpdatedAt,
-			IsDeleted: entryEntity.IsDeleted,
+			Id:         entryEntity.Id,
+			EntryType:  entryEntity.EntryType,
+			Title:      string(entryEntity.Title),
+			UpdatedAt:  entryEntity.UpdatedAt,
+			IsDeleted:  entryEntity.IsDeleted,
+			PublicMeta: entryEntity.PublicMeta,
 		})
 	}
 	return responseEntries
 }
+
+// CreateHistoryResponseFromEntity ревизии нумеруются от новых к старым, начиная с 1
+func (f *EntryResponseFactory) CreateHistoryResponseFromEntity(revisions []entity.Entry) []command_response.HistoryEntryResponse {
+	responseRevisions := make([]command_response.HistoryEntryResponse, 0, len(revisions))
+
+	for i, revision := range revisions {
+		responseRevisions = append(responseRevisions, command_response.HistoryEntryResponse{
+			Revision:  i + 1,
+			UpdatedAt: revision.UpdatedAt,
+			IsDeleted: revision.IsDeleted,
+		})
+	}
+	return responseRevisions
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
 	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	entryErrors "github.com/anoriar/gophkeeper/internal/client/entry/errors"
 	"github.com/anoriar/gophkeeper/internal/client/shared/errors"
 )
 
@@ -31,12 +32,14 @@ func (l *EntryFactory) CreateFromAddCmd(command command.AddEntryCommand) (entity
 		return entity.Entry{}, err
 	}
 	return entity.Entry{
-		Id:        l.uuidGen.NewString(),
-		EntryType: command.EntryType,
-		UpdatedAt: time.Now(),
-		IsDeleted: false,
-		Data:      data,
-		Meta:      command.Meta,
+		Id:         l.uuidGen.NewString(),
+		EntryType:  command.EntryType,
+		UpdatedAt:  time.Now(),
+		IsDeleted:  false,
+		Data:       data,
+		Title:      createTitle(command.Title),
+		Meta:       command.Meta,
+		PublicMeta: command.PublicMeta,
 	}, nil
 }
 
@@ -45,14 +48,28 @@ func (l *EntryFactory) CreateFromEditCmd(command command.EditEntryCommand) (enti
 	if err != nil {
 		return entity.Entry{}, err
 	}
-	return entity.Entry{
-		Id:        command.Id,
-		EntryType: command.EntryType,
-		UpdatedAt: time.Now(),
-		IsDeleted: false,
-		Data:      data,
-		Meta:      command.Meta,
-	}, nil
+	entry := entity.Entry{
+		Id:         command.Id,
+		EntryType:  command.EntryType,
+		UpdatedAt:  time.Now(),
+		IsDeleted:  false,
+		Data:       data,
+		Meta:       command.Meta,
+		PublicMeta: command.PublicMeta,
+	}
+	// без названия в команде его подставляет сервис из текущей записи
+	if command.Title != nil {
+		entry.Title = createTitle(*command.Title)
+	}
+	return entry, nil
+}
+
+// createTitle пустое название не шифруется
+func createTitle(title string) []byte {
+	if title == "" {
+		return nil
+	}
+	return []byte(title)
 }
 
 func (l *EntryFactory) createData(entryType enum.EntryType, data interface{}) ([]byte, error) {
@@ -86,7 +103,7 @@ func (l *EntryFactory) createData(entryType enum.EntryType, data interface{}) ([
 	}
 }
 
-func (l *EntryFactory) CreateFromSyncResponse(syncResponse entry_ext.SyncResponse) ([]entity.Entry, error) {
+func (l *EntryFactory) CreateFromSyncResponse(syncResponse entry_ext.SyncResponse, rejectPlainMeta bool) ([]entity.Entry, error) {
 	entries := make([]entity.Entry, 0, len(syncResponse.Items))
 
 	for _, responseItem := range syncResponse.Items {
@@ -94,14 +111,38 @@ func (l *EntryFactory) CreateFromSyncResponse(syncResponse entry_ext.SyncRespons
 		if err != nil {
 			return nil, fmt.Errorf("%w: %v", errors.ErrInternalError, "data is not decoded")
 		}
-		entries = append(entries, entity.Entry{
+		entry := entity.Entry{
 			Id:        responseItem.OriginalId,
 			EntryType: syncResponse.SyncType,
 			UpdatedAt: responseItem.UpdatedAt,
-			IsDeleted: false,
+			IsDeleted: responseItem.IsDeleted,
 			Data:      data,
-			Meta:      responseItem.Meta,
-		})
+		}
+
+		syncMeta, ok := entry_ext.ParseSyncMeta(responseItem.Meta)
+		if !ok {
+			// сервер не должен подсунуть открытые метаданные вместо зашифрованных
+			if rejectPlainMeta {
+				return nil, fmt.Errorf("%w: %w: entry %s", errors.ErrCorruptedEntry, entryErrors.ErrPlainMetaRejected, responseItem.OriginalId)
+			}
+			entry.PlainMeta = responseItem.Meta
+			entries = append(entries, entry)
+			continue
+		}
+		entry.PublicMeta = syncMeta.Public
+		if syncMeta.Title != "" {
+			entry.Title, err = base64.StdEncoding.DecodeString(syncMeta.Title)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", errors.ErrInternalError, "title is not decoded")
+			}
+		}
+		if syncMeta.Encrypted != "" {
+			entry.Meta, err = base64.StdEncoding.DecodeString(syncMeta.Encrypted)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", errors.ErrInternalError, "meta is not decoded")
+			}
+		}
+		entries = append(entries, entry)
 	}
 	return entries, nil
 }
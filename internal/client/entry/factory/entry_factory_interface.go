@@ -10,5 +10,6 @@ import (
 type EntryFactoryInterface interface {
 	CreateFromAddCmd(command command.AddEntryCommand) (entity.Entry, error)
 	CreateFromEditCmd(command command.EditEntryCommand) (entity.Entry, error)
-	CreateFromSyncResponse(syncResponse entry_ext.SyncResponse) ([]entity.Entry, error)
+	// CreateFromSyncResponse rejectPlainMeta - хранилище перешифровано: метаданные старого формата не принимаются
+	CreateFromSyncResponse(syncResponse entry_ext.SyncResponse, rejectPlainMeta bool) ([]entity.Entry, error)
 }
@@ -0,0 +1,116 @@
+package factory
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/repository/entry_ext"
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	entryErrors "github.com/anoriar/gophkeeper/internal/client/entry/errors"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+)
+
+func TestEntryFactory_CreateFromSyncResponse(t *testing.T) {
+	updatedAt := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+	data := base64.StdEncoding.EncodeToString([]byte("data"))
+
+	syncMeta, err := json.Marshal(entry_ext.NewSyncMeta(json.RawMessage(`{"tag":"work"}`), []byte("encrypted meta"), []byte("encrypted title")))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		meta            json.RawMessage
+		isDeleted       bool
+		rejectPlainMeta bool
+		want            entity.Entry
+		wantErr         error
+	}{
+		{
+			name: "encrypted meta",
+			meta: syncMeta,
+			want: entity.Entry{
+				Id:         "225de857-71c5-452f-96f7-ff385d808083",
+				EntryType:  enum.Login,
+				UpdatedAt:  updatedAt,
+				Data:       []byte("data"),
+				Meta:       []byte("encrypted meta"),
+				Title:      []byte("encrypted title"),
+				PublicMeta: json.RawMessage(`{"tag":"work"}`),
+			},
+		},
+		{
+			name: "plain meta",
+			meta: json.RawMessage(`{"site":"example.com"}`),
+			want: entity.Entry{
+				Id:        "225de857-71c5-452f-96f7-ff385d808083",
+				EntryType: enum.Login,
+				UpdatedAt: updatedAt,
+				Data:      []byte("data"),
+				PlainMeta: json.RawMessage(`{"site":"example.com"}`),
+			},
+		},
+		{
+			name:            "plain meta after vault upgrade",
+			meta:            json.RawMessage(`{"site":"example.com"}`),
+			rejectPlainMeta: true,
+			wantErr:         entryErrors.ErrPlainMetaRejected,
+		},
+		{
+			name:            "encrypted meta after vault upgrade",
+			meta:            syncMeta,
+			rejectPlainMeta: true,
+			want: entity.Entry{
+				Id:         "225de857-71c5-452f-96f7-ff385d808083",
+				EntryType:  enum.Login,
+				UpdatedAt:  updatedAt,
+				Data:       []byte("data"),
+				Meta:       []byte("encrypted meta"),
+				Title:      []byte("encrypted title"),
+				PublicMeta: json.RawMessage(`{"tag":"work"}`),
+			},
+		},
+		{
+			name:      "deleted entry",
+			meta:      json.RawMessage(`{"site":"example.com"}`),
+			isDeleted: true,
+			want: entity.Entry{
+				Id:        "225de857-71c5-452f-96f7-ff385d808083",
+				EntryType: enum.Login,
+				UpdatedAt: updatedAt,
+				IsDeleted: true,
+				Data:      []byte("data"),
+				PlainMeta: json.RawMessage(`{"site":"example.com"}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewEntryFactory(nil)
+			entries, err := f.CreateFromSyncResponse(entry_ext.SyncResponse{
+				SyncType: enum.Login,
+				Items: []entry_ext.SyncResponseItem{
+					{
+						OriginalId: "225de857-71c5-452f-96f7-ff385d808083",
+						UpdatedAt:  updatedAt,
+						Data:       data,
+						Meta:       tt.meta,
+						IsDeleted:  tt.isDeleted,
+					},
+				},
+			}, tt.rejectPlainMeta)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, sharedErrors.ErrCorruptedEntry)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []entity.Entry{tt.want}, entries)
+		})
+	}
+}
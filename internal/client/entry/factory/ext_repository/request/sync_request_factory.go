@@ -18,8 +18,9 @@ func NewSyncRequestFactory() *SyncRequestFactory {
 func (f *SyncRequestFactory) CreateSyncRequest(syncType enum.EntryType, entries []entity.Entry) entry_ext.SyncRequest {
 	items := f.CreateFromEntries(entries)
 	return entry_ext.SyncRequest{
-		Items:    items,
-		SyncType: syncType,
+		Items:     items,
+		SyncType:  syncType,
+		WithTrash: true,
 	}
 }
 
@@ -31,7 +32,7 @@ func (f *SyncRequestFactory) CreateFromEntries(entries []entity.Entry) []entry_e
 			UpdatedAt:  entryEntity.UpdatedAt,
 			IsDeleted:  entryEntity.IsDeleted,
 			Data:       base64.StdEncoding.EncodeToString(entryEntity.Data),
-			Meta:       entryEntity.Meta,
+			Meta:       entry_ext.NewSyncMeta(entryEntity.PublicMeta, entryEntity.Meta, entryEntity.Title),
 		})
 	}
 	return requestItems
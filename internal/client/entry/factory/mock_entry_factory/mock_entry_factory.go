@@ -7,11 +7,10 @@ package mock_entry_factory
 import (
 	reflect "reflect"
 
-	gomock "github.com/golang/mock/gomock"
-
 	command "github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
 	entry_ext "github.com/anoriar/gophkeeper/internal/client/entry/dto/repository/entry_ext"
 	entity "github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	gomock "github.com/golang/mock/gomock"
 )
 
 // MockEntryFactoryInterface is a mock of EntryFactoryInterface interface.
@@ -68,16 +67,16 @@ func (mr *MockEntryFactoryInterfaceMockRecorder) CreateFromEditCmd(command inter
 }
 
 // CreateFromSyncResponse mocks base method.
-func (m *MockEntryFactoryInterface) CreateFromSyncResponse(syncResponse entry_ext.SyncResponse) ([]entity.Entry, error) {
+func (m *MockEntryFactoryInterface) CreateFromSyncResponse(syncResponse entry_ext.SyncResponse, rejectPlainMeta bool) ([]entity.Entry, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateFromSyncResponse", syncResponse)
+	ret := m.ctrl.Call(m, "CreateFromSyncResponse", syncResponse, rejectPlainMeta)
 	ret0, _ := ret[0].([]entity.Entry)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateFromSyncResponse indicates an expected call of CreateFromSyncResponse.
-func (mr *MockEntryFactoryInterfaceMockRecorder) CreateFromSyncResponse(syncResponse interface{}) *gomock.Call {
+func (mr *MockEntryFactoryInterfaceMockRecorder) CreateFromSyncResponse(syncResponse, rejectPlainMeta interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFromSyncResponse", reflect.TypeOf((*MockEntryFactoryInterface)(nil).CreateFromSyncResponse), syncResponse)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFromSyncResponse", reflect.TypeOf((*MockEntryFactoryInterface)(nil).CreateFromSyncResponse), syncResponse, rejectPlainMeta)
 }
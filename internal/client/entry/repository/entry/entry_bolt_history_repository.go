@@ -0,0 +1,139 @@
+package entry
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+)
+
+const historyBucketPrefix = "history."
+
+// EntryBoltHistoryRepository ревизии записей одного типа в файле bbolt записей.
+// Все ревизии записи хранятся одним значением с ключом по id записи
+type EntryBoltHistoryRepository struct {
+	store     *EntryBoltStore
+	entryType enum.EntryType
+}
+
+func NewEntryBoltHistoryRepository(store *EntryBoltStore, entryType enum.EntryType) *EntryBoltHistoryRepository {
+	return &EntryBoltHistoryRepository{store: store, entryType: entryType}
+}
+
+func (e *EntryBoltHistoryRepository) Push(ctx context.Context, revision entity.Entry, limit int) error {
+	return e.store.update(func(tx *entryStoreTx) error {
+		bucket, err := tx.bucket(e.bucketName(), true)
+		if err != nil {
+			return err
+		}
+		revisions, err := e.getRevisions(bucket, revision.Id)
+		if err != nil {
+			return err
+		}
+
+		revisions = append([]entity.Entry{revision}, revisions...)
+		if len(revisions) > limit {
+			revisions = revisions[:limit]
+		}
+		value, err := json.Marshal(revisions)
+		if err != nil {
+			return err
+		}
+		return bucket.putValue(revision.Id, value)
+	})
+}
+
+func (e *EntryBoltHistoryRepository) GetList(ctx context.Context, id string) ([]entity.Entry, error) {
+	revisions := make([]entity.Entry, 0)
+	err := e.store.view(func(tx *entryStoreTx) error {
+		bucket, err := tx.bucket(e.bucketName(), false)
+		if err != nil || bucket == nil {
+			return err
+		}
+		revisions, err = e.getRevisions(bucket, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (e *EntryBoltHistoryRepository) GetAll(ctx context.Context) ([]entity.Entry, error) {
+	revisions := make([]entity.Entry, 0)
+	err := e.store.view(func(tx *entryStoreTx) error {
+		bucket, err := tx.bucket(e.bucketName(), false)
+		if err != nil || bucket == nil {
+			return err
+		}
+		return bucket.forEachValue(func(value []byte) error {
+			var entryRevisions []entity.Entry
+			err := json.Unmarshal(value, &entryRevisions)
+			if err != nil {
+				return err
+			}
+			revisions = append(revisions, entryRevisions...)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (e *EntryBoltHistoryRepository) Rewrite(ctx context.Context, revisions []entity.Entry) error {
+	ids := make([]string, 0)
+	revisionsById := make(map[string][]entity.Entry)
+	for _, revision := range revisions {
+		if _, ok := revisionsById[revision.Id]; !ok {
+			ids = append(ids, revision.Id)
+		}
+		revisionsById[revision.Id] = append(revisionsById[revision.Id], revision)
+	}
+	return e.store.update(func(tx *entryStoreTx) error {
+		err := tx.deleteBucket(e.bucketName())
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		bucket, err := tx.bucket(e.bucketName(), true)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			value, err := json.Marshal(revisionsById[id])
+			if err != nil {
+				return err
+			}
+			err = bucket.putValue(id, value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (e *EntryBoltHistoryRepository) Clear(ctx context.Context) error {
+	return e.store.update(func(tx *entryStoreTx) error {
+		return tx.deleteBucket(e.bucketName())
+	})
+}
+
+func (e *EntryBoltHistoryRepository) getRevisions(bucket *entryBucket, id string) ([]entity.Entry, error) {
+	revisions := make([]entity.Entry, 0)
+	value, err := bucket.getValue(id)
+	if err != nil || value == nil {
+		return revisions, err
+	}
+	err = json.Unmarshal(value, &revisions)
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (e *EntryBoltHistoryRepository) bucketName() string {
+	return historyBucketPrefix + string(e.entryType)
+}
@@ -0,0 +1,122 @@
+package entry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	sharedErr "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+)
+
+// EntryBoltRepository записи одного типа в общем файле bbolt.
+// Каждый тип хранится в своем bucket, ключ записи - id (в запечатанном хранилище - их HMAC):
+// поиск по id и типу не расшифровывает и не декодирует все записи
+type EntryBoltRepository struct {
+	store     *EntryBoltStore
+	entryType enum.EntryType
+}
+
+func NewEntryBoltRepository(store *EntryBoltStore, entryType enum.EntryType) *EntryBoltRepository {
+	return &EntryBoltRepository{store: store, entryType: entryType}
+}
+
+// Lock блокирует весь файл bbolt, то есть записи всех типов
+func (e *EntryBoltRepository) Lock(ctx context.Context) (func() error, error) {
+	return e.store.Lock()
+}
+
+func (e *EntryBoltRepository) Add(ctx context.Context, entry entity.Entry) error {
+	return e.store.update(func(tx *entryStoreTx) error {
+		bucket, err := tx.bucket(string(e.entryType), true)
+		if err != nil {
+			return err
+		}
+		return bucket.put(entry)
+	})
+}
+
+func (e *EntryBoltRepository) Edit(ctx context.Context, entry entity.Entry) error {
+	return e.store.update(func(tx *entryStoreTx) error {
+		bucket, err := tx.bucket(string(e.entryType), true)
+		if err != nil {
+			return err
+		}
+		fileEntry, err := bucket.get(entry.Id)
+		if err != nil {
+			return err
+		}
+		if fileEntry == nil {
+			return fmt.Errorf("%w", sharedErr.ErrEntryNotFound)
+		}
+		return bucket.put(entry)
+	})
+}
+
+func (e *EntryBoltRepository) GetById(ctx context.Context, id string) (entity.Entry, error) {
+	var entry *entity.Entry
+	err := e.store.view(func(tx *entryStoreTx) error {
+		bucket, err := tx.bucket(string(e.entryType), false)
+		if err != nil || bucket == nil {
+			return err
+		}
+		entry, err = bucket.get(id)
+		return err
+	})
+	if err != nil {
+		return entity.Entry{}, err
+	}
+	if entry == nil {
+		return entity.Entry{}, fmt.Errorf("%w", sharedErr.ErrEntryNotFound)
+	}
+	return *entry, nil
+}
+
+func (e *EntryBoltRepository) GetList(ctx context.Context) ([]entity.Entry, error) {
+	entries := make([]entity.Entry, 0)
+	err := e.store.view(func(tx *entryStoreTx) error {
+		bucket, err := tx.bucket(string(e.entryType), false)
+		if err != nil || bucket == nil {
+			return err
+		}
+		return bucket.forEach(func(entry entity.Entry) error {
+			entries = append(entries, entry)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (e *EntryBoltRepository) Rewrite(ctx context.Context, entries []entity.Entry) error {
+	return e.store.update(func(tx *entryStoreTx) error {
+		err := tx.deleteBucket(string(e.entryType))
+		if err != nil {
+			return err
+		}
+		return e.putAll(tx, entries)
+	})
+}
+
+// Import добавляет записи к уже сохраненным. Записи с совпадающим id заменяются
+func (e *EntryBoltRepository) Import(ctx context.Context, entries []entity.Entry) error {
+	return e.store.update(func(tx *entryStoreTx) error {
+		return e.putAll(tx, entries)
+	})
+}
+
+func (e *EntryBoltRepository) putAll(tx *entryStoreTx, entries []entity.Entry) error {
+	bucket, err := tx.bucket(string(e.entryType), true)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		err = bucket.put(entry)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package entry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry/mock_store_key_provider"
+	sharedErr "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+)
+
+var testStoreKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestStoreKeyProvider(t *testing.T, storeKey []byte) *mock_store_key_provider.MockStoreKeyProviderInterface {
+	ctrl := gomock.NewController(t)
+	provider := mock_store_key_provider.NewMockStoreKeyProviderInterface(ctrl)
+	provider.EXPECT().StoreKey().Return(storeKey, nil).AnyTimes()
+	// отметка о запечатывании хранится как в vault.json: один раз выставляется и не сбрасывается
+	sealed := false
+	provider.EXPECT().StoreSealed().DoAndReturn(func() (bool, error) { return sealed, nil }).AnyTimes()
+	provider.EXPECT().MarkStoreSealed().DoAndReturn(func() error {
+		sealed = true
+		return nil
+	}).AnyTimes()
+	return provider
+}
+
+func TestEntryBoltRepository(t *testing.T) {
+	ctx := context.Background()
+	store := NewEntryBoltStore(filepath.Join(t.TempDir(), "entries", "vault.db"), newTestStoreKeyProvider(t, testStoreKey), 100*time.Millisecond)
+	loginRepository := NewEntryBoltRepository(store, enum.Login)
+	cardRepository := NewEntryBoltRepository(store, enum.Card)
+
+	// пустое хранилище читается без создания файла
+	got, err := loginRepository.GetList(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+	_, err = loginRepository.GetById(ctx, "1")
+	assert.ErrorIs(t, err, sharedErr.ErrEntryNotFound)
+
+	login := entity.Entry{Id: "1", EntryType: enum.Login, UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC), Data: []byte("login")}
+	card := entity.Entry{Id: "2", EntryType: enum.Card, UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC), Data: []byte("card")}
+	require.NoError(t, loginRepository.Add(ctx, login))
+	require.NoError(t, cardRepository.Add(ctx, card))
+
+	got, err = loginRepository.GetList(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Entry{login}, got)
+
+	// записи другого типа не видны
+	_, err = loginRepository.GetById(ctx, card.Id)
+	assert.ErrorIs(t, err, sharedErr.ErrEntryNotFound)
+
+	edited := login
+	edited.Data = []byte("edited")
+	require.NoError(t, loginRepository.Edit(ctx, edited))
+	gotEntry, err := loginRepository.GetById(ctx, login.Id)
+	require.NoError(t, err)
+	assert.Equal(t, edited, gotEntry)
+
+	err = loginRepository.Edit(ctx, entity.Entry{Id: "3", EntryType: enum.Login})
+	assert.ErrorIs(t, err, sharedErr.ErrEntryNotFound)
+
+	require.NoError(t, loginRepository.Rewrite(ctx, []entity.Entry{}))
+	got, err = loginRepository.GetList(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+	got, err = cardRepository.GetList(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Entry{card}, got)
+}
+
+func TestEntryBoltRepository_Lock(t *testing.T) {
+	ctx := context.Background()
+	fileName := filepath.Join(t.TempDir(), "vault.db")
+	store := NewEntryBoltStore(fileName, newTestStoreKeyProvider(t, testStoreKey), 100*time.Millisecond)
+	loginRepository := NewEntryBoltRepository(store, enum.Login)
+	cardRepository := NewEntryBoltRepository(store, enum.Card)
+
+	// репозитории одного файла блокируют его вместе, операции выполняются через открытую базу
+	unlockLogin, err := loginRepository.Lock(ctx)
+	require.NoError(t, err)
+	unlockCard, err := cardRepository.Lock(ctx)
+	require.NoError(t, err)
+	require.NoError(t, cardRepository.Add(ctx, entity.Entry{Id: "1", EntryType: enum.Card}))
+
+	// другой процесс ждет освобождения файла
+	otherStore := NewEntryBoltStore(fileName, newTestStoreKeyProvider(t, testStoreKey), 100*time.Millisecond)
+	_, err = NewEntryBoltRepository(otherStore, enum.Card).GetList(ctx)
+	assert.ErrorIs(t, err, sharedErr.ErrVaultBusy)
+
+	require.NoError(t, unlockLogin())
+	require.NoError(t, unlockCard())
+
+	got, err := NewEntryBoltRepository(otherStore, enum.Card).GetList(ctx)
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
+
+func TestEntryBoltRepository_Seal(t *testing.T) {
+	ctx := context.Background()
+	fileName := filepath.Join(t.TempDir(), "vault.db")
+	entry := entity.Entry{Id: "1", EntryType: enum.Login, UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC), Data: []byte("data")}
+
+	// хранилище без ключа (до rekey старых хранилищ) читается и с ключом, пока его не запечатали через Seal
+	unsealedRepository := NewEntryBoltRepository(NewEntryBoltStore(fileName, newTestStoreKeyProvider(t, nil), time.Second), enum.Login)
+	require.NoError(t, unsealedRepository.Add(ctx, entry))
+
+	store := NewEntryBoltStore(fileName, newTestStoreKeyProvider(t, testStoreKey), time.Second)
+	repository := NewEntryBoltRepository(store, enum.Login)
+	got, err := repository.GetList(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Entry{entry}, got)
+
+	require.NoError(t, store.Seal())
+	got, err = repository.GetList(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Entry{entry}, got)
+
+	info, err := os.Stat(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// ни id, ни тип, ни данные записи не лежат в файле открыто
+	content, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), string(enum.Login))
+	assert.NotContains(t, string(content), "updatedAt")
+
+	_, err = NewEntryBoltRepository(NewEntryBoltStore(fileName, newTestStoreKeyProvider(t, []byte("another store key 0123456789abc")), time.Second), enum.Login).GetList(ctx)
+	assert.ErrorIs(t, err, sharedErr.ErrVaultTampered)
+
+	// удаление записи в обход клиента меняет MAC хранилища
+	db, err := bbolt.Open(fileName, 0600, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return tx.ForEach(func(name []byte, bucket *bbolt.Bucket) error {
+			if string(name) == string(metaBucketName) {
+				return nil
+			}
+			key, _ := bucket.Cursor().First()
+			return bucket.Delete(key)
+		})
+	}))
+	require.NoError(t, db.Close())
+
+	_, err = repository.GetList(ctx)
+	assert.ErrorIs(t, err, sharedErr.ErrVaultTampered)
+	err = repository.Add(ctx, entry)
+	assert.ErrorIs(t, err, sharedErr.ErrVaultTampered)
+
+	// без bucket meta запечатанное хранилище не выдается за старое открытое
+	db, err = bbolt.Open(fileName, 0600, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		return tx.DeleteBucket(metaBucketName)
+	}))
+	require.NoError(t, db.Close())
+
+	require.NoError(t, store.Seal())
+	_, err = repository.GetList(ctx)
+	assert.ErrorIs(t, err, sharedErr.ErrVaultTampered)
+
+	require.NoError(t, os.Remove(fileName))
+	_, err = repository.GetList(ctx)
+	assert.ErrorIs(t, err, sharedErr.ErrVaultTampered)
+
+	// пустой файл вместо запечатанного хранилища - тоже подмена
+	db, err = bbolt.Open(fileName, 0600, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	_, err = repository.GetList(ctx)
+	assert.ErrorIs(t, err, sharedErr.ErrVaultTampered)
+}
+
+func TestEntryBoltStore_Replace(t *testing.T) {
+	ctx := context.Background()
+	fileName := filepath.Join(t.TempDir(), "vault.db")
+	entry := entity.Entry{Id: "1", EntryType: enum.Login, UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC), Data: []byte("data")}
+	restored := entity.Entry{Id: "2", EntryType: enum.Card, UpdatedAt: time.Date(2024, time.March, 11, 12, 0, 0, 0, time.UTC), Data: []byte("restored")}
+
+	store := NewEntryBoltStore(fileName, newTestStoreKeyProvider(t, testStoreKey), time.Second)
+	require.NoError(t, NewEntryBoltRepository(store, enum.Login).Add(ctx, entry))
+	require.NoError(t, NewEntryBoltHistoryRepository(store, enum.Login).Push(ctx, entry, 10))
+
+	// хранилище с записями другого ключа заменяется целиком и запечатывается новым ключом.
+	// Под блокировкой записи после замены попадают в новый файл
+	anotherStoreKey := []byte("fedcba9876543210fedcba9876543210")
+	anotherStore := NewEntryBoltStore(fileName, newTestStoreKeyProvider(t, anotherStoreKey), time.Second)
+	unlock, err := anotherStore.Lock()
+	require.NoError(t, err)
+	require.NoError(t, anotherStore.Replace(map[enum.EntryType][]entity.Entry{enum.Card: {restored}}))
+	require.NoError(t, NewEntryBoltRepository(anotherStore, enum.Login).Add(ctx, entry))
+	require.NoError(t, unlock())
+
+	got, err := NewEntryBoltRepository(anotherStore, enum.Card).GetList(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Entry{restored}, got)
+	got, err = NewEntryBoltRepository(anotherStore, enum.Login).GetList(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Entry{entry}, got)
+	revisions, err := NewEntryBoltHistoryRepository(anotherStore, enum.Login).GetList(ctx, entry.Id)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+	_, err = NewEntryBoltRepository(store, enum.Login).GetList(ctx)
+	assert.ErrorIs(t, err, sharedErr.ErrVaultTampered)
+}
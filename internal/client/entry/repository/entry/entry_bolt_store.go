@@ -0,0 +1,637 @@
+package entry
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry/internal/seal"
+	sharedErr "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/atomicfile"
+)
+
+const sealVersion = 1
+
+// errStoreNotLegacy хранилище уже запечатано, переписывать его не нужно
+var errStoreNotLegacy = errors.New("store is not legacy")
+
+var (
+	metaBucketName = []byte("meta")
+	metaVersionKey = []byte("version")
+	metaMacKey     = []byte("mac")
+)
+
+// EntryBoltStore файл bbolt, общий для репозиториев всех типов записей.
+// bbolt блокирует файл (flock) на все время, пока база открыта, и не допускает повторного открытия в одном процессе,
+// поэтому база открывается на одну операцию, а через Lock - одна на все репозитории.
+//
+// Хранилище запечатано ключом из keyProvider: значения зашифрованы, id и типы записей заменены HMAC,
+// а MAC всего хранилища в bucket meta проверяется в каждой транзакции. Без ключа (хранилище заблокировано) записи не читаются.
+// Хранилище, созданное без ключа (до rekey хранилищ без KeyCheck), запечатывается через Seal.
+// После первого запечатывания это отмечается в хранилище ключей: запечатанное хранилище без MAC - подмена файла, а не старый формат
+type EntryBoltStore struct {
+	fileName    string
+	keyProvider StoreKeyProviderInterface
+	// lockTimeout - сколько ждать, пока другой процесс освободит файл
+	lockTimeout time.Duration
+
+	mu       sync.Mutex
+	heldDB   *bbolt.DB
+	heldRefs int
+}
+
+func NewEntryBoltStore(fileName string, keyProvider StoreKeyProviderInterface, lockTimeout time.Duration) *EntryBoltStore {
+	return &EntryBoltStore{fileName: fileName, keyProvider: keyProvider, lockTimeout: lockTimeout}
+}
+
+// Lock открывает базу на запись до вызова unlock. Вложенные вызовы используют уже открытую базу
+func (s *EntryBoltStore) Lock() (func() error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.heldDB == nil {
+		db, err := s.open(false)
+		if err != nil {
+			return nil, err
+		}
+		s.heldDB = db
+	}
+	s.heldRefs++
+
+	released := false
+	return func() error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if released {
+			return nil
+		}
+		released = true
+		s.heldRefs--
+		if s.heldRefs > 0 {
+			return nil
+		}
+		db := s.heldDB
+		s.heldDB = nil
+		return db.Close()
+	}, nil
+}
+
+// update транзакция на запись. После изменений пересчитывается MAC хранилища
+func (s *EntryBoltStore) update(callback func(tx *entryStoreTx) error) error {
+	sealer, sealed, err := s.sealState()
+	if err != nil {
+		return err
+	}
+	db, release, err := s.acquire(false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		storeTx, err := beginStoreTx(tx, sealer, sealed)
+		if err != nil {
+			return err
+		}
+		err = callback(storeTx)
+		if err != nil {
+			return err
+		}
+		return storeTx.commitSeal()
+	})
+	if err != nil {
+		return mapStoreError(err)
+	}
+	if sealer != nil && !sealed {
+		return s.markSealed()
+	}
+	return nil
+}
+
+// view транзакция на чтение. Если базы еще нет и хранилище не запечатывалось, callback не вызывается
+func (s *EntryBoltStore) view(callback func(tx *entryStoreTx) error) error {
+	sealer, sealed, err := s.sealState()
+	if err != nil {
+		return err
+	}
+	db, release, err := s.acquire(true)
+	if err != nil {
+		return err
+	}
+	if db == nil {
+		// файл запечатанного хранилища удален в обход клиента
+		if sealed {
+			return sharedErr.ErrVaultTampered
+		}
+		return nil
+	}
+	defer release()
+
+	hasMac := false
+	err = db.View(func(tx *bbolt.Tx) error {
+		storeTx, err := beginStoreTx(tx, sealer, sealed)
+		if err != nil {
+			return err
+		}
+		hasMac = storeTx.hasMac
+		return callback(storeTx)
+	})
+	if err != nil {
+		return mapStoreError(err)
+	}
+	// хранилище запечатано версией клиента, которая еще не отмечала это
+	if hasMac && !sealed {
+		return s.markSealed()
+	}
+	return nil
+}
+
+// Seal запечатывает хранилище, созданное без ключа. Записи переносятся в новый файл, который заменяет старый:
+// в освобожденных страницах старого файла остались бы открытые данные. Ключ нужен, только если хранилище еще не запечатано
+func (s *EntryBoltStore) Seal() error {
+	legacy, err := s.isLegacy()
+	if err != nil || !legacy {
+		return err
+	}
+	sealer, sealed, err := s.sealState()
+	if err != nil {
+		return err
+	}
+	// файл без MAC у запечатанного хранилища - подмена, ее покажет следующая транзакция
+	if sealer == nil || sealed {
+		return nil
+	}
+
+	err = s.rewriteFile(func(db *bbolt.DB, dst *bbolt.Tx) error {
+		return db.View(func(src *bbolt.Tx) error {
+			// хранилище мог запечатать другой процесс, пока ждали блокировку
+			if !isLegacyStore(src) {
+				return errStoreNotLegacy
+			}
+			err := copySealedBuckets(src, dst, sealer)
+			if err != nil {
+				return err
+			}
+			return (&entryStoreTx{tx: dst, sealer: sealer}).commitSeal()
+		})
+	})
+	if err != nil {
+		if errors.Is(err, errStoreNotLegacy) {
+			return nil
+		}
+		return err
+	}
+	return s.markSealed()
+}
+
+// Replace записи пишутся в новый файл, запечатанный текущим ключом, который заменяет прежний файл целиком:
+// при ошибке остается прежнее хранилище. Ключ прежнего хранилища не нужен, он может быть уже недоступен
+func (s *EntryBoltStore) Replace(entries map[enum.EntryType][]entity.Entry) error {
+	sealer, sealed, err := s.sealState()
+	if err != nil {
+		return err
+	}
+	err = s.rewriteFile(func(_ *bbolt.DB, tx *bbolt.Tx) error {
+		storeTx := &entryStoreTx{tx: tx, sealer: sealer}
+		for entryType, typeEntries := range entries {
+			bucket, err := storeTx.bucket(string(entryType), true)
+			if err != nil {
+				return err
+			}
+			for _, entry := range typeEntries {
+				err = bucket.put(entry)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return storeTx.commitSeal()
+	})
+	if err != nil {
+		return err
+	}
+	if sealer != nil && !sealed {
+		return s.markSealed()
+	}
+	return nil
+}
+
+// isLegacy хранилище создано без ключа. Ключ для проверки не нужен
+func (s *EntryBoltStore) isLegacy() (bool, error) {
+	db, release, err := s.acquire(true)
+	if err != nil || db == nil {
+		return false, err
+	}
+	defer release()
+
+	legacy := false
+	err = db.View(func(tx *bbolt.Tx) error {
+		legacy = isLegacyStore(tx)
+		return nil
+	})
+	return legacy, mapStoreError(err)
+}
+
+// rewriteFile пишет новый файл хранилища в транзакции write и заменяет им прежний. write получает прежнюю базу.
+// Прежний файл заблокирован до замены: другие процессы после ожидания блокировки откроют уже новый файл (см. open).
+// Если база открыта через Lock, блокировка переходит на новый файл до ее снятия
+func (s *EntryBoltStore) rewriteFile(write func(db *bbolt.DB, tx *bbolt.Tx) error) error {
+	s.mu.Lock()
+	held := s.heldDB
+	s.mu.Unlock()
+	db := held
+	if db == nil {
+		var err error
+		db, err = s.open(false)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+	}
+
+	newFileName, err := createTempStore(s.fileName)
+	if err != nil {
+		return mapStoreError(err)
+	}
+	newDB, err := bbolt.Open(newFileName, 0600, nil)
+	if err != nil {
+		_ = os.Remove(newFileName)
+		return mapStoreError(err)
+	}
+	err = newDB.Update(func(tx *bbolt.Tx) error {
+		return write(db, tx)
+	})
+	if err != nil {
+		_ = newDB.Close()
+		_ = os.Remove(newFileName)
+		return mapStoreError(err)
+	}
+
+	err = atomicfile.Replace(newFileName, s.fileName)
+	if err != nil {
+		_ = newDB.Close()
+		return mapStoreError(err)
+	}
+	if held == nil {
+		return mapStoreError(newDB.Close())
+	}
+	s.mu.Lock()
+	s.heldDB = newDB
+	s.mu.Unlock()
+	_ = held.Close()
+	return nil
+}
+
+// sealState sealer хранилища и отметка, что оно уже запечатывалось
+func (s *EntryBoltStore) sealState() (*seal.Sealer, bool, error) {
+	sealer, err := s.sealer()
+	if err != nil {
+		return nil, false, err
+	}
+	sealed, err := s.keyProvider.StoreSealed()
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", sharedErr.ErrInternalError, err)
+	}
+	return sealer, sealed, nil
+}
+
+func (s *EntryBoltStore) markSealed() error {
+	err := s.keyProvider.MarkStoreSealed()
+	if err != nil {
+		return fmt.Errorf("%w: %v", sharedErr.ErrInternalError, err)
+	}
+	return nil
+}
+
+func (s *EntryBoltStore) sealer() (*seal.Sealer, error) {
+	storeKey, err := s.keyProvider.StoreKey()
+	if err != nil {
+		return nil, err
+	}
+	if storeKey == nil {
+		return nil, nil
+	}
+	sealer, err := seal.NewSealer(storeKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", sharedErr.ErrInternalError, err)
+	}
+	return sealer, nil
+}
+
+// acquire база для одной операции. Если база открыта через Lock, используется она.
+// Для чтения база открывается только на чтение (разделяемая блокировка); если файла нет, возвращается nil
+func (s *EntryBoltStore) acquire(readOnly bool) (*bbolt.DB, func(), error) {
+	s.mu.Lock()
+	held := s.heldDB
+	s.mu.Unlock()
+	if held != nil {
+		return held, func() {}, nil
+	}
+
+	if readOnly && !fileExists(s.fileName) {
+		return nil, func() {}, nil
+	}
+	db, err := s.open(readOnly)
+	if err != nil {
+		return nil, nil, err
+	}
+	return db, func() {
+		_ = db.Close()
+	}, nil
+}
+
+func (s *EntryBoltStore) open(readOnly bool) (*bbolt.DB, error) {
+	err := os.MkdirAll(filepath.Dir(s.fileName), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", sharedErr.ErrInternalError, err)
+	}
+	for {
+		// файл, открытый до ожидания блокировки, мог быть заменен через Seal
+		before, statErr := os.Stat(s.fileName)
+		db, err := bbolt.Open(s.fileName, 0600, &bbolt.Options{Timeout: s.lockTimeout, ReadOnly: readOnly})
+		if err != nil {
+			if errors.Is(err, bbolt.ErrTimeout) {
+				return nil, sharedErr.ErrVaultBusy
+			}
+			return nil, fmt.Errorf("%w: %v", sharedErr.ErrInternalError, err)
+		}
+		after, err := os.Stat(s.fileName)
+		if err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("%w: %v", sharedErr.ErrInternalError, err)
+		}
+		if statErr == nil && !os.SameFile(before, after) {
+			_ = db.Close()
+			continue
+		}
+
+		if !readOnly {
+			// файл мог быть создан предыдущими версиями с более широкими правами
+			err = os.Chmod(s.fileName, 0600)
+			if err != nil {
+				_ = db.Close()
+				return nil, fmt.Errorf("%w: %v", sharedErr.ErrInternalError, err)
+			}
+		}
+		return db, nil
+	}
+}
+
+// entryStoreTx транзакция bbolt. sealer == nil - хранилище без ключа, записи хранятся открыто
+type entryStoreTx struct {
+	tx     *bbolt.Tx
+	sealer *seal.Sealer
+	// hasMac - MAC хранилища был в файле и проверен
+	hasMac bool
+}
+
+// beginStoreTx проверяет MAC запечатанного хранилища. Хранилище, созданное без ключа, до Seal читается и пишется открыто.
+// sealed - хранилище уже запечатывалось: файл без MAC подменен, в том числе на пустой
+func beginStoreTx(tx *bbolt.Tx, sealer *seal.Sealer, sealed bool) (*entryStoreTx, error) {
+	meta := tx.Bucket(metaBucketName)
+	if meta == nil {
+		if sealed {
+			return nil, sharedErr.ErrVaultTampered
+		}
+		if isLegacyStore(tx) {
+			return &entryStoreTx{tx: tx}, nil
+		}
+		return &entryStoreTx{tx: tx, sealer: sealer}, nil
+	}
+
+	if sealer == nil {
+		return nil, fmt.Errorf("%w: store is sealed, but vault has no store key", sharedErr.ErrInternalError)
+	}
+	if !hmac.Equal(computeStoreMac(tx, sealer), meta.Get(metaMacKey)) {
+		return nil, sharedErr.ErrVaultTampered
+	}
+	return &entryStoreTx{tx: tx, sealer: sealer, hasMac: true}, nil
+}
+
+func (t *entryStoreTx) bucketName(entryType string) []byte {
+	if t.sealer == nil {
+		return []byte(entryType)
+	}
+	return t.sealer.BucketName(entryType)
+}
+
+// bucket записи одного типа. Если bucket нет и create == false, возвращается nil
+func (t *entryStoreTx) bucket(entryType string, create bool) (*entryBucket, error) {
+	name := t.bucketName(entryType)
+	var bucket *bbolt.Bucket
+	if create {
+		var err error
+		bucket, err = t.tx.CreateBucketIfNotExists(name)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		bucket = t.tx.Bucket(name)
+		if bucket == nil {
+			return nil, nil
+		}
+	}
+	return &entryBucket{bucket: bucket, name: name, sealer: t.sealer}, nil
+}
+
+func (t *entryStoreTx) deleteBucket(entryType string) error {
+	err := t.tx.DeleteBucket(t.bucketName(entryType))
+	if err != nil && !errors.Is(err, bbolt.ErrBucketNotFound) {
+		return err
+	}
+	return nil
+}
+
+// commitSeal записывает MAC хранилища после изменений
+func (t *entryStoreTx) commitSeal() error {
+	if t.sealer == nil {
+		return nil
+	}
+	mac := computeStoreMac(t.tx, t.sealer)
+	meta, err := t.tx.CreateBucketIfNotExists(metaBucketName)
+	if err != nil {
+		return err
+	}
+	err = meta.Put(metaVersionKey, []byte{sealVersion})
+	if err != nil {
+		return err
+	}
+	return meta.Put(metaMacKey, mac)
+}
+
+type entryBucket struct {
+	bucket *bbolt.Bucket
+	name   []byte
+	sealer *seal.Sealer
+}
+
+func (b *entryBucket) get(id string) (*entity.Entry, error) {
+	value, err := b.getValue(id)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return decodeEntry(value)
+}
+
+func (b *entryBucket) put(entry entity.Entry) error {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return b.putValue(entry.Id, value)
+}
+
+func (b *entryBucket) forEach(callback func(entry entity.Entry) error) error {
+	return b.forEachValue(func(value []byte) error {
+		entry, err := decodeEntry(value)
+		if err != nil {
+			return err
+		}
+		return callback(*entry)
+	})
+}
+
+// forEachValue обходит расшифрованные значения бакета
+func (b *entryBucket) forEachValue(callback func(value []byte) error) error {
+	return b.bucket.ForEach(func(key, value []byte) error {
+		value, err := b.open(key, value)
+		if err != nil {
+			return err
+		}
+		return callback(value)
+	})
+}
+
+// getValue расшифрованное значение по id. Если значения нет, возвращается nil
+func (b *entryBucket) getValue(id string) ([]byte, error) {
+	key := b.recordKey(id)
+	value := b.bucket.Get(key)
+	if value == nil {
+		return nil, nil
+	}
+	return b.open(key, value)
+}
+
+func (b *entryBucket) putValue(id string, value []byte) error {
+	key := b.recordKey(id)
+	if b.sealer != nil {
+		var err error
+		value, err = b.sealer.Seal(b.name, key, value)
+		if err != nil {
+			return err
+		}
+	}
+	return b.bucket.Put(key, value)
+}
+
+func (b *entryBucket) recordKey(id string) []byte {
+	if b.sealer == nil {
+		return []byte(id)
+	}
+	return b.sealer.RecordKey(id)
+}
+
+func (b *entryBucket) open(key []byte, value []byte) ([]byte, error) {
+	if b.sealer == nil {
+		return value, nil
+	}
+	value, err := b.sealer.Open(b.name, key, value)
+	if err != nil {
+		return nil, sharedErr.ErrVaultTampered
+	}
+	return value, nil
+}
+
+func decodeEntry(value []byte) (*entity.Entry, error) {
+	entry := &entity.Entry{}
+	err := json.Unmarshal(value, entry)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// isLegacyStore записи есть, а MAC хранилища нет: хранилище создано без ключа
+func isLegacyStore(tx *bbolt.Tx) bool {
+	if tx.Bucket(metaBucketName) != nil {
+		return false
+	}
+	found := false
+	_ = tx.ForEach(func(name []byte, _ *bbolt.Bucket) error {
+		found = true
+		return nil
+	})
+	return found
+}
+
+// computeStoreMac MAC всех записей всех типов: удаление, подмена или перенос записи меняют его.
+// Считается проходом по всему файлу, поэтому каждая транзакция, включая чтение одной записи, стоит O(n) от числа записей
+// и ревизий. Для сотен и тысяч записей это миллисекунды; хранилищам крупнее понадобится дерево MAC по bucket
+func computeStoreMac(tx *bbolt.Tx, sealer *seal.Sealer) []byte {
+	mac := sealer.NewMac()
+	_ = tx.ForEach(func(name []byte, bucket *bbolt.Bucket) error {
+		if string(name) == string(metaBucketName) {
+			return nil
+		}
+		return bucket.ForEach(func(key, value []byte) error {
+			seal.WriteRecord(mac, name, key, value)
+			return nil
+		})
+	})
+	return mac.Sum(nil)
+}
+
+// copySealedBuckets переносит открытые записи в запечатанное хранилище: bucket по типу и ключ по id заменяются HMAC
+func copySealedBuckets(src *bbolt.Tx, dst *bbolt.Tx, sealer *seal.Sealer) error {
+	return src.ForEach(func(name []byte, srcBucket *bbolt.Bucket) error {
+		bucketName := sealer.BucketName(string(name))
+		dstBucket, err := dst.CreateBucketIfNotExists(bucketName)
+		if err != nil {
+			return err
+		}
+		return srcBucket.ForEach(func(key, value []byte) error {
+			sealedKey := sealer.RecordKey(string(key))
+			sealedValue, err := sealer.Seal(bucketName, sealedKey, value)
+			if err != nil {
+				return err
+			}
+			return dstBucket.Put(sealedKey, sealedValue)
+		})
+	})
+}
+
+// createTempStore пустой файл со случайным именем рядом с хранилищем. bbolt размечает пустой файл при открытии
+func createTempStore(fileName string) (string, error) {
+	file, err := atomicfile.CreateTemp(fileName)
+	if err != nil {
+		return "", err
+	}
+	err = file.Close()
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// mapStoreError ошибки, понятные вызывающему коду, возвращаются как есть, остальные - как внутренние
+func mapStoreError(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, known := range []error{sharedErr.ErrEntryNotFound, sharedErr.ErrVaultTampered, sharedErr.ErrVaultBusy, sharedErr.ErrInternalError} {
+		if errors.Is(err, known) {
+			return err
+		}
+	}
+	return fmt.Errorf("%w: %v", sharedErr.ErrInternalError, err)
+}
@@ -0,0 +1,77 @@
+package entry
+
+import (
+	"context"
+	"time"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+)
+
+const historyFileSuffix = ".history"
+
+// EntryFileHistoryRepository ревизии записей одного типа в файле JSON-lines <файл записей>.history.
+// Ревизии хранятся в порядке от новых к старым, у ревизий одной записи общий id
+type EntryFileHistoryRepository struct {
+	revisions *EntrySingleFileRepository
+}
+
+func NewEntryFileHistoryRepository(entryFileName string, lockTimeout time.Duration) *EntryFileHistoryRepository {
+	return &EntryFileHistoryRepository{revisions: NewEntrySingleFileRepository(entryFileName+historyFileSuffix, lockTimeout)}
+}
+
+func (e *EntryFileHistoryRepository) Push(ctx context.Context, revision entity.Entry, limit int) (err error) {
+	unlock, err := e.revisions.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		unlockErr := unlock()
+		if err == nil {
+			err = unlockErr
+		}
+	}()
+
+	revisions, err := e.revisions.GetList(ctx)
+	if err != nil {
+		return err
+	}
+
+	kept := 1
+	newRevisions := []entity.Entry{revision}
+	for _, fileRevision := range revisions {
+		if fileRevision.Id == revision.Id {
+			if kept >= limit {
+				continue
+			}
+			kept++
+		}
+		newRevisions = append(newRevisions, fileRevision)
+	}
+	return e.revisions.Rewrite(ctx, newRevisions)
+}
+
+func (e *EntryFileHistoryRepository) GetList(ctx context.Context, id string) ([]entity.Entry, error) {
+	revisions, err := e.revisions.GetList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entryRevisions := make([]entity.Entry, 0)
+	for _, revision := range revisions {
+		if revision.Id == id {
+			entryRevisions = append(entryRevisions, revision)
+		}
+	}
+	return entryRevisions, nil
+}
+
+func (e *EntryFileHistoryRepository) GetAll(ctx context.Context) ([]entity.Entry, error) {
+	return e.revisions.GetList(ctx)
+}
+
+func (e *EntryFileHistoryRepository) Rewrite(ctx context.Context, revisions []entity.Entry) error {
+	return e.revisions.Rewrite(ctx, revisions)
+}
+
+func (e *EntryFileHistoryRepository) Clear(ctx context.Context) error {
+	return e.revisions.Rewrite(ctx, []entity.Entry{})
+}
@@ -0,0 +1,98 @@
+package entry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	sharedErr "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+)
+
+// migratedFileSuffix файлы, которые предыдущие версии оставляли после переноса
+const migratedFileSuffix = ".migrated"
+
+// MigrateSingleFile переносит записи из файла JSON-lines в bolt хранилище.
+// Файл удаляется, только когда все записи прочитаны из bolt хранилища такими же, как в файле. Возвращает число перенесенных записей
+func MigrateSingleFile(ctx context.Context, source *EntrySingleFileRepository, target *EntryBoltRepository) (int, error) {
+	// <файл>.migrated остается от предыдущих версий, которые переименовывали файл после переноса записей
+	err := removeFile(source.fileName + migratedFileSuffix)
+	if err != nil {
+		return 0, err
+	}
+	if !fileExists(source.fileName) {
+		return 0, nil
+	}
+
+	unlock, err := source.Lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	// файл мог перенести другой процесс, пока ждали блокировку
+	if !fileExists(source.fileName) {
+		return 0, nil
+	}
+
+	entries, err := source.GetList(ctx)
+	if err != nil {
+		return 0, err
+	}
+	err = target.Import(ctx, entries)
+	if err != nil {
+		return 0, err
+	}
+	err = verifyImport(ctx, target, entries)
+	if err != nil {
+		return 0, err
+	}
+
+	err = removeFile(source.fileName)
+	if err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// verifyImport сравнивает записи в bolt хранилище с перенесенными. Из записей с одинаковым id сохраняется последняя
+func verifyImport(ctx context.Context, target *EntryBoltRepository, entries []entity.Entry) error {
+	imported := make(map[string]entity.Entry, len(entries))
+	for _, entry := range entries {
+		imported[entry.Id] = entry
+	}
+	for id, entry := range imported {
+		stored, err := target.GetById(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%w: verify migrated entry: %w", sharedErr.ErrInternalError, err)
+		}
+		want, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		got, err := json.Marshal(stored)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(want, got) {
+			return fmt.Errorf("%w: migrated entry %s differs from source file", sharedErr.ErrInternalError, id)
+		}
+	}
+	return nil
+}
+
+func removeFile(fileName string) error {
+	err := os.Remove(fileName)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %v", sharedErr.ErrInternalError, err)
+	}
+	return nil
+}
+
+func fileExists(fileName string) bool {
+	_, err := os.Stat(fileName)
+	return !errors.Is(err, os.ErrNotExist)
+}
@@ -0,0 +1,52 @@
+package entry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+)
+
+func TestMigrateSingleFile(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "entries", "logins")
+	source := NewEntrySingleFileRepository(fileName, time.Second)
+	target := NewEntryBoltRepository(NewEntryBoltStore(filepath.Join(dir, "entries", "vault.db"), newTestStoreKeyProvider(t, testStoreKey), time.Second), enum.Login)
+
+	// файла предыдущей версии нет - переносить нечего
+	migrated, err := MigrateSingleFile(ctx, source, target)
+	require.NoError(t, err)
+	assert.Equal(t, 0, migrated)
+	assert.False(t, fileExists(fileName))
+
+	entries := []entity.Entry{
+		{Id: "1", EntryType: enum.Login, UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC), Data: []byte("data1")},
+		{Id: "2", EntryType: enum.Login, UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC), Data: []byte("data2")},
+	}
+	require.NoError(t, source.Rewrite(ctx, entries))
+	// файл, оставленный переносом в предыдущей версии
+	require.NoError(t, os.WriteFile(fileName+migratedFileSuffix, []byte("old entries"), 0600))
+
+	migrated, err = MigrateSingleFile(ctx, source, target)
+	require.NoError(t, err)
+	assert.Equal(t, 2, migrated)
+	assert.False(t, fileExists(fileName))
+	assert.False(t, fileExists(fileName+migratedFileSuffix))
+
+	got, err := target.GetList(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, entries, got)
+
+	// повторный запуск не переносит записи еще раз
+	migrated, err = MigrateSingleFile(ctx, source, target)
+	require.NoError(t, err)
+	assert.Equal(t, 0, migrated)
+}
@@ -0,0 +1,61 @@
+package entry
+
+import (
+	"errors"
+	"os"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry/internal/single_file/writer"
+)
+
+// EntryFileStore файлы JSON-lines записей всех типов и их ревизий
+type EntryFileStore struct {
+	entryFileNames map[enum.EntryType]string
+}
+
+func NewEntryFileStore(entryFileNames map[enum.EntryType]string) *EntryFileStore {
+	return &EntryFileStore{entryFileNames: entryFileNames}
+}
+
+// Replace файлы всех типов сначала записываются во временные, затем заменяются по одному
+func (s *EntryFileStore) Replace(entries map[enum.EntryType][]entity.Entry) error {
+	fileWriters := make([]*writer.EntryFileWriter, 0, len(s.entryFileNames))
+	defer func() {
+		for _, fileWriter := range fileWriters {
+			_ = fileWriter.Close()
+		}
+	}()
+	for entryType, entryFileName := range s.entryFileNames {
+		fileWriter, err := writer.NewEntryFileAtomicWriter(entryFileName)
+		if err != nil {
+			return err
+		}
+		fileWriters = append(fileWriters, fileWriter)
+		for _, entry := range entries[entryType] {
+			err = fileWriter.WriteEntry(entry)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, fileWriter := range fileWriters {
+		err := fileWriter.Commit()
+		if err != nil {
+			return err
+		}
+	}
+	for _, entryFileName := range s.entryFileNames {
+		err := os.Remove(entryFileName + historyFileSuffix)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Seal файлы JSON-lines не запечатываются
+func (s *EntryFileStore) Seal() error {
+	return nil
+}
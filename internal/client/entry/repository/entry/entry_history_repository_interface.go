@@ -0,0 +1,24 @@
+package entry
+
+import (
+	"context"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+)
+
+// EntryHistoryRepositoryInterface предыдущие ревизии записей одного типа. Ревизии хранятся зашифрованными, как сами записи,
+// и не синхронизируются с сервером
+//
+//go:generate mockgen -source=entry_history_repository_interface.go -destination=mock_entry_history_repository/mock_entry_history_repository.go -package=mock_entry_history_repository
+type EntryHistoryRepositoryInterface interface {
+	// Push сохраняет ревизию записи. Остаются limit последних ревизий записи
+	Push(ctx context.Context, revision entity.Entry, limit int) error
+	// GetList ревизии записи, от новых к старым
+	GetList(ctx context.Context, id string) ([]entity.Entry, error)
+	// GetAll ревизии всех записей, ревизии одной записи от новых к старым
+	GetAll(ctx context.Context) ([]entity.Entry, error)
+	// Rewrite заменяет ревизии всех записей. Порядок ревизий одной записи сохраняется
+	Rewrite(ctx context.Context, revisions []entity.Entry) error
+	// Clear удаляет ревизии всех записей
+	Clear(ctx context.Context) error
+}
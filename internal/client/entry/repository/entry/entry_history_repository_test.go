@@ -0,0 +1,74 @@
+package entry
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+)
+
+func TestEntryHistoryRepository(t *testing.T) {
+	ctx := context.Background()
+	repositories := map[string]func(t *testing.T) EntryHistoryRepositoryInterface{
+		"bolt": func(t *testing.T) EntryHistoryRepositoryInterface {
+			store := NewEntryBoltStore(filepath.Join(t.TempDir(), "vault.db"), newTestStoreKeyProvider(t, testStoreKey), time.Second)
+			return NewEntryBoltHistoryRepository(store, enum.Login)
+		},
+		"file": func(t *testing.T) EntryHistoryRepositoryInterface {
+			return NewEntryFileHistoryRepository(filepath.Join(t.TempDir(), "logins"), time.Second)
+		},
+	}
+
+	revision := func(id string, day int) entity.Entry {
+		return entity.Entry{Id: id, EntryType: enum.Login, UpdatedAt: time.Date(2024, time.March, day, 12, 0, 0, 0, time.UTC), Data: []byte("data")}
+	}
+
+	for name, newRepository := range repositories {
+		t.Run(name, func(t *testing.T) {
+			repository := newRepository(t)
+
+			got, err := repository.GetList(ctx, "1")
+			require.NoError(t, err)
+			assert.Empty(t, got)
+
+			for day := 1; day <= 4; day++ {
+				require.NoError(t, repository.Push(ctx, revision("1", day), 3))
+			}
+			require.NoError(t, repository.Push(ctx, revision("2", 1), 3))
+
+			// от новых к старым, самая старая ревизия вытеснена
+			got, err = repository.GetList(ctx, "1")
+			require.NoError(t, err)
+			assert.Equal(t, []entity.Entry{revision("1", 4), revision("1", 3), revision("1", 2)}, got)
+
+			got, err = repository.GetList(ctx, "2")
+			require.NoError(t, err)
+			assert.Equal(t, []entity.Entry{revision("2", 1)}, got)
+
+			// rekey перезаписывает все ревизии разом, порядок ревизий записи сохраняется
+			all, err := repository.GetAll(ctx)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []entity.Entry{revision("1", 4), revision("1", 3), revision("1", 2), revision("2", 1)}, all)
+
+			rewritten := []entity.Entry{revision("1", 3), revision("1", 2)}
+			require.NoError(t, repository.Rewrite(ctx, rewritten))
+			got, err = repository.GetList(ctx, "1")
+			require.NoError(t, err)
+			assert.Equal(t, rewritten, got)
+			got, err = repository.GetList(ctx, "2")
+			require.NoError(t, err)
+			assert.Empty(t, got)
+
+			require.NoError(t, repository.Clear(ctx))
+			got, err = repository.GetList(ctx, "1")
+			require.NoError(t, err)
+			assert.Empty(t, got)
+		})
+	}
+}
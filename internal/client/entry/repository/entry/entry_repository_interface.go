@@ -13,4 +13,7 @@ type EntryRepositoryInterface interface {
 	GetById(ctx context.Context, id string) (entity.Entry, error)
 	GetList(ctx context.Context) ([]entity.Entry, error)
 	Rewrite(ctx context.Context, entries []entity.Entry) error
+	// Lock эксклюзивная блокировка хранилища на время нескольких операций (чтение - обращение к серверу - перезапись).
+	// Операции репозитория до вызова unlock выполняются под этой блокировкой
+	Lock(ctx context.Context) (unlock func() error, err error)
 }
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
+	"time"
 
 	sharedErr "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/filelock"
 
 	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
 	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry/internal/single_file/reader"
@@ -15,13 +18,71 @@ import (
 
 type EntrySingleFileRepository struct {
 	fileName string
+	// lockTimeout - сколько ждать, пока другой процесс освободит файл
+	lockTimeout time.Duration
+
+	mu sync.Mutex
+	// heldLock - блокировка, взятая через Lock
+	heldLock *filelock.FileLock
+}
+
+func NewEntrySingleFileRepository(fileName string, lockTimeout time.Duration) *EntrySingleFileRepository {
+	return &EntrySingleFileRepository{fileName: fileName, lockTimeout: lockTimeout}
 }
 
-func NewEntrySingleFileRepository(fileName string) *EntrySingleFileRepository {
-	return &EntrySingleFileRepository{fileName: fileName}
+func (e *EntrySingleFileRepository) Lock(ctx context.Context) (func() error, error) {
+	fileLock, err := e.acquire(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	e.heldLock = fileLock
+	e.mu.Unlock()
+
+	return func() error {
+		e.mu.Lock()
+		e.heldLock = nil
+		e.mu.Unlock()
+		return fileLock.Release()
+	}, nil
+}
+
+// lock блокировка одной операции. Если процесс уже держит блокировку через Lock, повторно файл не блокируется
+func (e *EntrySingleFileRepository) lock(ctx context.Context, exclusive bool) (func(), error) {
+	e.mu.Lock()
+	held := e.heldLock != nil
+	e.mu.Unlock()
+	if held {
+		return func() {}, nil
+	}
+
+	fileLock, err := e.acquire(ctx, exclusive)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		_ = fileLock.Release()
+	}, nil
+}
+
+func (e *EntrySingleFileRepository) acquire(ctx context.Context, exclusive bool) (*filelock.FileLock, error) {
+	fileLock, err := filelock.Acquire(ctx, e.fileName, exclusive, e.lockTimeout)
+	if err != nil {
+		if errors.Is(err, sharedErr.ErrVaultBusy) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", sharedErr.ErrInternalError, err)
+	}
+	return fileLock, nil
 }
 
 func (e *EntrySingleFileRepository) Add(ctx context.Context, entry entity.Entry) error {
+	unlock, err := e.lock(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	fileWriter, err := writer.NewEntryFileWriter(e.fileName)
 	if err != nil {
 		return fmt.Errorf("%w: %v", sharedErr.ErrInternalError, err)
@@ -36,6 +97,12 @@ func (e *EntrySingleFileRepository) Add(ctx context.Context, entry entity.Entry)
 }
 
 func (e *EntrySingleFileRepository) Edit(ctx context.Context, entry entity.Entry) error {
+	unlock, err := e.lock(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	return e.rewriteFile(func(fileEntries map[string]*entity.Entry) error {
 		if fileEntry, ok := fileEntries[entry.Id]; ok {
 			*fileEntry = entry
@@ -48,6 +115,12 @@ func (e *EntrySingleFileRepository) Edit(ctx context.Context, entry entity.Entry
 }
 
 func (e *EntrySingleFileRepository) GetById(ctx context.Context, id string) (entity.Entry, error) {
+	unlock, err := e.lock(ctx, false)
+	if err != nil {
+		return entity.Entry{}, err
+	}
+	defer unlock()
+
 	entry, err := e.findOneByCondition(func(entry entity.Entry) bool {
 		return entry.Id == id
 	})
@@ -61,6 +134,12 @@ func (e *EntrySingleFileRepository) GetById(ctx context.Context, id string) (ent
 }
 
 func (e *EntrySingleFileRepository) GetList(ctx context.Context) ([]entity.Entry, error) {
+	unlock, err := e.lock(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	fileEntries := make([]entity.Entry, 0)
 	fileReader, err := reader.NewEntryFileReader(e.fileName)
 	if err != nil {
@@ -107,7 +186,13 @@ func (e *EntrySingleFileRepository) findOneByCondition(condition func(entry enti
 }
 
 func (e *EntrySingleFileRepository) Rewrite(ctx context.Context, entries []entity.Entry) error {
-	fileWriter, err := writer.NewEntryFileEmptyWriter(e.fileName)
+	unlock, err := e.lock(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	fileWriter, err := writer.NewEntryFileAtomicWriter(e.fileName)
 	if err != nil {
 		return err
 	}
@@ -120,28 +205,13 @@ func (e *EntrySingleFileRepository) Rewrite(ctx context.Context, entries []entit
 		}
 	}
 
-	return nil
+	return fileWriter.Commit()
 }
 
 func (e *EntrySingleFileRepository) rewriteFile(callback func(fileEntries map[string]*entity.Entry) error) error {
-	fileReader, err := reader.NewEntryFileReader(e.fileName)
+	fileEntries, err := e.readEntriesMap()
 	if err != nil {
-		return nil
-	}
-	fileEntries := make(map[string]*entity.Entry)
-
-	defer fileReader.Close()
-
-	//Считываем все данные с файла
-	for {
-		entry, err := fileReader.ReadEntry()
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				break
-			}
-			return err
-		}
-		fileEntries[entry.Id] = entry
+		return fmt.Errorf("%w: %v", sharedErr.ErrInternalError, err)
 	}
 
 	err = callback(fileEntries)
@@ -149,8 +219,8 @@ func (e *EntrySingleFileRepository) rewriteFile(callback func(fileEntries map[st
 		return err
 	}
 
-	//Перезаписываем файл заново
-	fileWriter, err := writer.NewEntryFileEmptyWriter(e.fileName)
+	//Перезаписываем файл заново: старый файл заменяется только после полной записи нового
+	fileWriter, err := writer.NewEntryFileAtomicWriter(e.fileName)
 	if err != nil {
 		return err
 	}
@@ -163,5 +233,27 @@ func (e *EntrySingleFileRepository) rewriteFile(callback func(fileEntries map[st
 		}
 	}
 
-	return nil
+	return fileWriter.Commit()
+}
+
+func (e *EntrySingleFileRepository) readEntriesMap() (map[string]*entity.Entry, error) {
+	fileReader, err := reader.NewEntryFileReader(e.fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer fileReader.Close()
+
+	fileEntries := make(map[string]*entity.Entry)
+	//Считываем все данные с файла
+	for {
+		entry, err := fileReader.ReadEntry()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		fileEntries[entry.Id] = entry
+	}
+	return fileEntries, nil
 }
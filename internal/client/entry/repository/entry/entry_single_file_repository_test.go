@@ -0,0 +1,60 @@
+package entry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	sharedErr "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+)
+
+func TestEntrySingleFileRepository_Rewrite(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "entries", "logins")
+	repository := NewEntrySingleFileRepository(fileName, time.Second)
+
+	entries := []entity.Entry{
+		{Id: "1", EntryType: enum.Login, UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC), Data: []byte("data1")},
+		{Id: "2", EntryType: enum.Login, UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC), Data: []byte("data2")},
+	}
+	require.NoError(t, repository.Rewrite(ctx, entries))
+
+	got, err := repository.GetList(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, entries, got)
+
+	edited := entries[1]
+	edited.Data = []byte("edited")
+	require.NoError(t, repository.Edit(ctx, edited))
+
+	got, err = repository.GetList(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []entity.Entry{entries[0], edited}, got)
+
+	err = repository.Edit(ctx, entity.Entry{Id: "3", EntryType: enum.Login})
+	assert.ErrorIs(t, err, sharedErr.ErrEntryNotFound)
+
+	// временные файлы не остаются рядом с хранилищем, кроме файла блокировки
+	files, err := os.ReadDir(filepath.Dir(fileName))
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+}
+
+func TestEntrySingleFileRepository_EditReaderError(t *testing.T) {
+	dir := t.TempDir()
+	// директория вместо файла: читатель не может открыть хранилище
+	fileName := filepath.Join(dir, "logins")
+	require.NoError(t, os.Mkdir(fileName, 0700))
+
+	repository := NewEntrySingleFileRepository(fileName, time.Second)
+	err := repository.Edit(context.Background(), entity.Entry{Id: "1", EntryType: enum.Login})
+	assert.ErrorIs(t, err, sharedErr.ErrInternalError)
+}
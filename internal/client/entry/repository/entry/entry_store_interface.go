@@ -0,0 +1,17 @@
+package entry
+
+import (
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+)
+
+// EntryStoreInterface локальное хранилище записей всех типов вместе с их ревизиями
+//
+//go:generate mockgen -source=entry_store_interface.go -destination=mock_entry_store/mock_entry_store.go -package=mock_entry_store
+type EntryStoreInterface interface {
+	// Replace заменяет записи всех типов записями entries, ревизии удаляются. Хранилище запечатывается текущим ключом.
+	// Вызывается под блокировкой репозиториев всех типов. Новые записи пишутся рядом и заменяют прежние только целиком
+	Replace(entries map[enum.EntryType][]entity.Entry) error
+	// Seal запечатывает хранилище, созданное без ключа, когда ключ появился (после rekey). ErrVaultLocked, если ключ нужен, а хранилище заблокировано
+	Seal() error
+}
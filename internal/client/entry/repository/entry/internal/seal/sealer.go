@@ -0,0 +1,113 @@
+package seal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash"
+)
+
+const (
+	encryptionKeyLabel = "gophkeeper store encryption"
+	macKeyLabel        = "gophkeeper store mac"
+	nameKeyLabel       = "gophkeeper store names"
+
+	bucketNameSize = 8
+)
+
+var ErrInvalidRecord = errors.New("invalid sealed record")
+
+// Sealer шифрует записи локального хранилища и скрывает их id и тип.
+// Из ключа хранилища выводятся отдельные ключи для шифрования, MAC хранилища и имен
+type Sealer struct {
+	aead    cipher.AEAD
+	macKey  []byte
+	nameKey []byte
+}
+
+func NewSealer(storeKey []byte) (*Sealer, error) {
+	block, err := aes.NewCipher(subKey(storeKey, encryptionKeyLabel))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Sealer{
+		aead:    aead,
+		macKey:  subKey(storeKey, macKeyLabel),
+		nameKey: subKey(storeKey, nameKeyLabel),
+	}, nil
+}
+
+// BucketName имя bucket вместо типа записей
+func (s *Sealer) BucketName(entryType string) []byte {
+	name := s.hashName("bucket", entryType)
+	return []byte(hex.EncodeToString(name[:bucketNameSize]))
+}
+
+// RecordKey ключ записи вместо ее id
+func (s *Sealer) RecordKey(id string) []byte {
+	return s.hashName("record", id)
+}
+
+// Seal nonce | шифротекст. Bucket и ключ аутентифицируются: запись нельзя перенести под другой id или тип
+func (s *Sealer) Seal(bucket []byte, key []byte, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return s.aead.Seal(nonce, nonce, plaintext, associatedData(bucket, key)), nil
+}
+
+func (s *Sealer) Open(bucket []byte, key []byte, sealed []byte) ([]byte, error) {
+	if len(sealed) < s.aead.NonceSize() {
+		return nil, ErrInvalidRecord
+	}
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, associatedData(bucket, key))
+	if err != nil {
+		return nil, ErrInvalidRecord
+	}
+	return plaintext, nil
+}
+
+// NewMac MAC всего хранилища. Записи добавляются через WriteRecord в порядке хранения
+func (s *Sealer) NewMac() hash.Hash {
+	return hmac.New(sha256.New, s.macKey)
+}
+
+// WriteRecord bucket, ключ и значение с длинами, чтобы границы полей нельзя было сдвинуть
+func WriteRecord(mac hash.Hash, bucket []byte, key []byte, value []byte) {
+	for _, part := range [][]byte{bucket, key, value} {
+		mac.Write(binary.BigEndian.AppendUint32(nil, uint32(len(part))))
+		mac.Write(part)
+	}
+}
+
+func (s *Sealer) hashName(kind string, value string) []byte {
+	mac := hmac.New(sha256.New, s.nameKey)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+func subKey(storeKey []byte, label string) []byte {
+	mac := hmac.New(sha256.New, storeKey)
+	mac.Write([]byte(label))
+	return mac.Sum(nil)
+}
+
+func associatedData(bucket []byte, key []byte) []byte {
+	ad := binary.BigEndian.AppendUint32(nil, uint32(len(bucket)))
+	ad = append(ad, bucket...)
+	return append(ad, key...)
+}
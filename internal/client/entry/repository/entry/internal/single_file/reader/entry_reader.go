@@ -3,6 +3,7 @@ package reader
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 
 	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
 )
@@ -15,7 +16,11 @@ type EntryFileReader struct {
 
 // NewEntryFileReader missing godoc.
 func NewEntryFileReader(filename string) (*EntryFileReader, error) {
-	file, err := os.OpenFile(filename, os.O_RDONLY|os.O_CREATE, 0666)
+	err := os.MkdirAll(filepath.Dir(filename), 0700)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filename, os.O_RDONLY|os.O_CREATE, 0600)
 	if err != nil {
 		return nil, err
 	}
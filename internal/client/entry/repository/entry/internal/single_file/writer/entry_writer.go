@@ -2,16 +2,20 @@ package writer
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 
 	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/atomicfile"
 )
 
 // EntryFileWriter missing godoc.
 type EntryFileWriter struct {
 	file    *os.File
 	encoder *json.Encoder
+	// targetFilename - файл, который заменяется при Commit. Пустой для дописывающего writer
+	targetFilename string
 }
 
 // NewEntryFileWriter missing godoc.
@@ -22,7 +26,7 @@ func NewEntryFileWriter(filename string) (*EntryFileWriter, error) {
 	if err != nil {
 		return nil, err
 	}
-	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
 	if err != nil {
 		return nil, err
 	}
@@ -30,31 +34,66 @@ func NewEntryFileWriter(filename string) (*EntryFileWriter, error) {
 	return &EntryFileWriter{file: file, encoder: json.NewEncoder(file)}, nil
 }
 
-func NewEntryFileEmptyWriter(filename string) (*EntryFileWriter, error) {
-	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+// WriteEntry missing godoc.
+func (w *EntryFileWriter) WriteEntry(entry entity.Entry) error {
+	err := w.encoder.Encode(entry)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewEntryFileAtomicWriter пишет во временный файл рядом с filename.
+// filename заменяется только при Commit, поэтому сбой во время записи не портит существующие данные
+func NewEntryFileAtomicWriter(filename string) (*EntryFileWriter, error) {
+	file, err := atomicfile.CreateTemp(filename)
 	if err != nil {
 		return nil, err
 	}
 
-	return &EntryFileWriter{file: file, encoder: json.NewEncoder(file)}, nil
+	return &EntryFileWriter{file: file, encoder: json.NewEncoder(file), targetFilename: filename}, nil
 }
 
-// WriteEntry missing godoc.
-func (w *EntryFileWriter) WriteEntry(entry entity.Entry) error {
-	err := w.encoder.Encode(entry)
+// Commit сбрасывает временный файл на диск и переименовывает его в целевой
+func (w *EntryFileWriter) Commit() error {
+	err := w.file.Sync()
 	if err != nil {
 		return err
 	}
-	return nil
+	err = w.file.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Rename(w.file.Name(), w.targetFilename)
+	if err != nil {
+		return err
+	}
+	w.file = nil
+	return atomicfile.SyncDir(filepath.Dir(w.targetFilename))
 }
 
-// Close missing godoc.
+// Close у атомарного writer без Commit удаляет временный файл
 func (w *EntryFileWriter) Close() error {
-	return w.file.Close()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	// файл мог быть закрыт в Commit до неудачного переименования
+	if errors.Is(err, os.ErrClosed) {
+		err = nil
+	}
+	if w.targetFilename != "" {
+		removeErr := os.Remove(w.file.Name())
+		if err == nil && !errors.Is(removeErr, os.ErrNotExist) {
+			err = removeErr
+		}
+	}
+	w.file = nil
+	return err
 }
 
 func mkdir(dirName string) error {
-	err := os.MkdirAll(dirName, 0755)
+	err := os.MkdirAll(dirName, 0700)
 	if err != nil {
 		return err
 	}
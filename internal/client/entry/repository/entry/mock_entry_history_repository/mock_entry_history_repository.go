@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: entry_history_repository_interface.go
+
+// Package mock_entry_history_repository is a generated GoMock package.
+package mock_entry_history_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEntryHistoryRepositoryInterface is a mock of EntryHistoryRepositoryInterface interface.
+type MockEntryHistoryRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntryHistoryRepositoryInterfaceMockRecorder
+}
+
+// MockEntryHistoryRepositoryInterfaceMockRecorder is the mock recorder for MockEntryHistoryRepositoryInterface.
+type MockEntryHistoryRepositoryInterfaceMockRecorder struct {
+	mock *MockEntryHistoryRepositoryInterface
+}
+
+// NewMockEntryHistoryRepositoryInterface creates a new mock instance.
+func NewMockEntryHistoryRepositoryInterface(ctrl *gomock.Controller) *MockEntryHistoryRepositoryInterface {
+	mock := &MockEntryHistoryRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockEntryHistoryRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntryHistoryRepositoryInterface) EXPECT() *MockEntryHistoryRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Clear mocks base method.
+func (m *MockEntryHistoryRepositoryInterface) Clear(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clear", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear.
+func (mr *MockEntryHistoryRepositoryInterfaceMockRecorder) Clear(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockEntryHistoryRepositoryInterface)(nil).Clear), ctx)
+}
+
+// GetAll mocks base method.
+func (m *MockEntryHistoryRepositoryInterface) GetAll(ctx context.Context) ([]entity.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]entity.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockEntryHistoryRepositoryInterfaceMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockEntryHistoryRepositoryInterface)(nil).GetAll), ctx)
+}
+
+// GetList mocks base method.
+func (m *MockEntryHistoryRepositoryInterface) GetList(ctx context.Context, id string) ([]entity.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, id)
+	ret0, _ := ret[0].([]entity.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockEntryHistoryRepositoryInterfaceMockRecorder) GetList(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockEntryHistoryRepositoryInterface)(nil).GetList), ctx, id)
+}
+
+// Push mocks base method.
+func (m *MockEntryHistoryRepositoryInterface) Push(ctx context.Context, revision entity.Entry, limit int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Push", ctx, revision, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Push indicates an expected call of Push.
+func (mr *MockEntryHistoryRepositoryInterfaceMockRecorder) Push(ctx, revision, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Push", reflect.TypeOf((*MockEntryHistoryRepositoryInterface)(nil).Push), ctx, revision, limit)
+}
+
+// Rewrite mocks base method.
+func (m *MockEntryHistoryRepositoryInterface) Rewrite(ctx context.Context, revisions []entity.Entry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rewrite", ctx, revisions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rewrite indicates an expected call of Rewrite.
+func (mr *MockEntryHistoryRepositoryInterfaceMockRecorder) Rewrite(ctx, revisions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rewrite", reflect.TypeOf((*MockEntryHistoryRepositoryInterface)(nil).Rewrite), ctx, revisions)
+}
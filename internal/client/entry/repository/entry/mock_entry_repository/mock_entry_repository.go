@@ -8,9 +8,8 @@ import (
 	context "context"
 	reflect "reflect"
 
-	gomock "github.com/golang/mock/gomock"
-
 	entity "github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	gomock "github.com/golang/mock/gomock"
 )
 
 // MockEntryRepositoryInterface is a mock of EntryRepositoryInterface interface.
@@ -94,6 +93,21 @@ func (mr *MockEntryRepositoryInterfaceMockRecorder) GetList(ctx interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockEntryRepositoryInterface)(nil).GetList), ctx)
 }
 
+// Lock mocks base method.
+func (m *MockEntryRepositoryInterface) Lock(ctx context.Context) (func() error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx)
+	ret0, _ := ret[0].(func() error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockEntryRepositoryInterfaceMockRecorder) Lock(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockEntryRepositoryInterface)(nil).Lock), ctx)
+}
+
 // Rewrite mocks base method.
 func (m *MockEntryRepositoryInterface) Rewrite(ctx context.Context, entries []entity.Entry) error {
 	m.ctrl.T.Helper()
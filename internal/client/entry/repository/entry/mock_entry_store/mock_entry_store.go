@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: entry_store_interface.go
+
+// Package mock_entry_store is a generated GoMock package.
+package mock_entry_store
+
+import (
+	reflect "reflect"
+
+	entity "github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	enum "github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEntryStoreInterface is a mock of EntryStoreInterface interface.
+type MockEntryStoreInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntryStoreInterfaceMockRecorder
+}
+
+// MockEntryStoreInterfaceMockRecorder is the mock recorder for MockEntryStoreInterface.
+type MockEntryStoreInterfaceMockRecorder struct {
+	mock *MockEntryStoreInterface
+}
+
+// NewMockEntryStoreInterface creates a new mock instance.
+func NewMockEntryStoreInterface(ctrl *gomock.Controller) *MockEntryStoreInterface {
+	mock := &MockEntryStoreInterface{ctrl: ctrl}
+	mock.recorder = &MockEntryStoreInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntryStoreInterface) EXPECT() *MockEntryStoreInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Replace mocks base method.
+func (m *MockEntryStoreInterface) Replace(entries map[enum.EntryType][]entity.Entry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", entries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockEntryStoreInterfaceMockRecorder) Replace(entries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockEntryStoreInterface)(nil).Replace), entries)
+}
+
+// Seal mocks base method.
+func (m *MockEntryStoreInterface) Seal() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Seal")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Seal indicates an expected call of Seal.
+func (mr *MockEntryStoreInterfaceMockRecorder) Seal() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Seal", reflect.TypeOf((*MockEntryStoreInterface)(nil).Seal))
+}
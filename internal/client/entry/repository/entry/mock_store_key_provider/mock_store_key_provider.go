@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: store_key_provider_interface.go
+
+// Package mock_store_key_provider is a generated GoMock package.
+package mock_store_key_provider
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStoreKeyProviderInterface is a mock of StoreKeyProviderInterface interface.
+type MockStoreKeyProviderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreKeyProviderInterfaceMockRecorder
+}
+
+// MockStoreKeyProviderInterfaceMockRecorder is the mock recorder for MockStoreKeyProviderInterface.
+type MockStoreKeyProviderInterfaceMockRecorder struct {
+	mock *MockStoreKeyProviderInterface
+}
+
+// NewMockStoreKeyProviderInterface creates a new mock instance.
+func NewMockStoreKeyProviderInterface(ctrl *gomock.Controller) *MockStoreKeyProviderInterface {
+	mock := &MockStoreKeyProviderInterface{ctrl: ctrl}
+	mock.recorder = &MockStoreKeyProviderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStoreKeyProviderInterface) EXPECT() *MockStoreKeyProviderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// MarkStoreSealed mocks base method.
+func (m *MockStoreKeyProviderInterface) MarkStoreSealed() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkStoreSealed")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkStoreSealed indicates an expected call of MarkStoreSealed.
+func (mr *MockStoreKeyProviderInterfaceMockRecorder) MarkStoreSealed() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkStoreSealed", reflect.TypeOf((*MockStoreKeyProviderInterface)(nil).MarkStoreSealed))
+}
+
+// StoreKey mocks base method.
+func (m *MockStoreKeyProviderInterface) StoreKey() ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreKey")
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreKey indicates an expected call of StoreKey.
+func (mr *MockStoreKeyProviderInterfaceMockRecorder) StoreKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreKey", reflect.TypeOf((*MockStoreKeyProviderInterface)(nil).StoreKey))
+}
+
+// StoreSealed mocks base method.
+func (m *MockStoreKeyProviderInterface) StoreSealed() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreSealed")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreSealed indicates an expected call of StoreSealed.
+func (mr *MockStoreKeyProviderInterfaceMockRecorder) StoreSealed() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreSealed", reflect.TypeOf((*MockStoreKeyProviderInterface)(nil).StoreSealed))
+}
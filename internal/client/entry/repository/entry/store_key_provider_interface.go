@@ -0,0 +1,11 @@
+package entry
+
+//go:generate mockgen -source=store_key_provider_interface.go -destination=mock_store_key_provider/mock_store_key_provider.go -package=mock_store_key_provider
+type StoreKeyProviderInterface interface {
+	// StoreKey ключ, которым запечатано хранилище. nil - хранилище не запечатывается
+	StoreKey() ([]byte, error)
+	// StoreSealed хранилище уже запечатывалось: MAC у него обязателен
+	StoreSealed() (bool, error)
+	// MarkStoreSealed отмечает, что хранилище запечатано. Повторный вызов ничего не меняет
+	MarkStoreSealed() error
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/go-resty/resty/v2"
 
 	"github.com/anoriar/gophkeeper/internal/client/entry/dto/repository/entry_ext"
-	entryErr "github.com/anoriar/gophkeeper/internal/client/entry/errors"
 	sharedErr "github.com/anoriar/gophkeeper/internal/client/shared/errors"
 )
 
@@ -35,7 +34,7 @@ func (e *EntryExtRepository) Sync(ctx context.Context, token string, request ent
 		Post("/api/entries/sync")
 
 	if err != nil {
-		return entry_ext.SyncResponse{}, fmt.Errorf("%w: %v", sharedErr.ErrDependencyFailure, err)
+		return entry_ext.SyncResponse{}, fmt.Errorf("%w: %v", sharedErr.ErrServerUnreachable, err)
 	}
 
 	switch resp.StatusCode() {
@@ -46,8 +45,10 @@ func (e *EntryExtRepository) Sync(ctx context.Context, token string, request ent
 			return entry_ext.SyncResponse{}, fmt.Errorf("%w: %v", sharedErr.ErrInternalError, err)
 		}
 		return result, nil
+	case http.StatusUnauthorized:
+		return entry_ext.SyncResponse{}, fmt.Errorf("%w: %v", sharedErr.ErrNotLoggedIn, resp.Body())
 	case http.StatusConflict:
-		return entry_ext.SyncResponse{}, fmt.Errorf("%w: %v", entryErr.ErrSyncConflict, resp.Body())
+		return entry_ext.SyncResponse{}, fmt.Errorf("%w: %v", sharedErr.ErrSyncConflict, resp.Body())
 	default:
 		return entry_ext.SyncResponse{}, fmt.Errorf("%w: %v", sharedErr.ErrDependencyFailure, resp.Body())
 	}
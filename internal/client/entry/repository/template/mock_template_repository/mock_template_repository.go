@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: template_repository_interface.go
+
+// Package mock_template_repository is a generated GoMock package.
+package mock_template_repository
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTemplateRepositoryInterface is a mock of TemplateRepositoryInterface interface.
+type MockTemplateRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTemplateRepositoryInterfaceMockRecorder
+}
+
+// MockTemplateRepositoryInterfaceMockRecorder is the mock recorder for MockTemplateRepositoryInterface.
+type MockTemplateRepositoryInterfaceMockRecorder struct {
+	mock *MockTemplateRepositoryInterface
+}
+
+// NewMockTemplateRepositoryInterface creates a new mock instance.
+func NewMockTemplateRepositoryInterface(ctrl *gomock.Controller) *MockTemplateRepositoryInterface {
+	mock := &MockTemplateRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockTemplateRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTemplateRepositoryInterface) EXPECT() *MockTemplateRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockTemplateRepositoryInterface) Get(fileName string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", fileName)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTemplateRepositoryInterfaceMockRecorder) Get(fileName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTemplateRepositoryInterface)(nil).Get), fileName)
+}
+
+// Save mocks base method.
+func (m *MockTemplateRepositoryInterface) Save(fileName string, content []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", fileName, content)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockTemplateRepositoryInterfaceMockRecorder) Save(fileName, content interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTemplateRepositoryInterface)(nil).Save), fileName, content)
+}
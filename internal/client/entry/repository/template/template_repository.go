@@ -0,0 +1,22 @@
+package template
+
+import (
+	"os"
+
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/atomicfile"
+)
+
+type TemplateRepository struct {
+}
+
+func NewTemplateRepository() *TemplateRepository {
+	return &TemplateRepository{}
+}
+
+func (r *TemplateRepository) Get(fileName string) ([]byte, error) {
+	return os.ReadFile(fileName)
+}
+
+func (r *TemplateRepository) Save(fileName string, content []byte) error {
+	return atomicfile.WriteFile(fileName, content)
+}
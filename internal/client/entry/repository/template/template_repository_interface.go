@@ -0,0 +1,9 @@
+package template
+
+//go:generate mockgen -source=template_repository_interface.go -destination=mock_template_repository/mock_template_repository.go -package=mock_template_repository
+type TemplateRepositoryInterface interface {
+	Get(fileName string) ([]byte, error)
+	// Save файл с подставленными секретами доступен только владельцу (0600) и заменяется целиком:
+	// прерванная запись не оставляет половину файла
+	Save(fileName string, content []byte) error
+}
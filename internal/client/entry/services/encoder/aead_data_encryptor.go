@@ -0,0 +1,163 @@
+package encoder
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+var ErrInvalidSaltId = errors.New("invalid salt id")
+var ErrUnknownSaltId = errors.New("unknown salt id")
+var ErrUnsupportedCipher = errors.New("unsupported cipher")
+var ErrLegacyEntry = errors.New("entry uses legacy encryption, run login to upgrade it")
+var ErrLegacyEntryRejected = errors.New("entry uses legacy encryption, but vault is already upgraded")
+
+// AeadDataEncryptor шифрует новые записи выбранным алгоритмом и расшифровывает записи любым поддерживаемым
+type AeadDataEncryptor struct {
+	suite cipherSuite
+}
+
+// NewAeadDataEncryptor cipherName - алгоритм для новых шифротекстов, см. CipherNames
+func NewAeadDataEncryptor(cipherName string) (*AeadDataEncryptor, error) {
+	suite, err := findCipherSuiteByName(cipherName)
+	if err != nil {
+		return nil, err
+	}
+	return &AeadDataEncryptor{suite: suite}, nil
+}
+
+func (d *AeadDataEncryptor) Encrypt(data []byte, ad AssociatedData, keyring entity.Keyring) ([]byte, error) {
+	vaultKey := keyring.CurrentKey()
+	if vaultKey == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSaltId, keyring.CurrentKeyId)
+	}
+
+	header, err := cipherHeader{
+		Version:   headerVersion2,
+		Algorithm: d.suite.Id,
+		Kdf:       kdfArgon2id,
+		KdfParams: vaultKey.Kdf,
+		SaltId:    vaultKey.Id,
+	}.marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := d.suite.NewAEAD(vaultKey.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := append(header, nonce...)
+	return aead.Seal(encrypted, nonce, data, ad.marshal(header)), nil
+}
+
+func (d *AeadDataEncryptor) Decrypt(data []byte, ad AssociatedData, keyring entity.Keyring) ([]byte, error) {
+	header, body, ok := parseCipherHeader(data)
+	if !ok {
+		return d.decryptLegacy(data, keyring)
+	}
+	if header.Version != headerVersion2 && keyring.RejectLegacy {
+		return nil, fmt.Errorf("%w: %w", sharedErrors.ErrCorruptedEntry, ErrLegacyEntryRejected)
+	}
+
+	var additionalData []byte
+	if header.Version == headerVersion2 {
+		additionalData = ad.marshal(data[:headerSize])
+	}
+	decrypted, err := d.decryptWithHeader(header, body, additionalData, keyring)
+	if err != nil {
+		// подмененная запись v2 не должна расшифроваться по старой схеме без проверки заголовка
+		if header.Version == headerVersion2 {
+			return nil, err
+		}
+		// случайный nonce старой записи может совпасть с magic заголовка v1
+		legacyDecrypted, legacyErr := d.decryptLegacy(data, keyring)
+		if legacyErr == nil {
+			return legacyDecrypted, nil
+		}
+		return nil, err
+	}
+	return decrypted, nil
+}
+
+func (d *AeadDataEncryptor) NeedsReencrypt(data []byte, keyring entity.Keyring) bool {
+	header, _, ok := parseCipherHeader(data)
+	if !ok {
+		return true
+	}
+	return header.Version != headerVersion2 || header.Algorithm != d.suite.Id || header.SaltId != keyring.CurrentKeyId
+}
+
+func (d *AeadDataEncryptor) decryptWithHeader(header cipherHeader, body []byte, additionalData []byte, keyring entity.Keyring) ([]byte, error) {
+	suite, ok := findCipherSuite(header.Algorithm)
+	if !ok || header.Kdf != kdfArgon2id {
+		return nil, fmt.Errorf("%w: algorithm %d, kdf %d", ErrUnsupportedCipher, header.Algorithm, header.Kdf)
+	}
+
+	vaultKey := keyring.FindKey(header.SaltId)
+	if vaultKey == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSaltId, header.SaltId)
+	}
+
+	aead, err := suite.NewAEAD(vaultKey.Key)
+	if err != nil {
+		return nil, err
+	}
+	decrypted, err := d.open(aead, body, additionalData)
+	if err != nil {
+		return nil, d.openError(vaultKey.Verified, err)
+	}
+	return decrypted, nil
+}
+
+// decryptLegacy расшифровка записей, созданных до появления KDF (ключ - sha256 от мастер-пароля)
+func (d *AeadDataEncryptor) decryptLegacy(data []byte, keyring entity.Keyring) ([]byte, error) {
+	if keyring.RejectLegacy {
+		return nil, fmt.Errorf("%w: %w", sharedErrors.ErrCorruptedEntry, ErrLegacyEntryRejected)
+	}
+	if keyring.LegacyKey == nil {
+		return nil, ErrLegacyEntry
+	}
+	gcm, err := newAesGcm(keyring.LegacyKey)
+	if err != nil {
+		return nil, err
+	}
+	decrypted, err := d.open(gcm, data, nil)
+	if err != nil {
+		return nil, d.openError(false, err)
+	}
+	return decrypted, nil
+}
+
+// openError ключ, проверенный по KeyCheck, верен - значит повреждена запись.
+// Ключ слота без KeyCheck и ключ старого формата проверить нечем: считается, что неверен мастер-пароль
+func (d *AeadDataEncryptor) openError(keyVerified bool, err error) error {
+	if errors.Is(err, sharedErrors.ErrCorruptedEntry) {
+		return err
+	}
+	if keyVerified {
+		return fmt.Errorf("%w: %v", sharedErrors.ErrCorruptedEntry, err)
+	}
+	return fmt.Errorf("%w: %v", sharedErrors.ErrWrongMasterPassword, err)
+}
+
+func (d *AeadDataEncryptor) open(aead cipher.AEAD, data []byte, additionalData []byte) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, fmt.Errorf("%w: ciphertext too short", sharedErrors.ErrCorruptedEntry)
+	}
+	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
+
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
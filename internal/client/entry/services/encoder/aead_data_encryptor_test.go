@@ -0,0 +1,237 @@
+package encoder
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+var testKeyring = entity.Keyring{
+	CurrentKeyId: "0102030405060708",
+	Keys: []entity.VaultKey{
+		{
+			Id:  "0102030405060708",
+			Kdf: entity.KdfParams{Time: 3, Memory: 64 * 1024, Threads: 4},
+			Key: []byte("0123456789abcdef0123456789abcdef"),
+		},
+	},
+}
+
+var testAd = AssociatedData{EntryId: "cd06a579-311d-498e-aa01-d6ab589bf8bb", EntryType: enum.Login}
+
+func newTestEncryptor(t *testing.T, cipherName string) *AeadDataEncryptor {
+	encryptor, err := NewAeadDataEncryptor(cipherName)
+	require.NoError(t, err)
+	return encryptor
+}
+
+func TestAeadDataEncryptor_EncryptDecrypt(t *testing.T) {
+	encryptor := newTestEncryptor(t, CipherAes256Gcm)
+	data := []byte("{\"login\": \"test\", \"password\": \"pass\"}")
+
+	encrypted, err := encryptor.Encrypt(data, testAd, testKeyring)
+	require.NoError(t, err)
+
+	header, _, ok := parseCipherHeader(encrypted)
+	require.True(t, ok)
+	assert.Equal(t, "0102030405060708", header.SaltId)
+	assert.Equal(t, testKeyring.Keys[0].Kdf, header.KdfParams)
+	assert.False(t, encryptor.NeedsReencrypt(encrypted, testKeyring))
+
+	decrypted, err := encryptor.Decrypt(encrypted, testAd, testKeyring)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	wrongKeyring := entity.Keyring{
+		CurrentKeyId: testKeyring.CurrentKeyId,
+		Keys: []entity.VaultKey{
+			{Id: testKeyring.CurrentKeyId, Key: []byte("fedcba9876543210fedcba9876543210")},
+		},
+	}
+	_, err = encryptor.Decrypt(encrypted, testAd, wrongKeyring)
+	assert.ErrorIs(t, err, sharedErrors.ErrWrongMasterPassword)
+	assert.NotErrorIs(t, err, sharedErrors.ErrCorruptedEntry)
+
+	_, err = encryptor.Decrypt(encrypted, testAd, entity.Keyring{})
+	assert.ErrorIs(t, err, ErrUnknownSaltId)
+}
+
+func TestAeadDataEncryptor_DecryptCorrupted(t *testing.T) {
+	encryptor := newTestEncryptor(t, CipherAes256Gcm)
+	verifiedKeyring := entity.Keyring{
+		CurrentKeyId: testKeyring.CurrentKeyId,
+		Keys:         []entity.VaultKey{testKeyring.Keys[0]},
+	}
+	verifiedKeyring.Keys[0].Verified = true
+
+	encrypted, err := encryptor.Encrypt([]byte("data"), testAd, verifiedKeyring)
+	require.NoError(t, err)
+	encrypted[len(encrypted)-1] ^= 0xff
+
+	_, err = encryptor.Decrypt(encrypted, testAd, verifiedKeyring)
+	assert.ErrorIs(t, err, sharedErrors.ErrCorruptedEntry)
+	assert.NotErrorIs(t, err, sharedErrors.ErrWrongMasterPassword)
+
+	_, err = encryptor.Decrypt(encrypted[:headerSize+1], testAd, verifiedKeyring)
+	assert.ErrorIs(t, err, sharedErrors.ErrCorruptedEntry)
+}
+
+func TestAeadDataEncryptor_DecryptLegacy(t *testing.T) {
+	encryptor := newTestEncryptor(t, CipherAes256Gcm)
+
+	key := sha256.Sum256([]byte("master"))
+	block, err := aes.NewCipher(key[:])
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	require.NoError(t, err)
+	legacyEncrypted := gcm.Seal(nonce, nonce, []byte("legacy data"), nil)
+
+	assert.True(t, encryptor.NeedsReencrypt(legacyEncrypted, testKeyring))
+
+	_, err = encryptor.Decrypt(legacyEncrypted, testAd, testKeyring)
+	assert.ErrorIs(t, err, ErrLegacyEntry)
+
+	legacyKeyring := testKeyring
+	legacyKeyring.LegacyKey = key[:]
+	decrypted, err := encryptor.Decrypt(legacyEncrypted, testAd, legacyKeyring)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("legacy data"), decrypted)
+
+	// после перешифровки при login записи без заголовка не принимаются даже с ключом старого формата
+	legacyKeyring.RejectLegacy = true
+	_, err = encryptor.Decrypt(legacyEncrypted, testAd, legacyKeyring)
+	assert.ErrorIs(t, err, ErrLegacyEntryRejected)
+	assert.ErrorIs(t, err, sharedErrors.ErrCorruptedEntry)
+}
+
+func TestAeadDataEncryptor_AssociatedData(t *testing.T) {
+	encryptor := newTestEncryptor(t, CipherAes256Gcm)
+
+	encrypted, err := encryptor.Encrypt([]byte("data"), testAd, testKeyring)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		ad   AssociatedData
+	}{
+		{
+			name: "moved to another entry",
+			ad:   AssociatedData{EntryId: "ef77aba6-7ed4-421d-926a-93804ab96733", EntryType: testAd.EntryType},
+		},
+		{
+			name: "moved to another type",
+			ad:   AssociatedData{EntryId: testAd.EntryId, EntryType: enum.Card},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := encryptor.Decrypt(encrypted, tt.ad, testKeyring)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAeadDataEncryptor_DecryptWithoutAssociatedData(t *testing.T) {
+	encryptor := newTestEncryptor(t, CipherAes256Gcm)
+	vaultKey := testKeyring.CurrentKey()
+
+	header, err := cipherHeader{
+		Version:   headerVersion1,
+		Algorithm: algorithmAes256Gcm,
+		Kdf:       kdfArgon2id,
+		KdfParams: vaultKey.Kdf,
+		SaltId:    vaultKey.Id,
+	}.marshal()
+	require.NoError(t, err)
+	gcm, err := newAesGcm(vaultKey.Key)
+	require.NoError(t, err)
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	require.NoError(t, err)
+	v1Encrypted := gcm.Seal(append(header, nonce...), nonce, []byte("v1 data"), nil)
+
+	assert.True(t, encryptor.NeedsReencrypt(v1Encrypted, testKeyring))
+
+	decrypted, err := encryptor.Decrypt(v1Encrypted, testAd, testKeyring)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1 data"), decrypted)
+
+	migratedKeyring := testKeyring
+	migratedKeyring.RejectLegacy = true
+	_, err = encryptor.Decrypt(v1Encrypted, testAd, migratedKeyring)
+	assert.ErrorIs(t, err, ErrLegacyEntryRejected)
+}
+
+func TestAeadDataEncryptor_NoLegacyFallbackForV2(t *testing.T) {
+	encryptor := newTestEncryptor(t, CipherAes256Gcm)
+	vaultKey := testKeyring.CurrentKey()
+
+	// шифротекст старого формата под заголовком v2 расшифровался бы ключом старого формата
+	legacyKey := sha256.Sum256([]byte("master"))
+	header, err := cipherHeader{
+		Version:   headerVersion2,
+		Algorithm: algorithmAes256Gcm,
+		Kdf:       kdfArgon2id,
+		KdfParams: vaultKey.Kdf,
+		SaltId:    vaultKey.Id,
+	}.marshal()
+	require.NoError(t, err)
+	gcm, err := newAesGcm(legacyKey[:])
+	require.NoError(t, err)
+	// nonce - начало заголовка, открытый текст подобран так, чтобы шифротекст продолжил заголовок
+	nonce := header[:gcm.NonceSize()]
+	headerTail := header[gcm.NonceSize():]
+	keystream := gcm.Seal(nil, nonce, make([]byte, len(headerTail)), nil)[:len(headerTail)]
+	plaintext := make([]byte, 0, len(headerTail)+len("forged"))
+	for i := range headerTail {
+		plaintext = append(plaintext, headerTail[i]^keystream[i])
+	}
+	plaintext = append(plaintext, "forged"...)
+	forged := gcm.Seal(append([]byte(nil), nonce...), nonce, plaintext, nil)
+	require.Equal(t, header, forged[:headerSize])
+
+	legacyKeyring := testKeyring
+	legacyKeyring.LegacyKey = legacyKey[:]
+	_, err = encryptor.Decrypt(forged, testAd, legacyKeyring)
+	assert.Error(t, err)
+}
+
+func TestAeadDataEncryptor_CipherSuites(t *testing.T) {
+	aesEncryptor := newTestEncryptor(t, CipherAes256Gcm)
+	xchachaEncryptor := newTestEncryptor(t, CipherXChaCha20Poly1305)
+
+	aesEncrypted, err := aesEncryptor.Encrypt([]byte("aes data"), testAd, testKeyring)
+	require.NoError(t, err)
+	xchachaEncrypted, err := xchachaEncryptor.Encrypt([]byte("xchacha data"), testAd, testKeyring)
+	require.NoError(t, err)
+
+	header, _, ok := parseCipherHeader(xchachaEncrypted)
+	require.True(t, ok)
+	assert.Equal(t, byte(algorithmXChaCha20Poly1305), header.Algorithm)
+
+	// хранилище может содержать записи обоих алгоритмов
+	decrypted, err := xchachaEncryptor.Decrypt(aesEncrypted, testAd, testKeyring)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("aes data"), decrypted)
+	decrypted, err = aesEncryptor.Decrypt(xchachaEncrypted, testAd, testKeyring)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("xchacha data"), decrypted)
+
+	assert.True(t, xchachaEncryptor.NeedsReencrypt(aesEncrypted, testKeyring))
+	assert.False(t, xchachaEncryptor.NeedsReencrypt(xchachaEncrypted, testKeyring))
+
+	_, err = NewAeadDataEncryptor("des")
+	assert.ErrorIs(t, err, ErrUnknownCipher)
+}
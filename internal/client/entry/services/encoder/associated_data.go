@@ -0,0 +1,60 @@
+package encoder
+
+import (
+	"encoding/binary"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+)
+
+// AssociatedData данные записи, которые аутентифицируются вместе с шифротекстом.
+// Шифротекст, перенесенный в другую запись или другой тип, не расшифруется
+type AssociatedData struct {
+	EntryId   string
+	EntryType enum.EntryType
+	// Field поле записи. Пустое для данных, чтобы шифротексты данных и метаданных нельзя было поменять местами
+	Field string
+}
+
+const (
+	metaField  = "meta"
+	titleField = "title"
+)
+
+func NewAssociatedData(entry entity.Entry) AssociatedData {
+	return AssociatedData{
+		EntryId:   entry.Id,
+		EntryType: entry.EntryType,
+	}
+}
+
+func NewMetaAssociatedData(entry entity.Entry) AssociatedData {
+	return AssociatedData{
+		EntryId:   entry.Id,
+		EntryType: entry.EntryType,
+		Field:     metaField,
+	}
+}
+
+func NewTitleAssociatedData(entry entity.Entry) AssociatedData {
+	return AssociatedData{
+		EntryId:   entry.Id,
+		EntryType: entry.EntryType,
+		Field:     titleField,
+	}
+}
+
+// marshal header | len(id) | id | len(type) | type [| len(field) | field]
+func (ad AssociatedData) marshal(header []byte) []byte {
+	buf := make([]byte, 0, len(header)+6+len(ad.EntryId)+len(ad.EntryType)+len(ad.Field))
+	buf = append(buf, header...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(ad.EntryId)))
+	buf = append(buf, ad.EntryId...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(ad.EntryType)))
+	buf = append(buf, ad.EntryType...)
+	if ad.Field != "" {
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(ad.Field)))
+		buf = append(buf, ad.Field...)
+	}
+	return buf
+}
@@ -0,0 +1,75 @@
+package encoder
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"sort"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	CipherAes256Gcm         = "aes-256-gcm"
+	CipherXChaCha20Poly1305 = "xchacha20-poly1305"
+)
+
+var ErrUnknownCipher = errors.New("unknown cipher")
+
+// cipherSuite алгоритм шифрования. Id записывается в заголовок шифротекста и не должен меняться
+type cipherSuite struct {
+	Id      byte
+	Name    string
+	NewAEAD func(key []byte) (cipher.AEAD, error)
+}
+
+var cipherSuites = []cipherSuite{
+	{
+		Id:      algorithmAes256Gcm,
+		Name:    CipherAes256Gcm,
+		NewAEAD: newAesGcm,
+	},
+	{
+		// 192-битный nonce: случайные nonce безопасны для любого числа записей
+		Id:      algorithmXChaCha20Poly1305,
+		Name:    CipherXChaCha20Poly1305,
+		NewAEAD: chacha20poly1305.NewX,
+	},
+}
+
+func findCipherSuite(id byte) (cipherSuite, bool) {
+	for _, suite := range cipherSuites {
+		if suite.Id == id {
+			return suite, true
+		}
+	}
+	return cipherSuite{}, false
+}
+
+func findCipherSuiteByName(name string) (cipherSuite, error) {
+	for _, suite := range cipherSuites {
+		if suite.Name == name {
+			return suite, nil
+		}
+	}
+	return cipherSuite{}, fmt.Errorf("%w: %s, supported: %v", ErrUnknownCipher, name, CipherNames())
+}
+
+// CipherNames названия поддерживаемых алгоритмов для конфигурации
+func CipherNames() []string {
+	names := make([]string, 0, len(cipherSuites))
+	for _, suite := range cipherSuites {
+		names = append(names, suite.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newAesGcm(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
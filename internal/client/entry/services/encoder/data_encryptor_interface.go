@@ -1,7 +1,12 @@
 package encoder
 
+import "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+
 //go:generate mockgen -source=data_encryptor_interface.go -destination=mock_data_encryptor/mock_data_encryptor.go -package=mock_data_encryptor
 type DataEncryptorInterface interface {
-	Encrypt(data []byte, masterPass string) ([]byte, error)
-	Decrypt(data []byte, masterPass string) ([]byte, error)
+	Encrypt(data []byte, ad AssociatedData, keyring entity.Keyring) ([]byte, error)
+	// Decrypt отклоняет шифротекст, если ad не совпадает с данными при шифровании
+	Decrypt(data []byte, ad AssociatedData, keyring entity.Keyring) ([]byte, error)
+	// NeedsReencrypt данные зашифрованы не текущим ключом, не выбранным алгоритмом или в устаревшем формате
+	NeedsReencrypt(data []byte, keyring entity.Keyring) bool
 }
@@ -0,0 +1,105 @@
+package encoder
+
+import (
+	"fmt"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	entryErrors "github.com/anoriar/gophkeeper/internal/client/entry/errors"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	vaultEntity "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+// EncryptEntry шифрует данные, название и метаданные записи. Открытые метаданные переносятся в зашифрованные
+func EncryptEntry(encryptor DataEncryptorInterface, entry entity.Entry, keyring vaultEntity.Keyring) (entity.Entry, error) {
+	encryptedData, err := encryptor.Encrypt(entry.Data, NewAssociatedData(entry), keyring)
+	if err != nil {
+		return entity.Entry{}, fmt.Errorf("encrypt data error: %w", err)
+	}
+
+	meta := entry.Meta
+	if len(meta) == 0 {
+		meta = entry.PlainMeta
+	}
+	var encryptedMeta []byte
+	if len(meta) > 0 {
+		encryptedMeta, err = encryptor.Encrypt(meta, NewMetaAssociatedData(entry), keyring)
+		if err != nil {
+			return entity.Entry{}, fmt.Errorf("encrypt meta error: %w", err)
+		}
+	}
+
+	var encryptedTitle []byte
+	if len(entry.Title) > 0 {
+		encryptedTitle, err = encryptor.Encrypt(entry.Title, NewTitleAssociatedData(entry), keyring)
+		if err != nil {
+			return entity.Entry{}, fmt.Errorf("encrypt title error: %w", err)
+		}
+	}
+
+	entry.Data = encryptedData
+	entry.Title = encryptedTitle
+	entry.Meta = encryptedMeta
+	entry.PlainMeta = nil
+	return entry, nil
+}
+
+// DecryptEntry расшифровывает данные, название и метаданные записи
+func DecryptEntry(encryptor DataEncryptorInterface, entry entity.Entry, keyring vaultEntity.Keyring) (entity.Entry, error) {
+	decryptedData, err := encryptor.Decrypt(entry.Data, NewAssociatedData(entry), keyring)
+	if err != nil {
+		return entity.Entry{}, fmt.Errorf("decrypt data error: %w", err)
+	}
+
+	var decryptedMeta []byte
+	if len(entry.Meta) > 0 {
+		decryptedMeta, err = encryptor.Decrypt(entry.Meta, NewMetaAssociatedData(entry), keyring)
+		if err != nil {
+			return entity.Entry{}, fmt.Errorf("decrypt meta error: %w", err)
+		}
+	} else if len(entry.PlainMeta) > 0 {
+		if keyring.RejectLegacy {
+			return entity.Entry{}, fmt.Errorf("%w: %w", sharedErrors.ErrCorruptedEntry, entryErrors.ErrPlainMetaRejected)
+		}
+		decryptedMeta = entry.PlainMeta
+	}
+
+	var decryptedTitle []byte
+	if len(entry.Title) > 0 {
+		decryptedTitle, err = encryptor.Decrypt(entry.Title, NewTitleAssociatedData(entry), keyring)
+		if err != nil {
+			return entity.Entry{}, fmt.Errorf("decrypt title error: %w", err)
+		}
+	}
+
+	entry.Data = decryptedData
+	entry.Title = decryptedTitle
+	entry.Meta = decryptedMeta
+	entry.PlainMeta = nil
+	return entry, nil
+}
+
+// DecryptTitle расшифровывает только название: для list не нужно расшифровывать данные записей
+func DecryptTitle(encryptor DataEncryptorInterface, entry entity.Entry, keyring vaultEntity.Keyring) (string, error) {
+	if len(entry.Title) == 0 {
+		return "", nil
+	}
+	title, err := encryptor.Decrypt(entry.Title, NewTitleAssociatedData(entry), keyring)
+	if err != nil {
+		return "", fmt.Errorf("decrypt title error: %w", err)
+	}
+	return string(title), nil
+}
+
+// EntryNeedsReencrypt данные, название или метаданные записи зашифрованы не текущим ключом, в устаревшем формате или не зашифрованы
+func EntryNeedsReencrypt(encryptor DataEncryptorInterface, entry entity.Entry, keyring vaultEntity.Keyring) bool {
+	if len(entry.PlainMeta) > 0 {
+		return true
+	}
+	if len(entry.Meta) > 0 && encryptor.NeedsReencrypt(entry.Meta, keyring) {
+		return true
+	}
+	if len(entry.Title) > 0 && encryptor.NeedsReencrypt(entry.Title, keyring) {
+		return true
+	}
+	return encryptor.NeedsReencrypt(entry.Data, keyring)
+}
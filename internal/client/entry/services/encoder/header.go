@@ -0,0 +1,70 @@
+package encoder
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+const (
+	headerMagic    = "GK"
+	headerVersion1 = 1
+	// headerVersion2 заголовок и AssociatedData записи аутентифицируются как associated data
+	headerVersion2 = 2
+
+	algorithmAes256Gcm         = 1
+	algorithmXChaCha20Poly1305 = 2
+	kdfArgon2id                = 1
+
+	saltIdSize = 8
+	headerSize = len(headerMagic) + 3 + 4 + 4 + 1 + saltIdSize
+)
+
+// cipherHeader заголовок шифротекста:
+// magic(2) | version(1) | algorithm(1) | kdf(1) | time(4) | memory(4) | threads(1) | saltId(8)
+type cipherHeader struct {
+	Version   byte
+	Algorithm byte
+	Kdf       byte
+	KdfParams entity.KdfParams
+	SaltId    string
+}
+
+func (h cipherHeader) marshal() ([]byte, error) {
+	saltId, err := hex.DecodeString(h.SaltId)
+	if err != nil || len(saltId) != saltIdSize {
+		return nil, ErrInvalidSaltId
+	}
+
+	buf := make([]byte, 0, headerSize)
+	buf = append(buf, headerMagic...)
+	buf = append(buf, h.Version, h.Algorithm, h.Kdf)
+	buf = binary.BigEndian.AppendUint32(buf, h.KdfParams.Time)
+	buf = binary.BigEndian.AppendUint32(buf, h.KdfParams.Memory)
+	buf = append(buf, h.KdfParams.Threads)
+	buf = append(buf, saltId...)
+	return buf, nil
+}
+
+// parseCipherHeader разбирает заголовок. ok=false - данные зашифрованы до появления заголовка
+func parseCipherHeader(data []byte) (header cipherHeader, body []byte, ok bool) {
+	if len(data) < headerSize || string(data[:len(headerMagic)]) != headerMagic {
+		return cipherHeader{}, nil, false
+	}
+	pos := len(headerMagic)
+	header.Version = data[pos]
+	header.Algorithm = data[pos+1]
+	header.Kdf = data[pos+2]
+	pos += 3
+	if header.Version != headerVersion1 && header.Version != headerVersion2 {
+		return cipherHeader{}, nil, false
+	}
+	header.KdfParams.Time = binary.BigEndian.Uint32(data[pos:])
+	header.KdfParams.Memory = binary.BigEndian.Uint32(data[pos+4:])
+	header.KdfParams.Threads = data[pos+8]
+	pos += 9
+	header.SaltId = hex.EncodeToString(data[pos : pos+saltIdSize])
+
+	return header, data[headerSize:], true
+}
@@ -7,6 +7,8 @@ package mock_data_encryptor
 import (
 	reflect "reflect"
 
+	encoder "github.com/anoriar/gophkeeper/internal/client/entry/services/encoder"
+	entity "github.com/anoriar/gophkeeper/internal/client/vault/entity"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -34,31 +36,45 @@ func (m *MockDataEncryptorInterface) EXPECT() *MockDataEncryptorInterfaceMockRec
 }
 
 // Decrypt mocks base method.
-func (m *MockDataEncryptorInterface) Decrypt(data []byte, masterPass string) ([]byte, error) {
+func (m *MockDataEncryptorInterface) Decrypt(data []byte, ad encoder.AssociatedData, keyring entity.Keyring) ([]byte, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Decrypt", data, masterPass)
+	ret := m.ctrl.Call(m, "Decrypt", data, ad, keyring)
 	ret0, _ := ret[0].([]byte)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Decrypt indicates an expected call of Decrypt.
-func (mr *MockDataEncryptorInterfaceMockRecorder) Decrypt(data, masterPass interface{}) *gomock.Call {
+func (mr *MockDataEncryptorInterfaceMockRecorder) Decrypt(data, ad, keyring interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrypt", reflect.TypeOf((*MockDataEncryptorInterface)(nil).Decrypt), data, masterPass)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrypt", reflect.TypeOf((*MockDataEncryptorInterface)(nil).Decrypt), data, ad, keyring)
 }
 
 // Encrypt mocks base method.
-func (m *MockDataEncryptorInterface) Encrypt(data []byte, masterPass string) ([]byte, error) {
+func (m *MockDataEncryptorInterface) Encrypt(data []byte, ad encoder.AssociatedData, keyring entity.Keyring) ([]byte, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Encrypt", data, masterPass)
+	ret := m.ctrl.Call(m, "Encrypt", data, ad, keyring)
 	ret0, _ := ret[0].([]byte)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Encrypt indicates an expected call of Encrypt.
-func (mr *MockDataEncryptorInterfaceMockRecorder) Encrypt(data, masterPass interface{}) *gomock.Call {
+func (mr *MockDataEncryptorInterfaceMockRecorder) Encrypt(data, ad, keyring interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encrypt", reflect.TypeOf((*MockDataEncryptorInterface)(nil).Encrypt), data, masterPass)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encrypt", reflect.TypeOf((*MockDataEncryptorInterface)(nil).Encrypt), data, ad, keyring)
+}
+
+// NeedsReencrypt mocks base method.
+func (m *MockDataEncryptorInterface) NeedsReencrypt(data []byte, keyring entity.Keyring) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NeedsReencrypt", data, keyring)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NeedsReencrypt indicates an expected call of NeedsReencrypt.
+func (mr *MockDataEncryptorInterfaceMockRecorder) NeedsReencrypt(data, keyring interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedsReencrypt", reflect.TypeOf((*MockDataEncryptorInterface)(nil).NeedsReencrypt), data, keyring)
 }
@@ -4,60 +4,74 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
 	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command_response"
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	entryErrors "github.com/anoriar/gophkeeper/internal/client/entry/errors"
 	entryFactoryPkg "github.com/anoriar/gophkeeper/internal/client/entry/factory"
 	"github.com/anoriar/gophkeeper/internal/client/entry/factory/command/response"
 	"github.com/anoriar/gophkeeper/internal/client/entry/factory/ext_repository/request"
 	entryRepository "github.com/anoriar/gophkeeper/internal/client/entry/repository/entry"
 	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry_ext"
 	"github.com/anoriar/gophkeeper/internal/client/entry/services/encoder"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/search"
 	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
 	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret"
+	vaultEntity "github.com/anoriar/gophkeeper/internal/client/vault/entity"
 )
 
 type EntryService struct {
 	entryFactory       entryFactoryPkg.EntryFactoryInterface
 	entryRepository    entryRepository.EntryRepositoryInterface
+	historyRepository  entryRepository.EntryHistoryRepositoryInterface
 	secretRepository   secret.SecretRepositoryInterface
 	encoder            encoder.DataEncryptorInterface
 	responseFactory    *response.EntryResponseFactory
 	extEntryRepository entry_ext.EntryExtRepositoryInterface
 	syncRequestFactory *request.SyncRequestFactory
-	logger             *zap.Logger
+	// historySize - сколько ревизий записи хранить, 0 - не хранить
+	historySize int
+	logger      *zap.Logger
 }
 
 func NewEntryService(
 	entryFactory entryFactoryPkg.EntryFactoryInterface,
 	entryRepository entryRepository.EntryRepositoryInterface,
+	historyRepository entryRepository.EntryHistoryRepositoryInterface,
 	secretRepository secret.SecretRepositoryInterface,
 	encoderInterface encoder.DataEncryptorInterface,
 	extEntryRepository entry_ext.EntryExtRepositoryInterface,
+	historySize int,
 	logger *zap.Logger,
 ) *EntryService {
 	return &EntryService{
 		entryFactory:       entryFactory,
 		entryRepository:    entryRepository,
+		historyRepository:  historyRepository,
 		secretRepository:   secretRepository,
 		encoder:            encoderInterface,
 		responseFactory:    response.NewEntryResponseFactory(),
 		extEntryRepository: extEntryRepository,
 		syncRequestFactory: request.NewSyncRequestFactory(),
+		historySize:        historySize,
 		logger:             logger,
 	}
 }
 
 func (l *EntryService) Add(ctx context.Context, command command.AddEntryCommand) (command_response.DetailEntryResponse, error) {
-	masterPass, err := l.secretRepository.GetMasterPassword()
+	keyring, err := l.secretRepository.GetKeyring()
 
 	if err != nil {
-		if errors.Is(err, secret.ErrMasterPasswordNotFound) {
-			return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", secret.ErrMasterPasswordNotFound, err)
+		if errors.Is(err, secret.ErrVaultLocked) {
+			return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", secret.ErrVaultLocked, err)
 		}
-		l.logger.Error("get master password error", zap.String("error", err.Error()))
+		l.logger.Error("get keyring error", zap.String("error", err.Error()))
 		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
 	}
 	entry, err := l.entryFactory.CreateFromAddCmd(command)
@@ -65,7 +79,7 @@ func (l *EntryService) Add(ctx context.Context, command command.AddEntryCommand)
 		l.logger.Error("create entry error", zap.String("error", err.Error()))
 		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
 	}
-	encodedData, err := l.encoder.Encrypt(entry.Data, masterPass)
+	encryptedEntry, err := encoder.EncryptEntry(l.encoder, entry, keyring)
 	if err != nil {
 		l.logger.Error("encrypt data error", zap.String("error", err.Error()))
 		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
@@ -76,8 +90,7 @@ func (l *EntryService) Add(ctx context.Context, command command.AddEntryCommand)
 		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
 	}
 
-	entry.Data = encodedData
-	err = l.entryRepository.Add(ctx, entry)
+	err = l.entryRepository.Add(ctx, encryptedEntry)
 	if err != nil {
 		l.logger.Error("save data error", zap.String("error", err.Error()))
 		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
@@ -87,10 +100,10 @@ func (l *EntryService) Add(ctx context.Context, command command.AddEntryCommand)
 }
 
 func (l *EntryService) Edit(ctx context.Context, command command.EditEntryCommand) (command_response.DetailEntryResponse, error) {
-	masterPass, err := l.secretRepository.GetMasterPassword()
+	keyring, err := l.secretRepository.GetKeyring()
 	if err != nil {
-		if errors.Is(err, secret.ErrMasterPasswordNotFound) {
-			return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", secret.ErrMasterPasswordNotFound, err)
+		if errors.Is(err, secret.ErrVaultLocked) {
+			return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", secret.ErrVaultLocked, err)
 		}
 		l.logger.Error("save data error", zap.String("error", err.Error()))
 		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
@@ -101,7 +114,13 @@ func (l *EntryService) Edit(ctx context.Context, command command.EditEntryComman
 		l.logger.Error("save data error", zap.String("error", err.Error()))
 		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
 	}
-	encodedData, err := l.encoder.Encrypt(entry.Data, masterPass)
+	if command.Title == nil {
+		entry.Title, err = l.getCurrentTitle(ctx, command.Id, keyring)
+		if err != nil {
+			return command_response.DetailEntryResponse{}, err
+		}
+	}
+	encryptedEntry, err := encoder.EncryptEntry(l.encoder, entry, keyring)
 	if err != nil {
 		l.logger.Error("save data error", zap.String("error", err.Error()))
 		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
@@ -113,20 +132,73 @@ func (l *EntryService) Edit(ctx context.Context, command command.EditEntryComman
 		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
 	}
 
-	entry.Data = encodedData
-	err = l.entryRepository.Edit(ctx, entry)
+	err = l.editWithHistory(ctx, encryptedEntry)
+	if err != nil {
+		return command_response.DetailEntryResponse{}, err
+	}
+
+	return responseEntity, nil
+}
+
+// getCurrentTitle название текущей записи: edit без --title его не меняет
+func (l *EntryService) getCurrentTitle(ctx context.Context, id string, keyring vaultEntity.Keyring) ([]byte, error) {
+	current, err := l.entryRepository.GetById(ctx, id)
 	if err != nil {
 		if errors.Is(err, sharedErrors.ErrEntryNotFound) {
-			return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrEntryNotFound, err)
+			return nil, fmt.Errorf("%w: %w", sharedErrors.ErrEntryNotFound, err)
+		}
+		l.logger.Error("get entry error", zap.String("error", err.Error()))
+		return nil, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	title, err := encoder.DecryptTitle(l.encoder, current, keyring)
+	if err != nil {
+		if errors.Is(err, sharedErrors.ErrWrongMasterPassword) || errors.Is(err, sharedErrors.ErrCorruptedEntry) {
+			l.logger.Warn("decrypt title error", zap.String("id", id), zap.String("error", err.Error()))
+			return nil, fmt.Errorf("entry %s: %w", id, err)
+		}
+		l.logger.Error("decrypt title error", zap.String("error", err.Error()))
+		return nil, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	if title == "" {
+		return nil, nil
+	}
+	return []byte(title), nil
+}
+
+// editWithHistory сохраняет текущую версию записи в историю и перезаписывает запись
+func (l *EntryService) editWithHistory(ctx context.Context, encryptedEntry entity.Entry) error {
+	current, err := l.entryRepository.GetById(ctx, encryptedEntry.Id)
+	if err != nil {
+		if errors.Is(err, sharedErrors.ErrEntryNotFound) {
+			return fmt.Errorf("%w: %w", sharedErrors.ErrEntryNotFound, err)
+		}
+		l.logger.Error("get entry error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	if l.historySize > 0 {
+		err = l.historyRepository.Push(ctx, current, l.historySize)
+		if err != nil {
+			l.logger.Error("save revision error", zap.String("error", err.Error()))
+			return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
 		}
-		l.logger.Error("save data error", zap.String("error", err.Error()))
-		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
 	}
 
-	return responseEntity, nil
+	err = l.entryRepository.Edit(ctx, encryptedEntry)
+	if err != nil {
+		if errors.Is(err, sharedErrors.ErrEntryNotFound) {
+			return fmt.Errorf("%w: %w", sharedErrors.ErrEntryNotFound, err)
+		}
+		l.logger.Error("save data error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	return nil
 }
 
 func (l *EntryService) Delete(ctx context.Context, command command.DeleteEntryCommand) error {
+	err := l.requireUnlocked()
+	if err != nil {
+		return err
+	}
 	entryEntity, err := l.entryRepository.GetById(ctx, command.Id)
 	if err != nil {
 		if errors.Is(err, sharedErrors.ErrEntryNotFound) {
@@ -135,7 +207,9 @@ func (l *EntryService) Delete(ctx context.Context, command command.DeleteEntryCo
 		l.logger.Error("get entry error", zap.String("error", err.Error()))
 		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
 	}
+	// удаление - тоже изменение: по дате сервер понимает, что запись нужно перенести в корзину
 	entryEntity.IsDeleted = true
+	entryEntity.UpdatedAt = time.Now()
 
 	err = l.entryRepository.Edit(ctx, entryEntity)
 	if err != nil {
@@ -145,38 +219,184 @@ func (l *EntryService) Delete(ctx context.Context, command command.DeleteEntryCo
 	return nil
 }
 
+func (l *EntryService) Restore(ctx context.Context, command command.RestoreEntryCommand) error {
+	err := l.requireUnlocked()
+	if err != nil {
+		return err
+	}
+	entryEntity, err := l.entryRepository.GetById(ctx, command.Id)
+	if err != nil {
+		if errors.Is(err, sharedErrors.ErrEntryNotFound) {
+			return fmt.Errorf("%w: %w", sharedErrors.ErrEntryNotFound, err)
+		}
+		l.logger.Error("get entry error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	if !entryEntity.IsDeleted {
+		return fmt.Errorf("%w: entry %s", entryErrors.ErrEntryNotInTrash, command.Id)
+	}
+	entryEntity.IsDeleted = false
+	entryEntity.UpdatedAt = time.Now()
+
+	err = l.entryRepository.Edit(ctx, entryEntity)
+	if err != nil {
+		l.logger.Error("restore data error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	return nil
+}
+
 func (l *EntryService) Detail(ctx context.Context, command command.DetailEntryCommand) (command_response.DetailEntryResponse, error) {
-	masterPass, err := l.secretRepository.GetMasterPassword()
+	keyring, err := l.secretRepository.GetKeyring()
 	if err != nil {
-		if errors.Is(err, secret.ErrMasterPasswordNotFound) {
-			return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", secret.ErrMasterPasswordNotFound, err)
+		if errors.Is(err, secret.ErrVaultLocked) {
+			return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", secret.ErrVaultLocked, err)
 		}
-		l.logger.Error("get master password error", zap.String("error", err.Error()))
+		l.logger.Error("get keyring error", zap.String("error", err.Error()))
 		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
 	}
 
 	entry, err := l.entryRepository.GetById(ctx, command.Id)
 	if err != nil {
+		if errors.Is(err, sharedErrors.ErrEntryNotFound) {
+			l.logger.Warn("entry not found", zap.String("id", command.Id))
+			return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrEntryNotFound, err)
+		}
 		l.logger.Error("detail data error", zap.String("error", err.Error()))
 		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
 	}
-	decryptedData, err := l.encoder.Decrypt(entry.Data, masterPass)
+	decryptedEntry, err := encoder.DecryptEntry(l.encoder, entry, keyring)
 	if err != nil {
+		if errors.Is(err, sharedErrors.ErrWrongMasterPassword) || errors.Is(err, sharedErrors.ErrCorruptedEntry) {
+			l.logger.Warn("decrypt entry error", zap.String("id", entry.Id), zap.String("error", err.Error()))
+			return command_response.DetailEntryResponse{}, fmt.Errorf("entry %s: %w", entry.Id, err)
+		}
 		l.logger.Error("decrypt data error", zap.String("error", err.Error()))
 		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
 	}
-	entry.Data = decryptedData
-	return l.responseFactory.CreateDetailResponseFromEntity(entry)
+	return l.responseFactory.CreateDetailResponseFromEntity(decryptedEntry)
+}
+
+func (l *EntryService) List(ctx context.Context, command command.ListEntryCommand) ([]command_response.ListEntryCommandResponse, error) {
+	keyring, err := l.getKeyring()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := l.getListByDeleted(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	responseEntries := l.responseFactory.CreateListResponseFromEntity(l.decryptTitles(entries, keyring))
+	sortListResponse(responseEntries, command.SortBy, command.Desc)
+	if command.Limit > 0 && len(responseEntries) > command.Limit {
+		responseEntries = responseEntries[:command.Limit]
+	}
+	return responseEntries, nil
+}
+
+func (l *EntryService) Trash(ctx context.Context) ([]command_response.ListEntryCommandResponse, error) {
+	keyring, err := l.getKeyring()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := l.getListByDeleted(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	responseEntries := l.responseFactory.CreateListResponseFromEntity(l.decryptTitles(entries, keyring))
+	sortListResponse(responseEntries, command.ListSortTitle, false)
+	return responseEntries, nil
+}
+
+// Find расшифровывает записи целиком: ищет не только по названию, но и по метаданным и несекретным полям данных
+func (l *EntryService) Find(ctx context.Context, command command.FindEntryCommand) ([]command_response.FindEntryResponse, error) {
+	keyring, err := l.getKeyring()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := l.getListByDeleted(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]command_response.FindEntryResponse, 0)
+	for _, entry := range entries {
+		decryptedEntry, err := encoder.DecryptEntry(l.encoder, entry, keyring)
+		if err != nil {
+			l.logger.Warn("decrypt entry error", zap.String("id", entry.Id), zap.String("error", err.Error()))
+			continue
+		}
+		matchedField, score := search.Match(command.Query, search.EntryFields(decryptedEntry))
+		if score == 0 {
+			continue
+		}
+		found = append(found, command_response.FindEntryResponse{
+			Id:           decryptedEntry.Id,
+			EntryType:    decryptedEntry.EntryType,
+			Title:        string(decryptedEntry.Title),
+			UpdatedAt:    decryptedEntry.UpdatedAt,
+			MatchedField: matchedField,
+			Score:        score,
+		})
+	}
+
+	search.SortFound(found)
+	if command.Limit > 0 && len(found) > command.Limit {
+		found = found[:command.Limit]
+	}
+	return found, nil
+}
+
+// decryptTitles запись, название которой не расшифровалось, остается в списке без названия:
+// одна поврежденная запись не должна скрывать остальные, ошибку покажет detail
+func (l *EntryService) decryptTitles(entries []entity.Entry, keyring vaultEntity.Keyring) []entity.Entry {
+	for i := range entries {
+		title, err := encoder.DecryptTitle(l.encoder, entries[i], keyring)
+		if err != nil {
+			l.logger.Warn("decrypt title error", zap.String("id", entries[i].Id), zap.String("error", err.Error()))
+		}
+		entries[i].Title = []byte(title)
+	}
+	return entries
+}
+
+// sortListResponse записи с одинаковым ключом сортировки упорядочены по id, чтобы порядок не менялся между вызовами
+func sortListResponse(entries []command_response.ListEntryCommandResponse, sortBy string, desc bool) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if desc {
+			a, b = b, a
+		}
+		switch sortBy {
+		case command.ListSortUpdatedAt:
+			if !a.UpdatedAt.Equal(b.UpdatedAt) {
+				return a.UpdatedAt.Before(b.UpdatedAt)
+			}
+		default:
+			aTitle, bTitle := strings.ToLower(a.Title), strings.ToLower(b.Title)
+			if aTitle != bTitle {
+				return aTitle < bTitle
+			}
+		}
+		return a.Id < b.Id
+	})
 }
 
-func (l *EntryService) List(ctx context.Context) ([]command_response.ListEntryCommandResponse, error) {
+func (l *EntryService) getListByDeleted(ctx context.Context, isDeleted bool) ([]entity.Entry, error) {
 	entries, err := l.entryRepository.GetList(ctx)
 	if err != nil {
 		l.logger.Error("get list data error", zap.String("error", err.Error()))
 		return nil, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
 	}
-
-	return l.responseFactory.CreateListResponseFromEntity(entries), nil
+	filteredEntries := make([]entity.Entry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDeleted == isDeleted {
+			filteredEntries = append(filteredEntries, entry)
+		}
+	}
+	return filteredEntries, nil
 }
 
 func (l *EntryService) Sync(ctx context.Context, command command.SyncEntryCommand) error {
@@ -188,6 +408,25 @@ func (l *EntryService) Sync(ctx context.Context, command command.SyncEntryComman
 		l.logger.Error("get token error", zap.String("error", err.Error()))
 		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
 	}
+	keyring, err := l.getKeyring()
+	if err != nil {
+		return err
+	}
+	// записи, добавленные другим процессом во время запроса к серверу, не должны потеряться при перезаписи
+	unlock, err := l.entryRepository.Lock(ctx)
+	if err != nil {
+		if errors.Is(err, sharedErrors.ErrVaultBusy) {
+			return err
+		}
+		l.logger.Error("lock entries error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	defer func() {
+		unlockErr := unlock()
+		if unlockErr != nil {
+			l.logger.Error("unlock entries error", zap.String("error", unlockErr.Error()))
+		}
+	}()
 	entries, err := l.entryRepository.GetList(ctx)
 	if err != nil {
 		l.logger.Error("get entries list error", zap.String("error", err.Error()))
@@ -199,8 +438,12 @@ func (l *EntryService) Sync(ctx context.Context, command command.SyncEntryComman
 		l.logger.Error("sync entries error", zap.String("error", err.Error()))
 		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
 	}
-	newEntries, err := l.entryFactory.CreateFromSyncResponse(syncResponse)
+	newEntries, err := l.entryFactory.CreateFromSyncResponse(syncResponse, keyring.RejectLegacy)
 	if err != nil {
+		if errors.Is(err, sharedErrors.ErrCorruptedEntry) {
+			l.logger.Warn("sync response rejected", zap.String("error", err.Error()))
+			return err
+		}
 		l.logger.Error("create from sync response error", zap.String("error", err.Error()))
 		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
 	}
@@ -211,3 +454,84 @@ func (l *EntryService) Sync(ctx context.Context, command command.SyncEntryComman
 	}
 	return nil
 }
+
+func (l *EntryService) History(ctx context.Context, command command.HistoryEntryCommand) ([]command_response.HistoryEntryResponse, error) {
+	err := l.requireUnlocked()
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := l.historyRepository.GetList(ctx, command.Id)
+	if err != nil {
+		l.logger.Error("get revisions error", zap.String("error", err.Error()))
+		return nil, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	return l.responseFactory.CreateHistoryResponseFromEntity(revisions), nil
+}
+
+func (l *EntryService) Revert(ctx context.Context, command command.RevertEntryCommand) (command_response.DetailEntryResponse, error) {
+	keyring, err := l.secretRepository.GetKeyring()
+	if err != nil {
+		if errors.Is(err, secret.ErrVaultLocked) {
+			return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", secret.ErrVaultLocked, err)
+		}
+		l.logger.Error("get keyring error", zap.String("error", err.Error()))
+		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+
+	revisions, err := l.historyRepository.GetList(ctx, command.Id)
+	if err != nil {
+		l.logger.Error("get revisions error", zap.String("error", err.Error()))
+		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	if command.Revision < 1 || command.Revision > len(revisions) {
+		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: entry %s revision %d", entryErrors.ErrRevisionNotFound, command.Id, command.Revision)
+	}
+
+	decryptedRevision, err := encoder.DecryptEntry(l.encoder, revisions[command.Revision-1], keyring)
+	if err != nil {
+		if errors.Is(err, sharedErrors.ErrWrongMasterPassword) || errors.Is(err, sharedErrors.ErrCorruptedEntry) {
+			l.logger.Warn("decrypt revision error", zap.String("id", command.Id), zap.String("error", err.Error()))
+			return command_response.DetailEntryResponse{}, fmt.Errorf("entry %s revision %d: %w", command.Id, command.Revision, err)
+		}
+		l.logger.Error("decrypt revision error", zap.String("error", err.Error()))
+		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	// ревизия восстанавливается как новое изменение: оно уходит на сервер при следующей синхронизации
+	decryptedRevision.UpdatedAt = time.Now()
+	decryptedRevision.IsDeleted = false
+
+	encryptedEntry, err := encoder.EncryptEntry(l.encoder, decryptedRevision, keyring)
+	if err != nil {
+		l.logger.Error("encrypt data error", zap.String("error", err.Error()))
+		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	responseEntity, err := l.responseFactory.CreateDetailResponseFromEntity(decryptedRevision)
+	if err != nil {
+		l.logger.Error("create detail data error", zap.String("error", err.Error()))
+		return command_response.DetailEntryResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+
+	err = l.editWithHistory(ctx, encryptedEntry)
+	if err != nil {
+		return command_response.DetailEntryResponse{}, err
+	}
+	return responseEntity, nil
+}
+
+// requireUnlocked локальное хранилище запечатано ключом из мастер-пароля: без разблокировки записи не читаются
+func (l *EntryService) requireUnlocked() error {
+	_, err := l.getKeyring()
+	return err
+}
+
+func (l *EntryService) getKeyring() (vaultEntity.Keyring, error) {
+	keyring, err := l.secretRepository.GetKeyring()
+	if err != nil {
+		if errors.Is(err, secret.ErrVaultLocked) {
+			return vaultEntity.Keyring{}, fmt.Errorf("%w: %w", secret.ErrVaultLocked, err)
+		}
+		l.logger.Error("get keyring error", zap.String("error", err.Error()))
+		return vaultEntity.Keyring{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	return keyring, nil
+}
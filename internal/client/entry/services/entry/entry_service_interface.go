@@ -16,9 +16,19 @@ type EntryServiceInterface interface {
 	Edit(ctx context.Context, command command.EditEntryCommand) (command_response.DetailEntryResponse, error)
 	// Detail Полная информация по записи (с расшифрованной информацией)
 	Detail(ctx context.Context, command command.DetailEntryCommand) (command_response.DetailEntryResponse, error)
-	// Delete Удаление записи, isDeleted=true
+	// Delete Удаление записи в корзину, isDeleted=true
 	Delete(ctx context.Context, command command.DeleteEntryCommand) error
-	// List Список записей (без даты)
-	List(ctx context.Context) ([]command_response.ListEntryCommandResponse, error)
+	// Restore Восстановление записи из корзины
+	Restore(ctx context.Context, command command.RestoreEntryCommand) error
+	// History Ревизии записи, сохраненные при редактировании, от новых к старым
+	History(ctx context.Context, command command.HistoryEntryCommand) ([]command_response.HistoryEntryResponse, error)
+	// Revert Восстановление ревизии как нового изменения записи
+	Revert(ctx context.Context, command command.RevertEntryCommand) (command_response.DetailEntryResponse, error)
+	// List Список записей с расшифрованными названиями, кроме удаленных. Сортировка и ограничение количества - по command
+	List(ctx context.Context, command command.ListEntryCommand) ([]command_response.ListEntryCommandResponse, error)
+	// Find Поиск по названиям, метаданным и несекретным полям записей, кроме удаленных. Лучшие совпадения первыми
+	Find(ctx context.Context, command command.FindEntryCommand) ([]command_response.FindEntryResponse, error)
+	// Trash Список удаленных записей, по названию
+	Trash(ctx context.Context) ([]command_response.ListEntryCommandResponse, error)
 	Sync(ctx context.Context, command command.SyncEntryCommand) error
 }
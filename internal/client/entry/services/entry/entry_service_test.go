@@ -19,22 +19,45 @@ import (
 	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command_response"
 	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
 	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	entryErrors "github.com/anoriar/gophkeeper/internal/client/entry/errors"
 	"github.com/anoriar/gophkeeper/internal/client/entry/factory/mock_entry_factory"
+	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry/mock_entry_history_repository"
 	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry/mock_entry_repository"
 	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry_ext/mock_entry_ext_repository"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/encoder"
 	"github.com/anoriar/gophkeeper/internal/client/entry/services/encoder/mock_data_encryptor"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/search"
 	"github.com/anoriar/gophkeeper/internal/client/shared/app/logger"
 	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
 	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret"
 	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret/mock_secret_repository"
+	vaultEntity "github.com/anoriar/gophkeeper/internal/client/vault/entity"
 )
 
+const testHistorySize = 5
+
+// testCurrentEntry версия записи до редактирования, уходит в историю
+var testCurrentEntry = entity.Entry{
+	Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
+	EntryType: enum.Login,
+	UpdatedAt: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC),
+	Data:      []byte("old encrypted data"),
+}
+
+var testKeyring = vaultEntity.Keyring{
+	CurrentKeyId: "0102030405060708",
+	Keys: []vaultEntity.VaultKey{
+		{Id: "0102030405060708", Key: []byte("0123456789abcdef0123456789abcdef")},
+	},
+}
+
 func TestEntryService_Add(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	entryFactoryMock := mock_entry_factory.NewMockEntryFactoryInterface(ctrl)
 	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	historyRepositoryMock := mock_entry_history_repository.NewMockEntryHistoryRepositoryInterface(ctrl)
 	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
 	encryptorMock := mock_data_encryptor.NewMockDataEncryptorInterface(ctrl)
 	extRepositoryMock := mock_entry_ext_repository.NewMockEntryExtRepositoryInterface(ctrl)
@@ -68,33 +91,43 @@ func TestEntryService_Add(t *testing.T) {
 				command: addEntryCommand,
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.AddEntryCommand) {
-				masterPass := "12345"
+				keyring := testKeyring
 				dataInBytes := []byte("{\"login\": \"test\", \"password\": \"pass\"}")
 				entryMock := entity.Entry{
-					Id:        "cd06a579-311d-498e-aa01-d6ab589bf8bb",
-					EntryType: enum.Login,
-					UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
-					IsDeleted: false,
-					Data:      dataInBytes,
-					Meta:      []byte(""),
+					Id:         "cd06a579-311d-498e-aa01-d6ab589bf8bb",
+					EntryType:  enum.Login,
+					UpdatedAt:  time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+					IsDeleted:  false,
+					Data:       dataInBytes,
+					Meta:       []byte("{\"site\": \"example.com\"}"),
+					PublicMeta: []byte("{\"tag\": \"work\"}"),
+					Title:      []byte("Mail"),
 				}
 				encryptedData := []byte("encrypted data")
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return(masterPass, nil)
+				encryptedMeta := []byte("encrypted meta")
+				encryptedTitle := []byte("encrypted title")
+				secretRepositoryMock.EXPECT().GetKeyring().Return(keyring, nil)
 				entryFactoryMock.EXPECT().CreateFromAddCmd(entryCommand).Return(entryMock, nil)
-				encryptorMock.EXPECT().Encrypt(dataInBytes, masterPass).Return(encryptedData, nil)
+				encryptorMock.EXPECT().Encrypt(dataInBytes, encoder.AssociatedData{EntryId: "cd06a579-311d-498e-aa01-d6ab589bf8bb", EntryType: enum.Login}, keyring).Return(encryptedData, nil)
+				encryptorMock.EXPECT().Encrypt([]byte("{\"site\": \"example.com\"}"), encoder.AssociatedData{EntryId: "cd06a579-311d-498e-aa01-d6ab589bf8bb", EntryType: enum.Login, Field: "meta"}, keyring).Return(encryptedMeta, nil)
+				encryptorMock.EXPECT().Encrypt([]byte("Mail"), encoder.AssociatedData{EntryId: "cd06a579-311d-498e-aa01-d6ab589bf8bb", EntryType: enum.Login, Field: "title"}, keyring).Return(encryptedTitle, nil)
 				entryMock.Data = encryptedData
+				entryMock.Meta = encryptedMeta
+				entryMock.Title = encryptedTitle
 				entryRepositoryMock.EXPECT().Add(ctx, entryMock).Return(nil)
 			},
 			want: command_response.DetailEntryResponse{
 				Id:        "cd06a579-311d-498e-aa01-d6ab589bf8bb",
 				EntryType: enum.Login,
+				Title:     "Mail",
 				UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
 				IsDeleted: false,
 				Data: &dto.LoginData{
 					Login:    "test",
 					Password: "pass",
 				},
-				Meta: []byte(""),
+				Meta:       []byte("{\"site\": \"example.com\"}"),
+				PublicMeta: []byte("{\"tag\": \"work\"}"),
 			},
 			wantErr: nil,
 		},
@@ -105,9 +138,9 @@ func TestEntryService_Add(t *testing.T) {
 				command: addEntryCommand,
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.AddEntryCommand) {
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return("", secret.ErrMasterPasswordNotFound)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(vaultEntity.Keyring{}, secret.ErrVaultLocked)
 			},
-			wantErr: secret.ErrMasterPasswordNotFound,
+			wantErr: secret.ErrVaultLocked,
 		},
 		{
 			name: "get master pass internal error",
@@ -116,7 +149,7 @@ func TestEntryService_Add(t *testing.T) {
 				command: addEntryCommand,
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.AddEntryCommand) {
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return("", sharedErrors.ErrInternalError)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(vaultEntity.Keyring{}, sharedErrors.ErrInternalError)
 			},
 			wantErr: sharedErrors.ErrInternalError,
 		},
@@ -127,7 +160,7 @@ func TestEntryService_Add(t *testing.T) {
 				command: addEntryCommand,
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.AddEntryCommand) {
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return("12345", nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
 				entryFactoryMock.EXPECT().CreateFromAddCmd(addEntryCommand).Return(entity.Entry{}, sharedErrors.ErrInternalError)
 			},
 			wantErr: sharedErrors.ErrInternalError,
@@ -139,7 +172,7 @@ func TestEntryService_Add(t *testing.T) {
 				command: addEntryCommand,
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.AddEntryCommand) {
-				masterPass := "12345"
+				keyring := testKeyring
 				dataInBytes := []byte("{\"login\": \"test\", \"password\": \"pass\"}")
 				entryMock := entity.Entry{
 					Id:        "cd06a579-311d-498e-aa01-d6ab589bf8bb",
@@ -150,9 +183,9 @@ func TestEntryService_Add(t *testing.T) {
 					Meta:      []byte(""),
 				}
 
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return(masterPass, nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(keyring, nil)
 				entryFactoryMock.EXPECT().CreateFromAddCmd(addEntryCommand).Return(entryMock, nil)
-				encryptorMock.EXPECT().Encrypt(dataInBytes, masterPass).Return(nil, sharedErrors.ErrInternalError)
+				encryptorMock.EXPECT().Encrypt(dataInBytes, encoder.AssociatedData{EntryId: "cd06a579-311d-498e-aa01-d6ab589bf8bb", EntryType: enum.Login}, keyring).Return(nil, sharedErrors.ErrInternalError)
 			},
 			wantErr: sharedErrors.ErrInternalError,
 		},
@@ -163,7 +196,7 @@ func TestEntryService_Add(t *testing.T) {
 				command: addEntryCommand,
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.AddEntryCommand) {
-				masterPass := "12345"
+				keyring := testKeyring
 				dataInBytes := []byte("{\"login\": \"test\", \"password\": \"pass\"}")
 				entryMock := entity.Entry{
 					Id:        "cd06a579-311d-498e-aa01-d6ab589bf8bb",
@@ -174,10 +207,11 @@ func TestEntryService_Add(t *testing.T) {
 					Meta:      []byte(""),
 				}
 				encryptedData := []byte("encrypted data")
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return(masterPass, nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(keyring, nil)
 				entryFactoryMock.EXPECT().CreateFromAddCmd(entryCommand).Return(entryMock, nil)
-				encryptorMock.EXPECT().Encrypt(dataInBytes, masterPass).Return(encryptedData, nil)
+				encryptorMock.EXPECT().Encrypt(dataInBytes, encoder.AssociatedData{EntryId: "cd06a579-311d-498e-aa01-d6ab589bf8bb", EntryType: enum.Login}, keyring).Return(encryptedData, nil)
 				entryMock.Data = encryptedData
+				entryMock.Meta = nil
 				entryRepositoryMock.EXPECT().Add(ctx, entryMock).Return(sharedErrors.ErrInternalError)
 			},
 			wantErr: sharedErrors.ErrInternalError,
@@ -189,9 +223,11 @@ func TestEntryService_Add(t *testing.T) {
 			l := NewEntryService(
 				entryFactoryMock,
 				entryRepositoryMock,
+				historyRepositoryMock,
 				secretRepositoryMock,
 				encryptorMock,
 				extRepositoryMock,
+				testHistorySize,
 				loggerMock,
 			)
 			got, err := l.Add(tt.args.ctx, tt.args.command)
@@ -213,12 +249,14 @@ func TestEntryService_Edit(t *testing.T) {
 
 	entryFactoryMock := mock_entry_factory.NewMockEntryFactoryInterface(ctrl)
 	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	historyRepositoryMock := mock_entry_history_repository.NewMockEntryHistoryRepositoryInterface(ctrl)
 	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
 	encryptorMock := mock_data_encryptor.NewMockDataEncryptorInterface(ctrl)
 	extRepositoryMock := mock_entry_ext_repository.NewMockEntryExtRepositoryInterface(ctrl)
 	loggerMock, err := logger.Initialize("info")
 	require.NoError(t, err)
 
+	editTitle := ""
 	editEntryCommand := command.EditEntryCommand{
 		Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
 		EntryType: enum.Login,
@@ -226,8 +264,11 @@ func TestEntryService_Edit(t *testing.T) {
 			Login:    "user",
 			Password: "password",
 		},
-		Meta: []byte(""),
+		Title: &editTitle,
+		Meta:  []byte(""),
 	}
+	keepTitleCommand := editEntryCommand
+	keepTitleCommand.Title = nil
 	type args struct {
 		ctx     context.Context
 		command command.EditEntryCommand
@@ -246,7 +287,47 @@ func TestEntryService_Edit(t *testing.T) {
 				command: editEntryCommand,
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.EditEntryCommand) {
-				masterPass := "12345"
+				keyring := testKeyring
+				dataInBytes := []byte("{\"login\": \"test\", \"password\": \"pass\"}")
+				entryMock := entity.Entry{
+					Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
+					EntryType: enum.Login,
+					UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+					IsDeleted: false,
+					Data:      dataInBytes,
+					Meta:      []byte(""),
+				}
+				encryptedData := []byte("encrypted data")
+				secretRepositoryMock.EXPECT().GetKeyring().Return(keyring, nil)
+				entryFactoryMock.EXPECT().CreateFromEditCmd(entryCommand).Return(entryMock, nil)
+				encryptorMock.EXPECT().Encrypt(dataInBytes, encoder.AssociatedData{EntryId: "ef77aba6-7ed4-421d-926a-93804ab96733", EntryType: enum.Login}, keyring).Return(encryptedData, nil)
+				entryMock.Data = encryptedData
+				entryMock.Meta = nil
+				entryRepositoryMock.EXPECT().GetById(ctx, "ef77aba6-7ed4-421d-926a-93804ab96733").Return(testCurrentEntry, nil)
+				historyRepositoryMock.EXPECT().Push(ctx, testCurrentEntry, testHistorySize).Return(nil)
+				entryRepositoryMock.EXPECT().Edit(ctx, entryMock).Return(nil)
+			},
+			want: command_response.DetailEntryResponse{
+				Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
+				EntryType: enum.Login,
+				UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+				IsDeleted: false,
+				Data: &dto.LoginData{
+					Login:    "test",
+					Password: "pass",
+				},
+				Meta: []byte(""),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success keep title",
+			args: args{
+				ctx:     context.Background(),
+				command: keepTitleCommand,
+			},
+			mockBehaviour: func(ctx context.Context, entryCommand command.EditEntryCommand) {
+				keyring := testKeyring
 				dataInBytes := []byte("{\"login\": \"test\", \"password\": \"pass\"}")
 				entryMock := entity.Entry{
 					Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
@@ -256,11 +337,21 @@ func TestEntryService_Edit(t *testing.T) {
 					Data:      dataInBytes,
 					Meta:      []byte(""),
 				}
+				currentEntry := testCurrentEntry
+				currentEntry.Title = []byte("old encrypted title")
+				titleAd := encoder.AssociatedData{EntryId: "ef77aba6-7ed4-421d-926a-93804ab96733", EntryType: enum.Login, Field: "title"}
 				encryptedData := []byte("encrypted data")
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return(masterPass, nil)
+				encryptedTitle := []byte("encrypted title")
+				secretRepositoryMock.EXPECT().GetKeyring().Return(keyring, nil)
 				entryFactoryMock.EXPECT().CreateFromEditCmd(entryCommand).Return(entryMock, nil)
-				encryptorMock.EXPECT().Encrypt(dataInBytes, masterPass).Return(encryptedData, nil)
+				entryRepositoryMock.EXPECT().GetById(ctx, "ef77aba6-7ed4-421d-926a-93804ab96733").Return(currentEntry, nil).Times(2)
+				encryptorMock.EXPECT().Decrypt(currentEntry.Title, titleAd, keyring).Return([]byte("Mail"), nil)
+				encryptorMock.EXPECT().Encrypt(dataInBytes, encoder.AssociatedData{EntryId: "ef77aba6-7ed4-421d-926a-93804ab96733", EntryType: enum.Login}, keyring).Return(encryptedData, nil)
+				encryptorMock.EXPECT().Encrypt([]byte("Mail"), titleAd, keyring).Return(encryptedTitle, nil)
 				entryMock.Data = encryptedData
+				entryMock.Title = encryptedTitle
+				entryMock.Meta = nil
+				historyRepositoryMock.EXPECT().Push(ctx, currentEntry, testHistorySize).Return(nil)
 				entryRepositoryMock.EXPECT().Edit(ctx, entryMock).Return(nil)
 			},
 			want: command_response.DetailEntryResponse{
@@ -268,6 +359,7 @@ func TestEntryService_Edit(t *testing.T) {
 				EntryType: enum.Login,
 				UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
 				IsDeleted: false,
+				Title:     "Mail",
 				Data: &dto.LoginData{
 					Login:    "test",
 					Password: "pass",
@@ -276,6 +368,19 @@ func TestEntryService_Edit(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "keep title not found error",
+			args: args{
+				ctx:     context.Background(),
+				command: keepTitleCommand,
+			},
+			mockBehaviour: func(ctx context.Context, entryCommand command.EditEntryCommand) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryFactoryMock.EXPECT().CreateFromEditCmd(entryCommand).Return(entity.Entry{Id: "ef77aba6-7ed4-421d-926a-93804ab96733", EntryType: enum.Login}, nil)
+				entryRepositoryMock.EXPECT().GetById(ctx, "ef77aba6-7ed4-421d-926a-93804ab96733").Return(entity.Entry{}, sharedErrors.ErrEntryNotFound)
+			},
+			wantErr: sharedErrors.ErrEntryNotFound,
+		},
 		{
 			name: "master pass not found error",
 			args: args{
@@ -283,9 +388,9 @@ func TestEntryService_Edit(t *testing.T) {
 				command: editEntryCommand,
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.EditEntryCommand) {
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return("", secret.ErrMasterPasswordNotFound)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(vaultEntity.Keyring{}, secret.ErrVaultLocked)
 			},
-			wantErr: secret.ErrMasterPasswordNotFound,
+			wantErr: secret.ErrVaultLocked,
 		},
 		{
 			name: "get master pass internal error",
@@ -294,7 +399,7 @@ func TestEntryService_Edit(t *testing.T) {
 				command: editEntryCommand,
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.EditEntryCommand) {
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return("", sharedErrors.ErrInternalError)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(vaultEntity.Keyring{}, sharedErrors.ErrInternalError)
 			},
 			wantErr: sharedErrors.ErrInternalError,
 		},
@@ -305,7 +410,7 @@ func TestEntryService_Edit(t *testing.T) {
 				command: editEntryCommand,
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.EditEntryCommand) {
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return("12345", nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
 				entryFactoryMock.EXPECT().CreateFromEditCmd(editEntryCommand).Return(entity.Entry{}, sharedErrors.ErrInternalError)
 			},
 			wantErr: sharedErrors.ErrInternalError,
@@ -317,7 +422,7 @@ func TestEntryService_Edit(t *testing.T) {
 				command: editEntryCommand,
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.EditEntryCommand) {
-				masterPass := "12345"
+				keyring := testKeyring
 				dataInBytes := []byte("{\"login\": \"test\", \"password\": \"pass\"}")
 				entryMock := entity.Entry{
 					Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
@@ -328,9 +433,9 @@ func TestEntryService_Edit(t *testing.T) {
 					Meta:      []byte(""),
 				}
 
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return(masterPass, nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(keyring, nil)
 				entryFactoryMock.EXPECT().CreateFromEditCmd(editEntryCommand).Return(entryMock, nil)
-				encryptorMock.EXPECT().Encrypt(dataInBytes, masterPass).Return(nil, sharedErrors.ErrInternalError)
+				encryptorMock.EXPECT().Encrypt(dataInBytes, encoder.AssociatedData{EntryId: "ef77aba6-7ed4-421d-926a-93804ab96733", EntryType: enum.Login}, keyring).Return(nil, sharedErrors.ErrInternalError)
 			},
 			wantErr: sharedErrors.ErrInternalError,
 		},
@@ -341,7 +446,7 @@ func TestEntryService_Edit(t *testing.T) {
 				command: editEntryCommand,
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.EditEntryCommand) {
-				masterPass := "12345"
+				keyring := testKeyring
 				dataInBytes := []byte("{\"login\": \"test\", \"password\": \"pass\"}")
 				entryMock := entity.Entry{
 					Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
@@ -352,22 +457,25 @@ func TestEntryService_Edit(t *testing.T) {
 					Meta:      []byte(""),
 				}
 				encryptedData := []byte("encrypted data")
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return(masterPass, nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(keyring, nil)
 				entryFactoryMock.EXPECT().CreateFromEditCmd(entryCommand).Return(entryMock, nil)
-				encryptorMock.EXPECT().Encrypt(dataInBytes, masterPass).Return(encryptedData, nil)
+				encryptorMock.EXPECT().Encrypt(dataInBytes, encoder.AssociatedData{EntryId: "ef77aba6-7ed4-421d-926a-93804ab96733", EntryType: enum.Login}, keyring).Return(encryptedData, nil)
 				entryMock.Data = encryptedData
+				entryMock.Meta = nil
+				entryRepositoryMock.EXPECT().GetById(ctx, "ef77aba6-7ed4-421d-926a-93804ab96733").Return(testCurrentEntry, nil)
+				historyRepositoryMock.EXPECT().Push(ctx, testCurrentEntry, testHistorySize).Return(nil)
 				entryRepositoryMock.EXPECT().Edit(ctx, entryMock).Return(sharedErrors.ErrInternalError)
 			},
 			wantErr: sharedErrors.ErrInternalError,
 		},
 		{
-			name: "save entry not found error",
+			name: "entry not found error",
 			args: args{
 				ctx:     context.Background(),
 				command: editEntryCommand,
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.EditEntryCommand) {
-				masterPass := "12345"
+				keyring := testKeyring
 				dataInBytes := []byte("{\"login\": \"test\", \"password\": \"pass\"}")
 				entryMock := entity.Entry{
 					Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
@@ -377,15 +485,38 @@ func TestEntryService_Edit(t *testing.T) {
 					Data:      dataInBytes,
 					Meta:      []byte(""),
 				}
-				encryptedData := []byte("encrypted data")
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return(masterPass, nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(keyring, nil)
 				entryFactoryMock.EXPECT().CreateFromEditCmd(entryCommand).Return(entryMock, nil)
-				encryptorMock.EXPECT().Encrypt(dataInBytes, masterPass).Return(encryptedData, nil)
-				entryMock.Data = encryptedData
-				entryRepositoryMock.EXPECT().Edit(ctx, entryMock).Return(sharedErrors.ErrEntryNotFound)
+				encryptorMock.EXPECT().Encrypt(dataInBytes, encoder.AssociatedData{EntryId: "ef77aba6-7ed4-421d-926a-93804ab96733", EntryType: enum.Login}, keyring).Return([]byte("encrypted data"), nil)
+				entryRepositoryMock.EXPECT().GetById(ctx, "ef77aba6-7ed4-421d-926a-93804ab96733").Return(entity.Entry{}, sharedErrors.ErrEntryNotFound)
 			},
 			wantErr: sharedErrors.ErrEntryNotFound,
 		},
+		{
+			name: "save revision internal error",
+			args: args{
+				ctx:     context.Background(),
+				command: editEntryCommand,
+			},
+			mockBehaviour: func(ctx context.Context, entryCommand command.EditEntryCommand) {
+				keyring := testKeyring
+				dataInBytes := []byte("{\"login\": \"test\", \"password\": \"pass\"}")
+				entryMock := entity.Entry{
+					Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
+					EntryType: enum.Login,
+					UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+					IsDeleted: false,
+					Data:      dataInBytes,
+					Meta:      []byte(""),
+				}
+				secretRepositoryMock.EXPECT().GetKeyring().Return(keyring, nil)
+				entryFactoryMock.EXPECT().CreateFromEditCmd(entryCommand).Return(entryMock, nil)
+				encryptorMock.EXPECT().Encrypt(dataInBytes, encoder.AssociatedData{EntryId: "ef77aba6-7ed4-421d-926a-93804ab96733", EntryType: enum.Login}, keyring).Return([]byte("encrypted data"), nil)
+				entryRepositoryMock.EXPECT().GetById(ctx, "ef77aba6-7ed4-421d-926a-93804ab96733").Return(testCurrentEntry, nil)
+				historyRepositoryMock.EXPECT().Push(ctx, testCurrentEntry, testHistorySize).Return(sharedErrors.ErrInternalError)
+			},
+			wantErr: sharedErrors.ErrInternalError,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -393,9 +524,11 @@ func TestEntryService_Edit(t *testing.T) {
 			l := NewEntryService(
 				entryFactoryMock,
 				entryRepositoryMock,
+				historyRepositoryMock,
 				secretRepositoryMock,
 				encryptorMock,
 				extRepositoryMock,
+				testHistorySize,
 				loggerMock,
 			)
 			got, err := l.Edit(tt.args.ctx, tt.args.command)
@@ -417,6 +550,7 @@ func TestEntryService_Delete(t *testing.T) {
 
 	entryFactoryMock := mock_entry_factory.NewMockEntryFactoryInterface(ctrl)
 	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	historyRepositoryMock := mock_entry_history_repository.NewMockEntryHistoryRepositoryInterface(ctrl)
 	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
 	encryptorMock := mock_data_encryptor.NewMockDataEncryptorInterface(ctrl)
 	extRepositoryMock := mock_entry_ext_repository.NewMockEntryExtRepositoryInterface(ctrl)
@@ -443,6 +577,7 @@ func TestEntryService_Delete(t *testing.T) {
 				},
 			},
 			mockBehaviour: func(ctx context.Context, command command.DeleteEntryCommand) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
 				dataInBytes := []byte("test data")
 				entryMock := entity.Entry{
 					Id:        "225de857-71c5-452f-96f7-ff385d808083",
@@ -453,14 +588,13 @@ func TestEntryService_Delete(t *testing.T) {
 					Meta:      []byte(""),
 				}
 				entryRepositoryMock.EXPECT().GetById(ctx, "225de857-71c5-452f-96f7-ff385d808083").Return(entryMock, nil)
-				entryRepositoryMock.EXPECT().Edit(ctx, entity.Entry{
-					Id:        "225de857-71c5-452f-96f7-ff385d808083",
-					EntryType: enum.Login,
-					UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
-					IsDeleted: true,
-					Data:      dataInBytes,
-					Meta:      []byte(""),
-				}).Return(nil)
+				entryRepositoryMock.EXPECT().Edit(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, entry entity.Entry) error {
+					// удаление - новое изменение, которое уйдет на сервер
+					assert.True(t, entry.IsDeleted)
+					assert.Equal(t, dataInBytes, entry.Data)
+					assert.True(t, entry.UpdatedAt.After(entryMock.UpdatedAt))
+					return nil
+				})
 			},
 			wantErr: nil,
 		},
@@ -474,7 +608,7 @@ func TestEntryService_Delete(t *testing.T) {
 				},
 			},
 			mockBehaviour: func(ctx context.Context, command command.DeleteEntryCommand) {
-
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
 				entryRepositoryMock.EXPECT().GetById(ctx, "225de857-71c5-452f-96f7-ff385d808083").Return(entity.Entry{}, sharedErrors.ErrEntryNotFound)
 			},
 			wantErr: sharedErrors.ErrEntryNotFound,
@@ -489,7 +623,7 @@ func TestEntryService_Delete(t *testing.T) {
 				},
 			},
 			mockBehaviour: func(ctx context.Context, command command.DeleteEntryCommand) {
-
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
 				entryRepositoryMock.EXPECT().GetById(ctx, "225de857-71c5-452f-96f7-ff385d808083").Return(entity.Entry{}, sharedErrors.ErrInternalError)
 			},
 			wantErr: sharedErrors.ErrInternalError,
@@ -504,7 +638,7 @@ func TestEntryService_Delete(t *testing.T) {
 				},
 			},
 			mockBehaviour: func(ctx context.Context, command command.DeleteEntryCommand) {
-
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
 				dataInBytes := []byte("test data")
 				entryMock := entity.Entry{
 					Id:        "225de857-71c5-452f-96f7-ff385d808083",
@@ -515,14 +649,7 @@ func TestEntryService_Delete(t *testing.T) {
 					Meta:      []byte(""),
 				}
 				entryRepositoryMock.EXPECT().GetById(ctx, "225de857-71c5-452f-96f7-ff385d808083").Return(entryMock, nil)
-				entryRepositoryMock.EXPECT().Edit(ctx, entity.Entry{
-					Id:        "225de857-71c5-452f-96f7-ff385d808083",
-					EntryType: enum.Login,
-					UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
-					IsDeleted: true,
-					Data:      dataInBytes,
-					Meta:      []byte(""),
-				}).Return(sharedErrors.ErrInternalError)
+				entryRepositoryMock.EXPECT().Edit(ctx, gomock.Any()).Return(sharedErrors.ErrInternalError)
 			},
 			wantErr: sharedErrors.ErrInternalError,
 		},
@@ -533,9 +660,11 @@ func TestEntryService_Delete(t *testing.T) {
 			l := NewEntryService(
 				entryFactoryMock,
 				entryRepositoryMock,
+				historyRepositoryMock,
 				secretRepositoryMock,
 				encryptorMock,
 				extRepositoryMock,
+				testHistorySize,
 				loggerMock,
 			)
 			err := l.Delete(tt.args.ctx, tt.args.command)
@@ -554,6 +683,7 @@ func TestEntryService_Detail(t *testing.T) {
 
 	entryFactoryMock := mock_entry_factory.NewMockEntryFactoryInterface(ctrl)
 	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	historyRepositoryMock := mock_entry_history_repository.NewMockEntryHistoryRepositoryInterface(ctrl)
 	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
 	encryptorMock := mock_data_encryptor.NewMockDataEncryptorInterface(ctrl)
 	extRepositoryMock := mock_entry_ext_repository.NewMockEntryExtRepositoryInterface(ctrl)
@@ -570,6 +700,8 @@ func TestEntryService_Detail(t *testing.T) {
 		mockBehaviour func(ctx context.Context, command command.DetailEntryCommand)
 		want          command_response.DetailEntryResponse
 		wantErr       error
+		// notWantErr - класс ошибки, в который ошибка не должна попасть
+		notWantErr error
 	}{
 		{
 			name: "success",
@@ -581,7 +713,7 @@ func TestEntryService_Detail(t *testing.T) {
 				},
 			},
 			mockBehaviour: func(ctx context.Context, command command.DetailEntryCommand) {
-				masterPass := "1234"
+				keyring := testKeyring
 				encryptedData := []byte("test data")
 				entryMock := entity.Entry{
 					Id:        "225de857-71c5-452f-96f7-ff385d808083",
@@ -589,12 +721,13 @@ func TestEntryService_Detail(t *testing.T) {
 					UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
 					IsDeleted: false,
 					Data:      encryptedData,
-					Meta:      []byte(""),
+					Meta:      []byte("encrypted meta"),
 				}
 				decryptedData := []byte("{\"login\": \"test\", \"password\": \"pass\"}")
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return(masterPass, nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(keyring, nil)
 				entryRepositoryMock.EXPECT().GetById(ctx, "225de857-71c5-452f-96f7-ff385d808083").Return(entryMock, nil)
-				encryptorMock.EXPECT().Decrypt(encryptedData, masterPass).Return(decryptedData, nil)
+				encryptorMock.EXPECT().Decrypt(encryptedData, encoder.AssociatedData{EntryId: "225de857-71c5-452f-96f7-ff385d808083", EntryType: enum.Login}, keyring).Return(decryptedData, nil)
+				encryptorMock.EXPECT().Decrypt([]byte("encrypted meta"), encoder.AssociatedData{EntryId: "225de857-71c5-452f-96f7-ff385d808083", EntryType: enum.Login, Field: "meta"}, keyring).Return([]byte("{\"site\": \"example.com\"}"), nil)
 			},
 			want: command_response.DetailEntryResponse{
 				Id:        "225de857-71c5-452f-96f7-ff385d808083",
@@ -605,7 +738,7 @@ func TestEntryService_Detail(t *testing.T) {
 					Login:    "test",
 					Password: "pass",
 				},
-				Meta: []byte(""),
+				Meta: []byte("{\"site\": \"example.com\"}"),
 			},
 			wantErr: nil,
 		},
@@ -619,9 +752,9 @@ func TestEntryService_Detail(t *testing.T) {
 				},
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.DetailEntryCommand) {
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return("", secret.ErrMasterPasswordNotFound)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(vaultEntity.Keyring{}, secret.ErrVaultLocked)
 			},
-			wantErr: secret.ErrMasterPasswordNotFound,
+			wantErr: secret.ErrVaultLocked,
 		},
 		{
 			name: "get master pass internal error",
@@ -633,7 +766,7 @@ func TestEntryService_Detail(t *testing.T) {
 				},
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.DetailEntryCommand) {
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return("", sharedErrors.ErrInternalError)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(vaultEntity.Keyring{}, sharedErrors.ErrInternalError)
 			},
 			wantErr: sharedErrors.ErrInternalError,
 		},
@@ -647,10 +780,11 @@ func TestEntryService_Detail(t *testing.T) {
 				},
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.DetailEntryCommand) {
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return("12345", nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
 				entryRepositoryMock.EXPECT().GetById(ctx, "225de857-71c5-452f-96f7-ff385d808083").Return(entity.Entry{}, sharedErrors.ErrEntryNotFound)
 			},
-			wantErr: sharedErrors.ErrEntryNotFound,
+			wantErr:    sharedErrors.ErrEntryNotFound,
+			notWantErr: sharedErrors.ErrInternalError,
 		},
 		{
 			name: "get by id internal error",
@@ -662,7 +796,7 @@ func TestEntryService_Detail(t *testing.T) {
 				},
 			},
 			mockBehaviour: func(ctx context.Context, entryCommand command.DetailEntryCommand) {
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return("12345", nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
 				entryRepositoryMock.EXPECT().GetById(ctx, "225de857-71c5-452f-96f7-ff385d808083").Return(entity.Entry{}, sharedErrors.ErrInternalError)
 			},
 			wantErr: sharedErrors.ErrInternalError,
@@ -677,7 +811,7 @@ func TestEntryService_Detail(t *testing.T) {
 				},
 			},
 			mockBehaviour: func(ctx context.Context, command command.DetailEntryCommand) {
-				masterPass := "1234"
+				keyring := testKeyring
 				encryptedData := []byte("test data")
 				entryMock := entity.Entry{
 					Id:        "225de857-71c5-452f-96f7-ff385d808083",
@@ -687,12 +821,38 @@ func TestEntryService_Detail(t *testing.T) {
 					Data:      encryptedData,
 					Meta:      []byte(""),
 				}
-				secretRepositoryMock.EXPECT().GetMasterPassword().Return(masterPass, nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(keyring, nil)
 				entryRepositoryMock.EXPECT().GetById(ctx, "225de857-71c5-452f-96f7-ff385d808083").Return(entryMock, nil)
-				encryptorMock.EXPECT().Decrypt(encryptedData, masterPass).Return(nil, sharedErrors.ErrInternalError)
+				encryptorMock.EXPECT().Decrypt(encryptedData, encoder.AssociatedData{EntryId: "225de857-71c5-452f-96f7-ff385d808083", EntryType: enum.Login}, keyring).Return(nil, sharedErrors.ErrInternalError)
 			},
 			wantErr: sharedErrors.ErrInternalError,
 		},
+		{
+			name: "decrypt corrupted entry error",
+			args: args{
+				ctx: context.Background(),
+				command: command.DetailEntryCommand{
+					Id:        "225de857-71c5-452f-96f7-ff385d808083",
+					EntryType: enum.Login,
+				},
+			},
+			mockBehaviour: func(ctx context.Context, command command.DetailEntryCommand) {
+				keyring := testKeyring
+				encryptedData := []byte("test data")
+				entryMock := entity.Entry{
+					Id:        "225de857-71c5-452f-96f7-ff385d808083",
+					EntryType: enum.Login,
+					UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+					IsDeleted: false,
+					Data:      encryptedData,
+					Meta:      []byte(""),
+				}
+				secretRepositoryMock.EXPECT().GetKeyring().Return(keyring, nil)
+				entryRepositoryMock.EXPECT().GetById(ctx, "225de857-71c5-452f-96f7-ff385d808083").Return(entryMock, nil)
+				encryptorMock.EXPECT().Decrypt(encryptedData, encoder.AssociatedData{EntryId: "225de857-71c5-452f-96f7-ff385d808083", EntryType: enum.Login}, keyring).Return(nil, sharedErrors.ErrCorruptedEntry)
+			},
+			wantErr: sharedErrors.ErrCorruptedEntry,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -700,9 +860,11 @@ func TestEntryService_Detail(t *testing.T) {
 			l := NewEntryService(
 				entryFactoryMock,
 				entryRepositoryMock,
+				historyRepositoryMock,
 				secretRepositoryMock,
 				encryptorMock,
 				extRepositoryMock,
+				testHistorySize,
 				loggerMock,
 			)
 			got, err := l.Detail(tt.args.ctx, tt.args.command)
@@ -711,6 +873,9 @@ func TestEntryService_Detail(t *testing.T) {
 					t.Errorf("Detail() error expectation: got = %v, want %v", err, tt.wantErr)
 				}
 			}
+			if tt.notWantErr != nil {
+				assert.NotErrorIs(t, err, tt.notWantErr)
+			}
 
 			if !assert.Equal(t, got, tt.want) {
 				t.Errorf("Detail() got = %v, want %v", got, tt.want)
@@ -725,6 +890,7 @@ func TestEntryService_List(t *testing.T) {
 
 	entryFactoryMock := mock_entry_factory.NewMockEntryFactoryInterface(ctrl)
 	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	historyRepositoryMock := mock_entry_history_repository.NewMockEntryHistoryRepositoryInterface(ctrl)
 	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
 	encryptorMock := mock_data_encryptor.NewMockDataEncryptorInterface(ctrl)
 	extRepositoryMock := mock_entry_ext_repository.NewMockEntryExtRepositoryInterface(ctrl)
@@ -732,7 +898,34 @@ func TestEntryService_List(t *testing.T) {
 	require.NoError(t, err)
 
 	type args struct {
-		ctx context.Context
+		ctx     context.Context
+		command command.ListEntryCommand
+	}
+	titledEntries := []entity.Entry{
+		{
+			Id:        "225de857-71c5-452f-96f7-ff385d808083",
+			EntryType: enum.Login,
+			UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+			Data:      []byte("data"),
+			Title:     []byte("encrypted mail"),
+		},
+		{
+			Id:        "60d016e5-eae1-49f6-bb00-7d4709a38f4c",
+			EntryType: enum.Card,
+			UpdatedAt: time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC),
+			Data:      []byte("data2"),
+			Title:     []byte("encrypted bank"),
+		},
+		{
+			Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
+			EntryType: enum.Text,
+			UpdatedAt: time.Date(2024, time.March, 11, 12, 0, 0, 0, time.UTC),
+			Data:      []byte("data3"),
+		},
+	}
+	expectTitles := func(mailErr error) {
+		encryptorMock.EXPECT().Decrypt([]byte("encrypted mail"), encoder.AssociatedData{EntryId: "225de857-71c5-452f-96f7-ff385d808083", EntryType: enum.Login, Field: "title"}, testKeyring).Return([]byte("Mail"), mailErr)
+		encryptorMock.EXPECT().Decrypt([]byte("encrypted bank"), encoder.AssociatedData{EntryId: "60d016e5-eae1-49f6-bb00-7d4709a38f4c", EntryType: enum.Card, Field: "title"}, testKeyring).Return([]byte("bank"), nil)
 	}
 	var tests = []struct {
 		name          string
@@ -745,6 +938,7 @@ func TestEntryService_List(t *testing.T) {
 			name: "success",
 			args: args{ctx: context.Background()},
 			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
 				entryRepositoryMock.EXPECT().GetList(ctx).Return([]entity.Entry{
 					{
 						Id:        "225de857-71c5-452f-96f7-ff385d808083",
@@ -762,6 +956,14 @@ func TestEntryService_List(t *testing.T) {
 						Data:      []byte("data2"),
 						Meta:      []byte(""),
 					},
+					{
+						Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
+						EntryType: enum.Login,
+						UpdatedAt: time.Date(2024, time.March, 11, 12, 0, 0, 0, time.UTC),
+						IsDeleted: true,
+						Data:      []byte("data3"),
+						Meta:      []byte(""),
+					},
 				}, nil)
 			},
 			want: []command_response.ListEntryCommandResponse{
@@ -779,10 +981,97 @@ func TestEntryService_List(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "success sorted by title with limit",
+			args: args{ctx: context.Background(), command: command.ListEntryCommand{SortBy: command.ListSortTitle, Limit: 2}},
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().GetList(ctx).Return(titledEntries, nil)
+				expectTitles(nil)
+			},
+			want: []command_response.ListEntryCommandResponse{
+				{
+					Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
+					EntryType: enum.Text,
+					UpdatedAt: time.Date(2024, time.March, 11, 12, 0, 0, 0, time.UTC),
+				},
+				{
+					Id:        "60d016e5-eae1-49f6-bb00-7d4709a38f4c",
+					EntryType: enum.Card,
+					Title:     "bank",
+					UpdatedAt: time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "success sorted by updatedAt desc",
+			args: args{ctx: context.Background(), command: command.ListEntryCommand{SortBy: command.ListSortUpdatedAt, Desc: true}},
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().GetList(ctx).Return(titledEntries, nil)
+				expectTitles(nil)
+			},
+			want: []command_response.ListEntryCommandResponse{
+				{
+					Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
+					EntryType: enum.Text,
+					UpdatedAt: time.Date(2024, time.March, 11, 12, 0, 0, 0, time.UTC),
+				},
+				{
+					Id:        "225de857-71c5-452f-96f7-ff385d808083",
+					EntryType: enum.Login,
+					Title:     "Mail",
+					UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+				},
+				{
+					Id:        "60d016e5-eae1-49f6-bb00-7d4709a38f4c",
+					EntryType: enum.Card,
+					Title:     "bank",
+					UpdatedAt: time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "success title decrypt error keeps entry without title",
+			args: args{ctx: context.Background(), command: command.ListEntryCommand{SortBy: command.ListSortUpdatedAt}},
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().GetList(ctx).Return(titledEntries, nil)
+				expectTitles(errors.New("decrypt error"))
+			},
+			want: []command_response.ListEntryCommandResponse{
+				{
+					Id:        "60d016e5-eae1-49f6-bb00-7d4709a38f4c",
+					EntryType: enum.Card,
+					Title:     "bank",
+					UpdatedAt: time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC),
+				},
+				{
+					Id:        "225de857-71c5-452f-96f7-ff385d808083",
+					EntryType: enum.Login,
+					UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+				},
+				{
+					Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
+					EntryType: enum.Text,
+					UpdatedAt: time.Date(2024, time.March, 11, 12, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "vault locked error",
+			args: args{ctx: context.Background()},
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(vaultEntity.Keyring{}, secret.ErrVaultLocked)
+			},
+			want:    nil,
+			wantErr: secret.ErrVaultLocked,
+		},
 		{
 			name: "get list internal error",
 			args: args{ctx: context.Background()},
 			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
 				entryRepositoryMock.EXPECT().GetList(ctx).Return(nil, sharedErrors.ErrInternalError)
 			},
 			want:    nil,
@@ -795,12 +1084,14 @@ func TestEntryService_List(t *testing.T) {
 			l := NewEntryService(
 				entryFactoryMock,
 				entryRepositoryMock,
+				historyRepositoryMock,
 				secretRepositoryMock,
 				encryptorMock,
 				extRepositoryMock,
+				testHistorySize,
 				loggerMock,
 			)
-			got, err := l.List(tt.args.ctx)
+			got, err := l.List(tt.args.ctx, tt.args.command)
 			if tt.wantErr != nil {
 				if !errors.Is(err, tt.wantErr) {
 					t.Errorf("List() error expectation: got = %v, want %v", err, tt.wantErr)
@@ -813,37 +1104,29 @@ func TestEntryService_List(t *testing.T) {
 	}
 }
 
-func TestEntryService_Sync(t *testing.T) {
+func TestEntryService_Trash(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	entryFactoryMock := mock_entry_factory.NewMockEntryFactoryInterface(ctrl)
 	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	historyRepositoryMock := mock_entry_history_repository.NewMockEntryHistoryRepositoryInterface(ctrl)
 	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
 	encryptorMock := mock_data_encryptor.NewMockDataEncryptorInterface(ctrl)
 	extRepositoryMock := mock_entry_ext_repository.NewMockEntryExtRepositoryInterface(ctrl)
 	loggerMock, err := logger.Initialize("info")
 	require.NoError(t, err)
 
-	type args struct {
-		ctx     context.Context
-		command command.SyncEntryCommand
-	}
 	tests := []struct {
 		name          string
-		args          args
-		mockBehaviour func(ctx context.Context, command command.SyncEntryCommand)
+		mockBehaviour func(ctx context.Context)
+		want          []command_response.ListEntryCommandResponse
 		wantErr       error
 	}{
 		{
 			name: "success",
-			args: args{
-				ctx:     context.Background(),
-				command: command.SyncEntryCommand{EntryType: enum.Login},
-			},
-			mockBehaviour: func(ctx context.Context, command command.SyncEntryCommand) {
-				authToken := "cn8ewjf942tr49fehceo"
-				secretRepositoryMock.EXPECT().GetAuthToken().Return(authToken, nil)
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
 				entryRepositoryMock.EXPECT().GetList(ctx).Return([]entity.Entry{
 					{
 						Id:        "225de857-71c5-452f-96f7-ff385d808083",
@@ -851,32 +1134,403 @@ func TestEntryService_Sync(t *testing.T) {
 						UpdatedAt: time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC),
 						IsDeleted: false,
 						Data:      []byte("data"),
-						Meta:      []byte(""),
 					},
 					{
-						Id:        "60d016e5-eae1-49f6-bb00-7d4709a38f4c",
+						Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
 						EntryType: enum.Login,
-						UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
-						IsDeleted: false,
+						UpdatedAt: time.Date(2024, time.March, 11, 12, 0, 0, 0, time.UTC),
+						IsDeleted: true,
 						Data:      []byte("data2"),
-						Meta:      []byte(""),
 					},
 				}, nil)
+			},
+			want: []command_response.ListEntryCommandResponse{
+				{
+					Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
+					EntryType: enum.Login,
+					UpdatedAt: time.Date(2024, time.March, 11, 12, 0, 0, 0, time.UTC),
+					IsDeleted: true,
+				},
+			},
+		},
+		{
+			name: "vault locked error",
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(vaultEntity.Keyring{}, secret.ErrVaultLocked)
+			},
+			wantErr: secret.ErrVaultLocked,
+		},
+		{
+			name: "get list internal error",
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().GetList(ctx).Return(nil, sharedErrors.ErrInternalError)
+			},
+			wantErr: sharedErrors.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tt.mockBehaviour(ctx)
+			l := NewEntryService(
+				entryFactoryMock,
+				entryRepositoryMock,
+				historyRepositoryMock,
+				secretRepositoryMock,
+				encryptorMock,
+				extRepositoryMock,
+				testHistorySize,
+				loggerMock,
+			)
+			got, err := l.Trash(ctx)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
 
-				base64EncodedData1 := base64.StdEncoding.EncodeToString([]byte("data"))
-				base64EncodedData2 := base64.StdEncoding.EncodeToString([]byte("data2"))
+func TestEntryService_Find(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-				syncResponse := entry_ext.SyncResponse{
-					Items: []entry_ext.SyncResponseItem{
-						{
-							OriginalId: "225de857-71c5-452f-96f7-ff385d808083",
-							UpdatedAt:  time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC),
-							Data:       base64EncodedData1,
-							Meta:       []byte(""),
-						},
-						{
-							OriginalId: "60d016e5-eae1-49f6-bb00-7d4709a38f4c",
-							UpdatedAt:  time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+	entryFactoryMock := mock_entry_factory.NewMockEntryFactoryInterface(ctrl)
+	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	historyRepositoryMock := mock_entry_history_repository.NewMockEntryHistoryRepositoryInterface(ctrl)
+	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
+	encryptorMock := mock_data_encryptor.NewMockDataEncryptorInterface(ctrl)
+	extRepositoryMock := mock_entry_ext_repository.NewMockEntryExtRepositoryInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	entries := []entity.Entry{
+		{
+			Id:        "225de857-71c5-452f-96f7-ff385d808083",
+			EntryType: enum.Login,
+			UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+			Data:      []byte("encrypted mail data"),
+			Title:     []byte("encrypted mail title"),
+		},
+		{
+			Id:        "60d016e5-eae1-49f6-bb00-7d4709a38f4c",
+			EntryType: enum.Login,
+			UpdatedAt: time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC),
+			Data:      []byte("encrypted forum data"),
+			Meta:      []byte("encrypted forum meta"),
+		},
+		{
+			Id:        "ef77aba6-7ed4-421d-926a-93804ab96733",
+			EntryType: enum.Login,
+			UpdatedAt: time.Date(2024, time.March, 11, 12, 0, 0, 0, time.UTC),
+			IsDeleted: true,
+			Data:      []byte("encrypted deleted data"),
+		},
+	}
+	expectDecrypt := func(mailErr error) {
+		mail := encoder.AssociatedData{EntryId: "225de857-71c5-452f-96f7-ff385d808083", EntryType: enum.Login}
+		encryptorMock.EXPECT().Decrypt([]byte("encrypted mail data"), mail, testKeyring).Return([]byte(`{"login": "mailuser", "password": "mail"}`), mailErr)
+		if mailErr == nil {
+			mail.Field = "title"
+			encryptorMock.EXPECT().Decrypt([]byte("encrypted mail title"), mail, testKeyring).Return([]byte("Mail"), nil)
+		}
+		forum := encoder.AssociatedData{EntryId: "60d016e5-eae1-49f6-bb00-7d4709a38f4c", EntryType: enum.Login}
+		encryptorMock.EXPECT().Decrypt([]byte("encrypted forum data"), forum, testKeyring).Return([]byte(`{"login": "user", "password": "pass"}`), nil)
+		forum.Field = "meta"
+		encryptorMock.EXPECT().Decrypt([]byte("encrypted forum meta"), forum, testKeyring).Return([]byte(`{"site": "mail.example.com"}`), nil)
+	}
+
+	tests := []struct {
+		name          string
+		command       command.FindEntryCommand
+		mockBehaviour func(ctx context.Context)
+		want          []command_response.FindEntryResponse
+		wantErr       error
+	}{
+		{
+			name:    "success best match first",
+			command: command.FindEntryCommand{Query: "mail"},
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().GetList(ctx).Return(entries, nil)
+				expectDecrypt(nil)
+			},
+			want: []command_response.FindEntryResponse{
+				{
+					Id:           "225de857-71c5-452f-96f7-ff385d808083",
+					EntryType:    enum.Login,
+					Title:        "Mail",
+					UpdatedAt:    time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+					MatchedField: "title",
+					Score:        search.Score("mail", "Mail"),
+				},
+				{
+					Id:           "60d016e5-eae1-49f6-bb00-7d4709a38f4c",
+					EntryType:    enum.Login,
+					UpdatedAt:    time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC),
+					MatchedField: "meta",
+					Score:        search.Score("mail", "mail.example.com"),
+				},
+			},
+		},
+		{
+			name:    "success limit",
+			command: command.FindEntryCommand{Query: "mail", Limit: 1},
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().GetList(ctx).Return(entries, nil)
+				expectDecrypt(nil)
+			},
+			want: []command_response.FindEntryResponse{
+				{
+					Id:           "225de857-71c5-452f-96f7-ff385d808083",
+					EntryType:    enum.Login,
+					Title:        "Mail",
+					UpdatedAt:    time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+					MatchedField: "title",
+					Score:        search.Score("mail", "Mail"),
+				},
+			},
+		},
+		{
+			name:    "success corrupted entry skipped",
+			command: command.FindEntryCommand{Query: "mail"},
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().GetList(ctx).Return(entries, nil)
+				expectDecrypt(sharedErrors.ErrCorruptedEntry)
+			},
+			want: []command_response.FindEntryResponse{
+				{
+					Id:           "60d016e5-eae1-49f6-bb00-7d4709a38f4c",
+					EntryType:    enum.Login,
+					UpdatedAt:    time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC),
+					MatchedField: "meta",
+					Score:        search.Score("mail", "mail.example.com"),
+				},
+			},
+		},
+		{
+			name:    "success password not searched",
+			command: command.FindEntryCommand{Query: "pass"},
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().GetList(ctx).Return(entries, nil)
+				expectDecrypt(nil)
+			},
+			want: []command_response.FindEntryResponse{},
+		},
+		{
+			name:    "vault locked error",
+			command: command.FindEntryCommand{Query: "mail"},
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(vaultEntity.Keyring{}, secret.ErrVaultLocked)
+			},
+			wantErr: secret.ErrVaultLocked,
+		},
+		{
+			name:    "get list internal error",
+			command: command.FindEntryCommand{Query: "mail"},
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().GetList(ctx).Return(nil, sharedErrors.ErrInternalError)
+			},
+			wantErr: sharedErrors.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tt.mockBehaviour(ctx)
+			l := NewEntryService(
+				entryFactoryMock,
+				entryRepositoryMock,
+				historyRepositoryMock,
+				secretRepositoryMock,
+				encryptorMock,
+				extRepositoryMock,
+				testHistorySize,
+				loggerMock,
+			)
+			got, err := l.Find(ctx, tt.command)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEntryService_Restore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entryFactoryMock := mock_entry_factory.NewMockEntryFactoryInterface(ctrl)
+	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	historyRepositoryMock := mock_entry_history_repository.NewMockEntryHistoryRepositoryInterface(ctrl)
+	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
+	encryptorMock := mock_data_encryptor.NewMockDataEncryptorInterface(ctrl)
+	extRepositoryMock := mock_entry_ext_repository.NewMockEntryExtRepositoryInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	id := "ef77aba6-7ed4-421d-926a-93804ab96733"
+	deletedEntry := entity.Entry{
+		Id:        id,
+		EntryType: enum.Login,
+		UpdatedAt: time.Date(2024, time.March, 11, 12, 0, 0, 0, time.UTC),
+		IsDeleted: true,
+		Data:      []byte("encrypted data"),
+	}
+
+	tests := []struct {
+		name          string
+		mockBehaviour func(ctx context.Context)
+		wantErr       error
+	}{
+		{
+			name: "success",
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().GetById(ctx, id).Return(deletedEntry, nil)
+				entryRepositoryMock.EXPECT().Edit(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, entry entity.Entry) error {
+					// восстановление - новое изменение, которое уйдет на сервер
+					assert.False(t, entry.IsDeleted)
+					assert.Equal(t, deletedEntry.Data, entry.Data)
+					assert.True(t, entry.UpdatedAt.After(deletedEntry.UpdatedAt))
+					return nil
+				})
+			},
+		},
+		{
+			name: "vault locked error",
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(vaultEntity.Keyring{}, secret.ErrVaultLocked)
+			},
+			wantErr: secret.ErrVaultLocked,
+		},
+		{
+			name: "entry not found error",
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().GetById(ctx, id).Return(entity.Entry{}, sharedErrors.ErrEntryNotFound)
+			},
+			wantErr: sharedErrors.ErrEntryNotFound,
+		},
+		{
+			name: "entry not in trash error",
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().GetById(ctx, id).Return(testCurrentEntry, nil)
+			},
+			wantErr: entryErrors.ErrEntryNotInTrash,
+		},
+		{
+			name: "edit internal error",
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().GetById(ctx, id).Return(deletedEntry, nil)
+				entryRepositoryMock.EXPECT().Edit(ctx, gomock.Any()).Return(errors.New("error"))
+			},
+			wantErr: sharedErrors.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tt.mockBehaviour(ctx)
+			l := NewEntryService(
+				entryFactoryMock,
+				entryRepositoryMock,
+				historyRepositoryMock,
+				secretRepositoryMock,
+				encryptorMock,
+				extRepositoryMock,
+				testHistorySize,
+				loggerMock,
+			)
+			err := l.Restore(ctx, command.RestoreEntryCommand{Id: id, EntryType: enum.Login})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestEntryService_Sync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entryFactoryMock := mock_entry_factory.NewMockEntryFactoryInterface(ctrl)
+	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	historyRepositoryMock := mock_entry_history_repository.NewMockEntryHistoryRepositoryInterface(ctrl)
+	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
+	encryptorMock := mock_data_encryptor.NewMockDataEncryptorInterface(ctrl)
+	extRepositoryMock := mock_entry_ext_repository.NewMockEntryExtRepositoryInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	type args struct {
+		ctx     context.Context
+		command command.SyncEntryCommand
+	}
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour func(ctx context.Context, command command.SyncEntryCommand)
+		wantErr       error
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:     context.Background(),
+				command: command.SyncEntryCommand{EntryType: enum.Login},
+			},
+			mockBehaviour: func(ctx context.Context, command command.SyncEntryCommand) {
+				authToken := "cn8ewjf942tr49fehceo"
+				secretRepositoryMock.EXPECT().GetAuthToken().Return(authToken, nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
+				entryRepositoryMock.EXPECT().GetList(ctx).Return([]entity.Entry{
+					{
+						Id:        "225de857-71c5-452f-96f7-ff385d808083",
+						EntryType: enum.Login,
+						UpdatedAt: time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC),
+						IsDeleted: false,
+						Data:      []byte("data"),
+						Meta:      []byte(""),
+					},
+					{
+						Id:        "60d016e5-eae1-49f6-bb00-7d4709a38f4c",
+						EntryType: enum.Login,
+						UpdatedAt: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+						IsDeleted: false,
+						Data:      []byte("data2"),
+						Meta:      []byte(""),
+					},
+				}, nil)
+
+				base64EncodedData1 := base64.StdEncoding.EncodeToString([]byte("data"))
+				base64EncodedData2 := base64.StdEncoding.EncodeToString([]byte("data2"))
+
+				syncResponse := entry_ext.SyncResponse{
+					Items: []entry_ext.SyncResponseItem{
+						{
+							OriginalId: "225de857-71c5-452f-96f7-ff385d808083",
+							UpdatedAt:  time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC),
+							Data:       base64EncodedData1,
+							Meta:       []byte(""),
+						},
+						{
+							OriginalId: "60d016e5-eae1-49f6-bb00-7d4709a38f4c",
+							UpdatedAt:  time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
 							Data:       base64EncodedData2,
 							Meta:       []byte(""),
 						},
@@ -884,21 +1538,22 @@ func TestEntryService_Sync(t *testing.T) {
 					SyncType: enum.Login,
 				}
 				syncRequestMock := entry_ext.SyncRequest{
-					SyncType: enum.Login,
+					SyncType:  enum.Login,
+					WithTrash: true,
 					Items: []entry_ext.SyncRequestItem{
 						{
 							OriginalId: "225de857-71c5-452f-96f7-ff385d808083",
 							UpdatedAt:  time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC),
 							IsDeleted:  false,
 							Data:       base64EncodedData1,
-							Meta:       []byte(""),
+							Meta:       entry_ext.NewSyncMeta(nil, nil, nil),
 						},
 						{
 							OriginalId: "60d016e5-eae1-49f6-bb00-7d4709a38f4c",
 							UpdatedAt:  time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
 							IsDeleted:  false,
 							Data:       base64EncodedData2,
-							Meta:       []byte(""),
+							Meta:       entry_ext.NewSyncMeta(nil, nil, nil),
 						},
 					},
 				}
@@ -922,7 +1577,7 @@ func TestEntryService_Sync(t *testing.T) {
 						Meta:      []byte(""),
 					},
 				}
-				entryFactoryMock.EXPECT().CreateFromSyncResponse(syncResponse).Return(newEntries, nil)
+				entryFactoryMock.EXPECT().CreateFromSyncResponse(syncResponse, false).Return(newEntries, nil)
 				entryRepositoryMock.EXPECT().Rewrite(ctx, newEntries).Return(nil)
 			},
 			wantErr: nil,
@@ -960,6 +1615,20 @@ func TestEntryService_Sync(t *testing.T) {
 			},
 			wantErr: sharedErrors.ErrInternalError,
 		},
+		{
+			name: "vault busy error",
+			args: args{
+				ctx:     context.Background(),
+				command: command.SyncEntryCommand{EntryType: enum.Login},
+			},
+			mockBehaviour: func(ctx context.Context, command command.SyncEntryCommand) {
+				authToken := "f982hf8hwie"
+				secretRepositoryMock.EXPECT().GetAuthToken().Return(authToken, nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(nil, sharedErrors.ErrVaultBusy)
+			},
+			wantErr: sharedErrors.ErrVaultBusy,
+		},
 		{
 			name: "get list internal error",
 			args: args{
@@ -969,6 +1638,8 @@ func TestEntryService_Sync(t *testing.T) {
 			mockBehaviour: func(ctx context.Context, command command.SyncEntryCommand) {
 				authToken := "f982hf8hwie"
 				secretRepositoryMock.EXPECT().GetAuthToken().Return(authToken, nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
 				entryRepositoryMock.EXPECT().GetList(ctx).Return(nil, errors.New("error"))
 			},
 			wantErr: sharedErrors.ErrInternalError,
@@ -982,6 +1653,8 @@ func TestEntryService_Sync(t *testing.T) {
 			mockBehaviour: func(ctx context.Context, command command.SyncEntryCommand) {
 				authToken := "f982hf8hwie"
 				secretRepositoryMock.EXPECT().GetAuthToken().Return(authToken, nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
 				entryRepositoryMock.EXPECT().GetList(ctx).Return([]entity.Entry{
 					{
 						Id:        "225de857-71c5-452f-96f7-ff385d808083",
@@ -1003,21 +1676,22 @@ func TestEntryService_Sync(t *testing.T) {
 				base64EncodedData1 := base64.StdEncoding.EncodeToString([]byte("data"))
 				base64EncodedData2 := base64.StdEncoding.EncodeToString([]byte("data2"))
 				syncRequestMock := entry_ext.SyncRequest{
-					SyncType: enum.Login,
+					SyncType:  enum.Login,
+					WithTrash: true,
 					Items: []entry_ext.SyncRequestItem{
 						{
 							OriginalId: "225de857-71c5-452f-96f7-ff385d808083",
 							UpdatedAt:  time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC),
 							IsDeleted:  false,
 							Data:       base64EncodedData1,
-							Meta:       []byte(""),
+							Meta:       entry_ext.NewSyncMeta(nil, nil, nil),
 						},
 						{
 							OriginalId: "60d016e5-eae1-49f6-bb00-7d4709a38f4c",
 							UpdatedAt:  time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
 							IsDeleted:  false,
 							Data:       base64EncodedData2,
-							Meta:       []byte(""),
+							Meta:       entry_ext.NewSyncMeta(nil, nil, nil),
 						},
 					},
 				}
@@ -1034,6 +1708,8 @@ func TestEntryService_Sync(t *testing.T) {
 			mockBehaviour: func(ctx context.Context, command command.SyncEntryCommand) {
 				authToken := "f982hf8hwie"
 				secretRepositoryMock.EXPECT().GetAuthToken().Return(authToken, nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
 				entryRepositoryMock.EXPECT().GetList(ctx).Return([]entity.Entry{
 					{
 						Id:        "225de857-71c5-452f-96f7-ff385d808083",
@@ -1072,26 +1748,27 @@ func TestEntryService_Sync(t *testing.T) {
 					SyncType: enum.Login,
 				}
 				syncRequestMock := entry_ext.SyncRequest{
-					SyncType: enum.Login,
+					SyncType:  enum.Login,
+					WithTrash: true,
 					Items: []entry_ext.SyncRequestItem{
 						{
 							OriginalId: "225de857-71c5-452f-96f7-ff385d808083",
 							UpdatedAt:  time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC),
 							IsDeleted:  false,
 							Data:       base64EncodedData1,
-							Meta:       []byte(""),
+							Meta:       entry_ext.NewSyncMeta(nil, nil, nil),
 						},
 						{
 							OriginalId: "60d016e5-eae1-49f6-bb00-7d4709a38f4c",
 							UpdatedAt:  time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
 							IsDeleted:  false,
 							Data:       base64EncodedData2,
-							Meta:       []byte(""),
+							Meta:       entry_ext.NewSyncMeta(nil, nil, nil),
 						},
 					},
 				}
 				extRepositoryMock.EXPECT().Sync(ctx, authToken, syncRequestMock).Return(syncResponse, nil)
-				entryFactoryMock.EXPECT().CreateFromSyncResponse(syncResponse).Return(nil, errors.New("error"))
+				entryFactoryMock.EXPECT().CreateFromSyncResponse(syncResponse, false).Return(nil, errors.New("error"))
 			},
 			wantErr: sharedErrors.ErrInternalError,
 		},
@@ -1104,6 +1781,8 @@ func TestEntryService_Sync(t *testing.T) {
 			mockBehaviour: func(ctx context.Context, command command.SyncEntryCommand) {
 				authToken := "f982hf8hwie"
 				secretRepositoryMock.EXPECT().GetAuthToken().Return(authToken, nil)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
 				entryRepositoryMock.EXPECT().GetList(ctx).Return([]entity.Entry{
 					{
 						Id:        "225de857-71c5-452f-96f7-ff385d808083",
@@ -1142,21 +1821,22 @@ func TestEntryService_Sync(t *testing.T) {
 					SyncType: enum.Login,
 				}
 				syncRequestMock := entry_ext.SyncRequest{
-					SyncType: enum.Login,
+					SyncType:  enum.Login,
+					WithTrash: true,
 					Items: []entry_ext.SyncRequestItem{
 						{
 							OriginalId: "225de857-71c5-452f-96f7-ff385d808083",
 							UpdatedAt:  time.Date(2023, time.March, 10, 12, 0, 0, 0, time.UTC),
 							IsDeleted:  false,
 							Data:       base64EncodedData1,
-							Meta:       []byte(""),
+							Meta:       entry_ext.NewSyncMeta(nil, nil, nil),
 						},
 						{
 							OriginalId: "60d016e5-eae1-49f6-bb00-7d4709a38f4c",
 							UpdatedAt:  time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
 							IsDeleted:  false,
 							Data:       base64EncodedData2,
-							Meta:       []byte(""),
+							Meta:       entry_ext.NewSyncMeta(nil, nil, nil),
 						},
 					},
 				}
@@ -1179,7 +1859,7 @@ func TestEntryService_Sync(t *testing.T) {
 						Meta:      []byte(""),
 					},
 				}
-				entryFactoryMock.EXPECT().CreateFromSyncResponse(syncResponse).Return(newEntries, nil)
+				entryFactoryMock.EXPECT().CreateFromSyncResponse(syncResponse, false).Return(newEntries, nil)
 				entryRepositoryMock.EXPECT().Rewrite(ctx, newEntries).Return(errors.New("error"))
 			},
 			wantErr: sharedErrors.ErrInternalError,
@@ -1191,9 +1871,11 @@ func TestEntryService_Sync(t *testing.T) {
 			l := NewEntryService(
 				entryFactoryMock,
 				entryRepositoryMock,
+				historyRepositoryMock,
 				secretRepositoryMock,
 				encryptorMock,
 				extRepositoryMock,
+				testHistorySize,
 				loggerMock,
 			)
 			err := l.Sync(tt.args.ctx, tt.args.command)
@@ -1205,3 +1887,182 @@ func TestEntryService_Sync(t *testing.T) {
 		})
 	}
 }
+
+func TestEntryService_History(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entryFactoryMock := mock_entry_factory.NewMockEntryFactoryInterface(ctrl)
+	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	historyRepositoryMock := mock_entry_history_repository.NewMockEntryHistoryRepositoryInterface(ctrl)
+	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
+	encryptorMock := mock_data_encryptor.NewMockDataEncryptorInterface(ctrl)
+	extRepositoryMock := mock_entry_ext_repository.NewMockEntryExtRepositoryInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	historyCommand := command.HistoryEntryCommand{Id: "ef77aba6-7ed4-421d-926a-93804ab96733", EntryType: enum.Login}
+	tests := []struct {
+		name          string
+		mockBehaviour func(ctx context.Context)
+		want          []command_response.HistoryEntryResponse
+		wantErr       error
+	}{
+		{
+			name: "success",
+			mockBehaviour: func(ctx context.Context) {
+				olderEntry := testCurrentEntry
+				olderEntry.UpdatedAt = time.Date(2024, time.February, 1, 12, 0, 0, 0, time.UTC)
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				historyRepositoryMock.EXPECT().GetList(ctx, "ef77aba6-7ed4-421d-926a-93804ab96733").Return([]entity.Entry{testCurrentEntry, olderEntry}, nil)
+			},
+			want: []command_response.HistoryEntryResponse{
+				{Revision: 1, UpdatedAt: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)},
+				{Revision: 2, UpdatedAt: time.Date(2024, time.February, 1, 12, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "vault locked error",
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(vaultEntity.Keyring{}, secret.ErrVaultLocked)
+			},
+			wantErr: secret.ErrVaultLocked,
+		},
+		{
+			name: "get revisions internal error",
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				historyRepositoryMock.EXPECT().GetList(ctx, "ef77aba6-7ed4-421d-926a-93804ab96733").Return(nil, sharedErrors.ErrInternalError)
+			},
+			wantErr: sharedErrors.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tt.mockBehaviour(ctx)
+			l := NewEntryService(
+				entryFactoryMock,
+				entryRepositoryMock,
+				historyRepositoryMock,
+				secretRepositoryMock,
+				encryptorMock,
+				extRepositoryMock,
+				testHistorySize,
+				loggerMock,
+			)
+			got, err := l.History(ctx, historyCommand)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEntryService_Revert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entryFactoryMock := mock_entry_factory.NewMockEntryFactoryInterface(ctrl)
+	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	historyRepositoryMock := mock_entry_history_repository.NewMockEntryHistoryRepositoryInterface(ctrl)
+	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
+	encryptorMock := mock_data_encryptor.NewMockDataEncryptorInterface(ctrl)
+	extRepositoryMock := mock_entry_ext_repository.NewMockEntryExtRepositoryInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	id := "ef77aba6-7ed4-421d-926a-93804ab96733"
+	ad := encoder.AssociatedData{EntryId: id, EntryType: enum.Login}
+	revision := entity.Entry{
+		Id:        id,
+		EntryType: enum.Login,
+		UpdatedAt: time.Date(2024, time.February, 1, 12, 0, 0, 0, time.UTC),
+		IsDeleted: true,
+		Data:      []byte("revision encrypted data"),
+	}
+	decryptedData := []byte("{\"login\": \"old\", \"password\": \"old pass\"}")
+
+	tests := []struct {
+		name          string
+		revision      int
+		mockBehaviour func(ctx context.Context)
+		wantData      interface{}
+		wantErr       error
+	}{
+		{
+			name:     "success",
+			revision: 2,
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				historyRepositoryMock.EXPECT().GetList(ctx, id).Return([]entity.Entry{testCurrentEntry, revision}, nil)
+				encryptorMock.EXPECT().Decrypt([]byte("revision encrypted data"), ad, testKeyring).Return(decryptedData, nil)
+				encryptorMock.EXPECT().Encrypt(decryptedData, ad, testKeyring).Return([]byte("new encrypted data"), nil)
+				entryRepositoryMock.EXPECT().GetById(ctx, id).Return(testCurrentEntry, nil)
+				historyRepositoryMock.EXPECT().Push(ctx, testCurrentEntry, testHistorySize).Return(nil)
+				entryRepositoryMock.EXPECT().Edit(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, entry entity.Entry) error {
+					// восстановленная ревизия - новое изменение, которое уйдет на сервер
+					assert.Equal(t, []byte("new encrypted data"), entry.Data)
+					assert.False(t, entry.IsDeleted)
+					assert.True(t, entry.UpdatedAt.After(revision.UpdatedAt))
+					return nil
+				})
+			},
+			wantData: &dto.LoginData{Login: "old", Password: "old pass"},
+		},
+		{
+			name:     "revision not found error",
+			revision: 3,
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				historyRepositoryMock.EXPECT().GetList(ctx, id).Return([]entity.Entry{testCurrentEntry, revision}, nil)
+			},
+			wantErr: entryErrors.ErrRevisionNotFound,
+		},
+		{
+			name:     "vault locked error",
+			revision: 1,
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(vaultEntity.Keyring{}, secret.ErrVaultLocked)
+			},
+			wantErr: secret.ErrVaultLocked,
+		},
+		{
+			name:     "decrypt revision corrupted error",
+			revision: 2,
+			mockBehaviour: func(ctx context.Context) {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(testKeyring, nil)
+				historyRepositoryMock.EXPECT().GetList(ctx, id).Return([]entity.Entry{testCurrentEntry, revision}, nil)
+				encryptorMock.EXPECT().Decrypt([]byte("revision encrypted data"), ad, testKeyring).Return(nil, sharedErrors.ErrCorruptedEntry)
+			},
+			wantErr: sharedErrors.ErrCorruptedEntry,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tt.mockBehaviour(ctx)
+			l := NewEntryService(
+				entryFactoryMock,
+				entryRepositoryMock,
+				historyRepositoryMock,
+				secretRepositoryMock,
+				encryptorMock,
+				extRepositoryMock,
+				testHistorySize,
+				loggerMock,
+			)
+			got, err := l.Revert(ctx, command.RevertEntryCommand{Id: id, EntryType: enum.Login, Revision: tt.revision})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantData, got.Data)
+			assert.False(t, got.IsDeleted)
+		})
+	}
+}
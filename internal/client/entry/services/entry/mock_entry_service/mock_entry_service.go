@@ -8,10 +8,9 @@ import (
 	context "context"
 	reflect "reflect"
 
-	gomock "github.com/golang/mock/gomock"
-
 	command "github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
 	command_response "github.com/anoriar/gophkeeper/internal/client/entry/dto/command_response"
+	gomock "github.com/golang/mock/gomock"
 )
 
 // MockEntryServiceInterface is a mock of EntryServiceInterface interface.
@@ -96,19 +95,78 @@ func (mr *MockEntryServiceInterfaceMockRecorder) Edit(ctx, command interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockEntryServiceInterface)(nil).Edit), ctx, command)
 }
 
+// Find mocks base method.
+func (m *MockEntryServiceInterface) Find(ctx context.Context, command command.FindEntryCommand) ([]command_response.FindEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, command)
+	ret0, _ := ret[0].([]command_response.FindEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockEntryServiceInterfaceMockRecorder) Find(ctx, command interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockEntryServiceInterface)(nil).Find), ctx, command)
+}
+
+// History mocks base method.
+func (m *MockEntryServiceInterface) History(ctx context.Context, command command.HistoryEntryCommand) ([]command_response.HistoryEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, command)
+	ret0, _ := ret[0].([]command_response.HistoryEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockEntryServiceInterfaceMockRecorder) History(ctx, command interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockEntryServiceInterface)(nil).History), ctx, command)
+}
+
 // List mocks base method.
-func (m *MockEntryServiceInterface) List(ctx context.Context) ([]command_response.ListEntryCommandResponse, error) {
+func (m *MockEntryServiceInterface) List(ctx context.Context, command command.ListEntryCommand) ([]command_response.ListEntryCommandResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx)
+	ret := m.ctrl.Call(m, "List", ctx, command)
 	ret0, _ := ret[0].([]command_response.ListEntryCommandResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockEntryServiceInterfaceMockRecorder) List(ctx interface{}) *gomock.Call {
+func (mr *MockEntryServiceInterfaceMockRecorder) List(ctx, command interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockEntryServiceInterface)(nil).List), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockEntryServiceInterface)(nil).List), ctx, command)
+}
+
+// Restore mocks base method.
+func (m *MockEntryServiceInterface) Restore(ctx context.Context, command command.RestoreEntryCommand) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, command)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockEntryServiceInterfaceMockRecorder) Restore(ctx, command interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockEntryServiceInterface)(nil).Restore), ctx, command)
+}
+
+// Revert mocks base method.
+func (m *MockEntryServiceInterface) Revert(ctx context.Context, command command.RevertEntryCommand) (command_response.DetailEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revert", ctx, command)
+	ret0, _ := ret[0].(command_response.DetailEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revert indicates an expected call of Revert.
+func (mr *MockEntryServiceInterfaceMockRecorder) Revert(ctx, command interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockEntryServiceInterface)(nil).Revert), ctx, command)
 }
 
 // Sync mocks base method.
@@ -124,3 +182,18 @@ func (mr *MockEntryServiceInterfaceMockRecorder) Sync(ctx, command interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockEntryServiceInterface)(nil).Sync), ctx, command)
 }
+
+// Trash mocks base method.
+func (m *MockEntryServiceInterface) Trash(ctx context.Context) ([]command_response.ListEntryCommandResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trash", ctx)
+	ret0, _ := ret[0].([]command_response.ListEntryCommandResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Trash indicates an expected call of Trash.
+func (mr *MockEntryServiceInterfaceMockRecorder) Trash(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trash", reflect.TypeOf((*MockEntryServiceInterface)(nil).Trash), ctx)
+}
@@ -0,0 +1,123 @@
+package inject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto"
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command_response"
+	"github.com/anoriar/gophkeeper/internal/client/entry/repository/template"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/resolver"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+)
+
+type InjectService struct {
+	resolverService    resolver.ResolverServiceInterface
+	templateRepository template.TemplateRepositoryInterface
+	logger             *zap.Logger
+}
+
+func NewInjectService(resolverService resolver.ResolverServiceInterface, templateRepository template.TemplateRepositoryInterface, logger *zap.Logger) *InjectService {
+	return &InjectService{
+		resolverService:    resolverService,
+		templateRepository: templateRepository,
+		logger:             logger,
+	}
+}
+
+func (s *InjectService) Inject(ctx context.Context, command command.InjectCommand) (command_response.InjectResponse, error) {
+	content, err := s.templateRepository.Get(command.In)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return command_response.InjectResponse{}, err
+		}
+		s.logger.Error("get template error", zap.String("error", err.Error()))
+		return command_response.InjectResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+
+	placeholders := findPlaceholders(content)
+	// ошибки всех ссылок сразу, с номером строки шаблона: исправить шаблон за один проход
+	var errs []lineError
+	var references []dto.SecretReference
+	placeholderReferences := make([]dto.SecretReference, len(placeholders))
+	lines := make(map[dto.SecretReference]int)
+	for i, placeholder := range placeholders {
+		reference, err := dto.ParseSecretReference(placeholder.reference)
+		if err != nil {
+			errs = append(errs, lineError{line: placeholder.line, err: err})
+			continue
+		}
+		placeholderReferences[i] = reference
+		if _, ok := lines[reference]; !ok {
+			lines[reference] = placeholder.line
+			references = append(references, reference)
+		}
+	}
+	if len(errs) > 0 && !command.Check {
+		return command_response.InjectResponse{}, joinLineErrors(command.In, errs)
+	}
+
+	response := command_response.InjectResponse{References: make([]string, 0, len(references))}
+	for _, reference := range references {
+		response.References = append(response.References, reference.String())
+	}
+
+	if command.Check {
+		// каждая ссылка проверяется отдельно, чтобы сообщить обо всех неразрешенных, а не только о первой
+		for _, reference := range references {
+			_, err = s.resolverService.Resolve(ctx, []dto.SecretReference{reference})
+			if err != nil {
+				errs = append(errs, lineError{line: lines[reference], err: err})
+			}
+		}
+		if len(errs) > 0 {
+			return command_response.InjectResponse{}, joinLineErrors(command.In, errs)
+		}
+		return response, nil
+	}
+
+	values, err := s.resolverService.Resolve(ctx, references)
+	if err != nil {
+		return command_response.InjectResponse{}, err
+	}
+	result := make([]byte, 0, len(content))
+	offset := 0
+	for i, placeholder := range placeholders {
+		result = append(result, content[offset:placeholder.start]...)
+		result = append(result, values[placeholderReferences[i]]...)
+		offset = placeholder.end
+	}
+	result = append(result, content[offset:]...)
+
+	err = s.templateRepository.Save(command.Out, result)
+	if err != nil {
+		s.logger.Error("save injected template error", zap.String("error", err.Error()))
+		return command_response.InjectResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	response.FileName = command.Out
+	return response, nil
+}
+
+// lineError ошибка ссылки на строке шаблона
+type lineError struct {
+	line int
+	err  error
+}
+
+// joinLineErrors ошибки по порядку строк в виде file:line: error, errors.Is работает для каждой
+func joinLineErrors(fileName string, lineErrs []lineError) error {
+	sort.SliceStable(lineErrs, func(i, j int) bool {
+		return lineErrs[i].line < lineErrs[j].line
+	})
+	errs := make([]error, 0, len(lineErrs))
+	for _, lineErr := range lineErrs {
+		errs = append(errs, fmt.Errorf("%s:%d: %w", fileName, lineErr.line, lineErr.err))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,15 @@
+package inject
+
+import (
+	"context"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command_response"
+)
+
+//go:generate mockgen -source=inject_service_interface.go -destination=mock_inject_service/mock_inject_service.go -package=mock_inject_service
+type InjectServiceInterface interface {
+	// Inject подставляет в шаблон расшифрованные значения записей локального хранилища и записывает результат с правами 0600.
+	// С Check только проверяет все ссылки шаблона и сообщает о каждой неразрешенной
+	Inject(ctx context.Context, command command.InjectCommand) (command_response.InjectResponse, error)
+}
@@ -0,0 +1,182 @@
+package inject
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto"
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command_response"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	entryErrors "github.com/anoriar/gophkeeper/internal/client/entry/errors"
+	"github.com/anoriar/gophkeeper/internal/client/entry/repository/template/mock_template_repository"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/resolver/mock_resolver_service"
+	"github.com/anoriar/gophkeeper/internal/client/shared/app/logger"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+)
+
+func TestInjectService_Inject(t *testing.T) {
+	passwordReference := dto.SecretReference{EntryType: enum.Login, Id: "1", Field: "password"}
+	numberReference := dto.SecretReference{EntryType: enum.Card, Id: "2", Field: "number"}
+	textReference := dto.SecretReference{EntryType: enum.Text, Id: "3", Field: dto.SecretFieldData}
+
+	template := "db:\n  password: {{ gk \"login/1/password\" }}\n  dsn: postgres://app:gk://login/1/password@db/app\n" +
+		"card: {{gk \"card/2/number\"}}\ncert: |\n  gk://text/3.\n"
+	injected := "db:\n  password: pa ss\n  dsn: postgres://app:pa ss@db/app\n" +
+		"card: 4111111111111111\ncert: |\n  line1\nline2.\n"
+	values := map[dto.SecretReference]string{
+		passwordReference: "pa ss",
+		numberReference:   "4111111111111111",
+		textReference:     "line1\nline2",
+	}
+
+	type resolveCall struct {
+		references []dto.SecretReference
+		values     map[dto.SecretReference]string
+		err        error
+	}
+	tests := []struct {
+		name         string
+		command      command.InjectCommand
+		template     string
+		getErr       error
+		resolveCalls []resolveCall
+		wantSaved    string
+		want         command_response.InjectResponse
+		wantErrs     []error
+	}{
+		{
+			name:     "success both placeholder syntaxes",
+			command:  command.InjectCommand{In: "config.tmpl", Out: "config.yaml"},
+			template: template,
+			resolveCalls: []resolveCall{
+				{references: []dto.SecretReference{passwordReference, numberReference, textReference}, values: values},
+			},
+			wantSaved: injected,
+			want: command_response.InjectResponse{
+				FileName:   "config.yaml",
+				References: []string{"login/1/password", "card/2/number", "text/3/data"},
+			},
+		},
+		{
+			name:     "success template without placeholders",
+			command:  command.InjectCommand{In: "config.tmpl", Out: "config.yaml"},
+			template: "plain: value\n",
+			resolveCalls: []resolveCall{
+				{references: nil, values: map[dto.SecretReference]string{}},
+			},
+			wantSaved: "plain: value\n",
+			want:      command_response.InjectResponse{FileName: "config.yaml", References: []string{}},
+		},
+		{
+			name:     "success check without save",
+			command:  command.InjectCommand{In: "config.tmpl", Check: true},
+			template: template,
+			resolveCalls: []resolveCall{
+				{references: []dto.SecretReference{passwordReference}, values: values},
+				{references: []dto.SecretReference{numberReference}, values: values},
+				{references: []dto.SecretReference{textReference}, values: values},
+			},
+			want: command_response.InjectResponse{References: []string{"login/1/password", "card/2/number", "text/3/data"}},
+		},
+		{
+			name:     "check reports every not resolved reference",
+			command:  command.InjectCommand{In: "config.tmpl", Check: true},
+			template: template,
+			resolveCalls: []resolveCall{
+				{references: []dto.SecretReference{passwordReference}, err: entryErrors.ErrSecretFieldNotFound},
+				{references: []dto.SecretReference{numberReference}, values: values},
+				{references: []dto.SecretReference{textReference}, err: sharedErrors.ErrEntryNotFound},
+			},
+			wantErrs: []error{entryErrors.ErrSecretFieldNotFound, sharedErrors.ErrEntryNotFound},
+		},
+		{
+			name:     "not valid reference error",
+			command:  command.InjectCommand{In: "config.tmpl", Out: "config.yaml"},
+			template: "a: {{ gk \"login/1\" }}\nb: {{ gk \"note/1/data\" }}\n",
+			wantErrs: []error{entryErrors.ErrSecretReferenceNotValid},
+		},
+		{
+			name:     "resolve error without save",
+			command:  command.InjectCommand{In: "config.tmpl", Out: "config.yaml"},
+			template: template,
+			resolveCalls: []resolveCall{
+				{references: []dto.SecretReference{passwordReference, numberReference, textReference}, err: sharedErrors.ErrNotLoggedIn},
+			},
+			wantErrs: []error{sharedErrors.ErrNotLoggedIn},
+		},
+		{
+			name:     "template not found error",
+			command:  command.InjectCommand{In: "config.tmpl", Out: "config.yaml"},
+			getErr:   os.ErrNotExist,
+			wantErrs: []error{os.ErrNotExist},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			templateRepositoryMock := mock_template_repository.NewMockTemplateRepositoryInterface(ctrl)
+			templateRepositoryMock.EXPECT().Get(tt.command.In).Return([]byte(tt.template), tt.getErr).Times(1)
+			if tt.wantSaved != "" {
+				templateRepositoryMock.EXPECT().Save(tt.command.Out, []byte(tt.wantSaved)).Return(nil).Times(1)
+			} else {
+				templateRepositoryMock.EXPECT().Save(gomock.Any(), gomock.Any()).Times(0)
+			}
+
+			resolverServiceMock := mock_resolver_service.NewMockResolverServiceInterface(ctrl)
+			var calls []*gomock.Call
+			for _, call := range tt.resolveCalls {
+				calls = append(calls, resolverServiceMock.EXPECT().Resolve(gomock.Any(), call.references).Return(call.values, call.err).Times(1))
+			}
+			gomock.InOrder(calls...)
+
+			loggerMock, err := logger.Initialize("info")
+			require.NoError(t, err)
+
+			service := NewInjectService(resolverServiceMock, templateRepositoryMock, loggerMock)
+			got, err := service.Inject(context.Background(), tt.command)
+			if len(tt.wantErrs) > 0 {
+				for _, wantErr := range tt.wantErrs {
+					assert.ErrorIs(t, err, wantErr)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInjectService_Inject_ErrorLines(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	templateRepositoryMock := mock_template_repository.NewMockTemplateRepositoryInterface(ctrl)
+	templateRepositoryMock.EXPECT().Get("config.tmpl").Return([]byte("a: gk://text/1\nb: gk://login/1\n\nc: {{ gk \"bin/\" }}\n"), nil)
+	resolverServiceMock := mock_resolver_service.NewMockResolverServiceInterface(ctrl)
+	// в --check разрешаются и ссылки рядом с неверными
+	resolverServiceMock.EXPECT().
+		Resolve(gomock.Any(), []dto.SecretReference{{EntryType: enum.Text, Id: "1", Field: dto.SecretFieldData}}).
+		Return(nil, sharedErrors.ErrEntryNotFound)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	service := NewInjectService(resolverServiceMock, templateRepositoryMock, loggerMock)
+	_, err = service.Inject(context.Background(), command.InjectCommand{In: "config.tmpl", Check: true})
+	assert.ErrorIs(t, err, sharedErrors.ErrEntryNotFound)
+	assert.ErrorIs(t, err, entryErrors.ErrSecretReferenceNotValid)
+	lines := strings.Split(err.Error(), "\n")
+	require.Len(t, lines, 3)
+	for i, line := range []int{1, 2, 4} {
+		assert.True(t, strings.HasPrefix(lines[i], fmt.Sprintf("config.tmpl:%d: ", line)), lines[i])
+	}
+}
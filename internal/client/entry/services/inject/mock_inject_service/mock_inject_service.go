@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: inject_service_interface.go
+
+// Package mock_inject_service is a generated GoMock package.
+package mock_inject_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	command "github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
+	command_response "github.com/anoriar/gophkeeper/internal/client/entry/dto/command_response"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockInjectServiceInterface is a mock of InjectServiceInterface interface.
+type MockInjectServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockInjectServiceInterfaceMockRecorder
+}
+
+// MockInjectServiceInterfaceMockRecorder is the mock recorder for MockInjectServiceInterface.
+type MockInjectServiceInterfaceMockRecorder struct {
+	mock *MockInjectServiceInterface
+}
+
+// NewMockInjectServiceInterface creates a new mock instance.
+func NewMockInjectServiceInterface(ctrl *gomock.Controller) *MockInjectServiceInterface {
+	mock := &MockInjectServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockInjectServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInjectServiceInterface) EXPECT() *MockInjectServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Inject mocks base method.
+func (m *MockInjectServiceInterface) Inject(ctx context.Context, command command.InjectCommand) (command_response.InjectResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Inject", ctx, command)
+	ret0, _ := ret[0].(command_response.InjectResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Inject indicates an expected call of Inject.
+func (mr *MockInjectServiceInterfaceMockRecorder) Inject(ctx, command interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Inject", reflect.TypeOf((*MockInjectServiceInterface)(nil).Inject), ctx, command)
+}
@@ -0,0 +1,37 @@
+package inject
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// placeholderRegexp {{ gk "login/<id>/password" }} или gk://login/<id>/password. В gk:// id - буквы, цифры, - и _,
+// чтобы точка или кавычка после ссылки не попали в нее
+var placeholderRegexp = regexp.MustCompile(`\{\{\s*gk\s+"([^"]*)"\s*\}\}|gk://([A-Za-z]+/[A-Za-z0-9_-]+(?:/[A-Za-z]+)?)`)
+
+// placeholder место ссылки в шаблоне: [start, end) - вся подстановка вместе с {{ }} или gk://
+type placeholder struct {
+	start     int
+	end       int
+	line      int
+	reference string
+}
+
+func findPlaceholders(content []byte) []placeholder {
+	matches := placeholderRegexp.FindAllSubmatchIndex(content, -1)
+	placeholders := make([]placeholder, 0, len(matches))
+	line, lineOffset := 1, 0
+	for _, match := range matches {
+		line += bytes.Count(content[lineOffset:match[0]], []byte("\n"))
+		lineOffset = match[0]
+
+		reference := ""
+		if match[2] >= 0 {
+			reference = string(content[match[2]:match[3]])
+		} else {
+			reference = string(content[match[4]:match[5]])
+		}
+		placeholders = append(placeholders, placeholder{start: match[0], end: match[1], line: line, reference: reference})
+	}
+	return placeholders
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: resolver_service_interface.go
+
+// Package mock_resolver_service is a generated GoMock package.
+package mock_resolver_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/anoriar/gophkeeper/internal/client/entry/dto"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockResolverServiceInterface is a mock of ResolverServiceInterface interface.
+type MockResolverServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockResolverServiceInterfaceMockRecorder
+}
+
+// MockResolverServiceInterfaceMockRecorder is the mock recorder for MockResolverServiceInterface.
+type MockResolverServiceInterfaceMockRecorder struct {
+	mock *MockResolverServiceInterface
+}
+
+// NewMockResolverServiceInterface creates a new mock instance.
+func NewMockResolverServiceInterface(ctrl *gomock.Controller) *MockResolverServiceInterface {
+	mock := &MockResolverServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockResolverServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResolverServiceInterface) EXPECT() *MockResolverServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Resolve mocks base method.
+func (m *MockResolverServiceInterface) Resolve(ctx context.Context, references []dto.SecretReference) (map[dto.SecretReference]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, references)
+	ret0, _ := ret[0].(map[dto.SecretReference]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockResolverServiceInterfaceMockRecorder) Resolve(ctx, references interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockResolverServiceInterface)(nil).Resolve), ctx, references)
+}
@@ -0,0 +1,100 @@
+package resolver
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto"
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command_response"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	entryErrors "github.com/anoriar/gophkeeper/internal/client/entry/errors"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/service_provider"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+)
+
+type ResolverService struct {
+	entryServiceProvider service_provider.EntryServiceProviderInterface
+	logger               *zap.Logger
+}
+
+func NewResolverService(entryServiceProvider service_provider.EntryServiceProviderInterface, logger *zap.Logger) *ResolverService {
+	return &ResolverService{
+		entryServiceProvider: entryServiceProvider,
+		logger:               logger,
+	}
+}
+
+func (s *ResolverService) Resolve(ctx context.Context, references []dto.SecretReference) (map[dto.SecretReference]string, error) {
+	type entryKey struct {
+		entryType enum.EntryType
+		id        string
+	}
+	entries := make(map[entryKey]command_response.DetailEntryResponse)
+	values := make(map[dto.SecretReference]string, len(references))
+	for _, reference := range references {
+		key := entryKey{entryType: reference.EntryType, id: reference.Id}
+		entry, ok := entries[key]
+		if !ok {
+			var err error
+			entry, err = s.entryServiceProvider.Detail(ctx, command.DetailEntryCommand{Id: reference.Id, EntryType: reference.EntryType})
+			if err != nil {
+				return nil, err
+			}
+			// запись из корзины не должна попасть в окружение программы или конфигурацию
+			if entry.IsDeleted {
+				return nil, fmt.Errorf("%w: %s/%s is in trash", sharedErrors.ErrEntryNotFound, reference.EntryType, reference.Id)
+			}
+			entries[key] = entry
+		}
+		value, err := s.fieldValue(entry, reference)
+		if err != nil {
+			return nil, err
+		}
+		values[reference] = value
+	}
+	return values, nil
+}
+
+// fieldValue значение поля по типизированным данным записи: поля login и card - по LoginData.Field и CardData.Field,
+// data - текст или содержимое файла, title - название записи любого типа
+func (s *ResolverService) fieldValue(entry command_response.DetailEntryResponse, reference dto.SecretReference) (string, error) {
+	field := reference.Field
+	if strings.EqualFold(field, dto.SecretFieldTitle) {
+		return entry.Title, nil
+	}
+
+	var value string
+	var found bool
+	switch data := entry.Data.(type) {
+	case *dto.LoginData:
+		value, found = data.Field(field)
+	case *dto.CardData:
+		value, found = data.Field(field)
+	case string:
+		if !strings.EqualFold(field, dto.SecretFieldData) {
+			break
+		}
+		if entry.EntryType != enum.Bin {
+			return data, nil
+		}
+		content, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			s.logger.Error("decode bin entry data error", zap.String("id", entry.Id), zap.String("error", err.Error()))
+			return "", fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+		}
+		return string(content), nil
+	default:
+		s.logger.Error("unexpected entry data", zap.String("id", entry.Id), zap.String("type", string(entry.EntryType)))
+		return "", fmt.Errorf("%w: unexpected %s entry data", sharedErrors.ErrInternalError, entry.EntryType)
+	}
+
+	if !found {
+		return "", fmt.Errorf("%w: %s", entryErrors.ErrSecretFieldNotFound, reference)
+	}
+	return value, nil
+}
@@ -0,0 +1,13 @@
+package resolver
+
+import (
+	"context"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto"
+)
+
+//go:generate mockgen -source=resolver_service_interface.go -destination=mock_resolver_service/mock_resolver_service.go -package=mock_resolver_service
+type ResolverServiceInterface interface {
+	// Resolve расшифрованные значения полей записей локального хранилища по ссылкам. Каждая запись расшифровывается один раз
+	Resolve(ctx context.Context, references []dto.SecretReference) (map[dto.SecretReference]string, error)
+}
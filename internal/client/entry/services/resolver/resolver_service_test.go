@@ -0,0 +1,131 @@
+package resolver
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto"
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command_response"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	entryErrors "github.com/anoriar/gophkeeper/internal/client/entry/errors"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/service_provider/mock_entry_service_provider"
+	"github.com/anoriar/gophkeeper/internal/client/shared/app/logger"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+)
+
+func TestResolverService_Resolve(t *testing.T) {
+	loginEntry := command_response.DetailEntryResponse{
+		Id:        "1",
+		EntryType: enum.Login,
+		Title:     "Database",
+		Data:      &dto.LoginData{Login: "admin", Password: "secret"},
+	}
+	cardEntry := command_response.DetailEntryResponse{
+		Id:        "2",
+		EntryType: enum.Card,
+		Data:      &dto.CardData{Number: "4111111111111111", ExpireDate: "12/30", Holder: "IVAN", CVV: "123"},
+	}
+	textEntry := command_response.DetailEntryResponse{Id: "3", EntryType: enum.Text, Data: "line1\nline2"}
+	binEntry := command_response.DetailEntryResponse{Id: "4", EntryType: enum.Bin, Data: base64.StdEncoding.EncodeToString([]byte{0, 1, 2})}
+	deletedEntry := command_response.DetailEntryResponse{Id: "5", EntryType: enum.Text, IsDeleted: true, Data: "old"}
+
+	tests := []struct {
+		name       string
+		references []string
+		entries    []command_response.DetailEntryResponse
+		detailErr  error
+		want       map[string]string
+		wantErr    error
+	}{
+		{
+			name:       "success login fields from one detail",
+			references: []string{"login/1/login", "login/1/password", "login/1/title"},
+			entries:    []command_response.DetailEntryResponse{loginEntry},
+			want:       map[string]string{"login/1/login": "admin", "login/1/password": "secret", "login/1/title": "Database"},
+		},
+		{
+			name:       "success card fields ignore case",
+			references: []string{"card/2/number", "card/2/expiredate", "card/2/CVV"},
+			entries:    []command_response.DetailEntryResponse{cardEntry},
+			want:       map[string]string{"card/2/number": "4111111111111111", "card/2/expiredate": "12/30", "card/2/CVV": "123"},
+		},
+		{
+			name:       "success text and decoded bin data",
+			references: []string{"text/3", "bin/4/data"},
+			entries:    []command_response.DetailEntryResponse{textEntry, binEntry},
+			want:       map[string]string{"text/3/data": "line1\nline2", "bin/4/data": string([]byte{0, 1, 2})},
+		},
+		{
+			name:       "field not found error",
+			references: []string{"login/1/pin"},
+			entries:    []command_response.DetailEntryResponse{loginEntry},
+			wantErr:    entryErrors.ErrSecretFieldNotFound,
+		},
+		{
+			name:       "text entry has only data field error",
+			references: []string{"text/3/password"},
+			entries:    []command_response.DetailEntryResponse{textEntry},
+			wantErr:    entryErrors.ErrSecretFieldNotFound,
+		},
+		{
+			name:       "entry in trash error",
+			references: []string{"text/5"},
+			entries:    []command_response.DetailEntryResponse{deletedEntry},
+			wantErr:    sharedErrors.ErrEntryNotFound,
+		},
+		{
+			name:       "detail error",
+			references: []string{"login/1/password"},
+			entries:    []command_response.DetailEntryResponse{loginEntry},
+			detailErr:  sharedErrors.ErrEntryNotFound,
+			wantErr:    sharedErrors.ErrEntryNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			entryServiceProviderMock := mock_entry_service_provider.NewMockEntryServiceProviderInterface(ctrl)
+			for _, entry := range tt.entries {
+				detailEntry := entry
+				if tt.detailErr != nil {
+					detailEntry = command_response.DetailEntryResponse{}
+				}
+				entryServiceProviderMock.EXPECT().
+					Detail(gomock.Any(), command.DetailEntryCommand{Id: entry.Id, EntryType: entry.EntryType}).
+					Return(detailEntry, tt.detailErr).
+					Times(1)
+			}
+
+			references := make([]dto.SecretReference, 0, len(tt.references))
+			for _, value := range tt.references {
+				reference, err := dto.ParseSecretReference(value)
+				require.NoError(t, err)
+				references = append(references, reference)
+			}
+
+			loggerMock, err := logger.Initialize("info")
+			require.NoError(t, err)
+
+			service := NewResolverService(entryServiceProviderMock, loggerMock)
+			got, err := service.Resolve(context.Background(), references)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			gotByReference := make(map[string]string, len(got))
+			for reference, value := range got {
+				gotByReference[reference.String()] = value
+			}
+			assert.Equal(t, tt.want, gotByReference)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: run_service_interface.go
+
+// Package mock_run_service is a generated GoMock package.
+package mock_run_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	command "github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRunServiceInterface is a mock of RunServiceInterface interface.
+type MockRunServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRunServiceInterfaceMockRecorder
+}
+
+// MockRunServiceInterfaceMockRecorder is the mock recorder for MockRunServiceInterface.
+type MockRunServiceInterfaceMockRecorder struct {
+	mock *MockRunServiceInterface
+}
+
+// NewMockRunServiceInterface creates a new mock instance.
+func NewMockRunServiceInterface(ctrl *gomock.Controller) *MockRunServiceInterface {
+	mock := &MockRunServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockRunServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRunServiceInterface) EXPECT() *MockRunServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Run mocks base method.
+func (m *MockRunServiceInterface) Run(ctx context.Context, command command.RunCommand) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx, command)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockRunServiceInterfaceMockRecorder) Run(ctx, command interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockRunServiceInterface)(nil).Run), ctx, command)
+}
@@ -0,0 +1,124 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto"
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
+	entryErrors "github.com/anoriar/gophkeeper/internal/client/entry/errors"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/resolver"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+)
+
+// signalExitCodeBase код завершения программы, убитой сигналом, - 128 + номер сигнала, как в sh
+const signalExitCodeBase = 128
+
+// forwardedSignals сигналы клиенту, которые передаются программе. Клиент сам не завершается, пока не завершится программа
+var forwardedSignals = []os.Signal{syscall.SIGTERM, syscall.SIGHUP}
+
+// terminalSignals программа в группе процессов клиента, и Ctrl+C или Ctrl+\ терминал отправляет ей сам.
+// Клиент их только перехватывает, иначе программа получила бы сигнал дважды
+var terminalSignals = []os.Signal{os.Interrupt, syscall.SIGQUIT}
+
+type RunService struct {
+	resolverService resolver.ResolverServiceInterface
+	stdin           io.Reader
+	stdout          io.Writer
+	stderr          io.Writer
+	logger          *zap.Logger
+}
+
+func NewRunService(resolverService resolver.ResolverServiceInterface, stdin io.Reader, stdout io.Writer, stderr io.Writer, logger *zap.Logger) *RunService {
+	return &RunService{
+		resolverService: resolverService,
+		stdin:           stdin,
+		stdout:          stdout,
+		stderr:          stderr,
+		logger:          logger,
+	}
+}
+
+// Run секреты передаются только в окружение дочернего процесса: ни в файлы, ни в окружение клиента они не попадают
+func (s *RunService) Run(ctx context.Context, command command.RunCommand) (int, error) {
+	references := make([]dto.SecretReference, 0, len(command.Env))
+	for _, env := range command.Env {
+		references = append(references, env.Reference)
+	}
+	values, err := s.resolverService.Resolve(ctx, references)
+	if err != nil {
+		return 0, err
+	}
+
+	environ := os.Environ()
+	for _, env := range command.Env {
+		environ = append(environ, env.Name+"="+values[env.Reference])
+	}
+
+	// не CommandContext: отмена контекста по сигналу убила бы программу, а сигнал ей нужно передать
+	program := exec.Command(command.Args[0], command.Args[1:]...)
+	program.Env = environ
+	program.Stdin = s.stdin
+	program.Stdout = s.stdout
+	program.Stderr = s.stderr
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, append(forwardedSignals, terminalSignals...)...)
+	defer signal.Stop(signals)
+
+	err = program.Start()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", entryErrors.ErrProgramNotStarted, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- program.Wait()
+	}()
+	for {
+		select {
+		case sig := <-signals:
+			if isTerminalSignal(sig) {
+				continue
+			}
+			err = program.Process.Signal(sig)
+			if err != nil && !errors.Is(err, os.ErrProcessDone) {
+				s.logger.Error("forward signal error", zap.String("signal", sig.String()), zap.String("error", err.Error()))
+			}
+		case err = <-done:
+			return s.exitCode(err)
+		}
+	}
+}
+
+func isTerminalSignal(sig os.Signal) bool {
+	for _, terminalSignal := range terminalSignals {
+		if sig == terminalSignal {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *RunService) exitCode(waitErr error) (int, error) {
+	if waitErr == nil {
+		return sharedErrors.ExitCodeSuccess, nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(waitErr, &exitErr) {
+		s.logger.Error("wait program error", zap.String("error", waitErr.Error()))
+		return 0, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, waitErr)
+	}
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return signalExitCodeBase + int(status.Signal()), nil
+	}
+	return exitErr.ExitCode(), nil
+}
@@ -0,0 +1,14 @@
+package run
+
+import (
+	"context"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
+)
+
+//go:generate mockgen -source=run_service_interface.go -destination=mock_run_service/mock_run_service.go -package=mock_run_service
+type RunServiceInterface interface {
+	// Run запускает программу с секретами локального хранилища в переменных окружения и ждет ее завершения.
+	// Сигналы клиенту передаются программе, возвращается ее код завершения
+	Run(ctx context.Context, command command.RunCommand) (int, error)
+}
@@ -0,0 +1,187 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto"
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	entryErrors "github.com/anoriar/gophkeeper/internal/client/entry/errors"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/resolver/mock_resolver_service"
+	"github.com/anoriar/gophkeeper/internal/client/shared/app/logger"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+)
+
+const helperProcessEnv = "GK_TEST_HELPER_PROCESS"
+
+// TestHelperProcess не тест, а программа, которую запускает Run: go test перезапускает сам себя с -test.run
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv(helperProcessEnv) != "1" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	switch args[1] {
+	case "env":
+		fmt.Print(os.Getenv("DB_PASS"))
+		os.Exit(0)
+	case "exit":
+		os.Exit(3)
+	case "kill":
+		process, _ := os.FindProcess(os.Getpid())
+		_ = process.Kill()
+		time.Sleep(time.Minute)
+	case "trap":
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGTERM)
+		fmt.Println("ready")
+		<-signals
+		os.Exit(42)
+	}
+	os.Exit(1)
+}
+
+// readyWriter сообщает, когда программа вывела строку ready
+type readyWriter struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	ready chan struct{}
+	once  sync.Once
+}
+
+func (w *readyWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf.Write(p)
+	if strings.Contains(w.buf.String(), "ready") {
+		w.once.Do(func() { close(w.ready) })
+	}
+	return len(p), nil
+}
+
+func TestRunService_Run(t *testing.T) {
+	t.Setenv(helperProcessEnv, "1")
+	reference := dto.SecretReference{EntryType: enum.Login, Id: "1", Field: "password"}
+	secretEnv := []command.EnvReference{{Name: "DB_PASS", Reference: reference}}
+
+	tests := []struct {
+		name       string
+		mode       string
+		resolveErr error
+		sendSignal bool
+		// sendInterrupt - SIGINT только клиенту, как kill -INT: терминальный сигнал программе не передается
+		sendInterrupt bool
+		wantExitCode  int
+		wantStdout    string
+		wantErr       error
+	}{
+		{
+			name:       "success secret in program env",
+			mode:       "env",
+			wantStdout: "pa ss",
+		},
+		{
+			name:         "success program exit code",
+			mode:         "exit",
+			wantExitCode: 3,
+		},
+		{
+			name:         "success program killed by signal",
+			mode:         "kill",
+			wantExitCode: signalExitCodeBase + int(syscall.SIGKILL),
+		},
+		{
+			name:         "success signal forwarded to program",
+			mode:         "trap",
+			sendSignal:   true,
+			wantExitCode: 42,
+		},
+		{
+			name:          "success terminal signal not forwarded to program",
+			mode:          "trap",
+			sendInterrupt: true,
+			sendSignal:    true,
+			wantExitCode:  42,
+		},
+		{
+			name:       "resolve error",
+			mode:       "env",
+			resolveErr: sharedErrors.ErrEntryNotFound,
+			wantErr:    sharedErrors.ErrEntryNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			resolverServiceMock := mock_resolver_service.NewMockResolverServiceInterface(ctrl)
+			resolverServiceMock.EXPECT().
+				Resolve(gomock.Any(), []dto.SecretReference{reference}).
+				Return(map[dto.SecretReference]string{reference: "pa ss"}, tt.resolveErr).
+				Times(1)
+			loggerMock, err := logger.Initialize("info")
+			require.NoError(t, err)
+
+			stdout := &readyWriter{ready: make(chan struct{})}
+			if tt.sendSignal {
+				go func() {
+					<-stdout.ready
+					process, _ := os.FindProcess(os.Getpid())
+					if tt.sendInterrupt {
+						_ = process.Signal(os.Interrupt)
+						// канал сигналов с буфером 1: SIGTERM не должен прийти, пока SIGINT не прочитан
+						time.Sleep(100 * time.Millisecond)
+					}
+					_ = process.Signal(syscall.SIGTERM)
+				}()
+			}
+
+			service := NewRunService(resolverServiceMock, nil, stdout, os.Stderr, loggerMock)
+			exitCode, err := service.Run(context.Background(), command.RunCommand{
+				Env:  secretEnv,
+				Args: []string{os.Args[0], "-test.run=TestHelperProcess", "--", tt.mode},
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantExitCode, exitCode)
+			if tt.wantStdout != "" {
+				assert.Equal(t, tt.wantStdout, stdout.buf.String())
+			}
+			// секрет есть только в окружении программы
+			assert.Empty(t, os.Getenv("DB_PASS"))
+		})
+	}
+}
+
+func TestRunService_Run_ProgramNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resolverServiceMock := mock_resolver_service.NewMockResolverServiceInterface(ctrl)
+	resolverServiceMock.EXPECT().Resolve(gomock.Any(), gomock.Any()).Return(map[dto.SecretReference]string{}, nil).Times(1)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	service := NewRunService(resolverServiceMock, nil, os.Stdout, os.Stderr, loggerMock)
+	_, err = service.Run(context.Background(), command.RunCommand{Args: []string{"gophkeeper-not-existing-program"}})
+	assert.ErrorIs(t, err, entryErrors.ErrProgramNotStarted)
+}
@@ -0,0 +1,96 @@
+package search
+
+import (
+	"encoding/json"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto"
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+)
+
+const (
+	FieldTitle      = "title"
+	FieldMeta       = "meta"
+	FieldPublicMeta = "publicMeta"
+	FieldLogin      = "login"
+	FieldHolder     = "holder"
+)
+
+// Field значение записи, по которому идет поиск
+type Field struct {
+	Name  string
+	Value string
+}
+
+// EntryFields поля расшифрованной записи, по которым идет поиск: название, метаданные и несекретные поля данных.
+// Пароль, номер карты, CVV, текст и файлы в поиск не попадают
+func EntryFields(entry entity.Entry) []Field {
+	var fields []Field
+	if len(entry.Title) > 0 {
+		fields = append(fields, Field{Name: FieldTitle, Value: string(entry.Title)})
+	}
+
+	switch entry.EntryType {
+	case enum.Login:
+		var data dto.LoginData
+		if json.Unmarshal(entry.Data, &data) == nil && data.Login != "" {
+			fields = append(fields, Field{Name: FieldLogin, Value: data.Login})
+		}
+	case enum.Card:
+		var data dto.CardData
+		if json.Unmarshal(entry.Data, &data) == nil && data.Holder != "" {
+			fields = append(fields, Field{Name: FieldHolder, Value: data.Holder})
+		}
+	}
+
+	fields = appendMetaFields(fields, FieldMeta, entry.Meta)
+	fields = appendMetaFields(fields, FieldPublicMeta, entry.PublicMeta)
+	return fields
+}
+
+// appendMetaFields в метаданных JSON ищутся только строковые значения, чтобы запрос не совпадал с именами ключей
+func appendMetaFields(fields []Field, name string, meta []byte) []Field {
+	if len(meta) == 0 {
+		return fields
+	}
+	var value interface{}
+	if json.Unmarshal(meta, &value) != nil {
+		return append(fields, Field{Name: name, Value: string(meta)})
+	}
+	for _, str := range collectStrings(value, nil) {
+		fields = append(fields, Field{Name: name, Value: str})
+	}
+	return fields
+}
+
+func collectStrings(value interface{}, strs []string) []string {
+	switch v := value.(type) {
+	case string:
+		if v != "" {
+			strs = append(strs, v)
+		}
+	case []interface{}:
+		for _, item := range v {
+			strs = collectStrings(item, strs)
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			strs = collectStrings(item, strs)
+		}
+	}
+	return strs
+}
+
+// Match поле с лучшим совпадением, score 0 - запись не подходит
+func Match(query string, fields []Field) (string, int) {
+	bestName := ""
+	bestScore := 0
+	for _, field := range fields {
+		score := Score(query, field.Value)
+		if score > bestScore {
+			bestName = field.Name
+			bestScore = score
+		}
+	}
+	return bestName, bestScore
+}
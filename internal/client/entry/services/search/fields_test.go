@@ -0,0 +1,39 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+)
+
+func TestEntryFields(t *testing.T) {
+	fields := EntryFields(entryWithMeta())
+	assert.ElementsMatch(t, []Field{
+		{Name: FieldTitle, Value: "Mail"},
+		{Name: FieldLogin, Value: "user"},
+		{Name: FieldMeta, Value: "example.com"},
+		{Name: FieldPublicMeta, Value: "work"},
+	}, fields)
+
+	name, score := Match("example", fields)
+	assert.Equal(t, FieldMeta, name)
+	assert.Greater(t, score, 0)
+
+	// пароль в поиск не попадает
+	_, score = Match("secret", fields)
+	assert.Equal(t, 0, score)
+}
+
+func entryWithMeta() entity.Entry {
+	return entity.Entry{
+		Id:         "225de857-71c5-452f-96f7-ff385d808083",
+		EntryType:  enum.Login,
+		Title:      []byte("Mail"),
+		Data:       []byte(`{"login": "user", "password": "secret"}`),
+		Meta:       []byte(`{"site": "example.com"}`),
+		PublicMeta: []byte(`{"tag": "work"}`),
+	}
+}
@@ -0,0 +1,73 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// substringScore совпадение подстрокой всегда выше совпадения символов с пропусками
+	substringScore = 1000
+	wordStartBonus = 100
+	exactBonus     = 200
+
+	subsequenceCharScore = 1
+	consecutiveBonus     = 2
+	boundaryBonus        = 3
+)
+
+// Score насколько text подходит под query без учета регистра, 0 - не подходит.
+// Подстрока ценится выше, чем символы query, найденные в text по порядку с пропусками
+func Score(query string, text string) int {
+	query = strings.ToLower(strings.TrimSpace(query))
+	text = strings.ToLower(text)
+	if query == "" || text == "" {
+		return 0
+	}
+
+	if idx := strings.Index(text, query); idx >= 0 {
+		score := substringScore + utf8.RuneCountInString(query)
+		if query == text {
+			score += exactBonus
+		}
+		if prev, _ := utf8.DecodeLastRuneInString(text[:idx]); idx == 0 || isSeparator(prev) {
+			score += wordStartBonus
+		}
+		return score
+	}
+	return subsequenceScore([]rune(query), []rune(text))
+}
+
+func subsequenceScore(query []rune, text []rune) int {
+	score := 0
+	textIdx := 0
+	lastMatchIdx := -1
+	for _, queryRune := range query {
+		found := false
+		for ; textIdx < len(text); textIdx++ {
+			if text[textIdx] != queryRune {
+				continue
+			}
+			score += subsequenceCharScore
+			if lastMatchIdx >= 0 && textIdx == lastMatchIdx+1 {
+				score += consecutiveBonus
+			}
+			if textIdx == 0 || isSeparator(text[textIdx-1]) {
+				score += boundaryBonus
+			}
+			lastMatchIdx = textIdx
+			textIdx++
+			found = true
+			break
+		}
+		if !found {
+			return 0
+		}
+	}
+	return score
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -0,0 +1,39 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		text      string
+		wantMatch bool
+	}{
+		{name: "substring ignore case", query: "MAIL", text: "work gmail", wantMatch: true},
+		{name: "subsequence", query: "gml", text: "gmail.com", wantMatch: true},
+		{name: "not in order", query: "lmg", text: "gmail.com", wantMatch: false},
+		{name: "empty query", query: " ", text: "gmail.com", wantMatch: false},
+		{name: "empty text", query: "mail", text: "", wantMatch: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantMatch, Score(tt.query, tt.text) > 0)
+		})
+	}
+}
+
+func TestScore_Order(t *testing.T) {
+	// точное совпадение > начало слова > подстрока > символы с пропусками
+	exact := Score("bank", "Bank")
+	wordStart := Score("bank", "my bank")
+	substring := Score("bank", "databank")
+	subsequence := Score("bank", "big blank")
+	assert.Greater(t, exact, wordStart)
+	assert.Greater(t, wordStart, substring)
+	assert.Greater(t, substring, subsequence)
+	assert.Greater(t, subsequence, 0)
+}
@@ -0,0 +1,23 @@
+package search
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command_response"
+)
+
+// SortFound лучшие совпадения первыми, при равенстве - по названию и id, чтобы порядок не менялся между вызовами
+func SortFound(found []command_response.FindEntryResponse) {
+	sort.SliceStable(found, func(i, j int) bool {
+		a, b := found[i], found[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		aTitle, bTitle := strings.ToLower(a.Title), strings.ToLower(b.Title)
+		if aTitle != bTitle {
+			return aTitle < bTitle
+		}
+		return a.Id < b.Id
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
 	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
 	"github.com/anoriar/gophkeeper/internal/client/entry/services/entry"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/search"
 )
 
 type EntryServiceProvider struct {
@@ -76,12 +77,78 @@ func (sp *EntryServiceProvider) Delete(ctx context.Context, cmd command.DeleteEn
 	return nil
 }
 
+func (sp *EntryServiceProvider) Restore(ctx context.Context, cmd command.RestoreEntryCommand) error {
+	service, err := sp.getService(cmd.EntryType)
+	if err != nil {
+		return err
+	}
+	err = service.Restore(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sp *EntryServiceProvider) History(ctx context.Context, cmd command.HistoryEntryCommand) ([]command_response.HistoryEntryResponse, error) {
+	service, err := sp.getService(cmd.EntryType)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := service.History(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (sp *EntryServiceProvider) Revert(ctx context.Context, cmd command.RevertEntryCommand) (command_response.DetailEntryResponse, error) {
+	service, err := sp.getService(cmd.EntryType)
+	if err != nil {
+		return command_response.DetailEntryResponse{}, err
+	}
+	responseEntry, err := service.Revert(ctx, cmd)
+	if err != nil {
+		return command_response.DetailEntryResponse{}, err
+	}
+	return responseEntry, nil
+}
+
 func (sp *EntryServiceProvider) GetList(ctx context.Context, cmd command.ListEntryCommand) ([]command_response.ListEntryCommandResponse, error) {
 	service, err := sp.getService(cmd.EntryType)
 	if err != nil {
 		return nil, err
 	}
-	entries, err := service.List(ctx)
+	entries, err := service.List(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Find ищет сразу по записям всех типов
+func (sp *EntryServiceProvider) Find(ctx context.Context, cmd command.FindEntryCommand) ([]command_response.FindEntryResponse, error) {
+	found := make([]command_response.FindEntryResponse, 0)
+	for _, service := range []entry.EntryServiceInterface{sp.loginService, sp.cardService, sp.textService, sp.binService} {
+		entries, err := service.Find(ctx, cmd)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, entries...)
+	}
+
+	search.SortFound(found)
+	if cmd.Limit > 0 && len(found) > cmd.Limit {
+		found = found[:cmd.Limit]
+	}
+	return found, nil
+}
+
+func (sp *EntryServiceProvider) GetTrash(ctx context.Context, cmd command.TrashEntryCommand) ([]command_response.ListEntryCommandResponse, error) {
+	service, err := sp.getService(cmd.EntryType)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := service.Trash(ctx)
 	if err != nil {
 		return nil, err
 	}
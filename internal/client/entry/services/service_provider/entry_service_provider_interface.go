@@ -8,11 +8,17 @@ import (
 	"github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
 )
 
+//go:generate mockgen -source=entry_service_provider_interface.go -destination=mock_entry_service_provider/mock_entry_service_provider.go -package=mock_entry_service_provider
 type EntryServiceProviderInterface interface {
 	Add(ctx context.Context, cmd command.AddEntryCommand) (command_response.DetailEntryResponse, error)
 	Edit(ctx context.Context, cmd command.EditEntryCommand) (command_response.DetailEntryResponse, error)
 	Delete(ctx context.Context, cmd command.DeleteEntryCommand) error
+	Restore(ctx context.Context, cmd command.RestoreEntryCommand) error
 	Detail(ctx context.Context, cmd command.DetailEntryCommand) (command_response.DetailEntryResponse, error)
+	History(ctx context.Context, cmd command.HistoryEntryCommand) ([]command_response.HistoryEntryResponse, error)
+	Revert(ctx context.Context, cmd command.RevertEntryCommand) (command_response.DetailEntryResponse, error)
 	GetList(ctx context.Context, cmd command.ListEntryCommand) ([]command_response.ListEntryCommandResponse, error)
+	Find(ctx context.Context, cmd command.FindEntryCommand) ([]command_response.FindEntryResponse, error)
+	GetTrash(ctx context.Context, cmd command.TrashEntryCommand) ([]command_response.ListEntryCommandResponse, error)
 	Sync(ctx context.Context, cmd command.SyncEntryCommand) error
 }
@@ -0,0 +1,199 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: entry_service_provider_interface.go
+
+// Package mock_entry_service_provider is a generated GoMock package.
+package mock_entry_service_provider
+
+import (
+	context "context"
+	reflect "reflect"
+
+	command "github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
+	command_response "github.com/anoriar/gophkeeper/internal/client/entry/dto/command_response"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEntryServiceProviderInterface is a mock of EntryServiceProviderInterface interface.
+type MockEntryServiceProviderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntryServiceProviderInterfaceMockRecorder
+}
+
+// MockEntryServiceProviderInterfaceMockRecorder is the mock recorder for MockEntryServiceProviderInterface.
+type MockEntryServiceProviderInterfaceMockRecorder struct {
+	mock *MockEntryServiceProviderInterface
+}
+
+// NewMockEntryServiceProviderInterface creates a new mock instance.
+func NewMockEntryServiceProviderInterface(ctrl *gomock.Controller) *MockEntryServiceProviderInterface {
+	mock := &MockEntryServiceProviderInterface{ctrl: ctrl}
+	mock.recorder = &MockEntryServiceProviderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntryServiceProviderInterface) EXPECT() *MockEntryServiceProviderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockEntryServiceProviderInterface) Add(ctx context.Context, cmd command.AddEntryCommand) (command_response.DetailEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, cmd)
+	ret0, _ := ret[0].(command_response.DetailEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockEntryServiceProviderInterfaceMockRecorder) Add(ctx, cmd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockEntryServiceProviderInterface)(nil).Add), ctx, cmd)
+}
+
+// Delete mocks base method.
+func (m *MockEntryServiceProviderInterface) Delete(ctx context.Context, cmd command.DeleteEntryCommand) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, cmd)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockEntryServiceProviderInterfaceMockRecorder) Delete(ctx, cmd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEntryServiceProviderInterface)(nil).Delete), ctx, cmd)
+}
+
+// Detail mocks base method.
+func (m *MockEntryServiceProviderInterface) Detail(ctx context.Context, cmd command.DetailEntryCommand) (command_response.DetailEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Detail", ctx, cmd)
+	ret0, _ := ret[0].(command_response.DetailEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Detail indicates an expected call of Detail.
+func (mr *MockEntryServiceProviderInterfaceMockRecorder) Detail(ctx, cmd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detail", reflect.TypeOf((*MockEntryServiceProviderInterface)(nil).Detail), ctx, cmd)
+}
+
+// Edit mocks base method.
+func (m *MockEntryServiceProviderInterface) Edit(ctx context.Context, cmd command.EditEntryCommand) (command_response.DetailEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", ctx, cmd)
+	ret0, _ := ret[0].(command_response.DetailEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Edit indicates an expected call of Edit.
+func (mr *MockEntryServiceProviderInterfaceMockRecorder) Edit(ctx, cmd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockEntryServiceProviderInterface)(nil).Edit), ctx, cmd)
+}
+
+// Find mocks base method.
+func (m *MockEntryServiceProviderInterface) Find(ctx context.Context, cmd command.FindEntryCommand) ([]command_response.FindEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, cmd)
+	ret0, _ := ret[0].([]command_response.FindEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockEntryServiceProviderInterfaceMockRecorder) Find(ctx, cmd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockEntryServiceProviderInterface)(nil).Find), ctx, cmd)
+}
+
+// GetList mocks base method.
+func (m *MockEntryServiceProviderInterface) GetList(ctx context.Context, cmd command.ListEntryCommand) ([]command_response.ListEntryCommandResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, cmd)
+	ret0, _ := ret[0].([]command_response.ListEntryCommandResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockEntryServiceProviderInterfaceMockRecorder) GetList(ctx, cmd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockEntryServiceProviderInterface)(nil).GetList), ctx, cmd)
+}
+
+// GetTrash mocks base method.
+func (m *MockEntryServiceProviderInterface) GetTrash(ctx context.Context, cmd command.TrashEntryCommand) ([]command_response.ListEntryCommandResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx, cmd)
+	ret0, _ := ret[0].([]command_response.ListEntryCommandResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockEntryServiceProviderInterfaceMockRecorder) GetTrash(ctx, cmd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockEntryServiceProviderInterface)(nil).GetTrash), ctx, cmd)
+}
+
+// History mocks base method.
+func (m *MockEntryServiceProviderInterface) History(ctx context.Context, cmd command.HistoryEntryCommand) ([]command_response.HistoryEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, cmd)
+	ret0, _ := ret[0].([]command_response.HistoryEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockEntryServiceProviderInterfaceMockRecorder) History(ctx, cmd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockEntryServiceProviderInterface)(nil).History), ctx, cmd)
+}
+
+// Restore mocks base method.
+func (m *MockEntryServiceProviderInterface) Restore(ctx context.Context, cmd command.RestoreEntryCommand) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, cmd)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockEntryServiceProviderInterfaceMockRecorder) Restore(ctx, cmd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockEntryServiceProviderInterface)(nil).Restore), ctx, cmd)
+}
+
+// Revert mocks base method.
+func (m *MockEntryServiceProviderInterface) Revert(ctx context.Context, cmd command.RevertEntryCommand) (command_response.DetailEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revert", ctx, cmd)
+	ret0, _ := ret[0].(command_response.DetailEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revert indicates an expected call of Revert.
+func (mr *MockEntryServiceProviderInterfaceMockRecorder) Revert(ctx, cmd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockEntryServiceProviderInterface)(nil).Revert), ctx, cmd)
+}
+
+// Sync mocks base method.
+func (m *MockEntryServiceProviderInterface) Sync(ctx context.Context, cmd command.SyncEntryCommand) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", ctx, cmd)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockEntryServiceProviderInterfaceMockRecorder) Sync(ctx, cmd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockEntryServiceProviderInterface)(nil).Sync), ctx, cmd)
+}
@@ -0,0 +1,21 @@
+package command
+
+import (
+	"fmt"
+
+	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+)
+
+type AddProfileCommand struct {
+	Name string
+	// ServerAddress адрес сервера профиля, пустой - SERVER_ADDRESS
+	ServerAddress string
+}
+
+func (command *AddProfileCommand) Validate() validation.ValidationErrors {
+	var validationErrors validation.ValidationErrors
+	if command.Name == "" {
+		validationErrors = append(validationErrors, fmt.Errorf("name required"))
+	}
+	return validationErrors
+}
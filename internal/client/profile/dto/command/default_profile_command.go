@@ -0,0 +1,19 @@
+package command
+
+import (
+	"fmt"
+
+	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+)
+
+type DefaultProfileCommand struct {
+	Name string
+}
+
+func (command *DefaultProfileCommand) Validate() validation.ValidationErrors {
+	var validationErrors validation.ValidationErrors
+	if command.Name == "" {
+		validationErrors = append(validationErrors, fmt.Errorf("name required"))
+	}
+	return validationErrors
+}
@@ -0,0 +1,10 @@
+package command
+
+import validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+
+type ListProfilesCommand struct {
+}
+
+func (command *ListProfilesCommand) Validate() validation.ValidationErrors {
+	return nil
+}
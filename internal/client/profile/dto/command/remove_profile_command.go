@@ -0,0 +1,21 @@
+package command
+
+import (
+	"fmt"
+
+	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+)
+
+type RemoveProfileCommand struct {
+	Name string
+	// Purge удалить также токен, файлы хранилища и ключи профиля
+	Purge bool
+}
+
+func (command *RemoveProfileCommand) Validate() validation.ValidationErrors {
+	var validationErrors validation.ValidationErrors
+	if command.Name == "" {
+		validationErrors = append(validationErrors, fmt.Errorf("name required"))
+	}
+	return validationErrors
+}
@@ -0,0 +1,9 @@
+package command_response
+
+type ProfileResponse struct {
+	Name          string `json:"name"`
+	ServerAddress string `json:"serverAddress"`
+	DataDirName   string `json:"dataDirName"`
+	IsDefault     bool   `json:"isDefault"`
+	IsActive      bool   `json:"isActive"`
+}
@@ -0,0 +1,39 @@
+package entity
+
+// DefaultProfileName профиль, который хранится прямо в DATA_DIRNAME: так работали версии клиента без профилей
+const DefaultProfileName = "default"
+
+// Profile учетная запись клиента. У каждого профиля свой сервер, токен, файлы хранилища и ключи
+type Profile struct {
+	Name string `json:"name"`
+	// ServerAddress адрес сервера профиля, пустой - SERVER_ADDRESS
+	ServerAddress string `json:"serverAddress,omitempty"`
+}
+
+// Profiles реестр профилей
+type Profiles struct {
+	// Default профиль, который используется без --profile
+	Default  string    `json:"default"`
+	Profiles []Profile `json:"profiles"`
+}
+
+// Find профиль по имени. Профиль default есть всегда, даже если его нет в реестре
+func (p Profiles) Find(name string) (Profile, bool) {
+	for _, profile := range p.Profiles {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+	if name == DefaultProfileName {
+		return Profile{Name: DefaultProfileName}, true
+	}
+	return Profile{}, false
+}
+
+// GetDefault имя профиля по умолчанию
+func (p Profiles) GetDefault() string {
+	if p.Default == "" {
+		return DefaultProfileName
+	}
+	return p.Default
+}
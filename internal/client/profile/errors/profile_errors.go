@@ -0,0 +1,9 @@
+package errors
+
+import "errors"
+
+var ErrProfileNotFound = errors.New("profile not found")
+var ErrProfileExists = errors.New("profile already exists")
+var ErrProfileNameNotValid = errors.New("profile name must contain only latin letters, digits, '-' and '_'")
+var ErrDefaultProfileRemove = errors.New("default profile can not be removed")
+var ErrActiveProfileRemove = errors.New("active profile can not be removed")
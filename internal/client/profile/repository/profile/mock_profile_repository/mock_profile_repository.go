@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: profile_repository_interface.go
+
+// Package mock_profile_repository is a generated GoMock package.
+package mock_profile_repository
+
+import (
+	reflect "reflect"
+
+	entity "github.com/anoriar/gophkeeper/internal/client/profile/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockProfileRepositoryInterface is a mock of ProfileRepositoryInterface interface.
+type MockProfileRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfileRepositoryInterfaceMockRecorder
+}
+
+// MockProfileRepositoryInterfaceMockRecorder is the mock recorder for MockProfileRepositoryInterface.
+type MockProfileRepositoryInterfaceMockRecorder struct {
+	mock *MockProfileRepositoryInterface
+}
+
+// NewMockProfileRepositoryInterface creates a new mock instance.
+func NewMockProfileRepositoryInterface(ctrl *gomock.Controller) *MockProfileRepositoryInterface {
+	mock := &MockProfileRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockProfileRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProfileRepositoryInterface) EXPECT() *MockProfileRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockProfileRepositoryInterface) Get() (entity.Profiles, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get")
+	ret0, _ := ret[0].(entity.Profiles)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockProfileRepositoryInterfaceMockRecorder) Get() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProfileRepositoryInterface)(nil).Get))
+}
+
+// Save mocks base method.
+func (m *MockProfileRepositoryInterface) Save(profiles entity.Profiles) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", profiles)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockProfileRepositoryInterfaceMockRecorder) Save(profiles interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockProfileRepositoryInterface)(nil).Save), profiles)
+}
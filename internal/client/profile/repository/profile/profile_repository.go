@@ -0,0 +1,46 @@
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/anoriar/gophkeeper/internal/client/profile/entity"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/atomicfile"
+)
+
+// ProfileRepository реестр профилей в файле JSON
+type ProfileRepository struct {
+	fileName string
+}
+
+func NewProfileRepository(fileName string) *ProfileRepository {
+	return &ProfileRepository{fileName: fileName}
+}
+
+func (r *ProfileRepository) Get() (entity.Profiles, error) {
+	content, err := os.ReadFile(r.fileName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return entity.Profiles{}, nil
+		}
+		return entity.Profiles{}, err
+	}
+
+	var profiles entity.Profiles
+	err = json.Unmarshal(content, &profiles)
+	if err != nil {
+		return entity.Profiles{}, fmt.Errorf("profiles file is corrupted: %v", err)
+	}
+	return profiles, nil
+}
+
+// Save реестр перезаписывается через временный файл, чтобы прерванная запись не потеряла остальные профили
+func (r *ProfileRepository) Save(profiles entity.Profiles) error {
+	content, err := json.Marshal(profiles)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(r.fileName, content)
+}
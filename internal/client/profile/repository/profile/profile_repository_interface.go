@@ -0,0 +1,10 @@
+package profile
+
+import "github.com/anoriar/gophkeeper/internal/client/profile/entity"
+
+//go:generate mockgen -source=profile_repository_interface.go -destination=mock_profile_repository/mock_profile_repository.go -package=mock_profile_repository
+type ProfileRepositoryInterface interface {
+	// Get реестр профилей. Пока профили не добавлялись, реестр пустой
+	Get() (entity.Profiles, error)
+	Save(profiles entity.Profiles) error
+}
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: profile_service_interface.go
+
+// Package mock_profile_service is a generated GoMock package.
+package mock_profile_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	command "github.com/anoriar/gophkeeper/internal/client/profile/dto/command"
+	command_response "github.com/anoriar/gophkeeper/internal/client/profile/dto/command_response"
+	entity "github.com/anoriar/gophkeeper/internal/client/profile/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockProfileServiceInterface is a mock of ProfileServiceInterface interface.
+type MockProfileServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfileServiceInterfaceMockRecorder
+}
+
+// MockProfileServiceInterfaceMockRecorder is the mock recorder for MockProfileServiceInterface.
+type MockProfileServiceInterfaceMockRecorder struct {
+	mock *MockProfileServiceInterface
+}
+
+// NewMockProfileServiceInterface creates a new mock instance.
+func NewMockProfileServiceInterface(ctrl *gomock.Controller) *MockProfileServiceInterface {
+	mock := &MockProfileServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockProfileServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProfileServiceInterface) EXPECT() *MockProfileServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockProfileServiceInterface) Add(ctx context.Context, command command.AddProfileCommand) (command_response.ProfileResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, command)
+	ret0, _ := ret[0].(command_response.ProfileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockProfileServiceInterfaceMockRecorder) Add(ctx, command interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockProfileServiceInterface)(nil).Add), ctx, command)
+}
+
+// GetDataDirName mocks base method.
+func (m *MockProfileServiceInterface) GetDataDirName(name string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDataDirName", name)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetDataDirName indicates an expected call of GetDataDirName.
+func (mr *MockProfileServiceInterfaceMockRecorder) GetDataDirName(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataDirName", reflect.TypeOf((*MockProfileServiceInterface)(nil).GetDataDirName), name)
+}
+
+// List mocks base method.
+func (m *MockProfileServiceInterface) List(ctx context.Context, command command.ListProfilesCommand) ([]command_response.ProfileResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, command)
+	ret0, _ := ret[0].([]command_response.ProfileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockProfileServiceInterfaceMockRecorder) List(ctx, command interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockProfileServiceInterface)(nil).List), ctx, command)
+}
+
+// Remove mocks base method.
+func (m *MockProfileServiceInterface) Remove(ctx context.Context, command command.RemoveProfileCommand) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, command)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockProfileServiceInterfaceMockRecorder) Remove(ctx, command interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockProfileServiceInterface)(nil).Remove), ctx, command)
+}
+
+// Resolve mocks base method.
+func (m *MockProfileServiceInterface) Resolve(name string) (entity.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", name)
+	ret0, _ := ret[0].(entity.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockProfileServiceInterfaceMockRecorder) Resolve(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockProfileServiceInterface)(nil).Resolve), name)
+}
+
+// SetDefault mocks base method.
+func (m *MockProfileServiceInterface) SetDefault(ctx context.Context, command command.DefaultProfileCommand) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefault", ctx, command)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDefault indicates an expected call of SetDefault.
+func (mr *MockProfileServiceInterfaceMockRecorder) SetDefault(ctx, command interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefault", reflect.TypeOf((*MockProfileServiceInterface)(nil).SetDefault), ctx, command)
+}
@@ -0,0 +1,183 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"go.uber.org/zap"
+
+	"github.com/anoriar/gophkeeper/internal/client/profile/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/profile/dto/command_response"
+	"github.com/anoriar/gophkeeper/internal/client/profile/entity"
+	profileErrors "github.com/anoriar/gophkeeper/internal/client/profile/errors"
+	profileRepository "github.com/anoriar/gophkeeper/internal/client/profile/repository/profile"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+)
+
+const profilesDirName = "profiles"
+
+var profileNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+type ProfileService struct {
+	profileRepository profileRepository.ProfileRepositoryInterface
+	// rootDataDirName - DATA_DIRNAME: в нем реестр, файлы профиля default и каталоги остальных профилей
+	rootDataDirName string
+	// activeName - профиль, выбранный Resolve
+	activeName string
+	logger     *zap.Logger
+}
+
+func NewProfileService(profileRepository profileRepository.ProfileRepositoryInterface, rootDataDirName string, logger *zap.Logger) *ProfileService {
+	return &ProfileService{
+		profileRepository: profileRepository,
+		rootDataDirName:   rootDataDirName,
+		logger:            logger,
+	}
+}
+
+func (s *ProfileService) Resolve(name string) (entity.Profile, error) {
+	profiles, err := s.getProfiles()
+	if err != nil {
+		return entity.Profile{}, err
+	}
+	if name == "" {
+		name = profiles.GetDefault()
+	}
+	profile, ok := profiles.Find(name)
+	if !ok {
+		return entity.Profile{}, fmt.Errorf("%w: %s", profileErrors.ErrProfileNotFound, name)
+	}
+	s.activeName = profile.Name
+	return profile, nil
+}
+
+func (s *ProfileService) GetDataDirName(name string) string {
+	if name == entity.DefaultProfileName {
+		return s.rootDataDirName
+	}
+	return filepath.Join(s.rootDataDirName, profilesDirName, name)
+}
+
+func (s *ProfileService) List(ctx context.Context, command command.ListProfilesCommand) ([]command_response.ProfileResponse, error) {
+	profiles, err := s.getProfiles()
+	if err != nil {
+		return nil, err
+	}
+	responseProfiles := make([]command_response.ProfileResponse, 0, len(profiles.Profiles)+1)
+	if _, ok := s.findRegistered(profiles, entity.DefaultProfileName); !ok {
+		responseProfiles = append(responseProfiles, s.createResponse(entity.Profile{Name: entity.DefaultProfileName}, profiles))
+	}
+	for _, profile := range profiles.Profiles {
+		responseProfiles = append(responseProfiles, s.createResponse(profile, profiles))
+	}
+	return responseProfiles, nil
+}
+
+func (s *ProfileService) Add(ctx context.Context, command command.AddProfileCommand) (command_response.ProfileResponse, error) {
+	if !profileNameRegexp.MatchString(command.Name) {
+		return command_response.ProfileResponse{}, fmt.Errorf("%w: %q", profileErrors.ErrProfileNameNotValid, command.Name)
+	}
+	profiles, err := s.getProfiles()
+	if err != nil {
+		return command_response.ProfileResponse{}, err
+	}
+	if _, ok := profiles.Find(command.Name); ok {
+		return command_response.ProfileResponse{}, fmt.Errorf("%w: %s", profileErrors.ErrProfileExists, command.Name)
+	}
+
+	profile := entity.Profile{Name: command.Name, ServerAddress: command.ServerAddress}
+	profiles.Profiles = append(profiles.Profiles, profile)
+	err = s.saveProfiles(profiles)
+	if err != nil {
+		return command_response.ProfileResponse{}, err
+	}
+	return s.createResponse(profile, profiles), nil
+}
+
+func (s *ProfileService) Remove(ctx context.Context, command command.RemoveProfileCommand) error {
+	if command.Name == entity.DefaultProfileName {
+		return profileErrors.ErrDefaultProfileRemove
+	}
+	// файлы активного профиля открыты этим процессом
+	if command.Name == s.activeName {
+		return fmt.Errorf("%w: %s", profileErrors.ErrActiveProfileRemove, command.Name)
+	}
+	profiles, err := s.getProfiles()
+	if err != nil {
+		return err
+	}
+	i, ok := s.findRegistered(profiles, command.Name)
+	if !ok {
+		return fmt.Errorf("%w: %s", profileErrors.ErrProfileNotFound, command.Name)
+	}
+
+	profiles.Profiles = append(profiles.Profiles[:i], profiles.Profiles[i+1:]...)
+	if profiles.Default == command.Name {
+		profiles.Default = ""
+	}
+	err = s.saveProfiles(profiles)
+	if err != nil {
+		return err
+	}
+
+	if command.Purge {
+		err = os.RemoveAll(s.GetDataDirName(command.Name))
+		if err != nil {
+			s.logger.Error("remove profile data error", zap.String("error", err.Error()))
+			return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+		}
+	}
+	return nil
+}
+
+func (s *ProfileService) SetDefault(ctx context.Context, command command.DefaultProfileCommand) error {
+	profiles, err := s.getProfiles()
+	if err != nil {
+		return err
+	}
+	if _, ok := profiles.Find(command.Name); !ok {
+		return fmt.Errorf("%w: %s", profileErrors.ErrProfileNotFound, command.Name)
+	}
+	profiles.Default = command.Name
+	return s.saveProfiles(profiles)
+}
+
+func (s *ProfileService) findRegistered(profiles entity.Profiles, name string) (int, bool) {
+	for i, profile := range profiles.Profiles {
+		if profile.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (s *ProfileService) createResponse(profile entity.Profile, profiles entity.Profiles) command_response.ProfileResponse {
+	return command_response.ProfileResponse{
+		Name:          profile.Name,
+		ServerAddress: profile.ServerAddress,
+		DataDirName:   s.GetDataDirName(profile.Name),
+		IsDefault:     profile.Name == profiles.GetDefault(),
+		IsActive:      profile.Name == s.activeName,
+	}
+}
+
+func (s *ProfileService) getProfiles() (entity.Profiles, error) {
+	profiles, err := s.profileRepository.Get()
+	if err != nil {
+		s.logger.Error("get profiles error", zap.String("error", err.Error()))
+		return entity.Profiles{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	return profiles, nil
+}
+
+func (s *ProfileService) saveProfiles(profiles entity.Profiles) error {
+	err := s.profileRepository.Save(profiles)
+	if err != nil {
+		s.logger.Error("save profiles error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package profile
+
+import (
+	"context"
+
+	"github.com/anoriar/gophkeeper/internal/client/profile/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/profile/dto/command_response"
+	"github.com/anoriar/gophkeeper/internal/client/profile/entity"
+)
+
+//go:generate mockgen -source=profile_service_interface.go -destination=mock_profile_service/mock_profile_service.go -package=mock_profile_service
+type ProfileServiceInterface interface {
+	// Resolve выбирает активный профиль: по имени из --profile или PROFILE, иначе профиль по умолчанию
+	Resolve(name string) (entity.Profile, error)
+	// GetDataDirName каталог с токеном, файлами хранилища и ключами профиля
+	GetDataDirName(name string) string
+	// List Список профилей
+	List(ctx context.Context, command command.ListProfilesCommand) ([]command_response.ProfileResponse, error)
+	// Add Добавление профиля
+	Add(ctx context.Context, command command.AddProfileCommand) (command_response.ProfileResponse, error)
+	// Remove Удаление профиля из реестра, с Purge - вместе с файлами профиля
+	Remove(ctx context.Context, command command.RemoveProfileCommand) error
+	// SetDefault Смена профиля по умолчанию
+	SetDefault(ctx context.Context, command command.DefaultProfileCommand) error
+}
@@ -0,0 +1,346 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anoriar/gophkeeper/internal/client/profile/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/profile/dto/command_response"
+	"github.com/anoriar/gophkeeper/internal/client/profile/entity"
+	profileErrors "github.com/anoriar/gophkeeper/internal/client/profile/errors"
+	"github.com/anoriar/gophkeeper/internal/client/profile/repository/profile/mock_profile_repository"
+	"github.com/anoriar/gophkeeper/internal/client/shared/app/logger"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+)
+
+const testRootDataDirName = "/tmp/gophkeeper"
+
+var testProfiles = entity.Profiles{
+	Default: "work",
+	Profiles: []entity.Profile{
+		{Name: "work", ServerAddress: "https://work.example.com"},
+		{Name: "personal"},
+	},
+}
+
+func TestProfileService_Resolve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	profileRepositoryMock := mock_profile_repository.NewMockProfileRepositoryInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		profileName   string
+		mockBehaviour func()
+		want          entity.Profile
+		wantErr       error
+	}{
+		{
+			name: "success default profile without registry",
+			mockBehaviour: func() {
+				profileRepositoryMock.EXPECT().Get().Return(entity.Profiles{}, nil)
+			},
+			want: entity.Profile{Name: entity.DefaultProfileName},
+		},
+		{
+			name: "success default profile from registry",
+			mockBehaviour: func() {
+				profileRepositoryMock.EXPECT().Get().Return(testProfiles, nil)
+			},
+			want: entity.Profile{Name: "work", ServerAddress: "https://work.example.com"},
+		},
+		{
+			name:        "success named profile",
+			profileName: "personal",
+			mockBehaviour: func() {
+				profileRepositoryMock.EXPECT().Get().Return(testProfiles, nil)
+			},
+			want: entity.Profile{Name: "personal"},
+		},
+		{
+			name:        "profile not found error",
+			profileName: "unknown",
+			mockBehaviour: func() {
+				profileRepositoryMock.EXPECT().Get().Return(testProfiles, nil)
+			},
+			wantErr: profileErrors.ErrProfileNotFound,
+		},
+		{
+			name: "get profiles internal error",
+			mockBehaviour: func() {
+				profileRepositoryMock.EXPECT().Get().Return(entity.Profiles{}, errors.New("error"))
+			},
+			wantErr: sharedErrors.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour()
+			s := NewProfileService(profileRepositoryMock, testRootDataDirName, loggerMock)
+			got, err := s.Resolve(tt.profileName)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProfileService_GetDataDirName(t *testing.T) {
+	s := NewProfileService(nil, testRootDataDirName, nil)
+	// файлы профиля default остаются там, где их хранили версии клиента без профилей
+	assert.Equal(t, testRootDataDirName, s.GetDataDirName(entity.DefaultProfileName))
+	assert.Equal(t, filepath.Join(testRootDataDirName, "profiles", "work"), s.GetDataDirName("work"))
+}
+
+func TestProfileService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	profileRepositoryMock := mock_profile_repository.NewMockProfileRepositoryInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	profileRepositoryMock.EXPECT().Get().Return(testProfiles, nil).Times(2)
+
+	s := NewProfileService(profileRepositoryMock, testRootDataDirName, loggerMock)
+	_, err = s.Resolve("personal")
+	require.NoError(t, err)
+
+	got, err := s.List(context.Background(), command.ListProfilesCommand{})
+	require.NoError(t, err)
+	assert.Equal(t, []command_response.ProfileResponse{
+		{
+			Name:        entity.DefaultProfileName,
+			DataDirName: testRootDataDirName,
+		},
+		{
+			Name:          "work",
+			ServerAddress: "https://work.example.com",
+			DataDirName:   filepath.Join(testRootDataDirName, "profiles", "work"),
+			IsDefault:     true,
+		},
+		{
+			Name:        "personal",
+			DataDirName: filepath.Join(testRootDataDirName, "profiles", "personal"),
+			IsActive:    true,
+		},
+	}, got)
+}
+
+func TestProfileService_Add(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	profileRepositoryMock := mock_profile_repository.NewMockProfileRepositoryInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		command       command.AddProfileCommand
+		mockBehaviour func()
+		want          command_response.ProfileResponse
+		wantErr       error
+	}{
+		{
+			name:    "success",
+			command: command.AddProfileCommand{Name: "work", ServerAddress: "https://work.example.com"},
+			mockBehaviour: func() {
+				profileRepositoryMock.EXPECT().Get().Return(entity.Profiles{}, nil)
+				profileRepositoryMock.EXPECT().Save(entity.Profiles{
+					Profiles: []entity.Profile{{Name: "work", ServerAddress: "https://work.example.com"}},
+				}).Return(nil)
+			},
+			want: command_response.ProfileResponse{
+				Name:          "work",
+				ServerAddress: "https://work.example.com",
+				DataDirName:   filepath.Join(testRootDataDirName, "profiles", "work"),
+			},
+		},
+		{
+			name:          "not valid name error",
+			command:       command.AddProfileCommand{Name: "../work"},
+			mockBehaviour: func() {},
+			wantErr:       profileErrors.ErrProfileNameNotValid,
+		},
+		{
+			name:    "profile exists error",
+			command: command.AddProfileCommand{Name: "work"},
+			mockBehaviour: func() {
+				profileRepositoryMock.EXPECT().Get().Return(testProfiles, nil)
+			},
+			wantErr: profileErrors.ErrProfileExists,
+		},
+		{
+			name:    "default profile exists error",
+			command: command.AddProfileCommand{Name: entity.DefaultProfileName},
+			mockBehaviour: func() {
+				profileRepositoryMock.EXPECT().Get().Return(entity.Profiles{}, nil)
+			},
+			wantErr: profileErrors.ErrProfileExists,
+		},
+		{
+			name:    "save internal error",
+			command: command.AddProfileCommand{Name: "work"},
+			mockBehaviour: func() {
+				profileRepositoryMock.EXPECT().Get().Return(entity.Profiles{}, nil)
+				profileRepositoryMock.EXPECT().Save(gomock.Any()).Return(errors.New("error"))
+			},
+			wantErr: sharedErrors.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour()
+			s := NewProfileService(profileRepositoryMock, testRootDataDirName, loggerMock)
+			got, err := s.Add(context.Background(), tt.command)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProfileService_Remove(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	profileRepositoryMock := mock_profile_repository.NewMockProfileRepositoryInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	rootDataDirName := t.TempDir()
+	workDataDirName := filepath.Join(rootDataDirName, "profiles", "work")
+
+	tests := []struct {
+		name          string
+		command       command.RemoveProfileCommand
+		mockBehaviour func()
+		wantDataDir   bool
+		wantErr       error
+	}{
+		{
+			name:    "success keep profile files",
+			command: command.RemoveProfileCommand{Name: "work"},
+			mockBehaviour: func() {
+				profileRepositoryMock.EXPECT().Get().Return(entity.Profiles{
+					Default:  "work",
+					Profiles: []entity.Profile{{Name: "work"}, {Name: "personal"}},
+				}, nil)
+				// профиль по умолчанию удален - по умолчанию снова default
+				profileRepositoryMock.EXPECT().Save(entity.Profiles{
+					Profiles: []entity.Profile{{Name: "personal"}},
+				}).Return(nil)
+			},
+			wantDataDir: true,
+		},
+		{
+			name:    "success purge profile files",
+			command: command.RemoveProfileCommand{Name: "work", Purge: true},
+			mockBehaviour: func() {
+				profileRepositoryMock.EXPECT().Get().Return(entity.Profiles{
+					Profiles: []entity.Profile{{Name: "work"}},
+				}, nil)
+				profileRepositoryMock.EXPECT().Save(entity.Profiles{Profiles: []entity.Profile{}}).Return(nil)
+			},
+			wantDataDir: false,
+		},
+		{
+			name:          "default profile error",
+			command:       command.RemoveProfileCommand{Name: entity.DefaultProfileName},
+			mockBehaviour: func() {},
+			wantErr:       profileErrors.ErrDefaultProfileRemove,
+		},
+		{
+			name:          "active profile error",
+			command:       command.RemoveProfileCommand{Name: "personal"},
+			mockBehaviour: func() {},
+			wantErr:       profileErrors.ErrActiveProfileRemove,
+		},
+		{
+			name:    "profile not found error",
+			command: command.RemoveProfileCommand{Name: "unknown"},
+			mockBehaviour: func() {
+				profileRepositoryMock.EXPECT().Get().Return(testProfiles, nil)
+			},
+			wantErr: profileErrors.ErrProfileNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, os.MkdirAll(filepath.Join(workDataDirName, "secret"), 0700))
+			tt.mockBehaviour()
+			s := NewProfileService(profileRepositoryMock, rootDataDirName, loggerMock)
+			s.activeName = "personal"
+			err := s.Remove(context.Background(), tt.command)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			_, statErr := os.Stat(workDataDirName)
+			assert.Equal(t, tt.wantDataDir, statErr == nil)
+		})
+	}
+}
+
+func TestProfileService_SetDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	profileRepositoryMock := mock_profile_repository.NewMockProfileRepositoryInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		command       command.DefaultProfileCommand
+		mockBehaviour func()
+		wantErr       error
+	}{
+		{
+			name:    "success",
+			command: command.DefaultProfileCommand{Name: "personal"},
+			mockBehaviour: func() {
+				profileRepositoryMock.EXPECT().Get().Return(testProfiles, nil)
+				profileRepositoryMock.EXPECT().Save(entity.Profiles{Default: "personal", Profiles: testProfiles.Profiles}).Return(nil)
+			},
+		},
+		{
+			name:    "profile not found error",
+			command: command.DefaultProfileCommand{Name: "unknown"},
+			mockBehaviour: func() {
+				profileRepositoryMock.EXPECT().Get().Return(testProfiles, nil)
+			},
+			wantErr: profileErrors.ErrProfileNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour()
+			s := NewProfileService(profileRepositoryMock, testRootDataDirName, loggerMock)
+			err := s.SetDefault(context.Background(), tt.command)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
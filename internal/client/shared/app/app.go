@@ -1,24 +1,50 @@
 package app
 
 import (
+	"context"
+	"fmt"
+	"os"
+
 	"go.uber.org/zap"
 
 	"github.com/anoriar/gophkeeper/internal/client/shared/services/uuid"
 
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
 	entryFactoryPkg "github.com/anoriar/gophkeeper/internal/client/entry/factory"
 	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry_ext"
+	templateRepository "github.com/anoriar/gophkeeper/internal/client/entry/repository/template"
 
 	"github.com/anoriar/gophkeeper/internal/client/entry/services/encoder"
 
 	entryRepositoryPkg "github.com/anoriar/gophkeeper/internal/client/entry/repository/entry"
 	"github.com/anoriar/gophkeeper/internal/client/entry/services/entry"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/inject"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/resolver"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/run"
 	"github.com/anoriar/gophkeeper/internal/client/entry/services/service_provider"
 
+	profileEntity "github.com/anoriar/gophkeeper/internal/client/profile/entity"
+	profileRepository "github.com/anoriar/gophkeeper/internal/client/profile/repository/profile"
+	"github.com/anoriar/gophkeeper/internal/client/profile/services/profile"
+
 	"github.com/anoriar/gophkeeper/internal/client/shared/app/client"
 	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret"
 	"github.com/anoriar/gophkeeper/internal/client/user/repository/user"
 	"github.com/anoriar/gophkeeper/internal/client/user/services/auth"
 
+	"github.com/anoriar/gophkeeper/internal/client/vault/agent"
+	backupRepository "github.com/anoriar/gophkeeper/internal/client/vault/repository/backup"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/manifest"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/rollback"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/vault"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/vault_ext"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/backup"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/keyring"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/migration"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/reencrypt"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/rekey"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/vaultsync"
+
 	loggerPkg "github.com/anoriar/gophkeeper/internal/client/shared/app/logger"
 	"github.com/anoriar/gophkeeper/internal/client/shared/config"
 )
@@ -27,8 +53,15 @@ import (
 type App struct {
 	Config               *config.Config
 	Logger               *zap.Logger
+	ProfileService       profile.ProfileServiceInterface
 	AuthService          auth.AuthServiceInterface
 	EntryServiceProvider service_provider.EntryServiceProviderInterface
+	ResolverService      resolver.ResolverServiceInterface
+	RunService           run.RunServiceInterface
+	InjectService        inject.InjectServiceInterface
+	RekeyService         rekey.RekeyServiceInterface
+	BackupService        backup.BackupServiceInterface
+	Agent                *agent.Agent
 }
 
 // NewApp missing godoc.
@@ -39,69 +72,237 @@ func NewApp(cnf *config.Config) (*App, error) {
 		return nil, err
 	}
 
-	uuidGen := uuid.NewUUIDGenerator()
+	// профиль выбирается до создания остальных сервисов: у каждого профиля свои файлы и сервер
+	profileService, cnf, err := resolveProfile(cnf, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	gophkeeperHttpClient := client.NewHTTPClient(cnf.ServerAddress, logger)
 
 	userRepository := user.NewUserRepository(gophkeeperHttpClient)
-	secretRepository, err := secret.NewSecretRepository(cnf.GetAuthTokenFilename(), cnf.GetMasterPasswordFilename())
+	fileSecretRepository, err := secret.NewSecretRepository(cnf.GetAuthTokenFilename())
+	if err != nil {
+		return nil, err
+	}
+	var secretRepository secret.SecretRepositoryInterface = secret.NewAgentSecretRepository(
+		fileSecretRepository,
+		agent.NewAgentClient(cnf.GetAgentSocketFilename()),
+		agent.NewAgentLauncher(cnf.GetAgentSocketFilename(), []string{"--profile", cnf.Profile, "agent"}),
+	)
+	if cnf.InMemoryKeys {
+		secretRepository = secret.NewMemorySecretRepository(secretRepository)
+	}
+	err = secret.RemoveLegacyMasterPassword(cnf.GetLegacyMasterPasswordFilename())
+	if err != nil {
+		return nil, err
+	}
+	err = secret.RemoveLegacySession(cnf.GetLegacySessionFilename())
 	if err != nil {
 		return nil, err
 	}
-	authService := auth.NewAuthService(userRepository, secretRepository, logger)
 
-	aesEncoder := encoder.NewAesDataEncoder()
+	vaultRepository, err := vault.NewVaultRepository(cnf.GetVaultFilename())
+	if err != nil {
+		return nil, err
+	}
+	keyringService := keyring.NewKeyringService(vaultRepository, secretRepository, cnf.GetKdfParams())
 
-	loginEntryRepository := entryRepositoryPkg.NewEntrySingleFileRepository(cnf.GetLoginFilename())
-	cardEntryRepository := entryRepositoryPkg.NewEntrySingleFileRepository(cnf.GetCardFilename())
-	textEntryRepository := entryRepositoryPkg.NewEntrySingleFileRepository(cnf.GetTextFilename())
-	binEntryRepository := entryRepositoryPkg.NewEntrySingleFileRepository(cnf.GetBinFilename())
+	// файлы хранилища переводятся на текущий формат до того, как их откроет любой репозиторий записей
+	migrationService := newMigrationService(cnf, newEntryStoreMigration(cnf, keyringService, logger), logger)
+	err = migrationService.Migrate(context.Background())
+	if err != nil {
+		return nil, err
+	}
 
-	extEntryRepository := entry_ext.NewEntryExtRepository(gophkeeperHttpClient)
+	dataEncryptor, err := encoder.NewAeadDataEncryptor(cnf.Cipher)
+	if err != nil {
+		return nil, err
+	}
 
-	loginEntryService := entry.NewEntryService(
-		entryFactoryPkg.NewEntryFactory(uuidGen),
-		loginEntryRepository,
-		secretRepository,
-		aesEncoder,
-		extEntryRepository,
+	entryRepositories, historyRepositories, entryStore, err := newEntryRepositories(cnf, keyringService, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	reencryptService := reencrypt.NewReencryptService(
+		[]entryRepositoryPkg.EntryRepositoryInterface{entryRepositories[enum.Login], entryRepositories[enum.Card], entryRepositories[enum.Text], entryRepositories[enum.Bin]},
+		dataEncryptor,
 		logger,
 	)
-	cardEntryService := entry.NewEntryService(
-		entryFactoryPkg.NewEntryFactory(uuidGen),
-		cardEntryRepository,
+	vaultSyncService := vaultsync.NewVaultSyncService(vaultRepository, vault_ext.NewVaultExtRepository(gophkeeperHttpClient), logger)
+	rollbackRepository := rollback.NewRollbackRepository(cnf.GetRekeyRollbackFilename())
+	rekeyService := rekey.NewRekeyService(
+		entryRepositories,
+		historyRepositories,
+		entryStore,
+		vaultRepository,
+		rollbackRepository,
 		secretRepository,
-		aesEncoder,
-		extEntryRepository,
+		keyringService,
+		vaultSyncService,
+		dataEncryptor,
 		logger,
 	)
-	textEntryService := entry.NewEntryService(
-		entryFactoryPkg.NewEntryFactory(uuidGen),
-		textEntryRepository,
+	// хранилище не должно остаться наполовину перешифрованным после прерванного rekey
+	_, err = rekeyService.Recover(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	authService := auth.NewAuthService(userRepository, secretRepository, keyringService, reencryptService, rekeyService, vaultSyncService, migrationService, logger)
+	backupService := backup.NewBackupService(
+		entryRepositories,
+		entryStore,
+		vaultRepository,
+		rollbackRepository,
+		backupRepository.NewBackupRepository(),
 		secretRepository,
-		aesEncoder,
-		extEntryRepository,
+		keyringService,
 		logger,
 	)
 
-	binEntryService := entry.NewEntryService(
-		entryFactoryPkg.NewEntryFactory(uuidGen),
-		binEntryRepository,
-		secretRepository,
-		aesEncoder,
-		extEntryRepository,
-		logger,
-	)
+	extEntryRepository := entry_ext.NewEntryExtRepository(gophkeeperHttpClient)
 
-	entryServiceProvider := service_provider.NewEntryServiceProvider(loginEntryService, cardEntryService, textEntryService, binEntryService)
+	entryServiceProvider := newEntryServiceProvider(cnf, entryRepositories, historyRepositories, secretRepository, dataEncryptor, extEntryRepository, logger)
+	resolverService := resolver.NewResolverService(entryServiceProvider, logger)
 
 	return &App{
 		Config:               cnf,
 		Logger:               logger,
+		ProfileService:       profileService,
 		AuthService:          authService,
 		EntryServiceProvider: entryServiceProvider,
+		ResolverService:      resolverService,
+		RunService:           run.NewRunService(resolverService, os.Stdin, os.Stdout, os.Stderr, logger),
+		InjectService:        inject.NewInjectService(resolverService, templateRepository.NewTemplateRepository(), logger),
+		RekeyService:         rekeyService,
+		BackupService:        backupService,
+		Agent:                agent.NewAgent(cnf.GetAgentSocketFilename(), cnf.AgentIdleTimeout, logger),
 	}, nil
 }
 
+// resolveProfile активный профиль и конфигурация с его файлами и сервером
+func resolveProfile(cnf *config.Config, logger *zap.Logger) (*profile.ProfileService, *config.Config, error) {
+	profileService := profile.NewProfileService(profileRepository.NewProfileRepository(cnf.GetProfilesFilename()), cnf.DataDirName, logger)
+	activeProfile, err := profileService.Resolve(cnf.Profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	cnf = cnf.ForProfile(
+		activeProfile.Name,
+		profileService.GetDataDirName(activeProfile.Name),
+		activeProfile.ServerAddress,
+		activeProfile.Name == profileEntity.DefaultProfileName,
+	)
+	return profileService, cnf, nil
+}
+
+func newMigrationService(cnf *config.Config, migrateEntryStore func(ctx context.Context) error, logger *zap.Logger) *migration.MigrationService {
+	return migration.NewMigrationService(
+		manifest.NewManifestRepository(cnf.GetManifestFilename(), cnf.LockTimeout),
+		migration.NewMigrations(migrateEntryStore),
+		cnf.DataDirName,
+		cnf.GetVaultPaths(),
+		cnf.GetMigrationBackupDirName(),
+		logger,
+	)
+}
+
+// newEntryServiceProvider сервисы записей по типам
+func newEntryServiceProvider(
+	cnf *config.Config,
+	entryRepositories map[enum.EntryType]entryRepositoryPkg.EntryRepositoryInterface,
+	historyRepositories map[enum.EntryType]entryRepositoryPkg.EntryHistoryRepositoryInterface,
+	secretRepository secret.SecretRepositoryInterface,
+	dataEncryptor encoder.DataEncryptorInterface,
+	extEntryRepository entry_ext.EntryExtRepositoryInterface,
+	logger *zap.Logger,
+) *service_provider.EntryServiceProvider {
+	uuidGen := uuid.NewUUIDGenerator()
+	entryServices := make(map[enum.EntryType]*entry.EntryService, len(entryRepositories))
+	for _, entryType := range enum.AllEntryTypes {
+		entryServices[entryType] = entry.NewEntryService(
+			entryFactoryPkg.NewEntryFactory(uuidGen),
+			entryRepositories[entryType],
+			historyRepositories[entryType],
+			secretRepository,
+			dataEncryptor,
+			extEntryRepository,
+			cnf.HistorySize,
+			logger,
+		)
+	}
+	return service_provider.NewEntryServiceProvider(entryServices[enum.Login], entryServices[enum.Card], entryServices[enum.Text], entryServices[enum.Bin])
+}
+
+// newEntryRepositories репозитории записей и их ревизий по типам и хранилище, общее для них
+func newEntryRepositories(cnf *config.Config, storeKeyProvider entryRepositoryPkg.StoreKeyProviderInterface, logger *zap.Logger) (
+	map[enum.EntryType]entryRepositoryPkg.EntryRepositoryInterface,
+	map[enum.EntryType]entryRepositoryPkg.EntryHistoryRepositoryInterface,
+	entryRepositoryPkg.EntryStoreInterface,
+	error,
+) {
+	fileNames := entryFileNames(cnf)
+	repositories := make(map[enum.EntryType]entryRepositoryPkg.EntryRepositoryInterface, len(fileNames))
+	historyRepositories := make(map[enum.EntryType]entryRepositoryPkg.EntryHistoryRepositoryInterface, len(fileNames))
+	var entryStore entryRepositoryPkg.EntryStoreInterface
+
+	switch cnf.VaultStore {
+	case config.StoreFile:
+		logger.Warn("VAULT_STORE=file is not sealed: removed, replaced or rolled back entries are not detected, use bolt store")
+		for entryType, fileName := range fileNames {
+			repositories[entryType] = entryRepositoryPkg.NewEntrySingleFileRepository(fileName, cnf.LockTimeout)
+			historyRepositories[entryType] = entryRepositoryPkg.NewEntryFileHistoryRepository(fileName, cnf.LockTimeout)
+		}
+		entryStore = entryRepositoryPkg.NewEntryFileStore(fileNames)
+	case config.StoreBolt:
+		store := entryRepositoryPkg.NewEntryBoltStore(cnf.GetVaultDbFilename(), storeKeyProvider, cnf.LockTimeout)
+		entryStore = store
+		for entryType := range fileNames {
+			repositories[entryType] = entryRepositoryPkg.NewEntryBoltRepository(store, entryType)
+			historyRepositories[entryType] = entryRepositoryPkg.NewEntryBoltHistoryRepository(store, entryType)
+		}
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown vault store %q, expected %s or %s", cnf.VaultStore, config.StoreBolt, config.StoreFile)
+	}
+	return repositories, historyRepositories, entryStore, nil
+}
+
+// newEntryStoreMigration миграция файлов версии 3: записи из файлов JSON-lines предыдущих версий переносятся в bolt хранилище,
+// а хранилище, созданное без ключа, запечатывается. Для VAULT_STORE=file файлы остаются в прежнем формате
+func newEntryStoreMigration(cnf *config.Config, storeKeyProvider entryRepositoryPkg.StoreKeyProviderInterface, logger *zap.Logger) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if cnf.VaultStore != config.StoreBolt {
+			return nil
+		}
+		store := entryRepositoryPkg.NewEntryBoltStore(cnf.GetVaultDbFilename(), storeKeyProvider, cnf.LockTimeout)
+		for entryType, fileName := range entryFileNames(cnf) {
+			migrated, err := entryRepositoryPkg.MigrateSingleFile(
+				ctx,
+				entryRepositoryPkg.NewEntrySingleFileRepository(fileName, cnf.LockTimeout),
+				entryRepositoryPkg.NewEntryBoltRepository(store, entryType),
+			)
+			if err != nil {
+				return err
+			}
+			if migrated > 0 {
+				logger.Info("entries migrated to bolt store", zap.String("type", string(entryType)), zap.Int("count", migrated))
+			}
+		}
+		return store.Seal()
+	}
+}
+
+// entryFileNames файлы записей по типам: JSON-lines для VAULT_STORE=file и файлы предыдущих версий для bolt
+func entryFileNames(cnf *config.Config) map[enum.EntryType]string {
+	return map[enum.EntryType]string{
+		enum.Login: cnf.GetLoginFilename(),
+		enum.Card:  cnf.GetCardFilename(),
+		enum.Text:  cnf.GetTextFilename(),
+		enum.Bin:   cnf.GetBinFilename(),
+	}
+}
+
 func (app *App) Close() {
 	app.Logger.Sync()
 }
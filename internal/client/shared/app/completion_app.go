@@ -0,0 +1,118 @@
+package app
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	entryRepositoryPkg "github.com/anoriar/gophkeeper/internal/client/entry/repository/entry"
+	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry_ext"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/encoder"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/service_provider"
+	"github.com/anoriar/gophkeeper/internal/client/shared/app/client"
+	loggerPkg "github.com/anoriar/gophkeeper/internal/client/shared/app/logger"
+	"github.com/anoriar/gophkeeper/internal/client/shared/config"
+	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret"
+	"github.com/anoriar/gophkeeper/internal/client/vault/agent"
+	vaultErrors "github.com/anoriar/gophkeeper/internal/client/vault/errors"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/vault"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/keyring"
+)
+
+// CompletionApp сервисы автодополнения id записей. В отличие от NewApp файлы хранилища только читаются:
+// миграции, восстановление после rekey, перенос записей в bolt и запечатывание выполнит следующая команда
+type CompletionApp struct {
+	Logger               *zap.Logger
+	EntryServiceProvider service_provider.EntryServiceProviderInterface
+}
+
+// NewCompletionApp ErrMigrationPending, если файлы хранилища еще не переведены на текущий формат
+func NewCompletionApp(cnf *config.Config) (*CompletionApp, error) {
+	logger, err := loggerPkg.Initialize(cnf.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	_, cnf, err = resolveProfile(cnf, logger)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := newMigrationService(cnf, nil, logger).Pending()
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, vaultErrors.ErrMigrationPending
+	}
+
+	fileSecretRepository, err := secret.NewSecretRepository(cnf.GetAuthTokenFilename())
+	if err != nil {
+		return nil, err
+	}
+	// автодополнение берет ключи у запущенного агента и не запускает его
+	secretRepository := secret.NewAgentSecretRepository(fileSecretRepository, agent.NewAgentClient(cnf.GetAgentSocketFilename()), nil)
+
+	vaultRepository, err := vault.NewVaultRepository(cnf.GetVaultFilename())
+	if err != nil {
+		return nil, err
+	}
+	keyringService := keyring.NewKeyringService(vaultRepository, secretRepository, cnf.GetKdfParams())
+
+	dataEncryptor, err := encoder.NewAeadDataEncryptor(cnf.Cipher)
+	if err != nil {
+		return nil, err
+	}
+
+	entryRepositories, historyRepositories, err := newReadOnlyEntryRepositories(cnf, readOnlyStoreKeyProvider{keyringService})
+	if err != nil {
+		return nil, err
+	}
+	extEntryRepository := entry_ext.NewEntryExtRepository(client.NewHTTPClient(cnf.ServerAddress, logger))
+
+	return &CompletionApp{
+		Logger:               logger,
+		EntryServiceProvider: newEntryServiceProvider(cnf, entryRepositories, historyRepositories, secretRepository, dataEncryptor, extEntryRepository, logger),
+	}, nil
+}
+
+// newReadOnlyEntryRepositories репозитории записей без переноса записей из файлов JSON-lines и без запечатывания
+func newReadOnlyEntryRepositories(cnf *config.Config, storeKeyProvider entryRepositoryPkg.StoreKeyProviderInterface) (
+	map[enum.EntryType]entryRepositoryPkg.EntryRepositoryInterface,
+	map[enum.EntryType]entryRepositoryPkg.EntryHistoryRepositoryInterface,
+	error,
+) {
+	fileNames := entryFileNames(cnf)
+	repositories := make(map[enum.EntryType]entryRepositoryPkg.EntryRepositoryInterface, len(fileNames))
+	historyRepositories := make(map[enum.EntryType]entryRepositoryPkg.EntryHistoryRepositoryInterface, len(fileNames))
+
+	switch cnf.VaultStore {
+	case config.StoreFile:
+		for entryType, fileName := range fileNames {
+			repositories[entryType] = entryRepositoryPkg.NewEntrySingleFileRepository(fileName, cnf.LockTimeout)
+			historyRepositories[entryType] = entryRepositoryPkg.NewEntryFileHistoryRepository(fileName, cnf.LockTimeout)
+		}
+	case config.StoreBolt:
+		store := entryRepositoryPkg.NewEntryBoltStore(cnf.GetVaultDbFilename(), storeKeyProvider, cnf.LockTimeout)
+		for entryType := range fileNames {
+			repositories[entryType] = entryRepositoryPkg.NewEntryBoltRepository(store, entryType)
+			historyRepositories[entryType] = entryRepositoryPkg.NewEntryBoltHistoryRepository(store, entryType)
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown vault store %q, expected %s or %s", cnf.VaultStore, config.StoreBolt, config.StoreFile)
+	}
+	return repositories, historyRepositories, nil
+}
+
+// readOnlyStoreKeyProvider отметку о запечатывании хранилища сохранит следующая команда
+type readOnlyStoreKeyProvider struct {
+	entryRepositoryPkg.StoreKeyProviderInterface
+}
+
+func (p readOnlyStoreKeyProvider) MarkStoreSealed() error {
+	return nil
+}
+
+func (app *CompletionApp) Close() {
+	app.Logger.Sync()
+}
@@ -1,28 +1,145 @@
 package config
 
+import (
+	"path/filepath"
+	"time"
+
+	vaultEntity "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
 const (
 	defaultDataDirName = "./.data"
 	defaultLoginFile   = "/entries/logins"
 	defaultCardFile    = "/entries/cards"
 	defaultTextFile    = "/entries/texts"
 	defaultBinFile     = "/entries/binaries"
+	defaultVaultDbFile = "/entries/vault.db"
+
+	defaultAuthTokenFilename            = "/secret/.token"
+	defaultLegacyMasterPasswordFilename = "/secret/.pass"
+	defaultLegacySessionFilename        = "/secret/.session"
+	defaultVaultFilename                = "/secret/vault.json"
+	defaultAgentSocketFilename          = "/secret/agent.sock"
+	defaultRekeyRollbackFilename        = "/secret/rekey.rollback"
+	defaultProfilesFilename             = "/profiles.json"
+	defaultManifestFilename             = "/manifest.json"
+	defaultMigrationBackupDirName       = "/backups"
+
+	defaultAgentIdleTimeout = 15 * time.Minute
+	defaultShellIdleTimeout = 5 * time.Minute
+	defaultLockTimeout      = 5 * time.Second
 
-	defaultAuthTokenFilename      = "/secret/.token"
-	defaultMasterPasswordFilename = "/secret/.pass"
+	defaultKdfTime    = 3
+	defaultKdfMemory  = 64 * 1024
+	defaultKdfThreads = 4
+
+	defaultCipher = "aes-256-gcm"
+
+	defaultHistorySize = 10
+
+	// StoreBolt - все записи в одном файле bbolt
+	StoreBolt = "bolt"
+	// StoreFile - отдельный файл JSON-lines на каждый тип записей. Не запечатывается ключом хранилища:
+	// удаление, подмена и откат записей в обход клиента не обнаруживаются. Оставлен только для совместимости
+	StoreFile = "file"
 )
 
 // Config missing godoc.
 type Config struct {
 	ServerAddress string `env:"SERVER_ADDRESS"`
-	LogLevel      string `env:"LOG_LEVEL"`
-	DataDirName   string `env:"DATA_DIRNAME"`
+	// ServerAddressOverride - адрес из флага --server, важнее адреса профиля и SERVER_ADDRESS
+	ServerAddressOverride string
+	LogLevel              string `env:"LOG_LEVEL"`
+	DataDirName           string `env:"DATA_DIRNAME"`
+	// Profile - профиль клиента, пустой - профиль по умолчанию. Флаг --profile важнее
+	Profile string `env:"PROFILE"`
+	// AgentSocket - сокет агента, в памяти которого хранятся ключи хранилища. По умолчанию в каталоге данных
+	AgentSocket string `env:"AGENT_SOCKET"`
+	// AgentIdleTimeout - через сколько без обращений агент блокирует хранилище
+	AgentIdleTimeout time.Duration `env:"AGENT_IDLE_TIMEOUT"`
+	// ShellIdleTimeout - через сколько без команд shell блокирует хранилище
+	ShellIdleTimeout time.Duration `env:"SHELL_IDLE_TIMEOUT"`
+	// InMemoryKeys - ключи хранилища только в памяти процесса, не в агенте. Задается командой shell
+	InMemoryKeys bool
+	// LockTimeout - сколько ждать файлы хранилища, занятые другим процессом клиента
+	LockTimeout time.Duration `env:"LOCK_TIMEOUT"`
+
+	// Параметры Argon2id для новых хранилищ
+	KdfTime    uint32 `env:"KDF_TIME"`
+	KdfMemory  uint32 `env:"KDF_MEMORY"`
+	KdfThreads uint8  `env:"KDF_THREADS"`
+
+	// Cipher - алгоритм шифрования новых записей: aes-256-gcm или xchacha20-poly1305
+	Cipher string `env:"CIPHER"`
+	// VaultStore - формат локального хранилища записей: bolt или file
+	VaultStore string `env:"VAULT_STORE"`
+	// HistorySize - сколько предыдущих ревизий каждой записи хранить локально, 0 - не хранить
+	HistorySize int `env:"HISTORY_SIZE"`
 }
 
 // NewConfig missing godoc.
 func NewConfig() *Config {
 	return &Config{
-		LogLevel:    "info",
-		DataDirName: defaultDataDirName,
+		LogLevel:         "info",
+		DataDirName:      defaultDataDirName,
+		AgentIdleTimeout: defaultAgentIdleTimeout,
+		ShellIdleTimeout: defaultShellIdleTimeout,
+		LockTimeout:      defaultLockTimeout,
+		KdfTime:          defaultKdfTime,
+		KdfMemory:        defaultKdfMemory,
+		KdfThreads:       defaultKdfThreads,
+		Cipher:           defaultCipher,
+		VaultStore:       StoreBolt,
+		HistorySize:      defaultHistorySize,
+	}
+}
+
+// ForProfile конфигурация профиля: свой каталог данных и, если задан, свой сервер. Флаг --server важнее сервера профиля.
+// Явно заданный AGENT_SOCKET у каждого профиля свой, иначе агент одного профиля отдал бы ключи другому
+func (cnf *Config) ForProfile(name string, dataDirName string, serverAddress string, isDefault bool) *Config {
+	profileCnf := *cnf
+	profileCnf.Profile = name
+	profileCnf.DataDirName = dataDirName
+	if serverAddress != "" {
+		profileCnf.ServerAddress = serverAddress
+	}
+	if cnf.ServerAddressOverride != "" {
+		profileCnf.ServerAddress = cnf.ServerAddressOverride
+	}
+	if cnf.AgentSocket != "" && !isDefault {
+		profileCnf.AgentSocket = cnf.AgentSocket + "." + name
+	}
+	return &profileCnf
+}
+
+// GetProfilesFilename реестр профилей, общий для всех профилей
+func (cnf *Config) GetProfilesFilename() string {
+	return cnf.DataDirName + defaultProfilesFilename
+}
+
+func (cnf *Config) GetManifestFilename() string {
+	return cnf.DataDirName + defaultManifestFilename
+}
+
+// GetMigrationBackupDirName каталог копий файлов хранилища, сделанных перед миграциями формата
+func (cnf *Config) GetMigrationBackupDirName() string {
+	return cnf.DataDirName + defaultMigrationBackupDirName
+}
+
+// GetVaultPaths файлы и каталоги хранилища, формат которых описывает манифест
+func (cnf *Config) GetVaultPaths() []string {
+	return []string{
+		filepath.Dir(cnf.GetVaultDbFilename()),
+		cnf.GetVaultFilename(),
+	}
+}
+
+// GetKdfParams параметры Argon2id для новых слотов хранилища
+func (cnf *Config) GetKdfParams() vaultEntity.KdfParams {
+	return vaultEntity.KdfParams{
+		Time:    cnf.KdfTime,
+		Memory:  cnf.KdfMemory,
+		Threads: cnf.KdfThreads,
 	}
 }
 
@@ -30,8 +147,33 @@ func (cnf *Config) GetAuthTokenFilename() string {
 	return cnf.DataDirName + defaultAuthTokenFilename
 }
 
-func (cnf *Config) GetMasterPasswordFilename() string {
-	return cnf.DataDirName + defaultMasterPasswordFilename
+// GetLegacyMasterPasswordFilename файл, в котором предыдущие версии клиента хранили мастер-пароль
+func (cnf *Config) GetLegacyMasterPasswordFilename() string {
+	return cnf.DataDirName + defaultLegacyMasterPasswordFilename
+}
+
+// GetLegacySessionFilename файл, в котором предыдущие версии клиента хранили ключи разблокированного хранилища
+func (cnf *Config) GetLegacySessionFilename() string {
+	return cnf.DataDirName + defaultLegacySessionFilename
+}
+
+func (cnf *Config) GetAgentSocketFilename() string {
+	if cnf.AgentSocket != "" {
+		return cnf.AgentSocket
+	}
+	return cnf.DataDirName + defaultAgentSocketFilename
+}
+
+func (cnf *Config) GetVaultFilename() string {
+	return cnf.DataDirName + defaultVaultFilename
+}
+
+func (cnf *Config) GetRekeyRollbackFilename() string {
+	return cnf.DataDirName + defaultRekeyRollbackFilename
+}
+
+func (cnf *Config) GetVaultDbFilename() string {
+	return cnf.DataDirName + defaultVaultDbFile
 }
 
 func (cnf *Config) GetLoginFilename() string {
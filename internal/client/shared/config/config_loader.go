@@ -15,5 +15,10 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("parse env error: %v", err)
 	}
 
+	err = conf.GetKdfParams().Validate()
+	if err != nil {
+		return nil, fmt.Errorf("KDF_TIME, KDF_MEMORY, KDF_THREADS: %w", err)
+	}
+
 	return conf, nil
 }
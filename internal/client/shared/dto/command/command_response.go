@@ -1,7 +1,14 @@
 package command
 
+const (
+	StatusSuccess = "success"
+	StatusFail    = "fail"
+)
+
 type CommandResponse struct {
 	Status  string      `json:"status"`
 	Error   string      `json:"error"`
 	Payload interface{} `json:"payload"`
+	// ExitCode код завершения клиента по классу ошибки, в ответ не выводится
+	ExitCode int `json:"-"`
 }
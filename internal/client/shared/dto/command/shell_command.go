@@ -0,0 +1,11 @@
+package command
+
+import validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+
+// ShellCommand интерактивный режим: хранилище разблокируется один раз на сессию
+type ShellCommand struct {
+}
+
+func (command *ShellCommand) Validate() validation.ValidationErrors {
+	return nil
+}
@@ -0,0 +1,43 @@
+package errors
+
+import "errors"
+
+// Коды завершения клиента. Свой код получают классы ошибок, которые скрипту нужно различать, остальные - ExitCodeError
+const (
+	ExitCodeSuccess             = 0
+	ExitCodeError               = 1
+	ExitCodeUsage               = 2
+	ExitCodeNotLoggedIn         = 3
+	ExitCodeEntryNotFound       = 4
+	ExitCodeWrongMasterPassword = 5
+	ExitCodeServerUnreachable   = 6
+	ExitCodeSyncConflict        = 7
+	ExitCodeCorruptedEntry      = 8
+)
+
+// exitCodes ошибка относится к одному классу: расшифровка возвращает либо ErrWrongMasterPassword, либо ErrCorruptedEntry.
+// Ошибки сервисов обернуты в ErrInternalError
+var exitCodes = []struct {
+	err  error
+	code int
+}{
+	{err: ErrNotLoggedIn, code: ExitCodeNotLoggedIn},
+	{err: ErrEntryNotFound, code: ExitCodeEntryNotFound},
+	{err: ErrWrongMasterPassword, code: ExitCodeWrongMasterPassword},
+	{err: ErrServerUnreachable, code: ExitCodeServerUnreachable},
+	{err: ErrSyncConflict, code: ExitCodeSyncConflict},
+	{err: ErrCorruptedEntry, code: ExitCodeCorruptedEntry},
+}
+
+// ExitCode код завершения по ошибке команды, nil - ExitCodeSuccess
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeSuccess
+	}
+	for _, exitCode := range exitCodes {
+		if errors.Is(err, exitCode.err) {
+			return exitCode.code
+		}
+	}
+	return ExitCodeError
+}
@@ -0,0 +1,72 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "success",
+			want: ExitCodeSuccess,
+		},
+		{
+			name: "not logged in wrapped by service",
+			err:  fmt.Errorf("%w: %w", ErrInternalError, fmt.Errorf("%w: vault is locked", ErrNotLoggedIn)),
+			want: ExitCodeNotLoggedIn,
+		},
+		{
+			name: "entry not found",
+			err:  fmt.Errorf("%w: %w", ErrEntryNotFound, ErrEntryNotFound),
+			want: ExitCodeEntryNotFound,
+		},
+		{
+			name: "wrong master password",
+			err:  fmt.Errorf("old master password: %w", ErrWrongMasterPassword),
+			want: ExitCodeWrongMasterPassword,
+		},
+		{
+			name: "corrupted entry",
+			err:  fmt.Errorf("%w: %w", ErrInternalError, fmt.Errorf("%w: cipher error", ErrCorruptedEntry)),
+			want: ExitCodeCorruptedEntry,
+		},
+		{
+			name: "entry not decrypted by unchecked key",
+			err:  fmt.Errorf("entry %s: %w", "ef77aba6", fmt.Errorf("%w: cipher: message authentication failed", ErrWrongMasterPassword)),
+			want: ExitCodeWrongMasterPassword,
+		},
+		{
+			name: "server unreachable",
+			err:  fmt.Errorf("login error: %w", fmt.Errorf("%w: connection refused", ErrServerUnreachable)),
+			want: ExitCodeServerUnreachable,
+		},
+		{
+			name: "sync conflict",
+			err:  fmt.Errorf("%w: %w", ErrInternalError, ErrSyncConflict),
+			want: ExitCodeSyncConflict,
+		},
+		{
+			name: "server error response",
+			err:  fmt.Errorf("%w: bad gateway", ErrDependencyFailure),
+			want: ExitCodeError,
+		},
+		{
+			name: "other error",
+			err:  errors.New("other error"),
+			want: ExitCodeError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCode(tt.err))
+		})
+	}
+}
@@ -5,3 +5,16 @@ import "errors"
 var ErrDependencyFailure = errors.New("dependency failure")
 var ErrInternalError = errors.New("internal error")
 var ErrEntryNotFound = errors.New("entry not found")
+var ErrWrongMasterPassword = errors.New("wrong master password")
+var ErrCorruptedEntry = errors.New("entry is corrupted")
+var ErrVaultBusy = errors.New("vault is busy: another gophkeeper process is using it, try again later")
+var ErrVaultTampered = errors.New("vault integrity check failed: local vault files were modified outside gophkeeper")
+
+// ErrNotLoggedIn нет токена, сессия истекла или хранилище заблокировано: нужен login
+var ErrNotLoggedIn = errors.New("not logged in")
+
+// ErrServerUnreachable запрос не дошел до сервера. Ответ с ошибкой - ErrDependencyFailure
+var ErrServerUnreachable = errors.New("server unreachable")
+
+// ErrSyncConflict сервер отклонил синхронизацию: записи изменены на сервере
+var ErrSyncConflict = errors.New("sync entries conflict")
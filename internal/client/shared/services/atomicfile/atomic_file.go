@@ -0,0 +1,75 @@
+package atomicfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+const tempFileSuffix = ".tmp"
+
+// CreateTemp создает временный файл рядом с fileName с правами 0600.
+// Имя файла случайное, а файл создается с O_EXCL, поэтому заранее подложенный файл или symlink не будет перезаписан
+func CreateTemp(fileName string) (*os.File, error) {
+	dir := filepath.Dir(fileName)
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.CreateTemp(dir, filepath.Base(fileName)+".*"+tempFileSuffix)
+	if err != nil {
+		return nil, err
+	}
+	err = file.Chmod(0600)
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return nil, err
+	}
+	return file, nil
+}
+
+// WriteFile заменяет fileName содержимым content. После сбоя остается либо старый, либо новый файл целиком
+func WriteFile(fileName string, content []byte) error {
+	file, err := CreateTemp(fileName)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(content)
+	if err == nil {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	return Replace(file.Name(), fileName)
+}
+
+// Replace переименовывает готовый временный файл в fileName и фиксирует переименование на диске.
+// При ошибке переименования временный файл удаляется
+func Replace(tempFileName string, fileName string) error {
+	err := os.Rename(tempFileName, fileName)
+	if err != nil {
+		removeErr := os.Remove(tempFileName)
+		if removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+			return errors.Join(err, removeErr)
+		}
+		return err
+	}
+	return SyncDir(filepath.Dir(fileName))
+}
+
+// SyncDir фиксирует на диске переименование файла в директории
+func SyncDir(dirName string) error {
+	dir, err := os.Open(dirName)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
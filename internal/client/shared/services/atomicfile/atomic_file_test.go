@@ -0,0 +1,62 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, fileName string)
+	}{
+		{
+			name:    "new file in missing dir",
+			prepare: func(t *testing.T, fileName string) {},
+		},
+		{
+			name: "replace existing file",
+			prepare: func(t *testing.T, fileName string) {
+				require.NoError(t, os.MkdirAll(filepath.Dir(fileName), 0700))
+				require.NoError(t, os.WriteFile(fileName, []byte("old content"), 0644))
+			},
+		},
+		{
+			name: "planted tmp symlink is not followed",
+			prepare: func(t *testing.T, fileName string) {
+				require.NoError(t, os.MkdirAll(filepath.Dir(fileName), 0700))
+				target := filepath.Join(t.TempDir(), "target")
+				require.NoError(t, os.WriteFile(target, []byte("target"), 0644))
+				require.NoError(t, os.Symlink(target, fileName+".tmp"))
+				t.Cleanup(func() {
+					content, err := os.ReadFile(target)
+					require.NoError(t, err)
+					assert.Equal(t, "target", string(content))
+				})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "dir", "file.json")
+			tt.prepare(t, fileName)
+
+			require.NoError(t, WriteFile(fileName, []byte("content")))
+
+			content, err := os.ReadFile(fileName)
+			require.NoError(t, err)
+			assert.Equal(t, "content", string(content))
+			info, err := os.Stat(fileName)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+			temps, err := filepath.Glob(fileName + ".*" + tempFileSuffix)
+			require.NoError(t, err)
+			assert.Empty(t, temps)
+		})
+	}
+}
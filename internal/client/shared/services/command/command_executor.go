@@ -5,9 +5,12 @@ import (
 	"errors"
 
 	entryCommandPkg "github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
+	profileCommandPkg "github.com/anoriar/gophkeeper/internal/client/profile/dto/command"
 	"github.com/anoriar/gophkeeper/internal/client/shared/app"
 	sharedCommand "github.com/anoriar/gophkeeper/internal/client/shared/dto/command"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
 	userCommandPkg "github.com/anoriar/gophkeeper/internal/client/user/dto/command"
+	vaultCommandPkg "github.com/anoriar/gophkeeper/internal/client/vault/dto/command"
 )
 
 var ErrNotExecuted = errors.New("command did not executed")
@@ -21,16 +24,23 @@ func NewCommandExecutor(app *app.App) *CommandExecutor {
 	return &CommandExecutor{app: app}
 }
 func (sp *CommandExecutor) prepareCommandResponse(payload interface{}, error error) sharedCommand.CommandResponse {
-	status := "success"
+	status := sharedCommand.StatusSuccess
 	errorStr := ""
 	if error != nil {
 		errorStr = error.Error()
-		status = "fail"
+		// занятое другим процессом или измененное вне клиента хранилище - не внутренняя ошибка, ее нужно показать как есть
+		if errors.Is(error, sharedErrors.ErrVaultBusy) {
+			errorStr = sharedErrors.ErrVaultBusy.Error()
+		} else if errors.Is(error, sharedErrors.ErrVaultTampered) {
+			errorStr = sharedErrors.ErrVaultTampered.Error()
+		}
+		status = sharedCommand.StatusFail
 	}
 	return sharedCommand.CommandResponse{
-		Status:  status,
-		Error:   errorStr,
-		Payload: payload,
+		Status:   status,
+		Error:    errorStr,
+		Payload:  payload,
+		ExitCode: sharedErrors.ExitCode(error),
 	}
 }
 
@@ -48,6 +58,78 @@ func (sp *CommandExecutor) ExecuteCommand(ctx context.Context, command sharedCom
 			return sp.prepareCommandResponse(nil, err)
 		}
 		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *userCommandPkg.UnlockCommand:
+		if cmd, ok := command.(*userCommandPkg.UnlockCommand); ok {
+			err := sp.app.AuthService.Unlock(ctx, *cmd)
+			return sp.prepareCommandResponse(nil, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *userCommandPkg.LockCommand:
+		if _, ok := command.(*userCommandPkg.LockCommand); ok {
+			err := sp.app.AuthService.Lock(ctx)
+			return sp.prepareCommandResponse(nil, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *vaultCommandPkg.AgentCommand:
+		if _, ok := command.(*vaultCommandPkg.AgentCommand); ok {
+			err := sp.app.Agent.Run(ctx)
+			return sp.prepareCommandResponse(nil, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *vaultCommandPkg.RekeyCommand:
+		if cmd, ok := command.(*vaultCommandPkg.RekeyCommand); ok {
+			err := sp.app.RekeyService.Rekey(ctx, *cmd)
+			return sp.prepareCommandResponse(nil, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *vaultCommandPkg.BackupCommand:
+		if cmd, ok := command.(*vaultCommandPkg.BackupCommand); ok {
+			backup, err := sp.app.BackupService.Backup(ctx, *cmd)
+			if err != nil {
+				return sp.prepareCommandResponse(nil, err)
+			}
+			return sp.prepareCommandResponse(backup, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *vaultCommandPkg.RestoreBackupCommand:
+		if cmd, ok := command.(*vaultCommandPkg.RestoreBackupCommand); ok {
+			backup, err := sp.app.BackupService.Restore(ctx, *cmd)
+			if err != nil {
+				return sp.prepareCommandResponse(nil, err)
+			}
+			return sp.prepareCommandResponse(backup, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *profileCommandPkg.ListProfilesCommand:
+		if cmd, ok := command.(*profileCommandPkg.ListProfilesCommand); ok {
+			profiles, err := sp.app.ProfileService.List(ctx, *cmd)
+			if err != nil {
+				return sp.prepareCommandResponse(nil, err)
+			}
+			return sp.prepareCommandResponse(profiles, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *profileCommandPkg.AddProfileCommand:
+		if cmd, ok := command.(*profileCommandPkg.AddProfileCommand); ok {
+			profile, err := sp.app.ProfileService.Add(ctx, *cmd)
+			if err != nil {
+				return sp.prepareCommandResponse(nil, err)
+			}
+			return sp.prepareCommandResponse(profile, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *profileCommandPkg.RemoveProfileCommand:
+		if cmd, ok := command.(*profileCommandPkg.RemoveProfileCommand); ok {
+			err := sp.app.ProfileService.Remove(ctx, *cmd)
+			return sp.prepareCommandResponse(nil, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *profileCommandPkg.DefaultProfileCommand:
+		if cmd, ok := command.(*profileCommandPkg.DefaultProfileCommand); ok {
+			err := sp.app.ProfileService.SetDefault(ctx, *cmd)
+			return sp.prepareCommandResponse(nil, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
 	case *entryCommandPkg.AddEntryCommand:
 		if cmd, ok := command.(*entryCommandPkg.AddEntryCommand); ok {
 			entry, err := sp.app.EntryServiceProvider.Add(ctx, *cmd)
@@ -72,6 +154,12 @@ func (sp *CommandExecutor) ExecuteCommand(ctx context.Context, command sharedCom
 			return sp.prepareCommandResponse(nil, err)
 		}
 		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *entryCommandPkg.RestoreEntryCommand:
+		if cmd, ok := command.(*entryCommandPkg.RestoreEntryCommand); ok {
+			err := sp.app.EntryServiceProvider.Restore(ctx, *cmd)
+			return sp.prepareCommandResponse(nil, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
 	case *entryCommandPkg.DetailEntryCommand:
 		if cmd, ok := command.(*entryCommandPkg.DetailEntryCommand); ok {
 			entry, err := sp.app.EntryServiceProvider.Detail(ctx, *cmd)
@@ -81,6 +169,24 @@ func (sp *CommandExecutor) ExecuteCommand(ctx context.Context, command sharedCom
 			return sp.prepareCommandResponse(entry, err)
 		}
 		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *entryCommandPkg.HistoryEntryCommand:
+		if cmd, ok := command.(*entryCommandPkg.HistoryEntryCommand); ok {
+			revisions, err := sp.app.EntryServiceProvider.History(ctx, *cmd)
+			if err != nil {
+				return sp.prepareCommandResponse(nil, err)
+			}
+			return sp.prepareCommandResponse(revisions, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *entryCommandPkg.RevertEntryCommand:
+		if cmd, ok := command.(*entryCommandPkg.RevertEntryCommand); ok {
+			entry, err := sp.app.EntryServiceProvider.Revert(ctx, *cmd)
+			if err != nil {
+				return sp.prepareCommandResponse(nil, err)
+			}
+			return sp.prepareCommandResponse(entry, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
 	case *entryCommandPkg.ListEntryCommand:
 		if cmd, ok := command.(*entryCommandPkg.ListEntryCommand); ok {
 			entries, err := sp.app.EntryServiceProvider.GetList(ctx, *cmd)
@@ -91,12 +197,52 @@ func (sp *CommandExecutor) ExecuteCommand(ctx context.Context, command sharedCom
 			return sp.prepareCommandResponse(entries, err)
 		}
 		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *entryCommandPkg.FindEntryCommand:
+		if cmd, ok := command.(*entryCommandPkg.FindEntryCommand); ok {
+			entries, err := sp.app.EntryServiceProvider.Find(ctx, *cmd)
+			if err != nil {
+				return sp.prepareCommandResponse(nil, err)
+			}
+
+			return sp.prepareCommandResponse(entries, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *entryCommandPkg.TrashEntryCommand:
+		if cmd, ok := command.(*entryCommandPkg.TrashEntryCommand); ok {
+			entries, err := sp.app.EntryServiceProvider.GetTrash(ctx, *cmd)
+			if err != nil {
+				return sp.prepareCommandResponse(nil, err)
+			}
+
+			return sp.prepareCommandResponse(entries, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
 	case *entryCommandPkg.SyncEntryCommand:
 		if cmd, ok := command.(*entryCommandPkg.SyncEntryCommand); ok {
 			err := sp.app.EntryServiceProvider.Sync(ctx, *cmd)
 			return sp.prepareCommandResponse(nil, err)
 		}
 		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *entryCommandPkg.RunCommand:
+		if cmd, ok := command.(*entryCommandPkg.RunCommand); ok {
+			exitCode, err := sp.app.RunService.Run(ctx, *cmd)
+			response := sp.prepareCommandResponse(nil, err)
+			// код завершения клиента - код завершения программы
+			if err == nil {
+				response.ExitCode = exitCode
+			}
+			return response
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
+	case *entryCommandPkg.InjectCommand:
+		if cmd, ok := command.(*entryCommandPkg.InjectCommand); ok {
+			injected, err := sp.app.InjectService.Inject(ctx, *cmd)
+			if err != nil {
+				return sp.prepareCommandResponse(nil, err)
+			}
+			return sp.prepareCommandResponse(injected, err)
+		}
+		return sp.prepareCommandResponse(nil, ErrNotExecuted)
 	default:
 		return sp.prepareCommandResponse(nil, ErrNotExists)
 	}
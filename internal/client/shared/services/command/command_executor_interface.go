@@ -0,0 +1,12 @@
+package command
+
+import (
+	"context"
+
+	sharedCommand "github.com/anoriar/gophkeeper/internal/client/shared/dto/command"
+)
+
+//go:generate mockgen -source=command_executor_interface.go -destination=mock_command_executor/mock_command_executor.go -package=mock_command_executor
+type CommandExecutorInterface interface {
+	ExecuteCommand(ctx context.Context, cmd sharedCommand.CommandInterface) sharedCommand.CommandResponse
+}
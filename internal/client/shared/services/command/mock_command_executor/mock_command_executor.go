@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: command_executor_interface.go
+
+// Package mock_command_executor is a generated GoMock package.
+package mock_command_executor
+
+import (
+	context "context"
+	reflect "reflect"
+
+	command "github.com/anoriar/gophkeeper/internal/client/shared/dto/command"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCommandExecutorInterface is a mock of CommandExecutorInterface interface.
+type MockCommandExecutorInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommandExecutorInterfaceMockRecorder
+}
+
+// MockCommandExecutorInterfaceMockRecorder is the mock recorder for MockCommandExecutorInterface.
+type MockCommandExecutorInterfaceMockRecorder struct {
+	mock *MockCommandExecutorInterface
+}
+
+// NewMockCommandExecutorInterface creates a new mock instance.
+func NewMockCommandExecutorInterface(ctrl *gomock.Controller) *MockCommandExecutorInterface {
+	mock := &MockCommandExecutorInterface{ctrl: ctrl}
+	mock.recorder = &MockCommandExecutorInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommandExecutorInterface) EXPECT() *MockCommandExecutorInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ExecuteCommand mocks base method.
+func (m *MockCommandExecutorInterface) ExecuteCommand(ctx context.Context, cmd command.CommandInterface) command.CommandResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteCommand", ctx, cmd)
+	ret0, _ := ret[0].(command.CommandResponse)
+	return ret0
+}
+
+// ExecuteCommand indicates an expected call of ExecuteCommand.
+func (mr *MockCommandExecutorInterfaceMockRecorder) ExecuteCommand(ctx, cmd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteCommand", reflect.TypeOf((*MockCommandExecutorInterface)(nil).ExecuteCommand), ctx, cmd)
+}
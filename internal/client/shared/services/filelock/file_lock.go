@@ -0,0 +1,69 @@
+package filelock
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+)
+
+const (
+	lockFileSuffix = ".lock"
+	pollInterval   = 50 * time.Millisecond
+)
+
+var errWouldBlock = errors.New("lock is held by another process")
+
+// FileLock advisory-блокировка (flock) файла. Блокируется отдельный файл <fileName>.lock:
+// сам файл данных заменяется через rename, и блокировка на нем потерялась бы
+type FileLock struct {
+	file *os.File
+}
+
+// Acquire ждет блокировку не дольше timeout, после чего возвращает ErrVaultBusy.
+// exclusive=false - разделяемая блокировка для чтения
+func Acquire(ctx context.Context, fileName string, exclusive bool, timeout time.Duration) (*FileLock, error) {
+	err := os.MkdirAll(filepath.Dir(fileName), 0700)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(fileName+lockFileSuffix, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err = tryLock(file, exclusive)
+		if err == nil {
+			return &FileLock{file: file}, nil
+		}
+		if !errors.Is(err, errWouldBlock) || !time.Now().Before(deadline) {
+			file.Close()
+			if errors.Is(err, errWouldBlock) {
+				return nil, sharedErrors.ErrVaultBusy
+			}
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			file.Close()
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// Release снимает блокировку
+func (l *FileLock) Release() error {
+	err := unlock(l.file)
+	closeErr := l.file.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
@@ -0,0 +1,46 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package filelock
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+)
+
+func TestAcquire(t *testing.T) {
+	ctx := context.Background()
+	fileName := filepath.Join(t.TempDir(), "entries", "logins")
+	timeout := 100 * time.Millisecond
+
+	// разделяемые блокировки не мешают друг другу
+	first, err := Acquire(ctx, fileName, false, timeout)
+	require.NoError(t, err)
+	second, err := Acquire(ctx, fileName, false, timeout)
+	require.NoError(t, err)
+
+	_, err = Acquire(ctx, fileName, true, timeout)
+	assert.ErrorIs(t, err, sharedErrors.ErrVaultBusy)
+
+	require.NoError(t, first.Release())
+	require.NoError(t, second.Release())
+
+	exclusive, err := Acquire(ctx, fileName, true, timeout)
+	require.NoError(t, err)
+
+	_, err = Acquire(ctx, fileName, false, timeout)
+	assert.ErrorIs(t, err, sharedErrors.ErrVaultBusy)
+
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = Acquire(canceledCtx, fileName, true, time.Minute)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	require.NoError(t, exclusive.Release())
+}
@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package filelock
+
+import "os"
+
+// На платформах без flock блокировка не выполняется
+
+func tryLock(file *os.File, exclusive bool) error {
+	return nil
+}
+
+func unlock(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,257 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	sharedCommand "github.com/anoriar/gophkeeper/internal/client/shared/dto/command"
+)
+
+const (
+	// FormatJSON - CommandResponse целиком в JSON с отступами, по умолчанию
+	FormatJSON = "json"
+	// FormatTable - payload таблицей для чтения человеком
+	FormatTable = "table"
+	// FormatPlain - payload без оформления: строки как есть, столбцы через табуляцию, для скриптов
+	FormatPlain = "plain"
+)
+
+// Formats форматы для флага --output
+var Formats = []string{FormatJSON, FormatTable, FormatPlain}
+
+var (
+	ErrFormatNotValid = errors.New("not valid output format, expected json, table or plain")
+	ErrFieldNotFound  = errors.New("field not found in command response")
+)
+
+// Formatter вывод ответа команды. field - путь к значению в payload через точку: data.password, 0.id
+type Formatter struct {
+	format string
+	field  string
+}
+
+func NewFormatter(format string, field string) (*Formatter, error) {
+	switch format {
+	case FormatJSON, FormatTable, FormatPlain:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrFormatNotValid, format)
+	}
+	return &Formatter{format: format, field: field}, nil
+}
+
+// Write ошибка команды в json выводится в out, как и успешный ответ, в table и plain - в errOut:
+// в out скрипта не должно попасть ничего, кроме значения
+func (f *Formatter) Write(out io.Writer, errOut io.Writer, response sharedCommand.CommandResponse) error {
+	if response.Status != sharedCommand.StatusSuccess {
+		if f.format == FormatJSON {
+			return writeJSON(out, response)
+		}
+		_, err := fmt.Fprintf(errOut, "error: %s\n", response.Error)
+		return err
+	}
+	if f.format == FormatJSON && f.field == "" {
+		return writeJSON(out, response)
+	}
+
+	var value json.RawMessage
+	value, err := json.Marshal(response.Payload)
+	if err != nil {
+		return err
+	}
+	if f.field != "" {
+		value, err = selectField(value, f.field)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch f.format {
+	case FormatJSON:
+		return writeJSON(out, value)
+	case FormatPlain:
+		return writePlain(out, value)
+	default:
+		return writeTable(out, value)
+	}
+}
+
+func writeJSON(out io.Writer, value interface{}) error {
+	valueStr, err := json.MarshalIndent(value, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%s\n", valueStr)
+	return err
+}
+
+// selectField значение по пути: имя поля объекта или номер элемента массива
+func selectField(value json.RawMessage, path string) (json.RawMessage, error) {
+	for _, name := range strings.Split(path, ".") {
+		if fields, ok := objectFields(value); ok {
+			found := false
+			for _, field := range fields {
+				if field.name == name {
+					value = field.value
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("%w: %s", ErrFieldNotFound, path)
+			}
+			continue
+		}
+		var items []json.RawMessage
+		if json.Unmarshal(value, &items) != nil {
+			return nil, fmt.Errorf("%w: %s", ErrFieldNotFound, path)
+		}
+		index, err := strconv.Atoi(name)
+		if err != nil || index < 0 || index >= len(items) {
+			return nil, fmt.Errorf("%w: %s", ErrFieldNotFound, path)
+		}
+		value = items[index]
+	}
+	return value, nil
+}
+
+// writePlain строка - как есть, объект - строки "поле<TAB>значение", массив объектов - строка на элемент
+func writePlain(out io.Writer, value json.RawMessage) error {
+	var lines []string
+	if fields, ok := objectFields(value); ok {
+		for _, field := range fields {
+			lines = append(lines, field.name+"\t"+cell(field.value))
+		}
+	} else if items, ok := arrayItems(value); ok {
+		columns := arrayColumns(items)
+		for _, item := range items {
+			lines = append(lines, strings.Join(rowCells(item, columns), "\t"))
+		}
+	} else if !isNull(value) {
+		lines = append(lines, cell(value))
+	}
+	for _, line := range lines {
+		_, err := fmt.Fprintln(out, line)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTable массив объектов - таблица с заголовком из имен полей, объект - два столбца: поле и значение
+func writeTable(out io.Writer, value json.RawMessage) error {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if fields, ok := objectFields(value); ok {
+		for _, field := range fields {
+			fmt.Fprintf(writer, "%s\t%s\n", strings.ToUpper(field.name), cell(field.value))
+		}
+	} else if items, ok := arrayItems(value); ok {
+		columns := arrayColumns(items)
+		if len(columns) > 0 {
+			fmt.Fprintf(writer, "%s\n", strings.ToUpper(strings.Join(columns, "\t")))
+		}
+		for _, item := range items {
+			fmt.Fprintf(writer, "%s\n", strings.Join(rowCells(item, columns), "\t"))
+		}
+	} else if !isNull(value) {
+		fmt.Fprintf(writer, "%s\n", cell(value))
+	}
+	return writer.Flush()
+}
+
+type objectField struct {
+	name  string
+	value json.RawMessage
+}
+
+// objectFields поля объекта в порядке из JSON: в нем же поля структуры ответа
+func objectFields(value json.RawMessage) ([]objectField, bool) {
+	decoder := json.NewDecoder(strings.NewReader(string(value)))
+	token, err := decoder.Token()
+	if err != nil || token != json.Delim('{') {
+		return nil, false
+	}
+	var fields []objectField
+	for decoder.More() {
+		token, err = decoder.Token()
+		if err != nil {
+			return nil, false
+		}
+		name, _ := token.(string)
+		var fieldValue json.RawMessage
+		err = decoder.Decode(&fieldValue)
+		if err != nil {
+			return nil, false
+		}
+		fields = append(fields, objectField{name: name, value: fieldValue})
+	}
+	return fields, true
+}
+
+func arrayItems(value json.RawMessage) ([]json.RawMessage, bool) {
+	var items []json.RawMessage
+	if json.Unmarshal(value, &items) != nil || items == nil {
+		return nil, false
+	}
+	return items, true
+}
+
+// arrayColumns поля всех объектов массива в порядке появления: поля с omitempty есть не у всех элементов
+func arrayColumns(items []json.RawMessage) []string {
+	var columns []string
+	seen := make(map[string]bool)
+	for _, item := range items {
+		fields, _ := objectFields(item)
+		for _, field := range fields {
+			if !seen[field.name] {
+				seen[field.name] = true
+				columns = append(columns, field.name)
+			}
+		}
+	}
+	return columns
+}
+
+// rowCells значения элемента массива по столбцам. Элемент не объект - один столбец
+func rowCells(item json.RawMessage, columns []string) []string {
+	fields, ok := objectFields(item)
+	if !ok {
+		return []string{cell(item)}
+	}
+	values := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		values[field.name] = field.value
+	}
+	cells := make([]string, 0, len(columns))
+	for _, column := range columns {
+		cells = append(cells, cell(values[column]))
+	}
+	return cells
+}
+
+// cell строка без кавычек, null и отсутствующее значение - пусто, объекты и массивы - компактный JSON
+func cell(value json.RawMessage) string {
+	if len(value) == 0 || isNull(value) {
+		return ""
+	}
+	var str string
+	if json.Unmarshal(value, &str) == nil {
+		return str
+	}
+	var compact bytes.Buffer
+	err := json.Compact(&compact, value)
+	if err != nil {
+		return string(value)
+	}
+	return compact.String()
+}
+
+func isNull(value json.RawMessage) bool {
+	return strings.TrimSpace(string(value)) == "null"
+}
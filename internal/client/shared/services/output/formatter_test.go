@@ -0,0 +1,147 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sharedCommand "github.com/anoriar/gophkeeper/internal/client/shared/dto/command"
+)
+
+type testLoginData struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+type testEntry struct {
+	Id         string      `json:"id"`
+	Title      string      `json:"title"`
+	Data       interface{} `json:"data,omitempty"`
+	PublicMeta interface{} `json:"publicMeta,omitempty"`
+}
+
+func TestFormatter_Write(t *testing.T) {
+	detailResponse := sharedCommand.CommandResponse{
+		Status:  sharedCommand.StatusSuccess,
+		Payload: testEntry{Id: "1", Title: "Work mail", Data: testLoginData{Login: "user", Password: "pa ss"}},
+	}
+	listResponse := sharedCommand.CommandResponse{
+		Status: sharedCommand.StatusSuccess,
+		Payload: []testEntry{
+			{Id: "1", Title: "Work mail"},
+			{Id: "22", Title: "Bank", PublicMeta: map[string]string{"site": "bank"}},
+		},
+	}
+	failResponse := sharedCommand.CommandResponse{Status: sharedCommand.StatusFail, Error: "entry not found"}
+
+	tests := []struct {
+		name       string
+		format     string
+		field      string
+		response   sharedCommand.CommandResponse
+		wantOut    string
+		wantErrOut string
+		wantErr    error
+	}{
+		{
+			name:     "success json whole response",
+			format:   FormatJSON,
+			response: sharedCommand.CommandResponse{Status: sharedCommand.StatusSuccess},
+			wantOut:  "{\n    \"status\": \"success\",\n    \"error\": \"\",\n    \"payload\": null\n}\n",
+		},
+		{
+			name:     "success json field",
+			format:   FormatJSON,
+			field:    "data",
+			response: detailResponse,
+			wantOut:  "{\n    \"login\": \"user\",\n    \"password\": \"pa ss\"\n}\n",
+		},
+		{
+			name:     "success plain string field without quotes",
+			format:   FormatPlain,
+			field:    "data.password",
+			response: detailResponse,
+			wantOut:  "pa ss\n",
+		},
+		{
+			name:     "success plain array item field",
+			format:   FormatPlain,
+			field:    "1.id",
+			response: listResponse,
+			wantOut:  "22\n",
+		},
+		{
+			name:     "success plain object fields",
+			format:   FormatPlain,
+			response: detailResponse,
+			wantOut:  "id\t1\ntitle\tWork mail\ndata\t{\"login\":\"user\",\"password\":\"pa ss\"}\n",
+		},
+		{
+			name:     "success plain array rows",
+			format:   FormatPlain,
+			response: listResponse,
+			wantOut:  "1\tWork mail\t\n22\tBank\t{\"site\":\"bank\"}\n",
+		},
+		{
+			name:     "success table array with columns of all items",
+			format:   FormatTable,
+			response: listResponse,
+			wantOut:  "ID  TITLE      PUBLICMETA\n1   Work mail  \n22  Bank       {\"site\":\"bank\"}\n",
+		},
+		{
+			name:     "success table without payload",
+			format:   FormatTable,
+			response: sharedCommand.CommandResponse{Status: sharedCommand.StatusSuccess},
+		},
+		{
+			name:     "field not found error",
+			format:   FormatPlain,
+			field:    "data.pin",
+			response: detailResponse,
+			wantErr:  ErrFieldNotFound,
+		},
+		{
+			name:     "array index out of range error",
+			format:   FormatPlain,
+			field:    "2.id",
+			response: listResponse,
+			wantErr:  ErrFieldNotFound,
+		},
+		{
+			name:     "fail json response in out",
+			format:   FormatJSON,
+			field:    "data.password",
+			response: failResponse,
+			wantOut:  "{\n    \"status\": \"fail\",\n    \"error\": \"entry not found\",\n    \"payload\": null\n}\n",
+		},
+		{
+			name:       "fail plain error in err out",
+			format:     FormatPlain,
+			field:      "data.password",
+			response:   failResponse,
+			wantErrOut: "error: entry not found\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFormatter(tt.format, tt.field)
+			require.NoError(t, err)
+			var out, errOut bytes.Buffer
+			err = f.Write(&out, &errOut, tt.response)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOut, out.String())
+			assert.Equal(t, tt.wantErrOut, errOut.String())
+		})
+	}
+}
+
+func TestNewFormatter(t *testing.T) {
+	_, err := NewFormatter("yaml", "")
+	assert.ErrorIs(t, err, ErrFormatNotValid)
+}
@@ -0,0 +1,95 @@
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var (
+	ErrNotTerminal          = errors.New("input is not a terminal: pass the value with a flag or --stdin")
+	ErrStdinNotAvailable    = errors.New("--stdin is not available here")
+	ErrConfirmationMismatch = errors.New("values do not match")
+	ErrDataSourceConflict   = errors.New("use only one of -d, --stdin and --data-file")
+)
+
+// SecretReader секреты, не переданные флагами: пароли, мастер-пароли и данные записей.
+// Значения из аргументов видны в ps и остаются в истории shell, поэтому их можно ввести без эха или передать через stdin
+type SecretReader struct {
+	passwordReader PasswordReaderInterface
+	// stdin - nil, если stdin занят (ввод команд shell)
+	stdin *bufio.Reader
+}
+
+func NewSecretReader(passwordReader PasswordReaderInterface, stdin io.Reader) *SecretReader {
+	secretReader := &SecretReader{passwordReader: passwordReader}
+	if stdin != nil {
+		secretReader.stdin = bufio.NewReader(stdin)
+	}
+	return secretReader
+}
+
+// ReadSecret fromStdin - следующая строка stdin, иначе ввод без эха. Секреты команды читаются из stdin по строкам
+// в порядке, в котором их запрашивает команда
+func (r *SecretReader) ReadSecret(prompt string, fromStdin bool) (string, error) {
+	if fromStdin {
+		return r.readStdinLine()
+	}
+	return r.passwordReader.ReadPassword(prompt)
+}
+
+// ReadNewSecret новый секрет (мастер-пароль при register и rekey) вводится дважды: опечатку без эха не видно.
+// Из stdin значение читается один раз
+func (r *SecretReader) ReadNewSecret(prompt string, confirmPrompt string, fromStdin bool) (string, error) {
+	value, err := r.ReadSecret(prompt, fromStdin)
+	if err != nil || fromStdin {
+		return value, err
+	}
+	confirmation, err := r.passwordReader.ReadPassword(confirmPrompt)
+	if err != nil {
+		return "", err
+	}
+	if value != confirmation {
+		return "", ErrConfirmationMismatch
+	}
+	return value, nil
+}
+
+// ReadData данные записи из файла или всего stdin, без изменений. ok=false - источник не задан
+func (r *SecretReader) ReadData(dataFile string, fromStdin bool) ([]byte, bool, error) {
+	switch {
+	case dataFile != "" && fromStdin:
+		return nil, false, ErrDataSourceConflict
+	case dataFile != "":
+		data, err := os.ReadFile(dataFile)
+		if err != nil {
+			return nil, false, fmt.Errorf("read data file error: %w", err)
+		}
+		return data, true, nil
+	case fromStdin:
+		if r.stdin == nil {
+			return nil, false, ErrStdinNotAvailable
+		}
+		data, err := io.ReadAll(r.stdin)
+		if err != nil {
+			return nil, false, fmt.Errorf("read stdin error: %w", err)
+		}
+		return data, true, nil
+	default:
+		return nil, false, nil
+	}
+}
+
+func (r *SecretReader) readStdinLine() (string, error) {
+	if r.stdin == nil {
+		return "", ErrStdinNotAvailable
+	}
+	line, err := r.stdin.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", fmt.Errorf("read stdin error: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,164 @@
+package prompt
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPasswordReader возвращает пароли по порядку и запоминает подсказки
+type testPasswordReader struct {
+	passwords []string
+	prompts   []string
+}
+
+func (r *testPasswordReader) ReadPassword(prompt string) (string, error) {
+	r.prompts = append(r.prompts, prompt)
+	if len(r.passwords) == 0 {
+		return "", io.EOF
+	}
+	password := r.passwords[0]
+	r.passwords = r.passwords[1:]
+	return password, nil
+}
+
+func TestSecretReader_ReadNewSecret(t *testing.T) {
+	tests := []struct {
+		name        string
+		passwords   []string
+		stdin       io.Reader
+		fromStdin   bool
+		want        string
+		wantPrompts []string
+		wantErr     error
+	}{
+		{
+			name:        "success confirmed",
+			passwords:   []string{"master", "master"},
+			want:        "master",
+			wantPrompts: []string{"master password: ", "repeat master password: "},
+		},
+		{
+			name:        "confirmation mismatch error",
+			passwords:   []string{"master", "mastre"},
+			wantPrompts: []string{"master password: ", "repeat master password: "},
+			wantErr:     ErrConfirmationMismatch,
+		},
+		{
+			name:      "success from stdin without confirmation",
+			stdin:     strings.NewReader("master\r\nother\n"),
+			fromStdin: true,
+			want:      "master",
+		},
+		{
+			name:      "success last stdin line without newline",
+			stdin:     strings.NewReader("master"),
+			fromStdin: true,
+			want:      "master",
+		},
+		{
+			name:      "empty stdin error",
+			stdin:     strings.NewReader(""),
+			fromStdin: true,
+			wantErr:   io.EOF,
+		},
+		{
+			name:      "stdin not available error",
+			fromStdin: true,
+			wantErr:   ErrStdinNotAvailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passwordReader := &testPasswordReader{passwords: tt.passwords}
+			r := NewSecretReader(passwordReader, tt.stdin)
+			got, err := r.ReadNewSecret("master password: ", "repeat master password: ", tt.fromStdin)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.Equal(t, tt.wantPrompts, passwordReader.prompts)
+		})
+	}
+}
+
+func TestSecretReader_ReadSecret(t *testing.T) {
+	r := NewSecretReader(&testPasswordReader{}, strings.NewReader("pass\nmaster\n"))
+
+	// секреты одной команды читаются из stdin по строкам
+	password, err := r.ReadSecret("password: ", true)
+	require.NoError(t, err)
+	assert.Equal(t, "pass", password)
+	masterPassword, err := r.ReadSecret("master password: ", true)
+	require.NoError(t, err)
+	assert.Equal(t, "master", masterPassword)
+}
+
+func TestSecretReader_ReadData(t *testing.T) {
+	dataFileName := filepath.Join(t.TempDir(), "data.bin")
+	require.NoError(t, os.WriteFile(dataFileName, []byte{0, 1, 2}, 0600))
+
+	tests := []struct {
+		name      string
+		stdin     io.Reader
+		dataFile  string
+		fromStdin bool
+		want      []byte
+		wantOk    bool
+		wantErr   error
+	}{
+		{
+			name:     "success data file",
+			dataFile: dataFileName,
+			want:     []byte{0, 1, 2},
+			wantOk:   true,
+		},
+		{
+			name:      "success whole stdin",
+			stdin:     strings.NewReader("line1\nline2\n"),
+			fromStdin: true,
+			want:      []byte("line1\nline2\n"),
+			wantOk:    true,
+		},
+		{
+			name: "success source not set",
+		},
+		{
+			name:      "data file and stdin conflict error",
+			stdin:     strings.NewReader("data"),
+			dataFile:  dataFileName,
+			fromStdin: true,
+			wantErr:   ErrDataSourceConflict,
+		},
+		{
+			name:     "data file not found error",
+			dataFile: filepath.Join(t.TempDir(), "not-found"),
+			wantErr:  os.ErrNotExist,
+		},
+		{
+			name:      "stdin not available error",
+			fromStdin: true,
+			wantErr:   ErrStdinNotAvailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewSecretReader(&testPasswordReader{}, tt.stdin)
+			got, ok, err := r.ReadData(tt.dataFile, tt.fromStdin)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
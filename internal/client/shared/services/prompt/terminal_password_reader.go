@@ -0,0 +1,38 @@
+package prompt
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// PasswordReaderInterface чтение значения без эха. Реализуют терминал и строка ввода shell
+type PasswordReaderInterface interface {
+	ReadPassword(prompt string) (string, error)
+}
+
+// TerminalPasswordReader приглашение пишется в out (stderr), чтобы не смешиваться с ответом команды в stdout
+type TerminalPasswordReader struct {
+	in  *os.File
+	out io.Writer
+}
+
+func NewTerminalPasswordReader(in *os.File, out io.Writer) *TerminalPasswordReader {
+	return &TerminalPasswordReader{in: in, out: out}
+}
+
+func (r *TerminalPasswordReader) ReadPassword(prompt string) (string, error) {
+	fd := int(r.in.Fd())
+	if !term.IsTerminal(fd) {
+		return "", ErrNotTerminal
+	}
+	fmt.Fprint(r.out, prompt)
+	value, err := term.ReadPassword(fd)
+	fmt.Fprintln(r.out)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
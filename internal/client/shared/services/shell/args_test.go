@@ -0,0 +1,58 @@
+package shell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "spaces",
+			line: "  list   -t login ",
+			want: []string{"list", "-t", "login"},
+		},
+		{
+			name: "single quotes keep json",
+			line: `add -t login -d '{"login": "user", "password": "p\ss"}'`,
+			want: []string{"add", "-t", "login", "-d", `{"login": "user", "password": "p\ss"}`},
+		},
+		{
+			name: "double quotes with escape",
+			line: `add --title "My \"work\" mail"`,
+			want: []string{"add", "--title", `My "work" mail`},
+		},
+		{
+			name: "escaped space and empty argument",
+			line: `find my\ mail ""`,
+			want: []string{"find", "my mail", ""},
+		},
+		{
+			name: "empty line",
+			line: "   ",
+		},
+		{
+			name:    "unterminated quote error",
+			line:    `add --title 'mail`,
+			wantErr: ErrUnterminatedQuote,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitArgs(tt.line)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package shell
+
+import (
+	"strings"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+)
+
+// Completer автодополнение по Tab: имя команды в начале строки, тип записи после -t и --type
+type Completer struct {
+	commands   []string
+	entryTypes []string
+}
+
+func NewCompleter(commands []string) *Completer {
+	entryTypes := make([]string, 0, len(enum.AllEntryTypes))
+	for _, entryType := range enum.AllEntryTypes {
+		entryTypes = append(entryTypes, string(entryType))
+	}
+	return &Completer{
+		commands:   append(append([]string{}, commands...), builtinCommands...),
+		entryTypes: entryTypes,
+	}
+}
+
+// Complete сигнатура term.Terminal.AutoCompleteCallback. Если вариантов несколько, дописывается их общий префикс
+func (c *Completer) Complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+	beforeCursor := line[:pos]
+	words := strings.Fields(beforeCursor)
+	current := ""
+	if len(words) > 0 && !strings.HasSuffix(beforeCursor, " ") {
+		current = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	var candidates []string
+	switch {
+	case len(words) == 0:
+		candidates = c.commands
+	case words[len(words)-1] == "-t" || words[len(words)-1] == "--type":
+		candidates = c.entryTypes
+	default:
+		return "", 0, false
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, current) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return "", 0, false
+	}
+	completion := commonPrefix(matches)
+	if len(matches) == 1 {
+		completion += " "
+	}
+	if completion == current {
+		return "", 0, false
+	}
+
+	newBeforeCursor := beforeCursor[:len(beforeCursor)-len(current)] + completion
+	return newBeforeCursor + line[pos:], len(newBeforeCursor), true
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
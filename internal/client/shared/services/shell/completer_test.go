@@ -0,0 +1,81 @@
+package shell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompleter_Complete(t *testing.T) {
+	completer := NewCompleter([]string{"list", "login", "lock", "detail"})
+
+	tests := []struct {
+		name     string
+		line     string
+		pos      int
+		key      rune
+		wantLine string
+		wantPos  int
+		wantOk   bool
+	}{
+		{
+			name:     "single command",
+			line:     "de",
+			pos:      2,
+			key:      '\t',
+			wantLine: "detail ",
+			wantPos:  7,
+			wantOk:   true,
+		},
+		{
+			name:     "common prefix of commands",
+			line:     "lo",
+			pos:      2,
+			key:      '\t',
+			wantLine: "lo",
+			wantOk:   false,
+		},
+		{
+			name:     "entry type after -t",
+			line:     "list -t ca",
+			pos:      10,
+			key:      '\t',
+			wantLine: "list -t card ",
+			wantPos:  13,
+			wantOk:   true,
+		},
+		{
+			name:     "text after cursor kept",
+			line:     "li -t login",
+			pos:      2,
+			key:      '\t',
+			wantLine: "list  -t login",
+			wantPos:  5,
+			wantOk:   true,
+		},
+		{
+			name:   "other argument",
+			line:   "list --sort ti",
+			pos:    14,
+			key:    '\t',
+			wantOk: false,
+		},
+		{
+			name:   "not tab",
+			line:   "de",
+			pos:    2,
+			key:    'x',
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, pos, ok := completer.Complete(tt.line, tt.pos, tt.key)
+			assert.Equal(t, tt.wantOk, ok)
+			if tt.wantOk {
+				assert.Equal(t, tt.wantLine, line)
+				assert.Equal(t, tt.wantPos, pos)
+			}
+		})
+	}
+}
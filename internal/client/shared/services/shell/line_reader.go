@@ -0,0 +1,64 @@
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// LineReader ввод shell. Вывод команд идет через него же: терминал перерисовывает строку ввода после вывода
+type LineReader interface {
+	io.Writer
+	ReadLine() (string, error)
+	// ReadPassword чтение без эха
+	ReadPassword(prompt string) (string, error)
+}
+
+// NewLineReader для терминала - ввод с историей команд (стрелки вверх и вниз) и автодополнением.
+// История хранится только в памяти: в аргументах команд бывают данные записей.
+// Если ввод не терминал (команды переданы через pipe), строки читаются как есть, без приглашения.
+// restore возвращает терминал в исходный режим
+func NewLineReader(in *os.File, out io.Writer, prompt string, completer *Completer) (LineReader, func() error, error) {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return &plainLineReader{reader: bufio.NewReader(in), out: out}, func() error { return nil }, nil
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, nil, err
+	}
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{in, out}, prompt)
+	terminal.AutoCompleteCallback = completer.Complete
+	return terminal, func() error {
+		return term.Restore(fd, state)
+	}, nil
+}
+
+type plainLineReader struct {
+	reader *bufio.Reader
+	out    io.Writer
+}
+
+func (r *plainLineReader) Write(p []byte) (int, error) {
+	return r.out.Write(p)
+}
+
+func (r *plainLineReader) ReadLine() (string, error) {
+	line, err := r.reader.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (r *plainLineReader) ReadPassword(prompt string) (string, error) {
+	return r.ReadLine()
+}
@@ -0,0 +1,237 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	profileCommands "github.com/anoriar/gophkeeper/internal/client/profile/dto/command"
+	sharedCommand "github.com/anoriar/gophkeeper/internal/client/shared/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/command"
+	userCommands "github.com/anoriar/gophkeeper/internal/client/user/dto/command"
+)
+
+const maxUnlockAttempts = 3
+
+// builtinCommands команды самого shell, не CommandExecutor
+var builtinCommands = []string{"help", "exit", "quit"}
+
+// unavailableCommands не имеют смысла внутри shell: agent блокирует ввод, shell уже запущен,
+// скрипт автодополнения нужен командной оболочке, а не shell, run отдал бы программе терминал, который читает shell
+var unavailableCommands = []string{"shell", "agent", "completion", "run"}
+
+// Parser разбирает команду shell так же, как аргументы командной строки: args[0] - имя команды.
+// nil без ошибки - команды нет, parser сам вывел справку: help list
+type Parser func(args []string) (sharedCommand.CommandInterface, error)
+
+// ResponseWriter вывод ответа команды в формате, выбранном при запуске shell
+type ResponseWriter interface {
+	Write(out io.Writer, errOut io.Writer, response sharedCommand.CommandResponse) error
+}
+
+// Shell интерактивный режим клиента. Хранилище разблокируется один раз, ключи хранятся только в памяти процесса
+// и забываются после idleTimeout без команд. Команды выполняет тот же CommandExecutor, что и в обычном режиме
+type Shell struct {
+	executor    command.CommandExecutorInterface
+	parser      Parser
+	writer      ResponseWriter
+	commands    []string
+	idleTimeout time.Duration
+
+	// mu - команда и блокировка по таймеру не выполняются одновременно
+	mu        sync.Mutex
+	locked    bool
+	idleTimer *time.Timer
+}
+
+// NewShell commands - имена команд для help. idleTimeout 0 - не блокировать по бездействию
+func NewShell(executor command.CommandExecutorInterface, parser Parser, writer ResponseWriter, commands []string, idleTimeout time.Duration) *Shell {
+	return &Shell{
+		executor:    executor,
+		parser:      parser,
+		writer:      writer,
+		commands:    commands,
+		idleTimeout: idleTimeout,
+		locked:      true,
+	}
+}
+
+// Run читает команды до exit, quit или конца ввода
+func (s *Shell) Run(ctx context.Context, reader LineReader) error {
+	s.mu.Lock()
+	err := s.unlock(ctx, reader)
+	s.mu.Unlock()
+	if err != nil {
+		return ignoreEOF(err)
+	}
+
+	defer s.stopIdleTimer()
+	for {
+		s.resetIdleTimer(ctx, reader)
+		line, err := reader.ReadLine()
+		s.stopIdleTimer()
+		if err != nil {
+			return ignoreEOF(err)
+		}
+		exit, err := s.executeLine(ctx, reader, line)
+		if err != nil || exit {
+			return ignoreEOF(err)
+		}
+	}
+}
+
+// executeLine exit - команда завершения shell. Ошибка - только ошибка ввода, ошибки команд выводятся
+func (s *Shell) executeLine(ctx context.Context, reader LineReader, line string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	args, err := SplitArgs(line)
+	if err != nil {
+		fmt.Fprintf(reader, "error: %v\n", err)
+		return false, nil
+	}
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	switch {
+	case args[0] == "exit" || args[0] == "quit":
+		return true, nil
+	case args[0] == "help" && len(args) == 1:
+		fmt.Fprintf(reader, "commands: %s\n", strings.Join(append(append([]string{}, s.commands...), builtinCommands...), ", "))
+		return false, nil
+	case contains(unavailableCommands, args[0]):
+		fmt.Fprintf(reader, "error: command %s is not available in shell\n", args[0])
+		return false, nil
+	case strings.HasPrefix(args[0], "--profile"):
+		fmt.Fprintf(reader, "error: profile is selected when shell starts: --profile [name] shell\n")
+		return false, nil
+	}
+
+	cmd, err := s.parser(args)
+	if err != nil {
+		fmt.Fprintf(reader, "error: %v\n", err)
+		return false, nil
+	}
+	if cmd == nil {
+		return false, nil
+	}
+	validationErrors := cmd.Validate()
+	if len(validationErrors) > 0 {
+		fmt.Fprintf(reader, "validation error: %v\n", errors.Join(validationErrors...))
+		return false, nil
+	}
+
+	if s.locked && requiresUnlock(cmd) {
+		err = s.unlock(ctx, reader)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	response := s.executor.ExecuteCommand(ctx, cmd)
+	s.updateLocked(cmd, response)
+	s.printResponse(reader, response)
+	return false, nil
+}
+
+// unlock при пустом мастер-пароле хранилище остается заблокированным: можно выполнить register, login или profiles
+func (s *Shell) unlock(ctx context.Context, reader LineReader) error {
+	for attempt := 0; attempt < maxUnlockAttempts; attempt++ {
+		masterPassword, err := reader.ReadPassword("master password (empty - skip): ")
+		if err != nil {
+			return err
+		}
+		if masterPassword == "" {
+			fmt.Fprintf(reader, "vault is locked\n")
+			return nil
+		}
+		response := s.executor.ExecuteCommand(ctx, &userCommands.UnlockCommand{MasterPassword: masterPassword})
+		if response.Status == sharedCommand.StatusSuccess {
+			s.locked = false
+			return nil
+		}
+		fmt.Fprintf(reader, "unlock error: %s\n", response.Error)
+	}
+	return nil
+}
+
+func (s *Shell) updateLocked(cmd sharedCommand.CommandInterface, response sharedCommand.CommandResponse) {
+	switch cmd.(type) {
+	case *userCommands.LockCommand:
+		s.locked = true
+	case *userCommands.LoginCommand, *userCommands.RegisterCommand, *userCommands.UnlockCommand:
+		if response.Status == sharedCommand.StatusSuccess {
+			s.locked = false
+		}
+	}
+}
+
+// requiresUnlock команды, которые сами разблокируют хранилище или не работают с записями, мастер-пароль не запрашивают
+func requiresUnlock(cmd sharedCommand.CommandInterface) bool {
+	switch cmd.(type) {
+	case *userCommands.LoginCommand, *userCommands.RegisterCommand, *userCommands.UnlockCommand, *userCommands.LockCommand,
+		*profileCommands.ListProfilesCommand, *profileCommands.AddProfileCommand,
+		*profileCommands.RemoveProfileCommand, *profileCommands.DefaultProfileCommand:
+		return false
+	default:
+		return true
+	}
+}
+
+func (s *Shell) resetIdleTimer(ctx context.Context, out io.Writer) {
+	if s.idleTimeout <= 0 {
+		return
+	}
+	s.idleTimer = time.AfterFunc(s.idleTimeout, func() {
+		s.lockIdle(ctx, out)
+	})
+}
+
+func (s *Shell) stopIdleTimer() {
+	if s.idleTimer != nil {
+		s.idleTimer.Stop()
+	}
+}
+
+func (s *Shell) lockIdle(ctx context.Context, out io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locked {
+		return
+	}
+	response := s.executor.ExecuteCommand(ctx, &userCommands.LockCommand{})
+	if response.Status != sharedCommand.StatusSuccess {
+		fmt.Fprintf(out, "lock error: %s\n", response.Error)
+		return
+	}
+	s.locked = true
+	fmt.Fprintf(out, "vault locked after %s of inactivity\n", s.idleTimeout)
+}
+
+func (s *Shell) printResponse(out io.Writer, response sharedCommand.CommandResponse) {
+	err := s.writer.Write(out, out, response)
+	if err != nil {
+		fmt.Fprintf(out, "error: %v\n", err)
+	}
+}
+
+func ignoreEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	entryCommands "github.com/anoriar/gophkeeper/internal/client/entry/dto/command"
+	sharedCommand "github.com/anoriar/gophkeeper/internal/client/shared/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/command/mock_command_executor"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/output"
+	userCommands "github.com/anoriar/gophkeeper/internal/client/user/dto/command"
+)
+
+// testLineReader строки и пароли по порядку. Перед строкой с индексом из waitBefore ждет сигнала, например автоблокировки
+type testLineReader struct {
+	bytes.Buffer
+	lines      []string
+	passwords  []string
+	waitBefore map[int]chan struct{}
+	lineIdx    int
+}
+
+func (r *testLineReader) ReadLine() (string, error) {
+	if wait, ok := r.waitBefore[r.lineIdx]; ok {
+		select {
+		case <-wait:
+		case <-time.After(time.Second):
+			return "", errors.New("wait timeout")
+		}
+	}
+	if r.lineIdx >= len(r.lines) {
+		return "", io.EOF
+	}
+	line := r.lines[r.lineIdx]
+	r.lineIdx++
+	return line, nil
+}
+
+func (r *testLineReader) ReadPassword(prompt string) (string, error) {
+	if len(r.passwords) == 0 {
+		return "", io.EOF
+	}
+	password := r.passwords[0]
+	r.passwords = r.passwords[1:]
+	return password, nil
+}
+
+func testParser(args []string) (sharedCommand.CommandInterface, error) {
+	switch args[0] {
+	case "list":
+		return &entryCommands.ListEntryCommand{}, nil
+	case "lock":
+		return &userCommands.LockCommand{}, nil
+	case "help":
+		return nil, nil
+	case "login":
+		return &userCommands.LoginCommand{UserName: "user", Password: "pass", MasterPassword: "master"}, nil
+	default:
+		return nil, errors.New("not valid command")
+	}
+}
+
+var (
+	successResponse = sharedCommand.CommandResponse{Status: sharedCommand.StatusSuccess}
+	failResponse    = sharedCommand.CommandResponse{Status: sharedCommand.StatusFail, Error: "wrong master password"}
+)
+
+func TestShell_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	executorMock := mock_command_executor.NewMockCommandExecutorInterface(ctrl)
+	writer, err := output.NewFormatter(output.FormatJSON, "")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		reader        *testLineReader
+		idleTimeout   time.Duration
+		mockBehaviour func(reader *testLineReader)
+		wantOutput    []string
+	}{
+		{
+			name:   "success unlock once for several commands",
+			reader: &testLineReader{passwords: []string{"master"}, lines: []string{"list", "", "list", "exit", "list"}},
+			mockBehaviour: func(reader *testLineReader) {
+				gomock.InOrder(
+					executorMock.EXPECT().ExecuteCommand(gomock.Any(), &userCommands.UnlockCommand{MasterPassword: "master"}).Return(successResponse),
+					executorMock.EXPECT().ExecuteCommand(gomock.Any(), &entryCommands.ListEntryCommand{}).Return(successResponse).Times(2),
+				)
+			},
+			wantOutput: []string{`"status": "success"`},
+		},
+		{
+			name:   "success retry wrong master password",
+			reader: &testLineReader{passwords: []string{"wrong", "master"}, lines: []string{"list"}},
+			mockBehaviour: func(reader *testLineReader) {
+				gomock.InOrder(
+					executorMock.EXPECT().ExecuteCommand(gomock.Any(), &userCommands.UnlockCommand{MasterPassword: "wrong"}).Return(failResponse),
+					executorMock.EXPECT().ExecuteCommand(gomock.Any(), &userCommands.UnlockCommand{MasterPassword: "master"}).Return(successResponse),
+					executorMock.EXPECT().ExecuteCommand(gomock.Any(), &entryCommands.ListEntryCommand{}).Return(successResponse),
+				)
+			},
+			wantOutput: []string{"unlock error: wrong master password"},
+		},
+		{
+			name:   "success skip unlock for login",
+			reader: &testLineReader{passwords: []string{""}, lines: []string{"login", "list"}},
+			mockBehaviour: func(reader *testLineReader) {
+				gomock.InOrder(
+					executorMock.EXPECT().ExecuteCommand(gomock.Any(), gomock.AssignableToTypeOf(&userCommands.LoginCommand{})).Return(successResponse),
+					executorMock.EXPECT().ExecuteCommand(gomock.Any(), &entryCommands.ListEntryCommand{}).Return(successResponse),
+				)
+			},
+			wantOutput: []string{"vault is locked"},
+		},
+		{
+			name:   "success help of command printed by parser",
+			reader: &testLineReader{passwords: []string{"master"}, lines: []string{"help list", "help"}},
+			mockBehaviour: func(reader *testLineReader) {
+				executorMock.EXPECT().ExecuteCommand(gomock.Any(), &userCommands.UnlockCommand{MasterPassword: "master"}).Return(successResponse)
+			},
+			wantOutput: []string{"commands: list, help, exit, quit"},
+		},
+		{
+			name:   "success unlock again after lock",
+			reader: &testLineReader{passwords: []string{"master", "master"}, lines: []string{"lock", "list"}},
+			mockBehaviour: func(reader *testLineReader) {
+				gomock.InOrder(
+					executorMock.EXPECT().ExecuteCommand(gomock.Any(), &userCommands.UnlockCommand{MasterPassword: "master"}).Return(successResponse),
+					executorMock.EXPECT().ExecuteCommand(gomock.Any(), &userCommands.LockCommand{}).Return(successResponse),
+					executorMock.EXPECT().ExecuteCommand(gomock.Any(), &userCommands.UnlockCommand{MasterPassword: "master"}).Return(successResponse),
+					executorMock.EXPECT().ExecuteCommand(gomock.Any(), &entryCommands.ListEntryCommand{}).Return(successResponse),
+				)
+			},
+		},
+		{
+			name:        "success auto lock after inactivity",
+			reader:      &testLineReader{passwords: []string{"master", "master"}, lines: []string{"list"}},
+			idleTimeout: 10 * time.Millisecond,
+			mockBehaviour: func(reader *testLineReader) {
+				idleLocked := make(chan struct{})
+				reader.waitBefore = map[int]chan struct{}{0: idleLocked}
+				gomock.InOrder(
+					executorMock.EXPECT().ExecuteCommand(gomock.Any(), &userCommands.UnlockCommand{MasterPassword: "master"}).Return(successResponse),
+					executorMock.EXPECT().ExecuteCommand(gomock.Any(), &userCommands.LockCommand{}).DoAndReturn(
+						func(ctx context.Context, cmd sharedCommand.CommandInterface) sharedCommand.CommandResponse {
+							close(idleLocked)
+							return successResponse
+						}),
+					executorMock.EXPECT().ExecuteCommand(gomock.Any(), &userCommands.UnlockCommand{MasterPassword: "master"}).Return(successResponse),
+					executorMock.EXPECT().ExecuteCommand(gomock.Any(), &entryCommands.ListEntryCommand{}).Return(successResponse),
+				)
+			},
+		},
+		{
+			name:   "not available and not valid commands",
+			reader: &testLineReader{passwords: []string{"master"}, lines: []string{"agent", "--profile work list", "unknown", "list 'x"}},
+			mockBehaviour: func(reader *testLineReader) {
+				executorMock.EXPECT().ExecuteCommand(gomock.Any(), &userCommands.UnlockCommand{MasterPassword: "master"}).Return(successResponse)
+			},
+			wantOutput: []string{
+				"error: command agent is not available in shell",
+				"error: profile is selected when shell starts",
+				"error: not valid command",
+				"error: unterminated quote",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.reader)
+			s := NewShell(executorMock, testParser, writer, []string{"list"}, tt.idleTimeout)
+			err := s.Run(context.Background(), tt.reader)
+			require.NoError(t, err)
+			for _, wantOutput := range tt.wantOutput {
+				assert.Contains(t, tt.reader.String(), wantOutput)
+			}
+		})
+	}
+}
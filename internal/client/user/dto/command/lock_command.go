@@ -0,0 +1,10 @@
+package command
+
+import validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+
+type LockCommand struct {
+}
+
+func (command *LockCommand) Validate() validation.ValidationErrors {
+	return nil
+}
@@ -0,0 +1,20 @@
+package command
+
+import (
+	"fmt"
+
+	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+)
+
+// UnlockCommand разблокировка хранилища мастер-паролем без обращения к серверу
+type UnlockCommand struct {
+	MasterPassword string
+}
+
+func (command *UnlockCommand) Validate() validation.ValidationErrors {
+	var validationErrors validation.ValidationErrors
+	if command.MasterPassword == "" {
+		validationErrors = append(validationErrors, fmt.Errorf("master password required"))
+	}
+	return validationErrors
+}
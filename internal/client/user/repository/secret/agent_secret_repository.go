@@ -0,0 +1,53 @@
+package secret
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/anoriar/gophkeeper/internal/client/vault/agent"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+// AgentSecretRepository ключи хранилища хранятся только в памяти агента, токен - в файле.
+// Если агент не запущен, login запускает его
+type AgentSecretRepository struct {
+	*SecretRepository
+	agentClient   *agent.AgentClient
+	agentLauncher *agent.AgentLauncher
+}
+
+func NewAgentSecretRepository(fileRepository *SecretRepository, agentClient *agent.AgentClient, agentLauncher *agent.AgentLauncher) *AgentSecretRepository {
+	return &AgentSecretRepository{SecretRepository: fileRepository, agentClient: agentClient, agentLauncher: agentLauncher}
+}
+
+func (s AgentSecretRepository) SaveKeyring(keyring entity.Keyring) error {
+	err := s.agentClient.Unlock(keyring)
+	if !errors.Is(err, agent.ErrAgentNotRunning) {
+		return err
+	}
+	err = s.agentLauncher.Start()
+	if err != nil {
+		return err
+	}
+	return s.agentClient.Unlock(keyring)
+}
+
+func (s AgentSecretRepository) GetKeyring() (entity.Keyring, error) {
+	keyring, err := s.agentClient.GetKeyring()
+	if err != nil {
+		if errors.Is(err, agent.ErrAgentLocked) || errors.Is(err, agent.ErrAgentNotRunning) {
+			return entity.Keyring{}, fmt.Errorf("%w: %v", ErrVaultLocked, err)
+		}
+		return entity.Keyring{}, err
+	}
+	return keyring, nil
+}
+
+// DeleteKeyring агента нет - ключей тоже нет
+func (s AgentSecretRepository) DeleteKeyring() error {
+	err := s.agentClient.Lock()
+	if errors.Is(err, agent.ErrAgentNotRunning) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,42 @@
+package secret
+
+import (
+	"sync"
+
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+// MemorySecretRepository ключи хранилища хранятся только в памяти процесса (shell), токен - в исходном репозитории.
+// Блокировка забывает только ключи процесса: ключи в агенте остаются как есть
+type MemorySecretRepository struct {
+	SecretRepositoryInterface
+	mu      sync.Mutex
+	keyring *entity.Keyring
+}
+
+func NewMemorySecretRepository(secretRepository SecretRepositoryInterface) *MemorySecretRepository {
+	return &MemorySecretRepository{SecretRepositoryInterface: secretRepository}
+}
+
+func (s *MemorySecretRepository) SaveKeyring(keyring entity.Keyring) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keyring = &keyring
+	return nil
+}
+
+func (s *MemorySecretRepository) GetKeyring() (entity.Keyring, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keyring == nil {
+		return entity.Keyring{}, ErrVaultLocked
+	}
+	return *s.keyring, nil
+}
+
+func (s *MemorySecretRepository) DeleteKeyring() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keyring = nil
+	return nil
+}
@@ -7,6 +7,7 @@ package mock_secret_repository
 import (
 	reflect "reflect"
 
+	entity "github.com/anoriar/gophkeeper/internal/client/vault/entity"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -33,6 +34,20 @@ func (m *MockSecretRepositoryInterface) EXPECT() *MockSecretRepositoryInterfaceM
 	return m.recorder
 }
 
+// DeleteKeyring mocks base method.
+func (m *MockSecretRepositoryInterface) DeleteKeyring() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteKeyring")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteKeyring indicates an expected call of DeleteKeyring.
+func (mr *MockSecretRepositoryInterfaceMockRecorder) DeleteKeyring() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKeyring", reflect.TypeOf((*MockSecretRepositoryInterface)(nil).DeleteKeyring))
+}
+
 // GetAuthToken mocks base method.
 func (m *MockSecretRepositoryInterface) GetAuthToken() (string, error) {
 	m.ctrl.T.Helper()
@@ -48,19 +63,19 @@ func (mr *MockSecretRepositoryInterfaceMockRecorder) GetAuthToken() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthToken", reflect.TypeOf((*MockSecretRepositoryInterface)(nil).GetAuthToken))
 }
 
-// GetMasterPassword mocks base method.
-func (m *MockSecretRepositoryInterface) GetMasterPassword() (string, error) {
+// GetKeyring mocks base method.
+func (m *MockSecretRepositoryInterface) GetKeyring() (entity.Keyring, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMasterPassword")
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetKeyring")
+	ret0, _ := ret[0].(entity.Keyring)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetMasterPassword indicates an expected call of GetMasterPassword.
-func (mr *MockSecretRepositoryInterfaceMockRecorder) GetMasterPassword() *gomock.Call {
+// GetKeyring indicates an expected call of GetKeyring.
+func (mr *MockSecretRepositoryInterfaceMockRecorder) GetKeyring() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMasterPassword", reflect.TypeOf((*MockSecretRepositoryInterface)(nil).GetMasterPassword))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyring", reflect.TypeOf((*MockSecretRepositoryInterface)(nil).GetKeyring))
 }
 
 // SaveAuthToken mocks base method.
@@ -77,16 +92,16 @@ func (mr *MockSecretRepositoryInterfaceMockRecorder) SaveAuthToken(token interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAuthToken", reflect.TypeOf((*MockSecretRepositoryInterface)(nil).SaveAuthToken), token)
 }
 
-// SaveMasterPassword mocks base method.
-func (m *MockSecretRepositoryInterface) SaveMasterPassword(pass string) error {
+// SaveKeyring mocks base method.
+func (m *MockSecretRepositoryInterface) SaveKeyring(keyring entity.Keyring) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveMasterPassword", pass)
+	ret := m.ctrl.Call(m, "SaveKeyring", keyring)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SaveMasterPassword indicates an expected call of SaveMasterPassword.
-func (mr *MockSecretRepositoryInterfaceMockRecorder) SaveMasterPassword(pass interface{}) *gomock.Call {
+// SaveKeyring indicates an expected call of SaveKeyring.
+func (mr *MockSecretRepositoryInterfaceMockRecorder) SaveKeyring(keyring interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMasterPassword", reflect.TypeOf((*MockSecretRepositoryInterface)(nil).SaveMasterPassword), pass)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveKeyring", reflect.TypeOf((*MockSecretRepositoryInterface)(nil).SaveKeyring), keyring)
 }
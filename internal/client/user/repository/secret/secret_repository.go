@@ -2,51 +2,39 @@ package secret
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/atomicfile"
 )
 
-var ErrTokenNotFound = errors.New("auth token not found")
-var ErrMasterPasswordNotFound = errors.New("master password not found")
+// ErrTokenNotFound и ErrVaultLocked - частные случаи sharedErrors.ErrNotLoggedIn
+var ErrTokenNotFound = fmt.Errorf("%w: auth token not found", sharedErrors.ErrNotLoggedIn)
+var ErrVaultLocked = fmt.Errorf("%w: vault is locked, run login to unlock it", sharedErrors.ErrNotLoggedIn)
 
+// SecretRepository токен авторизации в файле. Ключи хранилища на диск не попадают: их хранят агент или память процесса
 type SecretRepository struct {
-	authTokenFileName      string
-	masterPasswordFileName string
+	authTokenFileName string
 }
 
-func NewSecretRepository(authTokenFilename string, masterPasswordFilename string) (*SecretRepository, error) {
-	authTokenDir := filepath.Dir(authTokenFilename)
-	masterPassDir := filepath.Dir(masterPasswordFilename)
-	err := mkdir(authTokenDir)
-	if err != nil {
-		return nil, err
-	}
-	err = mkdir(masterPassDir)
+func NewSecretRepository(authTokenFilename string) (*SecretRepository, error) {
+	err := mkdir(filepath.Dir(authTokenFilename))
 	if err != nil {
 		return nil, err
 	}
 
-	return &SecretRepository{authTokenFileName: authTokenFilename, masterPasswordFileName: masterPasswordFilename}, nil
+	return &SecretRepository{authTokenFileName: authTokenFilename}, nil
 }
 
 func (s SecretRepository) SaveAuthToken(token string) error {
-	file, err := os.OpenFile(s.authTokenFileName, os.O_WRONLY|os.O_CREATE, 0666)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	_, err = file.Write([]byte(token))
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return atomicfile.WriteFile(s.authTokenFileName, []byte(token))
 }
 
 func (s SecretRepository) GetAuthToken() (string, error) {
-	file, err := os.OpenFile(s.authTokenFileName, os.O_RDONLY|os.O_CREATE, 0666)
+	file, err := os.OpenFile(s.authTokenFileName, os.O_RDONLY|os.O_CREATE, 0600)
 	if err != nil {
 		return "", err
 	}
@@ -64,42 +52,35 @@ func (s SecretRepository) GetAuthToken() (string, error) {
 	return string(content), nil
 }
 
-func (s SecretRepository) SaveMasterPassword(pass string) error {
-	file, err := os.OpenFile(s.masterPasswordFileName, os.O_WRONLY|os.O_CREATE, 0666)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	_, err = file.Write([]byte(pass))
-	if err != nil {
-		return err
-	}
+// RemoveLegacyMasterPassword удаление мастер-пароля, сохраненного предыдущими версиями клиента
+func RemoveLegacyMasterPassword(fileName string) error {
+	return wipeFile(fileName)
+}
 
-	return nil
+// RemoveLegacySession удаление файла сессии предыдущих версий клиента: в нем ключи хранилища в открытом виде
+func RemoveLegacySession(fileName string) error {
+	return wipeFile(fileName)
 }
 
-func (s SecretRepository) GetMasterPassword() (string, error) {
-	file, err := os.OpenFile(s.masterPasswordFileName, os.O_RDONLY|os.O_CREATE, 0666)
+// wipeFile файл перед удалением перезаписывается нулями
+func wipeFile(fileName string) error {
+	info, err := os.Stat(fileName)
 	if err != nil {
-		return "", err
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
 	}
-	defer file.Close()
 
-	content, err := io.ReadAll(file)
+	err = os.WriteFile(fileName, make([]byte, info.Size()), 0600)
 	if err != nil {
-		return "", err
-	}
-
-	if len(content) == 0 {
-		return "", ErrMasterPasswordNotFound
+		return err
 	}
-
-	return string(content), nil
+	return os.Remove(fileName)
 }
 
 func mkdir(dirName string) error {
-	err := os.MkdirAll(dirName, 0755)
+	err := os.MkdirAll(dirName, 0700)
 	if err != nil {
 		return err
 	}
@@ -1,10 +1,16 @@
 package secret
 
+import "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+
 //go:generate mockgen -source=secret_repository_interface.go -destination=mock_secret_repository/mock_secret_repository.go -package=mock_secret_repository
 type SecretRepositoryInterface interface {
 	SaveAuthToken(token string) error
 	GetAuthToken() (string, error)
 
-	SaveMasterPassword(pass string) error
-	GetMasterPassword() (string, error)
+	// SaveKeyring разблокировка хранилища: ключи доступны до истечения сессии
+	SaveKeyring(keyring entity.Keyring) error
+	// GetKeyring ErrVaultLocked, если хранилище заблокировано или сессия истекла
+	GetKeyring() (entity.Keyring, error)
+	// DeleteKeyring блокировка хранилища
+	DeleteKeyring() error
 }
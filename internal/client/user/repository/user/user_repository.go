@@ -34,7 +34,7 @@ func (u UserRepository) Register(ctx context.Context, request request.RegisterRe
 		Post("/api/user/register")
 
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("%w: %v", sharedErr.ErrServerUnreachable, err)
 	}
 
 	switch resp.StatusCode() {
@@ -61,7 +61,7 @@ func (u UserRepository) Login(ctx context.Context, request request.LoginRequest)
 		Post("/api/user/login")
 
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("%w: %v", sharedErr.ErrServerUnreachable, err)
 	}
 
 	switch resp.StatusCode() {
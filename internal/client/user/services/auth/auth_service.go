@@ -2,38 +2,70 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"go.uber.org/zap"
 
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
 	"github.com/anoriar/gophkeeper/internal/client/user/dto/command"
 	"github.com/anoriar/gophkeeper/internal/client/user/dto/repository/request"
 	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret"
 	"github.com/anoriar/gophkeeper/internal/client/user/repository/user"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/keyring"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/migration"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/reencrypt"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/rekey"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/vaultsync"
 )
 
 type AuthService struct {
 	userRepository   user.UserRepositoryInterface
 	secretRepository secret.SecretRepositoryInterface
+	keyringService   keyring.KeyringServiceInterface
+	reencryptService reencrypt.ReencryptServiceInterface
+	rekeyService     rekey.RekeyServiceInterface
+	vaultSyncService vaultsync.VaultSyncServiceInterface
+	migrationService migration.MigrationServiceInterface
 	logger           *zap.Logger
 }
 
-func NewAuthService(userRepository user.UserRepositoryInterface, secretRepository secret.SecretRepositoryInterface, logger *zap.Logger) *AuthService {
+func NewAuthService(
+	userRepository user.UserRepositoryInterface,
+	secretRepository secret.SecretRepositoryInterface,
+	keyringService keyring.KeyringServiceInterface,
+	reencryptService reencrypt.ReencryptServiceInterface,
+	rekeyService rekey.RekeyServiceInterface,
+	vaultSyncService vaultsync.VaultSyncServiceInterface,
+	migrationService migration.MigrationServiceInterface,
+	logger *zap.Logger,
+) *AuthService {
 	return &AuthService{
 		userRepository:   userRepository,
 		secretRepository: secretRepository,
+		keyringService:   keyringService,
+		reencryptService: reencryptService,
+		rekeyService:     rekeyService,
+		vaultSyncService: vaultSyncService,
+		migrationService: migrationService,
 		logger:           logger,
 	}
 }
 
 func (a *AuthService) Register(ctx context.Context, command command.RegisterCommand) error {
+	keyring, err := a.unlockKeyring(ctx, command.MasterPassword)
+	if err != nil {
+		return err
+	}
+
 	token, err := a.userRepository.Register(ctx, request.RegisterRequest{
 		Login:    command.UserName,
 		Password: command.Password,
 	})
 	if err != nil {
 		a.logger.Error("register error", zap.String("error", err.Error()))
-		return fmt.Errorf("register error: %v", err.Error())
+		return fmt.Errorf("register error: %w", err)
 	}
 
 	err = a.secretRepository.SaveAuthToken(token)
@@ -41,15 +73,13 @@ func (a *AuthService) Register(ctx context.Context, command command.RegisterComm
 		a.logger.Error("save auth token error", zap.String("error", err.Error()))
 		return fmt.Errorf("save auth token error: %v", err.Error())
 	}
+	a.pushVault(ctx, token)
 
-	err = a.secretRepository.SaveMasterPassword(command.MasterPassword)
-	if err != nil {
-		a.logger.Error("save master password error", zap.String("error", err.Error()))
-		return fmt.Errorf("save master password error: %v", err.Error())
-	}
-	return nil
+	return a.saveKeyring(ctx, keyring)
 }
 
+// Login слоты ключей с сервера добавляются в хранилище до проверки мастер-пароля: на новом устройстве
+// или после потери локальных файлов без них ключи записей из мастер-пароля не получить
 func (a *AuthService) Login(ctx context.Context, command command.LoginCommand) error {
 	token, err := a.userRepository.Login(ctx, request.LoginRequest{
 		Login:    command.UserName,
@@ -57,7 +87,18 @@ func (a *AuthService) Login(ctx context.Context, command command.LoginCommand) e
 	})
 	if err != nil {
 		a.logger.Error("login error", zap.String("error", err.Error()))
-		return fmt.Errorf("login error: %v", err.Error())
+		return fmt.Errorf("login error: %w", err)
+	}
+
+	err = a.vaultSyncService.Pull(ctx, token)
+	if err != nil {
+		a.logger.Error("get key slots error", zap.String("error", err.Error()))
+		return fmt.Errorf("get key slots error: %w", err)
+	}
+
+	keyring, err := a.unlockKeyring(ctx, command.MasterPassword)
+	if err != nil {
+		return err
 	}
 
 	err = a.secretRepository.SaveAuthToken(token)
@@ -65,10 +106,118 @@ func (a *AuthService) Login(ctx context.Context, command command.LoginCommand) e
 		a.logger.Error("save auth token error", zap.String("error", err.Error()))
 		return fmt.Errorf("save auth token error: %v", err.Error())
 	}
-	err = a.secretRepository.SaveMasterPassword(command.MasterPassword)
+	a.pushVault(ctx, token)
+
+	return a.saveKeyring(ctx, keyring)
+}
+
+// Unlock разблокировка без login: токен и сервер не нужны, мастер-пароль проверяется по keyCheck
+func (a *AuthService) Unlock(ctx context.Context, command command.UnlockCommand) error {
+	keyring, err := a.unlockKeyring(ctx, command.MasterPassword)
+	if err != nil {
+		return err
+	}
+	return a.saveKeyring(ctx, keyring)
+}
+
+func (a *AuthService) Lock(ctx context.Context) error {
+	a.keyringService.Lock()
+	err := a.secretRepository.DeleteKeyring()
+	if err != nil {
+		a.logger.Error("lock vault error", zap.String("error", err.Error()))
+		return fmt.Errorf("lock vault error: %v", err.Error())
+	}
+	return nil
+}
+
+// unlockKeyring получает ключи из мастер-пароля и проверяет его по keyCheck.
+// Прерванный rekey откатывается здесь: пока хранилище заблокировано, ключа для отката нет
+func (a *AuthService) unlockKeyring(ctx context.Context, masterPassword string) (entity.Keyring, error) {
+	keyring, err := a.unlock(masterPassword)
+	if err != nil {
+		return entity.Keyring{}, err
+	}
+
+	recovered, err := a.rekeyService.Recover(ctx)
+	if err != nil {
+		a.logger.Error("restore vault error", zap.String("error", err.Error()))
+		return entity.Keyring{}, fmt.Errorf("restore vault error: %v", err.Error())
+	}
+	if !recovered {
+		return keyring, nil
+	}
+	// восстановлено хранилище старого мастер-пароля
+	keyring, err = a.unlock(masterPassword)
+	if err != nil {
+		if errors.Is(err, sharedErrors.ErrWrongMasterPassword) {
+			return entity.Keyring{}, fmt.Errorf("interrupted rekey was rolled back, use the old master password: %w", err)
+		}
+		return entity.Keyring{}, err
+	}
+	return keyring, nil
+}
+
+func (a *AuthService) unlock(masterPassword string) (entity.Keyring, error) {
+	keyring, err := a.keyringService.Unlock(masterPassword)
+	if err != nil {
+		if errors.Is(err, sharedErrors.ErrWrongMasterPassword) {
+			return entity.Keyring{}, err
+		}
+		a.logger.Error("unlock vault error", zap.String("error", err.Error()))
+		return entity.Keyring{}, fmt.Errorf("unlock vault error: %v", err.Error())
+	}
+	return keyring, nil
+}
+
+// saveKeyring сохраняет ключи на время сессии. Сам мастер-пароль не сохраняется.
+// Записи, которые не удалось перешифровать, не мешают разблокировке: попытка повторится при следующем login
+func (a *AuthService) saveKeyring(ctx context.Context, keyring entity.Keyring) error {
+	// миграции файлов, отложенные до разблокировки, переносят записи в хранилище до их перешифрования
+	migrateErr := a.migrationService.Migrate(ctx)
+	if migrateErr != nil {
+		a.logger.Warn("migrate vault files error", zap.String("error", migrateErr.Error()))
+	}
+	// пока ключ старого формата есть в памяти, переводим записи на ключ из KDF
+	skipped, err := a.reencryptService.Reencrypt(ctx, keyring)
 	if err != nil {
-		a.logger.Error("save master password error", zap.String("error", err.Error()))
-		return fmt.Errorf("save master password error: %v", err.Error())
+		a.logger.Warn("upgrade legacy entries error", zap.String("error", err.Error()))
+	}
+	if len(skipped) > 0 {
+		a.logger.Warn("entries can't be decrypted with this master password and are left as is", zap.Strings("ids", skipped))
+	}
+	// записи, которые еще не перенесены миграцией, могут быть в старых форматах
+	if migrateErr == nil && err == nil && len(skipped) == 0 {
+		err = a.keyringService.CompleteLegacyMigration()
+		if err != nil {
+			a.logger.Warn("complete legacy migration error", zap.String("error", err.Error()))
+		} else {
+			keyring.RejectLegacy = true
+			a.clearMigrationBackups(ctx)
+		}
+	}
+
+	err = a.secretRepository.SaveKeyring(keyring)
+	if err != nil {
+		a.logger.Error("save keyring error", zap.String("error", err.Error()))
+		return fmt.Errorf("save keyring error: %v", err.Error())
 	}
 	return nil
 }
+
+// clearMigrationBackups удаляет копии файлов, сделанные перед миграциями формата: записи в них больше не нужны,
+// а старый мастер-пароль и старые форматы по-прежнему их открывают. Ошибка повторится при следующем login
+func (a *AuthService) clearMigrationBackups(ctx context.Context) {
+	err := a.migrationService.ClearBackups(ctx)
+	if err != nil {
+		a.logger.Warn("clear migration backups error", zap.String("error", err.Error()))
+	}
+}
+
+// pushVault отправляет слоты ключей на сервер, чтобы записи этого устройства расшифровывались на других.
+// Ошибка не мешает работе с локальным хранилищем: слоты отправятся при следующем login
+func (a *AuthService) pushVault(ctx context.Context, token string) {
+	err := a.vaultSyncService.Push(ctx, token)
+	if err != nil {
+		a.logger.Warn("send key slots error", zap.String("error", err.Error()))
+	}
+}
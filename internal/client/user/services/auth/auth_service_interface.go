@@ -9,4 +9,8 @@ import (
 type AuthServiceInterface interface {
 	Register(ctx context.Context, command command.RegisterCommand) error
 	Login(ctx context.Context, command command.LoginCommand) error
+	// Unlock разблокировка хранилища мастер-паролем без обращения к серверу
+	Unlock(ctx context.Context, command command.UnlockCommand) error
+	// Lock блокировка хранилища до следующего login
+	Lock(ctx context.Context) error
 }
@@ -0,0 +1,202 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+const connTimeout = 5 * time.Second
+
+// Agent хранит ключи разблокированного хранилища в памяти и отдает их по unix сокету.
+// Блокируется после idleTimeout без обращений или по команде lock
+type Agent struct {
+	socketPath  string
+	idleTimeout time.Duration
+	logger      *zap.Logger
+
+	mu        sync.Mutex
+	keyring   *entity.Keyring
+	idleTimer *time.Timer
+	// idleGeneration меняется при каждом обращении и блокировке: таймер, сработавший до них, не блокирует агента
+	idleGeneration uint64
+}
+
+func NewAgent(socketPath string, idleTimeout time.Duration, logger *zap.Logger) *Agent {
+	return &Agent{
+		socketPath:  socketPath,
+		idleTimeout: idleTimeout,
+		logger:      logger,
+	}
+}
+
+// Run слушает сокет до отмены контекста
+func (a *Agent) Run(ctx context.Context) error {
+	err := os.MkdirAll(filepath.Dir(a.socketPath), 0700)
+	if err != nil {
+		return err
+	}
+	err = a.removeStaleSocket()
+	if err != nil {
+		return err
+	}
+
+	listener, err := listenUnix(a.socketPath)
+	if err != nil {
+		return fmt.Errorf("listen agent socket error: %w", err)
+	}
+	defer os.Remove(a.socketPath)
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	a.logger.Info("agent started", zap.String("socket", a.socketPath), zap.Duration("idleTimeout", a.idleTimeout))
+	defer a.lock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				a.logger.Info("agent stopped")
+				return nil
+			}
+			return fmt.Errorf("accept agent connection error: %w", err)
+		}
+		go a.handle(conn)
+	}
+}
+
+func (a *Agent) handle(conn net.Conn) {
+	defer conn.Close()
+	err := checkPeer(conn)
+	if err != nil {
+		a.logger.Warn("agent connection rejected", zap.String("error", err.Error()))
+		return
+	}
+	_ = conn.SetDeadline(time.Now().Add(connTimeout))
+
+	var req request
+	err = json.NewDecoder(conn).Decode(&req)
+	if err != nil {
+		// проверка, запущен ли агент, закрывает соединение без запроса
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		a.logger.Error("decode agent request error", zap.String("error", err.Error()))
+		return
+	}
+
+	err = json.NewEncoder(conn).Encode(a.process(req))
+	if err != nil {
+		a.logger.Error("encode agent response error", zap.String("error", err.Error()))
+	}
+}
+
+func (a *Agent) process(req request) response {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch req.Action {
+	case actionUnlock:
+		if req.Keyring == nil {
+			return response{Error: "keyring required"}
+		}
+		a.keyring = req.Keyring
+		a.resetIdleTimer()
+		a.logger.Info("vault unlocked")
+		return response{}
+	case actionGet:
+		if a.keyring == nil {
+			return response{Error: errorLocked}
+		}
+		a.resetIdleTimer()
+		return response{Keyring: copyKeyring(a.keyring)}
+	case actionLock:
+		a.lockLocked()
+		return response{}
+	default:
+		return response{Error: fmt.Sprintf("unknown action %q", req.Action)}
+	}
+}
+
+func (a *Agent) resetIdleTimer() {
+	if a.idleTimer != nil {
+		a.idleTimer.Stop()
+	}
+	a.idleGeneration++
+	generation := a.idleGeneration
+	a.idleTimer = time.AfterFunc(a.idleTimeout, func() {
+		a.lockIdle(generation)
+	})
+}
+
+// lockIdle блокировка по таймеру. Stop не отменяет уже сработавший таймер, поэтому он сверяет поколение
+func (a *Agent) lockIdle(generation uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if generation != a.idleGeneration {
+		return
+	}
+	a.lockLocked()
+}
+
+func (a *Agent) lock() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lockLocked()
+}
+
+func (a *Agent) lockLocked() {
+	a.idleGeneration++
+	if a.idleTimer != nil {
+		a.idleTimer.Stop()
+		a.idleTimer = nil
+	}
+	if a.keyring == nil {
+		return
+	}
+	for _, key := range a.keyring.Keys {
+		clear(key.Key)
+	}
+	clear(a.keyring.StoreKey)
+	a.keyring = nil
+	a.logger.Info("vault locked")
+}
+
+// removeStaleSocket удаляет сокет, оставшийся от завершившегося агента
+func (a *Agent) removeStaleSocket() error {
+	if _, err := os.Stat(a.socketPath); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	conn, err := net.DialTimeout("unix", a.socketPath, connTimeout)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("agent is already running on %s", a.socketPath)
+	}
+	return os.Remove(a.socketPath)
+}
+
+// copyKeyring ответ кодируется вне блокировки, поэтому агент отдает копию ключей
+func copyKeyring(keyring *entity.Keyring) *entity.Keyring {
+	keyringCopy := *keyring
+	keyringCopy.Keys = make([]entity.VaultKey, 0, len(keyring.Keys))
+	for _, key := range keyring.Keys {
+		key.Key = append([]byte(nil), key.Key...)
+		keyringCopy.Keys = append(keyringCopy.Keys, key)
+	}
+	keyringCopy.StoreKey = append([]byte(nil), keyring.StoreKey...)
+	return &keyringCopy
+}
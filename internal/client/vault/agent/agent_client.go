@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+var ErrAgentNotRunning = errors.New("agent is not running")
+var ErrAgentLocked = errors.New("agent is locked")
+
+type AgentClient struct {
+	socketPath string
+}
+
+func NewAgentClient(socketPath string) *AgentClient {
+	return &AgentClient{socketPath: socketPath}
+}
+
+func (c *AgentClient) Unlock(keyring entity.Keyring) error {
+	_, err := c.call(request{Action: actionUnlock, Keyring: &keyring})
+	return err
+}
+
+func (c *AgentClient) GetKeyring() (entity.Keyring, error) {
+	resp, err := c.call(request{Action: actionGet})
+	if err != nil {
+		return entity.Keyring{}, err
+	}
+	if resp.Keyring == nil {
+		return entity.Keyring{}, ErrAgentLocked
+	}
+	return *resp.Keyring, nil
+}
+
+func (c *AgentClient) Lock() error {
+	_, err := c.call(request{Action: actionLock})
+	return err
+}
+
+func (c *AgentClient) call(req request) (response, error) {
+	conn, err := net.DialTimeout("unix", c.socketPath, connTimeout)
+	if err != nil {
+		return response{}, fmt.Errorf("%w: %v", ErrAgentNotRunning, err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(connTimeout))
+
+	err = json.NewEncoder(conn).Encode(req)
+	if err != nil {
+		return response{}, err
+	}
+
+	var resp response
+	err = json.NewDecoder(conn).Decode(&resp)
+	if err != nil {
+		return response{}, err
+	}
+
+	switch resp.Error {
+	case "":
+		return resp, nil
+	case errorLocked:
+		return response{}, ErrAgentLocked
+	default:
+		return response{}, errors.New(resp.Error)
+	}
+}
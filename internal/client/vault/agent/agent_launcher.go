@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"time"
+)
+
+const launchPollInterval = 50 * time.Millisecond
+
+// AgentLauncher запускает агента в фоне, если login не застал его запущенным. Агент - тот же исполняемый файл
+// с командой agent, поэтому читает то же окружение
+type AgentLauncher struct {
+	socketPath string
+	args       []string
+}
+
+func NewAgentLauncher(socketPath string, args []string) *AgentLauncher {
+	return &AgentLauncher{socketPath: socketPath, args: args}
+}
+
+// Start запускает агента и ждет, пока он начнет слушать сокет
+func (l *AgentLauncher) Start() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	// stdin и вывод агента - /dev/null: агент переживает терминал, из которого запущен
+	cmd := exec.Command(executable, l.args...)
+	cmd.SysProcAttr = detachedProcAttr()
+	err = cmd.Start()
+	if err != nil {
+		return fmt.Errorf("start agent error: %w", err)
+	}
+	err = cmd.Process.Release()
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(connTimeout)
+	for time.Now().Before(deadline) {
+		conn, err := net.DialTimeout("unix", l.socketPath, connTimeout)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		time.Sleep(launchPollInterval)
+	}
+	return fmt.Errorf("%w: agent did not start on %s", ErrAgentNotRunning, l.socketPath)
+}
@@ -0,0 +1,10 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package agent
+
+import "syscall"
+
+// detachedProcAttr агент в своей сессии не получает SIGHUP и Ctrl+C терминала
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anoriar/gophkeeper/internal/client/shared/app/logger"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+func startAgent(t *testing.T, idleTimeout time.Duration) *AgentClient {
+	loggerMock, err := logger.Initialize("error")
+	require.NoError(t, err)
+
+	// путь к unix сокету ограничен ~100 символами, поэтому не t.TempDir()
+	dir, err := os.MkdirTemp("", "gk")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, "agent.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- NewAgent(socketPath, idleTimeout, loggerMock).Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(socketPath)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	info, err := os.Stat(socketPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	return NewAgentClient(socketPath)
+}
+
+func TestAgent_UnlockGetLock(t *testing.T) {
+	client := startAgent(t, time.Minute)
+	keyring := entity.Keyring{
+		CurrentKeyId: "0102030405060708",
+		Keys:         []entity.VaultKey{{Id: "0102030405060708", Key: []byte("0123456789abcdef0123456789abcdef")}},
+		StoreKey:     []byte("fedcba9876543210fedcba9876543210"),
+	}
+
+	_, err := client.GetKeyring()
+	assert.ErrorIs(t, err, ErrAgentLocked)
+
+	require.NoError(t, client.Unlock(keyring))
+	got, err := client.GetKeyring()
+	require.NoError(t, err)
+	assert.Equal(t, keyring, got)
+
+	require.NoError(t, client.Lock())
+	_, err = client.GetKeyring()
+	assert.ErrorIs(t, err, ErrAgentLocked)
+}
+
+func TestAgent_IdleTimeout(t *testing.T) {
+	client := startAgent(t, 50*time.Millisecond)
+
+	require.NoError(t, client.Unlock(entity.Keyring{CurrentKeyId: "0102030405060708"}))
+	assert.Eventually(t, func() bool {
+		_, err := client.GetKeyring()
+		return err != nil
+	}, time.Second, 100*time.Millisecond)
+}
+
+func TestAgentClient_NotRunning(t *testing.T) {
+	_, err := NewAgentClient(filepath.Join(t.TempDir(), "agent.sock")).GetKeyring()
+	assert.ErrorIs(t, err, ErrAgentNotRunning)
+}
+
+func TestAgent_StaleIdleTimer(t *testing.T) {
+	loggerMock, err := logger.Initialize("error")
+	require.NoError(t, err)
+	a := NewAgent("", time.Minute, loggerMock)
+
+	require.Empty(t, a.process(request{Action: actionUnlock, Keyring: &entity.Keyring{CurrentKeyId: "0102030405060708"}}).Error)
+	staleGeneration := a.idleGeneration
+	// обращение после срабатывания таймера продлевает разблокировку
+	require.Empty(t, a.process(request{Action: actionGet}).Error)
+	a.lockIdle(staleGeneration)
+	assert.Empty(t, a.process(request{Action: actionGet}).Error)
+
+	a.lockIdle(a.idleGeneration)
+	assert.Equal(t, errorLocked, a.process(request{Action: actionGet}).Error)
+}
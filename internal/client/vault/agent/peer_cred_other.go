@@ -0,0 +1,11 @@
+//go:build !(linux || darwin || freebsd)
+
+package agent
+
+import "net"
+
+// Без SO_PEERCRED и LOCAL_PEERCRED доступ к агенту ограничивают только права сокета и его каталога
+
+func checkPeer(conn net.Conn) error {
+	return nil
+}
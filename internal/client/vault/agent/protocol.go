@@ -0,0 +1,22 @@
+package agent
+
+import "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+
+const (
+	actionUnlock = "unlock"
+	actionGet    = "get"
+	actionLock   = "lock"
+
+	errorLocked = "locked"
+)
+
+// request запрос к агенту, одна json строка на соединение
+type request struct {
+	Action  string          `json:"action"`
+	Keyring *entity.Keyring `json:"keyring,omitempty"`
+}
+
+type response struct {
+	Keyring *entity.Keyring `json:"keyring,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
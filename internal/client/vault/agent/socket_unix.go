@@ -0,0 +1,15 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package agent
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenUnix сокет создается сразу с правами 0600: между созданием и chmod к нему успел бы подключиться другой пользователь
+func listenUnix(socketPath string) (net.Listener, error) {
+	oldMask := syscall.Umask(0177)
+	defer syscall.Umask(oldMask)
+	return net.Listen("unix", socketPath)
+}
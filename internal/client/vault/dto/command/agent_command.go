@@ -0,0 +1,11 @@
+package command
+
+import validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+
+// AgentCommand запуск агента, хранящего ключи хранилища в памяти
+type AgentCommand struct {
+}
+
+func (command *AgentCommand) Validate() validation.ValidationErrors {
+	return nil
+}
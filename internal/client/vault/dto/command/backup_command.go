@@ -0,0 +1,20 @@
+package command
+
+import (
+	"fmt"
+
+	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+)
+
+// BackupCommand резервная копия локального хранилища в один зашифрованный файл
+type BackupCommand struct {
+	FileName string
+}
+
+func (command *BackupCommand) Validate() validation.ValidationErrors {
+	var validationErrors validation.ValidationErrors
+	if command.FileName == "" {
+		validationErrors = append(validationErrors, fmt.Errorf("out file required"))
+	}
+	return validationErrors
+}
@@ -0,0 +1,24 @@
+package command
+
+import (
+	"fmt"
+
+	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+)
+
+// RekeyCommand смена мастер-пароля с перешифрованием всех записей
+type RekeyCommand struct {
+	OldMasterPassword string
+	NewMasterPassword string
+}
+
+func (command *RekeyCommand) Validate() validation.ValidationErrors {
+	var validationErrors validation.ValidationErrors
+	if command.OldMasterPassword == "" {
+		validationErrors = append(validationErrors, fmt.Errorf("old master password required"))
+	}
+	if command.NewMasterPassword == "" {
+		validationErrors = append(validationErrors, fmt.Errorf("new master password required"))
+	}
+	return validationErrors
+}
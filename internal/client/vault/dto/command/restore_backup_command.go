@@ -0,0 +1,25 @@
+package command
+
+import (
+	"fmt"
+
+	validation "github.com/anoriar/gophkeeper/internal/client/shared/dto"
+)
+
+// RestoreBackupCommand замена локального хранилища резервной копией.
+// MasterPassword - мастер-пароль, действовавший на момент создания копии
+type RestoreBackupCommand struct {
+	FileName       string
+	MasterPassword string
+}
+
+func (command *RestoreBackupCommand) Validate() validation.ValidationErrors {
+	var validationErrors validation.ValidationErrors
+	if command.FileName == "" {
+		validationErrors = append(validationErrors, fmt.Errorf("in file required"))
+	}
+	if command.MasterPassword == "" {
+		validationErrors = append(validationErrors, fmt.Errorf("master password required"))
+	}
+	return validationErrors
+}
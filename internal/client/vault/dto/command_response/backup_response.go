@@ -0,0 +1,11 @@
+package command_response
+
+import "time"
+
+// BackupResponse сведения о созданной или восстановленной резервной копии
+type BackupResponse struct {
+	FileName      string    `json:"fileName"`
+	FormatVersion int       `json:"formatVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+	EntriesCount  int       `json:"entriesCount"`
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	entryEntity "github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+)
+
+const (
+	BackupFormat = "gophkeeper-backup"
+	// BackupFormatVersion версия формата резервной копии. Копии более новых версий не восстанавливаются
+	// 2 - записи с зашифрованным названием
+	BackupFormatVersion = 2
+)
+
+// Backup файл резервной копии. Заголовок (все поля, кроме Data) открыт, но аутентифицируется вместе с Data:
+// Data - BackupPayload, зашифрованный AES-256-GCM ключом текущего слота Vault
+type Backup struct {
+	Format        string    `json:"format"`
+	FormatVersion int       `json:"formatVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+	// Vault - соли и параметры KDF, по ним из мастер-пароля получается ключ копии. Мастер-пароль не сохраняется
+	Vault Vault  `json:"vault"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data,omitempty"`
+}
+
+// BackupPayload записи всех типов в том виде, в котором они лежат в хранилище: каждая зашифрована своим ключом.
+// Ревизии записей в копию не попадают
+type BackupPayload struct {
+	FormatVersion int                                    `json:"formatVersion"`
+	Entries       map[enum.EntryType][]entryEntity.Entry `json:"entries"`
+}
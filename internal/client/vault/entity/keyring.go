@@ -0,0 +1,36 @@
+package entity
+
+// Keyring ключи шифрования разблокированного хранилища
+type Keyring struct {
+	CurrentKeyId string     `json:"currentKeyId"`
+	Keys         []VaultKey `json:"keys"`
+	// LegacyKey - ключ записей, зашифрованных до появления KDF.
+	// Существует только в памяти на время login и никогда не сохраняется
+	LegacyKey []byte `json:"-"`
+	// StoreKey - ключ, которым запечатано локальное хранилище записей. Пустой у хранилищ без KeyCheck
+	StoreKey []byte `json:"storeKey,omitempty"`
+	// RejectLegacy - записи старых форматов не расшифровываются, см. Vault.LegacyMigrated
+	RejectLegacy bool `json:"rejectLegacy,omitempty"`
+}
+
+// VaultKey ключ, полученный из мастер-пароля для одного слота хранилища
+type VaultKey struct {
+	Id  string    `json:"id"`
+	Kdf KdfParams `json:"kdf"`
+	Key []byte    `json:"key"`
+	// Verified - ключ совпал с KeyCheck слота, ошибка расшифровки означает поврежденную запись
+	Verified bool `json:"verified"`
+}
+
+func (k Keyring) FindKey(id string) *VaultKey {
+	for i := range k.Keys {
+		if k.Keys[i].Id == id {
+			return &k.Keys[i]
+		}
+	}
+	return nil
+}
+
+func (k Keyring) CurrentKey() *VaultKey {
+	return k.FindKey(k.CurrentKeyId)
+}
@@ -0,0 +1,10 @@
+package entity
+
+import "time"
+
+// Manifest версия формата локальных файлов хранилища: записей, их ревизий и vault.json.
+// Файлы без манифеста записаны версиями клиента до его появления (версия 0)
+type Manifest struct {
+	FormatVersion int       `json:"formatVersion"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import (
+	entryEntity "github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+)
+
+// RekeyRollback состояние хранилища до смены мастер-пароля.
+// Содержит только шифротексты и соли, расшифрованные данные сюда не попадают
+type RekeyRollback struct {
+	Vault   Vault                                  `json:"vault"`
+	Entries map[enum.EntryType][]entryEntity.Entry `json:"entries"`
+	// History - ревизии записей. Пустой в откатах, сохраненных до перешифровки ревизий
+	History map[enum.EntryType][]entryEntity.Entry `json:"history,omitempty"`
+}
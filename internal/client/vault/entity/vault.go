@@ -0,0 +1,126 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	vaultErrors "github.com/anoriar/gophkeeper/internal/client/vault/errors"
+)
+
+// Границы параметров Argon2id: нулевые Time и Threads роняют argon2, а Memory задает объем памяти в КиБ
+const (
+	MaxKdfTime    = 64
+	MaxKdfMemory  = 1024 * 1024
+	MaxKdfThreads = 64
+)
+
+// Vault метаданные локального хранилища: соли и параметры получения ключа из мастер-пароля
+type Vault struct {
+	// CurrentKeyId - id слота, которым шифруются новые записи
+	CurrentKeyId string    `json:"currentKeyId"`
+	KeySlots     []KeySlot `json:"keySlots"`
+	// LegacyMigrated - login перешифровал все записи старых форматов: шифротексты без заголовка и v1 больше не принимаются
+	LegacyMigrated bool `json:"legacyMigrated,omitempty"`
+	// StoreSealed - локальное хранилище записей запечатано ключом хранилища: файл без MAC больше не считается старым форматом
+	StoreSealed bool `json:"storeSealed,omitempty"`
+}
+
+// KeySlot соль и параметры KDF, с которыми был получен ключ шифрования
+type KeySlot struct {
+	// Id - идентификатор соли, попадает в заголовок шифротекста
+	Id   string    `json:"id"`
+	Salt []byte    `json:"salt"`
+	Kdf  KdfParams `json:"kdf"`
+	// KeyCheck - HMAC от ключа, позволяет проверить мастер-пароль без расшифровки записей.
+	// Пустой у слотов, созданных до появления проверки
+	KeyCheck []byte `json:"keyCheck,omitempty"`
+	// StoreKey - ключ локального хранилища записей, зашифрованный ключом слота.
+	// Пустой у слотов без KeyCheck: без проверки пароля ключ хранилища мог бы оказаться зашифрован опечаткой
+	StoreKey []byte `json:"storeKey,omitempty"`
+	// Generation - номер смены мастер-пароля: rekey создает слот с номером больше, чем у всех слотов хранилища.
+	// Текущим на всех устройствах становится слот с наибольшим номером
+	Generation uint64 `json:"generation,omitempty"`
+	// PreviousKeyId и PreviousKey - слот, бывший текущим до rekey, и его ключ, зашифрованный ключом этого слота
+	PreviousKeyId string    `json:"previousKeyId,omitempty"`
+	PreviousKey   []byte    `json:"previousKey,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// KdfParams параметры Argon2id
+type KdfParams struct {
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+// Validate параметры приходят из конфигурации, с сервера и из резервных копий, поэтому проверяются до получения ключа
+func (p KdfParams) Validate() error {
+	if p.Time < 1 || p.Time > MaxKdfTime {
+		return fmt.Errorf("%w: time %d is out of range 1..%d", vaultErrors.ErrKdfParamsNotValid, p.Time, MaxKdfTime)
+	}
+	if p.Threads < 1 || p.Threads > MaxKdfThreads {
+		return fmt.Errorf("%w: threads %d is out of range 1..%d", vaultErrors.ErrKdfParamsNotValid, p.Threads, MaxKdfThreads)
+	}
+	// argon2 требует не меньше 8 КиБ на поток
+	minMemory := 8 * uint32(p.Threads)
+	if p.Memory < minMemory || p.Memory > MaxKdfMemory {
+		return fmt.Errorf("%w: memory %d KiB is out of range %d..%d", vaultErrors.ErrKdfParamsNotValid, p.Memory, minMemory, MaxKdfMemory)
+	}
+	return nil
+}
+
+func (v *Vault) FindKeySlot(id string) *KeySlot {
+	for i := range v.KeySlots {
+		if v.KeySlots[i].Id == id {
+			return &v.KeySlots[i]
+		}
+	}
+	return nil
+}
+
+func (v *Vault) CurrentKeySlot() *KeySlot {
+	return v.FindKeySlot(v.CurrentKeyId)
+}
+
+// NextGeneration номер для слота, созданного rekey
+func (v *Vault) NextGeneration() uint64 {
+	var generation uint64
+	for _, keySlot := range v.KeySlots {
+		generation = max(generation, keySlot.Generation)
+	}
+	return generation + 1
+}
+
+// Merge добавляет слоты remote, которых нет локально: записи, синхронизированные с другого устройства, зашифрованы ими.
+// Слоты с недопустимыми параметрами KDF пропускаются. Текущий слот берется из remote, если локального хранилища еще нет
+// или если на другом устройстве сменили мастер-пароль (номер смены у remote больше).
+// Ключ хранилища записей у каждого устройства свой, поэтому у добавленных в существующее хранилище слотов он не сохраняется.
+// Возвращает true, если хранилище изменилось
+func (v *Vault) Merge(remote Vault) bool {
+	localCurrent := v.CurrentKeySlot()
+	isNew := localCurrent == nil
+	var localGeneration uint64
+	if !isNew {
+		localGeneration = localCurrent.Generation
+	}
+	changed := false
+	for _, keySlot := range remote.KeySlots {
+		if v.FindKeySlot(keySlot.Id) != nil || keySlot.Kdf.Validate() != nil {
+			continue
+		}
+		if !isNew {
+			keySlot.StoreKey = nil
+		}
+		v.KeySlots = append(v.KeySlots, keySlot)
+		changed = true
+	}
+	remoteCurrent := v.FindKeySlot(remote.CurrentKeyId)
+	if remoteCurrent == nil || remote.CurrentKeyId == v.CurrentKeyId {
+		return changed
+	}
+	if isNew || remoteCurrent.Generation > localGeneration {
+		v.CurrentKeyId = remote.CurrentKeyId
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,9 @@
+package errors
+
+import "errors"
+
+var ErrBackupNotValid = errors.New("backup file is not valid or corrupted")
+var ErrBackupVersionNotSupported = errors.New("backup format version is not supported, update gophkeeper")
+var ErrFormatVersionNotSupported = errors.New("local vault files were written by a newer gophkeeper version, update gophkeeper")
+var ErrMigrationPending = errors.New("local vault files need migration, run any gophkeeper command to migrate them")
+var ErrKdfParamsNotValid = errors.New("kdf params are not valid")
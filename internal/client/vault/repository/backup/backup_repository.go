@@ -0,0 +1,41 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/atomicfile"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	vaultErrors "github.com/anoriar/gophkeeper/internal/client/vault/errors"
+)
+
+type BackupRepository struct {
+}
+
+func NewBackupRepository() *BackupRepository {
+	return &BackupRepository{}
+}
+
+func (r *BackupRepository) Get(fileName string) (entity.Backup, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return entity.Backup{}, err
+	}
+
+	var backup entity.Backup
+	err = json.Unmarshal(content, &backup)
+	if err != nil {
+		return entity.Backup{}, fmt.Errorf("%w: %v", vaultErrors.ErrBackupNotValid, err)
+	}
+	return backup, nil
+}
+
+func (r *BackupRepository) Save(fileName string, backup entity.Backup) error {
+	content, err := json.Marshal(backup)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(fileName, content)
+}
@@ -0,0 +1,10 @@
+package backup
+
+import "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+
+//go:generate mockgen -source=backup_repository_interface.go -destination=mock_backup_repository/mock_backup_repository.go -package=mock_backup_repository
+type BackupRepositoryInterface interface {
+	Get(fileName string) (entity.Backup, error)
+	// Save файл копии заменяется целиком: прерванная запись не портит предыдущую копию
+	Save(fileName string, backup entity.Backup) error
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: backup_repository_interface.go
+
+// Package mock_backup_repository is a generated GoMock package.
+package mock_backup_repository
+
+import (
+	reflect "reflect"
+
+	entity "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBackupRepositoryInterface is a mock of BackupRepositoryInterface interface.
+type MockBackupRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackupRepositoryInterfaceMockRecorder
+}
+
+// MockBackupRepositoryInterfaceMockRecorder is the mock recorder for MockBackupRepositoryInterface.
+type MockBackupRepositoryInterfaceMockRecorder struct {
+	mock *MockBackupRepositoryInterface
+}
+
+// NewMockBackupRepositoryInterface creates a new mock instance.
+func NewMockBackupRepositoryInterface(ctrl *gomock.Controller) *MockBackupRepositoryInterface {
+	mock := &MockBackupRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockBackupRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackupRepositoryInterface) EXPECT() *MockBackupRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockBackupRepositoryInterface) Get(fileName string) (entity.Backup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", fileName)
+	ret0, _ := ret[0].(entity.Backup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBackupRepositoryInterfaceMockRecorder) Get(fileName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBackupRepositoryInterface)(nil).Get), fileName)
+}
+
+// Save mocks base method.
+func (m *MockBackupRepositoryInterface) Save(fileName string, backup entity.Backup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", fileName, backup)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockBackupRepositoryInterfaceMockRecorder) Save(fileName, backup interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockBackupRepositoryInterface)(nil).Save), fileName, backup)
+}
@@ -0,0 +1,60 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/atomicfile"
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/filelock"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+var ErrManifestNotFound = errors.New("manifest not found")
+
+type ManifestRepository struct {
+	fileName string
+	// lockTimeout - сколько ждать, пока другой процесс закончит миграцию
+	lockTimeout time.Duration
+}
+
+func NewManifestRepository(fileName string, lockTimeout time.Duration) *ManifestRepository {
+	return &ManifestRepository{fileName: fileName, lockTimeout: lockTimeout}
+}
+
+func (r *ManifestRepository) Get() (entity.Manifest, error) {
+	content, err := os.ReadFile(r.fileName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return entity.Manifest{}, ErrManifestNotFound
+		}
+		return entity.Manifest{}, err
+	}
+
+	var manifest entity.Manifest
+	err = json.Unmarshal(content, &manifest)
+	if err != nil {
+		return entity.Manifest{}, fmt.Errorf("manifest file is corrupted: %v", err)
+	}
+	return manifest, nil
+}
+
+// Save манифест заменяется целиком: после сбоя остается версия последней завершенной миграции
+func (r *ManifestRepository) Save(manifest entity.Manifest) error {
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(r.fileName, content)
+}
+
+func (r *ManifestRepository) Lock(ctx context.Context) (func() error, error) {
+	fileLock, err := filelock.Acquire(ctx, r.fileName, true, r.lockTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return fileLock.Release, nil
+}
@@ -0,0 +1,16 @@
+package manifest
+
+import (
+	"context"
+
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+//go:generate mockgen -source=manifest_repository_interface.go -destination=mock_manifest_repository/mock_manifest_repository.go -package=mock_manifest_repository
+type ManifestRepositoryInterface interface {
+	// Get ErrManifestNotFound, если файлы хранилища записаны до появления манифеста или их еще нет
+	Get() (entity.Manifest, error)
+	Save(manifest entity.Manifest) error
+	// Lock эксклюзивная блокировка на время миграции: два процесса клиента не должны мигрировать файлы одновременно
+	Lock(ctx context.Context) (unlock func() error, err error)
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: manifest_repository_interface.go
+
+// Package mock_manifest_repository is a generated GoMock package.
+package mock_manifest_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockManifestRepositoryInterface is a mock of ManifestRepositoryInterface interface.
+type MockManifestRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockManifestRepositoryInterfaceMockRecorder
+}
+
+// MockManifestRepositoryInterfaceMockRecorder is the mock recorder for MockManifestRepositoryInterface.
+type MockManifestRepositoryInterfaceMockRecorder struct {
+	mock *MockManifestRepositoryInterface
+}
+
+// NewMockManifestRepositoryInterface creates a new mock instance.
+func NewMockManifestRepositoryInterface(ctrl *gomock.Controller) *MockManifestRepositoryInterface {
+	mock := &MockManifestRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockManifestRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockManifestRepositoryInterface) EXPECT() *MockManifestRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockManifestRepositoryInterface) Get() (entity.Manifest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get")
+	ret0, _ := ret[0].(entity.Manifest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockManifestRepositoryInterfaceMockRecorder) Get() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockManifestRepositoryInterface)(nil).Get))
+}
+
+// Lock mocks base method.
+func (m *MockManifestRepositoryInterface) Lock(ctx context.Context) (func() error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx)
+	ret0, _ := ret[0].(func() error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockManifestRepositoryInterfaceMockRecorder) Lock(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockManifestRepositoryInterface)(nil).Lock), ctx)
+}
+
+// Save mocks base method.
+func (m *MockManifestRepositoryInterface) Save(manifest entity.Manifest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", manifest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockManifestRepositoryInterfaceMockRecorder) Save(manifest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockManifestRepositoryInterface)(nil).Save), manifest)
+}
@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: rollback_repository_interface.go
+
+// Package mock_rollback_repository is a generated GoMock package.
+package mock_rollback_repository
+
+import (
+	reflect "reflect"
+
+	entity "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRollbackRepositoryInterface is a mock of RollbackRepositoryInterface interface.
+type MockRollbackRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRollbackRepositoryInterfaceMockRecorder
+}
+
+// MockRollbackRepositoryInterfaceMockRecorder is the mock recorder for MockRollbackRepositoryInterface.
+type MockRollbackRepositoryInterfaceMockRecorder struct {
+	mock *MockRollbackRepositoryInterface
+}
+
+// NewMockRollbackRepositoryInterface creates a new mock instance.
+func NewMockRollbackRepositoryInterface(ctrl *gomock.Controller) *MockRollbackRepositoryInterface {
+	mock := &MockRollbackRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRollbackRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRollbackRepositoryInterface) EXPECT() *MockRollbackRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockRollbackRepositoryInterface) Delete() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRollbackRepositoryInterfaceMockRecorder) Delete() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRollbackRepositoryInterface)(nil).Delete))
+}
+
+// Get mocks base method.
+func (m *MockRollbackRepositoryInterface) Get() (entity.RekeyRollback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get")
+	ret0, _ := ret[0].(entity.RekeyRollback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRollbackRepositoryInterfaceMockRecorder) Get() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRollbackRepositoryInterface)(nil).Get))
+}
+
+// Save mocks base method.
+func (m *MockRollbackRepositoryInterface) Save(rollback entity.RekeyRollback) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", rollback)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockRollbackRepositoryInterfaceMockRecorder) Save(rollback interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRollbackRepositoryInterface)(nil).Save), rollback)
+}
@@ -0,0 +1,55 @@
+package rollback
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/atomicfile"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+var ErrRollbackNotFound = errors.New("rollback not found")
+
+type RollbackRepository struct {
+	fileName string
+}
+
+func NewRollbackRepository(fileName string) *RollbackRepository {
+	return &RollbackRepository{fileName: fileName}
+}
+
+func (r *RollbackRepository) Get() (entity.RekeyRollback, error) {
+	content, err := os.ReadFile(r.fileName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return entity.RekeyRollback{}, ErrRollbackNotFound
+		}
+		return entity.RekeyRollback{}, err
+	}
+
+	var rollback entity.RekeyRollback
+	err = json.Unmarshal(content, &rollback)
+	if err != nil {
+		return entity.RekeyRollback{}, err
+	}
+	return rollback, nil
+}
+
+// Save файл должен гарантированно оказаться на диске до начала перезаписи хранилища
+func (r *RollbackRepository) Save(rollback entity.RekeyRollback) error {
+	content, err := json.Marshal(rollback)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(r.fileName, content)
+}
+
+func (r *RollbackRepository) Delete() error {
+	err := os.Remove(r.fileName)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package rollback
+
+import "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+
+//go:generate mockgen -source=rollback_repository_interface.go -destination=mock_rollback_repository/mock_rollback_repository.go -package=mock_rollback_repository
+type RollbackRepositoryInterface interface {
+	Get() (entity.RekeyRollback, error)
+	Save(rollback entity.RekeyRollback) error
+	Delete() error
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vault_repository_interface.go
+
+// Package mock_vault_repository is a generated GoMock package.
+package mock_vault_repository
+
+import (
+	reflect "reflect"
+
+	entity "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockVaultRepositoryInterface is a mock of VaultRepositoryInterface interface.
+type MockVaultRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockVaultRepositoryInterfaceMockRecorder
+}
+
+// MockVaultRepositoryInterfaceMockRecorder is the mock recorder for MockVaultRepositoryInterface.
+type MockVaultRepositoryInterfaceMockRecorder struct {
+	mock *MockVaultRepositoryInterface
+}
+
+// NewMockVaultRepositoryInterface creates a new mock instance.
+func NewMockVaultRepositoryInterface(ctrl *gomock.Controller) *MockVaultRepositoryInterface {
+	mock := &MockVaultRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockVaultRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVaultRepositoryInterface) EXPECT() *MockVaultRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockVaultRepositoryInterface) Get() (entity.Vault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get")
+	ret0, _ := ret[0].(entity.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockVaultRepositoryInterfaceMockRecorder) Get() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockVaultRepositoryInterface)(nil).Get))
+}
+
+// Save mocks base method.
+func (m *MockVaultRepositoryInterface) Save(vault entity.Vault) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", vault)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockVaultRepositoryInterfaceMockRecorder) Save(vault interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockVaultRepositoryInterface)(nil).Save), vault)
+}
@@ -0,0 +1,51 @@
+package vault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/anoriar/gophkeeper/internal/client/shared/services/atomicfile"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+var ErrVaultNotFound = errors.New("vault not found")
+
+type VaultRepository struct {
+	fileName string
+}
+
+func NewVaultRepository(fileName string) (*VaultRepository, error) {
+	err := os.MkdirAll(filepath.Dir(fileName), 0700)
+	if err != nil {
+		return nil, err
+	}
+	return &VaultRepository{fileName: fileName}, nil
+}
+
+func (r *VaultRepository) Get() (entity.Vault, error) {
+	content, err := os.ReadFile(r.fileName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return entity.Vault{}, ErrVaultNotFound
+		}
+		return entity.Vault{}, err
+	}
+
+	var vault entity.Vault
+	err = json.Unmarshal(content, &vault)
+	if err != nil {
+		return entity.Vault{}, fmt.Errorf("vault file is corrupted: %v", err)
+	}
+	return vault, nil
+}
+
+func (r *VaultRepository) Save(vault entity.Vault) error {
+	content, err := json.Marshal(vault)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(r.fileName, content)
+}
@@ -0,0 +1,9 @@
+package vault
+
+import "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+
+//go:generate mockgen -source=vault_repository_interface.go -destination=mock_vault_repository/mock_vault_repository.go -package=mock_vault_repository
+type VaultRepositoryInterface interface {
+	Get() (entity.Vault, error)
+	Save(vault entity.Vault) error
+}
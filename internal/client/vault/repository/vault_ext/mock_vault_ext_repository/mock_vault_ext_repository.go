@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vault_ext_repository_interface.go
+
+// Package mock_vault_ext_repository is a generated GoMock package.
+package mock_vault_ext_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockVaultExtRepositoryInterface is a mock of VaultExtRepositoryInterface interface.
+type MockVaultExtRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockVaultExtRepositoryInterfaceMockRecorder
+}
+
+// MockVaultExtRepositoryInterfaceMockRecorder is the mock recorder for MockVaultExtRepositoryInterface.
+type MockVaultExtRepositoryInterfaceMockRecorder struct {
+	mock *MockVaultExtRepositoryInterface
+}
+
+// NewMockVaultExtRepositoryInterface creates a new mock instance.
+func NewMockVaultExtRepositoryInterface(ctrl *gomock.Controller) *MockVaultExtRepositoryInterface {
+	mock := &MockVaultExtRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockVaultExtRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVaultExtRepositoryInterface) EXPECT() *MockVaultExtRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockVaultExtRepositoryInterface) Get(ctx context.Context, token string) (entity.Vault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, token)
+	ret0, _ := ret[0].(entity.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockVaultExtRepositoryInterfaceMockRecorder) Get(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockVaultExtRepositoryInterface)(nil).Get), ctx, token)
+}
+
+// Save mocks base method.
+func (m *MockVaultExtRepositoryInterface) Save(ctx context.Context, token string, vault entity.Vault) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, token, vault)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockVaultExtRepositoryInterfaceMockRecorder) Save(ctx, token, vault interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockVaultExtRepositoryInterface)(nil).Save), ctx, token, vault)
+}
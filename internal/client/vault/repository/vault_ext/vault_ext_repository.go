@@ -0,0 +1,79 @@
+package vault_ext
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+
+	sharedErr "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+// ErrRemoteVaultNotFound слоты ключей на сервер еще не отправлялись или сервер не поддерживает их хранение
+var ErrRemoteVaultNotFound = errors.New("remote vault not found")
+
+type VaultExtRepository struct {
+	client *resty.Client
+}
+
+func NewVaultExtRepository(client *resty.Client) *VaultExtRepository {
+	return &VaultExtRepository{client: client}
+}
+
+func (r *VaultExtRepository) Get(ctx context.Context, token string) (entity.Vault, error) {
+	resp, err := r.client.R().
+		SetContext(ctx).
+		SetHeader("Authorization", token).
+		Get("/api/vault")
+	if err != nil {
+		return entity.Vault{}, fmt.Errorf("%w: %v", sharedErr.ErrServerUnreachable, err)
+	}
+
+	switch resp.StatusCode() {
+	case http.StatusOK:
+		var vault entity.Vault
+		err = json.Unmarshal(resp.Body(), &vault)
+		if err != nil {
+			return entity.Vault{}, fmt.Errorf("%w: %v", sharedErr.ErrDependencyFailure, err)
+		}
+		return vault, nil
+	case http.StatusNotFound:
+		return entity.Vault{}, ErrRemoteVaultNotFound
+	case http.StatusUnauthorized:
+		return entity.Vault{}, fmt.Errorf("%w: %v", sharedErr.ErrNotLoggedIn, resp.Body())
+	default:
+		return entity.Vault{}, fmt.Errorf("%w: %v", sharedErr.ErrDependencyFailure, resp.Body())
+	}
+}
+
+func (r *VaultExtRepository) Save(ctx context.Context, token string, vault entity.Vault) error {
+	body, err := json.Marshal(vault)
+	if err != nil {
+		return fmt.Errorf("%w: %v", sharedErr.ErrInternalError, err)
+	}
+
+	resp, err := r.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Authorization", token).
+		SetBody(body).
+		Put("/api/vault")
+	if err != nil {
+		return fmt.Errorf("%w: %v", sharedErr.ErrServerUnreachable, err)
+	}
+
+	switch resp.StatusCode() {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return ErrRemoteVaultNotFound
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w: %v", sharedErr.ErrNotLoggedIn, resp.Body())
+	default:
+		return fmt.Errorf("%w: %v", sharedErr.ErrDependencyFailure, resp.Body())
+	}
+}
@@ -0,0 +1,14 @@
+package vault_ext
+
+import (
+	"context"
+
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+//go:generate mockgen -source=vault_ext_repository_interface.go -destination=mock_vault_ext_repository/mock_vault_ext_repository.go -package=mock_vault_ext_repository
+type VaultExtRepositoryInterface interface {
+	// Get слоты ключей хранилища с сервера. ErrRemoteVaultNotFound, если их там еще нет
+	Get(ctx context.Context, token string) (entity.Vault, error)
+	Save(ctx context.Context, token string, vault entity.Vault) error
+}
@@ -0,0 +1,67 @@
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+const backupLabel = "gophkeeper backup"
+
+var errInvalidNonce = errors.New("invalid backup nonce")
+
+// sealBackup шифрует payload ключом слота (AES-256-GCM). Заголовок копии аутентифицируется:
+// подмена версии формата или солей в заголовке обнаруживается при восстановлении
+func sealBackup(key []byte, backup *entity.Backup, payload []byte) error {
+	aead, err := newBackupAead(key)
+	if err != nil {
+		return err
+	}
+	backup.Nonce = make([]byte, aead.NonceSize())
+	_, err = rand.Read(backup.Nonce)
+	if err != nil {
+		return err
+	}
+	associatedData, err := backupAssociatedData(*backup)
+	if err != nil {
+		return err
+	}
+	backup.Data = aead.Seal(nil, backup.Nonce, payload, associatedData)
+	return nil
+}
+
+func openBackup(key []byte, backup entity.Backup) ([]byte, error) {
+	aead, err := newBackupAead(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(backup.Nonce) != aead.NonceSize() {
+		return nil, errInvalidNonce
+	}
+	associatedData, err := backupAssociatedData(backup)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, backup.Nonce, backup.Data, associatedData)
+}
+
+func backupAssociatedData(backup entity.Backup) ([]byte, error) {
+	backup.Data = nil
+	header, err := json.Marshal(backup)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(backupLabel), header...), nil
+}
+
+func newBackupAead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
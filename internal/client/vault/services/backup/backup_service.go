@@ -0,0 +1,312 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	entryEntity "github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	entryRepository "github.com/anoriar/gophkeeper/internal/client/entry/repository/entry"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret"
+	"github.com/anoriar/gophkeeper/internal/client/vault/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/vault/dto/command_response"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	vaultErrors "github.com/anoriar/gophkeeper/internal/client/vault/errors"
+	backupRepository "github.com/anoriar/gophkeeper/internal/client/vault/repository/backup"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/rollback"
+	vaultRepository "github.com/anoriar/gophkeeper/internal/client/vault/repository/vault"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/kdf"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/keyring"
+)
+
+type BackupService struct {
+	entryRepositories map[enum.EntryType]entryRepository.EntryRepositoryInterface
+	// entryStore - хранилище записей всех типов, при восстановлении очищается вместе с ревизиями
+	entryStore         entryRepository.EntryStoreInterface
+	vaultRepository    vaultRepository.VaultRepositoryInterface
+	rollbackRepository rollback.RollbackRepositoryInterface
+	backupRepository   backupRepository.BackupRepositoryInterface
+	secretRepository   secret.SecretRepositoryInterface
+	keyringService     keyring.KeyringServiceInterface
+	keyDeriver         *kdf.Argon2idKeyDeriver
+	now                func() time.Time
+	logger             *zap.Logger
+}
+
+func NewBackupService(
+	entryRepositories map[enum.EntryType]entryRepository.EntryRepositoryInterface,
+	entryStore entryRepository.EntryStoreInterface,
+	vaultRepository vaultRepository.VaultRepositoryInterface,
+	rollbackRepository rollback.RollbackRepositoryInterface,
+	backupRepository backupRepository.BackupRepositoryInterface,
+	secretRepository secret.SecretRepositoryInterface,
+	keyringService keyring.KeyringServiceInterface,
+	logger *zap.Logger,
+) *BackupService {
+	return &BackupService{
+		entryRepositories:  entryRepositories,
+		entryStore:         entryStore,
+		vaultRepository:    vaultRepository,
+		rollbackRepository: rollbackRepository,
+		backupRepository:   backupRepository,
+		secretRepository:   secretRepository,
+		keyringService:     keyringService,
+		keyDeriver:         kdf.NewArgon2idKeyDeriver(),
+		now:                time.Now,
+		logger:             logger,
+	}
+}
+
+func (s *BackupService) Backup(ctx context.Context, command command.BackupCommand) (command_response.BackupResponse, error) {
+	keyring, err := s.secretRepository.GetKeyring()
+	if err != nil {
+		if errors.Is(err, secret.ErrVaultLocked) {
+			return command_response.BackupResponse{}, err
+		}
+		s.logger.Error("get keyring error", zap.String("error", err.Error()))
+		return command_response.BackupResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	vault, err := s.vaultRepository.Get()
+	if err != nil {
+		s.logger.Error("get vault error", zap.String("error", err.Error()))
+		return command_response.BackupResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	// копия шифруется ключом слота, из которого ее потом можно получить по мастер-паролю
+	key := keyring.CurrentKey()
+	if key == nil || vault.CurrentKeySlot() == nil || key.Id != vault.CurrentKeyId {
+		s.logger.Error("keyring does not match vault", zap.String("keyId", keyring.CurrentKeyId), zap.String("vaultKeyId", vault.CurrentKeyId))
+		return command_response.BackupResponse{}, fmt.Errorf("%w: keyring does not match vault, login again", sharedErrors.ErrInternalError)
+	}
+
+	// записи разных типов должны попасть в копию в одном состоянии
+	unlock, err := s.lockEntryRepositories(ctx)
+	if err != nil {
+		return command_response.BackupResponse{}, err
+	}
+	defer unlock()
+
+	payload := entity.BackupPayload{
+		FormatVersion: entity.BackupFormatVersion,
+		Entries:       make(map[enum.EntryType][]entryEntity.Entry, len(s.entryRepositories)),
+	}
+	entriesCount := 0
+	for _, entryType := range enum.AllEntryTypes {
+		repository, ok := s.entryRepositories[entryType]
+		if !ok {
+			continue
+		}
+		entries, err := repository.GetList(ctx)
+		if err != nil {
+			if errors.Is(err, sharedErrors.ErrVaultTampered) {
+				return command_response.BackupResponse{}, err
+			}
+			s.logger.Error("get entries error", zap.String("error", err.Error()))
+			return command_response.BackupResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+		}
+		payload.Entries[entryType] = entries
+		entriesCount += len(entries)
+	}
+	payloadContent, err := json.Marshal(payload)
+	if err != nil {
+		s.logger.Error("marshal backup error", zap.String("error", err.Error()))
+		return command_response.BackupResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+
+	backup := entity.Backup{
+		Format:        entity.BackupFormat,
+		FormatVersion: entity.BackupFormatVersion,
+		CreatedAt:     s.now().UTC(),
+		Vault:         vault,
+	}
+	err = sealBackup(key.Key, &backup, payloadContent)
+	if err != nil {
+		s.logger.Error("encrypt backup error", zap.String("error", err.Error()))
+		return command_response.BackupResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	err = s.backupRepository.Save(command.FileName, backup)
+	if err != nil {
+		s.logger.Error("save backup error", zap.String("error", err.Error()))
+		return command_response.BackupResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+
+	return command_response.BackupResponse{
+		FileName:      command.FileName,
+		FormatVersion: backup.FormatVersion,
+		CreatedAt:     backup.CreatedAt,
+		EntriesCount:  entriesCount,
+	}, nil
+}
+
+func (s *BackupService) Restore(ctx context.Context, command command.RestoreBackupCommand) (command_response.BackupResponse, error) {
+	backup, err := s.backupRepository.Get(command.FileName)
+	if err != nil {
+		if errors.Is(err, vaultErrors.ErrBackupNotValid) || errors.Is(err, os.ErrNotExist) {
+			return command_response.BackupResponse{}, err
+		}
+		s.logger.Error("get backup error", zap.String("error", err.Error()))
+		return command_response.BackupResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+
+	// копия проверяется целиком до того, как будет изменен хоть один локальный файл
+	payload, err := s.openPayload(backup, command.MasterPassword)
+	if err != nil {
+		return command_response.BackupResponse{}, err
+	}
+
+	// другие процессы клиента не должны писать записи, пока хранилище заменяется
+	unlock, err := s.lockEntryRepositories(ctx)
+	if err != nil {
+		return command_response.BackupResponse{}, err
+	}
+	defer unlock()
+
+	err = s.apply(ctx, backup.Vault, payload, command.MasterPassword)
+	if err != nil {
+		s.logger.Error("restore backup error", zap.String("error", err.Error()))
+		return command_response.BackupResponse{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+
+	entriesCount := 0
+	for _, entries := range payload.Entries {
+		entriesCount += len(entries)
+	}
+	return command_response.BackupResponse{
+		FileName:      command.FileName,
+		FormatVersion: backup.FormatVersion,
+		CreatedAt:     backup.CreatedAt,
+		EntriesCount:  entriesCount,
+	}, nil
+}
+
+// openPayload проверяет формат копии и мастер-пароль и расшифровывает записи
+func (s *BackupService) openPayload(backup entity.Backup, masterPassword string) (entity.BackupPayload, error) {
+	if backup.Format != entity.BackupFormat {
+		return entity.BackupPayload{}, vaultErrors.ErrBackupNotValid
+	}
+	if backup.FormatVersion < 1 || backup.FormatVersion > entity.BackupFormatVersion {
+		return entity.BackupPayload{}, fmt.Errorf("%w: version %d", vaultErrors.ErrBackupVersionNotSupported, backup.FormatVersion)
+	}
+	slot := backup.Vault.CurrentKeySlot()
+	if slot == nil {
+		return entity.BackupPayload{}, vaultErrors.ErrBackupNotValid
+	}
+	err := slot.Kdf.Validate()
+	if err != nil {
+		return entity.BackupPayload{}, fmt.Errorf("%w: %w", vaultErrors.ErrBackupNotValid, err)
+	}
+
+	key := s.keyDeriver.DeriveKey(masterPassword, slot.Salt, slot.Kdf)
+	if slot.KeyCheck != nil && !kdf.VerifyKeyCheck(key, slot.KeyCheck) {
+		return entity.BackupPayload{}, sharedErrors.ErrWrongMasterPassword
+	}
+	payloadContent, err := openBackup(key, backup)
+	if err != nil {
+		// у слотов без KeyCheck неверный мастер-пароль не отличить от поврежденной копии
+		if slot.KeyCheck == nil {
+			return entity.BackupPayload{}, fmt.Errorf("%w, check master password", vaultErrors.ErrBackupNotValid)
+		}
+		return entity.BackupPayload{}, vaultErrors.ErrBackupNotValid
+	}
+
+	var payload entity.BackupPayload
+	err = json.Unmarshal(payloadContent, &payload)
+	if err != nil || payload.FormatVersion != backup.FormatVersion {
+		return entity.BackupPayload{}, vaultErrors.ErrBackupNotValid
+	}
+	for entryType, entries := range payload.Entries {
+		if !slices.Contains(enum.AllEntryTypes, entryType) {
+			return entity.BackupPayload{}, fmt.Errorf("%w: unknown entry type %q", vaultErrors.ErrBackupNotValid, entryType)
+		}
+		for _, entry := range entries {
+			if entry.EntryType != entryType {
+				return entity.BackupPayload{}, fmt.Errorf("%w: entry %s has type %q", vaultErrors.ErrBackupNotValid, entry.Id, entry.EntryType)
+			}
+		}
+	}
+	return payload, nil
+}
+
+// apply заменяет хранилище копией. Записи пишутся в новый файл хранилища, запечатанный ключом из метаданных копии,
+// поэтому сначала сохраняется и разблокируется vault.json копии. Пока файл хранилища не заменен, ошибка возвращает
+// прежний vault.json: хранилище, ключи в сессии и незавершенный откат rekey остаются такими, как до restore
+func (s *BackupService) apply(ctx context.Context, vault entity.Vault, payload entity.BackupPayload, masterPassword string) error {
+	previousVault, err := s.vaultRepository.Get()
+	hasPreviousVault := err == nil
+	if err != nil && !errors.Is(err, vaultRepository.ErrVaultNotFound) {
+		return fmt.Errorf("get vault error: %w", err)
+	}
+	err = s.vaultRepository.Save(vault)
+	if err != nil {
+		return fmt.Errorf("save vault error: %w", err)
+	}
+
+	keyring, err := s.keyringService.Unlock(masterPassword)
+	if err != nil {
+		err = fmt.Errorf("unlock vault error: %w", err)
+	} else {
+		err = s.entryStore.Replace(payload.Entries)
+		if err != nil {
+			err = fmt.Errorf("replace entry store error: %w", err)
+		}
+	}
+	if err != nil {
+		// ключи копии не должны запечатывать прежнее хранилище
+		s.keyringService.Lock()
+		if hasPreviousVault {
+			rollbackErr := s.vaultRepository.Save(previousVault)
+			if rollbackErr != nil {
+				s.logger.Error("restore previous vault error", zap.String("error", rollbackErr.Error()))
+			}
+		}
+		return err
+	}
+
+	// откат прерванного rekey вернул бы поверх копии прежние записи
+	err = s.rollbackRepository.Delete()
+	if err != nil {
+		return fmt.Errorf("delete rollback error: %w", err)
+	}
+	// ключи прежнего хранилища к восстановленному не подходят
+	err = s.secretRepository.SaveKeyring(keyring)
+	if err != nil {
+		return fmt.Errorf("save keyring error: %w", err)
+	}
+	return nil
+}
+
+func (s *BackupService) lockEntryRepositories(ctx context.Context) (func(), error) {
+	unlocks := make([]func() error, 0, len(s.entryRepositories))
+	unlockAll := func() {
+		for _, unlock := range unlocks {
+			err := unlock()
+			if err != nil {
+				s.logger.Error("unlock entries error", zap.String("error", err.Error()))
+			}
+		}
+	}
+	for _, entryType := range enum.AllEntryTypes {
+		repository, ok := s.entryRepositories[entryType]
+		if !ok {
+			continue
+		}
+		unlock, err := repository.Lock(ctx)
+		if err != nil {
+			unlockAll()
+			if errors.Is(err, sharedErrors.ErrVaultBusy) {
+				return nil, err
+			}
+			s.logger.Error("lock entries error", zap.String("error", err.Error()))
+			return nil, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+		}
+		unlocks = append(unlocks, unlock)
+	}
+	return unlockAll, nil
+}
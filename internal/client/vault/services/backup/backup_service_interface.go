@@ -0,0 +1,17 @@
+package backup
+
+import (
+	"context"
+
+	"github.com/anoriar/gophkeeper/internal/client/vault/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/vault/dto/command_response"
+)
+
+//go:generate mockgen -source=backup_service_interface.go -destination=mock_backup_service/mock_backup_service.go -package=mock_backup_service
+type BackupServiceInterface interface {
+	// Backup сохраняет записи всех типов и метаданные хранилища в зашифрованный файл. Хранилище должно быть разблокировано
+	Backup(ctx context.Context, command command.BackupCommand) (command_response.BackupResponse, error)
+	// Restore заменяет локальное хранилище копией после проверки мастер-пароля, версии формата и целостности копии.
+	// После восстановления хранилище разблокировано
+	Restore(ctx context.Context, command command.RestoreBackupCommand) (command_response.BackupResponse, error)
+}
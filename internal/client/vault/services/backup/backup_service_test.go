@@ -0,0 +1,355 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	entryEntity "github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	entryRepository "github.com/anoriar/gophkeeper/internal/client/entry/repository/entry"
+	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry/mock_entry_repository"
+	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry/mock_entry_store"
+	"github.com/anoriar/gophkeeper/internal/client/shared/app/logger"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret"
+	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret/mock_secret_repository"
+	"github.com/anoriar/gophkeeper/internal/client/vault/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/vault/dto/command_response"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	vaultErrors "github.com/anoriar/gophkeeper/internal/client/vault/errors"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/backup/mock_backup_repository"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/rollback/mock_rollback_repository"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/vault/mock_vault_repository"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/kdf"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/keyring/mock_keyring_service"
+)
+
+const testMasterPassword = "master"
+
+var (
+	testBackupNow = time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+	// testKdfParams минимальные параметры Argon2id, чтобы тесты не тратили 64 MiB на каждый ключ
+	testKdfParams = entity.KdfParams{Time: 1, Memory: 64, Threads: 1}
+)
+
+// newTestVault хранилище с одним слотом и ключ этого слота, полученный из testMasterPassword
+func newTestVault(t *testing.T) (entity.Vault, []byte) {
+	salt := []byte("0123456789abcdef")
+	key := kdf.NewArgon2idKeyDeriver().DeriveKey(testMasterPassword, salt, testKdfParams)
+	storeKey, err := kdf.WrapStoreKey(key, "0102030405060708", []byte("0123456789abcdef0123456789abcdef"), kdf.StoreFlags{})
+	require.NoError(t, err)
+	return entity.Vault{
+		CurrentKeyId: "0102030405060708",
+		KeySlots: []entity.KeySlot{{
+			Id:        "0102030405060708",
+			Salt:      salt,
+			Kdf:       testKdfParams,
+			KeyCheck:  kdf.KeyCheck(key),
+			StoreKey:  storeKey,
+			CreatedAt: testBackupNow,
+		}},
+	}, key
+}
+
+func newTestBackup(t *testing.T, vault entity.Vault, key []byte, payload entity.BackupPayload) entity.Backup {
+	payloadContent, err := json.Marshal(payload)
+	require.NoError(t, err)
+	backup := entity.Backup{
+		Format:        entity.BackupFormat,
+		FormatVersion: entity.BackupFormatVersion,
+		CreatedAt:     testBackupNow,
+		Vault:         vault,
+	}
+	require.NoError(t, sealBackup(key, &backup, payloadContent))
+	return backup
+}
+
+func TestBackupService_Backup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	entryStoreMock := mock_entry_store.NewMockEntryStoreInterface(ctrl)
+	vaultRepositoryMock := mock_vault_repository.NewMockVaultRepositoryInterface(ctrl)
+	rollbackRepositoryMock := mock_rollback_repository.NewMockRollbackRepositoryInterface(ctrl)
+	backupRepositoryMock := mock_backup_repository.NewMockBackupRepositoryInterface(ctrl)
+	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
+	keyringServiceMock := mock_keyring_service.NewMockKeyringServiceInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	vault, key := newTestVault(t)
+	keyring := entity.Keyring{
+		CurrentKeyId: vault.CurrentKeyId,
+		Keys:         []entity.VaultKey{{Id: vault.CurrentKeyId, Kdf: testKdfParams, Key: key, Verified: true}},
+	}
+	entries := []entryEntity.Entry{{Id: "1", EntryType: enum.Login, Data: []byte("encrypted")}}
+	cmd := command.BackupCommand{FileName: "vault.gkbak"}
+
+	tests := []struct {
+		name          string
+		mockBehaviour func()
+		want          command_response.BackupResponse
+		wantErr       error
+	}{
+		{
+			name: "success",
+			mockBehaviour: func() {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(keyring, nil)
+				vaultRepositoryMock.EXPECT().Get().Return(vault, nil)
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
+				entryRepositoryMock.EXPECT().GetList(gomock.Any()).Return(entries, nil)
+				backupRepositoryMock.EXPECT().Save("vault.gkbak", gomock.Any()).DoAndReturn(func(fileName string, backup entity.Backup) error {
+					assert.Equal(t, vault, backup.Vault)
+					assert.NotContains(t, string(backup.Data), "encrypted")
+
+					// копия расшифровывается ключом из мастер-пароля, а заголовок аутентифицирован
+					payloadContent, err := openBackup(key, backup)
+					require.NoError(t, err)
+					var payload entity.BackupPayload
+					require.NoError(t, json.Unmarshal(payloadContent, &payload))
+					assert.Equal(t, entity.BackupPayload{
+						FormatVersion: entity.BackupFormatVersion,
+						Entries:       map[enum.EntryType][]entryEntity.Entry{enum.Login: entries},
+					}, payload)
+
+					backup.FormatVersion++
+					_, err = openBackup(key, backup)
+					assert.Error(t, err)
+					return nil
+				})
+			},
+			want: command_response.BackupResponse{
+				FileName:      "vault.gkbak",
+				FormatVersion: entity.BackupFormatVersion,
+				CreatedAt:     testBackupNow,
+				EntriesCount:  1,
+			},
+		},
+		{
+			name: "vault locked error",
+			mockBehaviour: func() {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(entity.Keyring{}, secret.ErrVaultLocked)
+			},
+			wantErr: secret.ErrVaultLocked,
+		},
+		{
+			name: "keyring does not match vault error",
+			mockBehaviour: func() {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(entity.Keyring{CurrentKeyId: "0807060504030201"}, nil)
+				vaultRepositoryMock.EXPECT().Get().Return(vault, nil)
+			},
+			wantErr: sharedErrors.ErrInternalError,
+		},
+		{
+			name: "vault busy error",
+			mockBehaviour: func() {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(keyring, nil)
+				vaultRepositoryMock.EXPECT().Get().Return(vault, nil)
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(nil, sharedErrors.ErrVaultBusy)
+			},
+			wantErr: sharedErrors.ErrVaultBusy,
+		},
+		{
+			name: "save backup internal error",
+			mockBehaviour: func() {
+				secretRepositoryMock.EXPECT().GetKeyring().Return(keyring, nil)
+				vaultRepositoryMock.EXPECT().Get().Return(vault, nil)
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
+				entryRepositoryMock.EXPECT().GetList(gomock.Any()).Return(entries, nil)
+				backupRepositoryMock.EXPECT().Save("vault.gkbak", gomock.Any()).Return(errors.New("error"))
+			},
+			wantErr: sharedErrors.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour()
+			s := NewBackupService(
+				map[enum.EntryType]entryRepository.EntryRepositoryInterface{enum.Login: entryRepositoryMock},
+				entryStoreMock,
+				vaultRepositoryMock,
+				rollbackRepositoryMock,
+				backupRepositoryMock,
+				secretRepositoryMock,
+				keyringServiceMock,
+				loggerMock,
+			)
+			s.now = func() time.Time { return testBackupNow }
+			got, err := s.Backup(context.Background(), cmd)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBackupService_Restore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	entryStoreMock := mock_entry_store.NewMockEntryStoreInterface(ctrl)
+	vaultRepositoryMock := mock_vault_repository.NewMockVaultRepositoryInterface(ctrl)
+	rollbackRepositoryMock := mock_rollback_repository.NewMockRollbackRepositoryInterface(ctrl)
+	backupRepositoryMock := mock_backup_repository.NewMockBackupRepositoryInterface(ctrl)
+	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
+	keyringServiceMock := mock_keyring_service.NewMockKeyringServiceInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	vault, key := newTestVault(t)
+	keyring := entity.Keyring{CurrentKeyId: vault.CurrentKeyId, StoreKey: []byte("store key")}
+	previousVault := entity.Vault{CurrentKeyId: "0807060504030201"}
+	entries := []entryEntity.Entry{{Id: "1", EntryType: enum.Login, Data: []byte("encrypted")}}
+	backup := newTestBackup(t, vault, key, entity.BackupPayload{
+		FormatVersion: entity.BackupFormatVersion,
+		Entries:       map[enum.EntryType][]entryEntity.Entry{enum.Login: entries},
+	})
+
+	tamperedBackup := backup
+	tamperedBackup.CreatedAt = testBackupNow.Add(time.Hour)
+	newerBackup := backup
+	newerBackup.FormatVersion = entity.BackupFormatVersion + 1
+	// огромный Memory в заголовке копии занял бы всю память еще до проверки мастер-пароля
+	hugeKdfBackup := backup
+	hugeKdfBackup.Vault = entity.Vault{CurrentKeyId: vault.CurrentKeyId, KeySlots: []entity.KeySlot{vault.KeySlots[0]}}
+	hugeKdfBackup.Vault.KeySlots[0].Kdf.Memory = math.MaxUint32
+	wrongTypeBackup := newTestBackup(t, vault, key, entity.BackupPayload{
+		FormatVersion: entity.BackupFormatVersion,
+		Entries:       map[enum.EntryType][]entryEntity.Entry{enum.Card: entries},
+	})
+
+	tests := []struct {
+		name           string
+		masterPassword string
+		mockBehaviour  func()
+		want           command_response.BackupResponse
+		wantErr        error
+	}{
+		{
+			name:           "success",
+			masterPassword: testMasterPassword,
+			mockBehaviour: func() {
+				backupRepositoryMock.EXPECT().Get("vault.gkbak").Return(backup, nil)
+				gomock.InOrder(
+					entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil),
+					vaultRepositoryMock.EXPECT().Get().Return(previousVault, nil),
+					vaultRepositoryMock.EXPECT().Save(vault).Return(nil),
+					keyringServiceMock.EXPECT().Unlock(testMasterPassword).Return(keyring, nil),
+					entryStoreMock.EXPECT().Replace(map[enum.EntryType][]entryEntity.Entry{enum.Login: entries}).Return(nil),
+					rollbackRepositoryMock.EXPECT().Delete().Return(nil),
+					secretRepositoryMock.EXPECT().SaveKeyring(keyring).Return(nil),
+				)
+			},
+			want: command_response.BackupResponse{
+				FileName:      "vault.gkbak",
+				FormatVersion: entity.BackupFormatVersion,
+				CreatedAt:     testBackupNow,
+				EntriesCount:  1,
+			},
+		},
+		{
+			name:           "wrong master password error",
+			masterPassword: "wrong",
+			mockBehaviour: func() {
+				backupRepositoryMock.EXPECT().Get("vault.gkbak").Return(backup, nil)
+			},
+			wantErr: sharedErrors.ErrWrongMasterPassword,
+		},
+		{
+			name:           "tampered header error",
+			masterPassword: testMasterPassword,
+			mockBehaviour: func() {
+				backupRepositoryMock.EXPECT().Get("vault.gkbak").Return(tamperedBackup, nil)
+			},
+			wantErr: vaultErrors.ErrBackupNotValid,
+		},
+		{
+			name:           "newer format version error",
+			masterPassword: testMasterPassword,
+			mockBehaviour: func() {
+				backupRepositoryMock.EXPECT().Get("vault.gkbak").Return(newerBackup, nil)
+			},
+			wantErr: vaultErrors.ErrBackupVersionNotSupported,
+		},
+		{
+			name:           "invalid kdf params error",
+			masterPassword: testMasterPassword,
+			mockBehaviour: func() {
+				backupRepositoryMock.EXPECT().Get("vault.gkbak").Return(hugeKdfBackup, nil)
+			},
+			wantErr: vaultErrors.ErrKdfParamsNotValid,
+		},
+		{
+			name:           "entry type mismatch error",
+			masterPassword: testMasterPassword,
+			mockBehaviour: func() {
+				backupRepositoryMock.EXPECT().Get("vault.gkbak").Return(wrongTypeBackup, nil)
+			},
+			wantErr: vaultErrors.ErrBackupNotValid,
+		},
+		{
+			name:           "vault busy error",
+			masterPassword: testMasterPassword,
+			mockBehaviour: func() {
+				backupRepositoryMock.EXPECT().Get("vault.gkbak").Return(backup, nil)
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(nil, sharedErrors.ErrVaultBusy)
+			},
+			wantErr: sharedErrors.ErrVaultBusy,
+		},
+		{
+			name:           "replace store error keeps previous vault",
+			masterPassword: testMasterPassword,
+			mockBehaviour: func() {
+				backupRepositoryMock.EXPECT().Get("vault.gkbak").Return(backup, nil)
+				gomock.InOrder(
+					entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil),
+					vaultRepositoryMock.EXPECT().Get().Return(previousVault, nil),
+					vaultRepositoryMock.EXPECT().Save(vault).Return(nil),
+					keyringServiceMock.EXPECT().Unlock(testMasterPassword).Return(keyring, nil),
+					entryStoreMock.EXPECT().Replace(gomock.Any()).Return(errors.New("disk error")),
+					keyringServiceMock.EXPECT().Lock(),
+					vaultRepositoryMock.EXPECT().Save(previousVault).Return(nil),
+				)
+				// откат rekey и ключи в сессии не трогаются
+				rollbackRepositoryMock.EXPECT().Delete().Times(0)
+				secretRepositoryMock.EXPECT().SaveKeyring(gomock.Any()).Times(0)
+			},
+			wantErr: sharedErrors.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour()
+			s := NewBackupService(
+				map[enum.EntryType]entryRepository.EntryRepositoryInterface{enum.Login: entryRepositoryMock},
+				entryStoreMock,
+				vaultRepositoryMock,
+				rollbackRepositoryMock,
+				backupRepositoryMock,
+				secretRepositoryMock,
+				keyringServiceMock,
+				loggerMock,
+			)
+			got, err := s.Restore(context.Background(), command.RestoreBackupCommand{FileName: "vault.gkbak", MasterPassword: tt.masterPassword})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: backup_service_interface.go
+
+// Package mock_backup_service is a generated GoMock package.
+package mock_backup_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	command "github.com/anoriar/gophkeeper/internal/client/vault/dto/command"
+	command_response "github.com/anoriar/gophkeeper/internal/client/vault/dto/command_response"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBackupServiceInterface is a mock of BackupServiceInterface interface.
+type MockBackupServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackupServiceInterfaceMockRecorder
+}
+
+// MockBackupServiceInterfaceMockRecorder is the mock recorder for MockBackupServiceInterface.
+type MockBackupServiceInterfaceMockRecorder struct {
+	mock *MockBackupServiceInterface
+}
+
+// NewMockBackupServiceInterface creates a new mock instance.
+func NewMockBackupServiceInterface(ctrl *gomock.Controller) *MockBackupServiceInterface {
+	mock := &MockBackupServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockBackupServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackupServiceInterface) EXPECT() *MockBackupServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Backup mocks base method.
+func (m *MockBackupServiceInterface) Backup(ctx context.Context, command command.BackupCommand) (command_response.BackupResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backup", ctx, command)
+	ret0, _ := ret[0].(command_response.BackupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Backup indicates an expected call of Backup.
+func (mr *MockBackupServiceInterfaceMockRecorder) Backup(ctx, command interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backup", reflect.TypeOf((*MockBackupServiceInterface)(nil).Backup), ctx, command)
+}
+
+// Restore mocks base method.
+func (m *MockBackupServiceInterface) Restore(ctx context.Context, command command.RestoreBackupCommand) (command_response.BackupResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, command)
+	ret0, _ := ret[0].(command_response.BackupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockBackupServiceInterfaceMockRecorder) Restore(ctx, command interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockBackupServiceInterface)(nil).Restore), ctx, command)
+}
@@ -0,0 +1,24 @@
+package kdf
+
+import (
+	"golang.org/x/crypto/argon2"
+
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+const (
+	KeySize  = 32
+	SaltSize = 16
+)
+
+type Argon2idKeyDeriver struct {
+}
+
+func NewArgon2idKeyDeriver() *Argon2idKeyDeriver {
+	return &Argon2idKeyDeriver{}
+}
+
+// DeriveKey получение ключа шифрования из мастер-пароля
+func (d *Argon2idKeyDeriver) DeriveKey(masterPass string, salt []byte, params entity.KdfParams) []byte {
+	return argon2.IDKey([]byte(masterPass), salt, params.Time, params.Memory, params.Threads, KeySize)
+}
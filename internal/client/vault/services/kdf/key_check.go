@@ -0,0 +1,20 @@
+package kdf
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+const keyCheckLabel = "gophkeeper key check"
+
+// KeyCheck значение для проверки ключа. Из него нельзя получить сам ключ
+func KeyCheck(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(keyCheckLabel))
+	return mac.Sum(nil)
+}
+
+// VerifyKeyCheck сравнение за постоянное время
+func VerifyKeyCheck(key []byte, keyCheck []byte) bool {
+	return hmac.Equal(KeyCheck(key), keyCheck)
+}
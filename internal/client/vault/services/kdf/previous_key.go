@@ -0,0 +1,24 @@
+package kdf
+
+import (
+	"errors"
+	"fmt"
+)
+
+const previousKeyLabel = "gophkeeper previous key"
+
+var ErrInvalidPreviousKey = errors.New("invalid previous key")
+
+// WrapPreviousKey шифрует ключ предыдущего текущего слота ключом нового слота. По цепочке таких ключей
+// новый мастер-пароль открывает записи и ключ хранилища, зашифрованные до rekey, в том числе на других устройствах
+func WrapPreviousKey(key []byte, slotId string, previousKey []byte) ([]byte, error) {
+	return wrapKey(key, previousKeyLabel+slotId, previousKey)
+}
+
+func UnwrapPreviousKey(key []byte, slotId string, wrapped []byte) ([]byte, error) {
+	previousKey, err := unwrapKey(key, previousKeyLabel+slotId, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPreviousKey, err)
+	}
+	return previousKey, nil
+}
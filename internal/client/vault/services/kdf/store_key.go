@@ -0,0 +1,92 @@
+package kdf
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+const storeKeyLabel = "gophkeeper store key"
+
+var ErrInvalidStoreKey = errors.New("invalid store key")
+
+// NewStoreKey случайный ключ, которым запечатывается локальное хранилище записей.
+// Не меняется при смене мастер-пароля: rekey только заново шифрует его ключом нового слота
+func NewStoreKey() ([]byte, error) {
+	storeKey := make([]byte, KeySize)
+	_, err := rand.Read(storeKey)
+	if err != nil {
+		return nil, err
+	}
+	return storeKey, nil
+}
+
+// StoreFlags отметки хранилища из vault.json. Аутентифицируются вместе с ключом хранилища:
+// их нельзя сбросить в файле, не сломав расшифровку ключа
+type StoreFlags struct {
+	LegacyMigrated bool
+	StoreSealed    bool
+}
+
+func (f StoreFlags) label(slotId string) string {
+	return fmt.Sprintf("%s/flags:%t,%t/%s", storeKeyLabel, f.LegacyMigrated, f.StoreSealed, slotId)
+}
+
+// WrapStoreKey шифрует ключ хранилища ключом слота (AES-256-GCM). Id слота и отметки хранилища аутентифицируются
+func WrapStoreKey(key []byte, slotId string, storeKey []byte, flags StoreFlags) ([]byte, error) {
+	return wrapKey(key, flags.label(slotId), storeKey)
+}
+
+// UnwrapStoreKey ErrInvalidStoreKey, если ключ слота не подходит или отметки отличаются от сохраненных при шифровании
+func UnwrapStoreKey(key []byte, slotId string, wrapped []byte, flags StoreFlags) ([]byte, error) {
+	storeKey, err := unwrapKey(key, flags.label(slotId), wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStoreKey, err)
+	}
+	return storeKey, nil
+}
+
+// UnwrapLegacyStoreKey ключ хранилища, зашифрованный до аутентификации отметок
+func UnwrapLegacyStoreKey(key []byte, slotId string, wrapped []byte) ([]byte, error) {
+	storeKey, err := unwrapKey(key, storeKeyLabel+slotId, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStoreKey, err)
+	}
+	return storeKey, nil
+}
+
+// wrapKey шифрует ключ другим ключом, label аутентифицируется и разделяет назначения обернутых ключей
+func wrapKey(key []byte, label string, data []byte) ([]byte, error) {
+	aead, err := newWrapAead(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, []byte(label)), nil
+}
+
+func unwrapKey(key []byte, label string, wrapped []byte) ([]byte, error) {
+	aead, err := newWrapAead(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("wrapped key is too short")
+	}
+	nonce, ciphertext := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, []byte(label))
+}
+
+func newWrapAead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
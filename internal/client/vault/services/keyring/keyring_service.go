@@ -0,0 +1,433 @@
+package keyring
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	vaultRepository "github.com/anoriar/gophkeeper/internal/client/vault/repository/vault"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/kdf"
+)
+
+const keyIdSize = 8
+
+type KeyringService struct {
+	vaultRepository  vaultRepository.VaultRepositoryInterface
+	secretRepository secret.SecretRepositoryInterface
+	keyDeriver       *kdf.Argon2idKeyDeriver
+	kdfParams        entity.KdfParams
+
+	// unlocked - ключи, полученные из мастер-пароля в этом процессе (login, rekey), до сохранения в сессию
+	unlocked *entity.Keyring
+}
+
+// NewKeyringService kdfParams применяются при создании нового хранилища
+func NewKeyringService(
+	vaultRepository vaultRepository.VaultRepositoryInterface,
+	secretRepository secret.SecretRepositoryInterface,
+	kdfParams entity.KdfParams,
+) *KeyringService {
+	return &KeyringService{
+		vaultRepository:  vaultRepository,
+		secretRepository: secretRepository,
+		keyDeriver:       kdf.NewArgon2idKeyDeriver(),
+		kdfParams:        kdfParams,
+	}
+}
+
+// Unlock при неверном мастер-пароле возвращает ErrWrongMasterPassword. Хранилище создается при первом вызове
+func (s *KeyringService) Unlock(masterPass string) (entity.Keyring, error) {
+	vault, err := s.vaultRepository.Get()
+	if err != nil && !errors.Is(err, vaultRepository.ErrVaultNotFound) {
+		return entity.Keyring{}, err
+	}
+
+	var keyring entity.Keyring
+	if vault.CurrentKeySlot() == nil {
+		vault, keyring, err = s.CreateVault(masterPass, nil)
+		if err != nil {
+			return entity.Keyring{}, err
+		}
+		err = s.vaultRepository.Save(vault)
+		if err != nil {
+			return entity.Keyring{}, fmt.Errorf("save vault error: %v", err)
+		}
+	} else {
+		keyring, err = s.deriveKeyring(vault, masterPass)
+		if err != nil {
+			return entity.Keyring{}, err
+		}
+		keyring.StoreKey, err = s.unlockStoreKey(&vault, keyring)
+		if err != nil {
+			return entity.Keyring{}, err
+		}
+	}
+
+	legacyKey := sha256.Sum256([]byte(masterPass))
+	keyring.LegacyKey = legacyKey[:]
+
+	s.unlocked = &keyring
+	return keyring, nil
+}
+
+func (s *KeyringService) StoreKey() ([]byte, error) {
+	keyring, err := s.keyring()
+	if err != nil {
+		return nil, err
+	}
+	return keyring.StoreKey, nil
+}
+
+func (s *KeyringService) Lock() {
+	s.unlocked = nil
+}
+
+// StoreSealed отметка аутентифицируется ключом хранилища: если ее сбросили в vault.json, ключ не расшифруется
+func (s *KeyringService) StoreSealed() (bool, error) {
+	vault, err := s.vaultRepository.Get()
+	if err != nil {
+		if errors.Is(err, vaultRepository.ErrVaultNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	keyring, err := s.keyring()
+	if err != nil {
+		return false, err
+	}
+	_, err = s.findStoreKey(vault, keyring)
+	if err != nil {
+		return false, err
+	}
+	return vault.StoreSealed, nil
+}
+
+func (s *KeyringService) MarkStoreSealed() error {
+	return s.saveFlags(func(vault *entity.Vault) {
+		vault.StoreSealed = true
+	})
+}
+
+func (s *KeyringService) CompleteLegacyMigration() error {
+	err := s.saveFlags(func(vault *entity.Vault) {
+		vault.LegacyMigrated = true
+	})
+	if err != nil {
+		return err
+	}
+	if s.unlocked != nil {
+		s.unlocked.RejectLegacy = true
+	}
+	return nil
+}
+
+// saveFlags меняет отметки хранилища и заново шифрует с ними ключ хранилища во всех слотах, где он есть
+func (s *KeyringService) saveFlags(update func(vault *entity.Vault)) error {
+	vault, err := s.vaultRepository.Get()
+	if err != nil {
+		return err
+	}
+	flags := storeFlags(vault)
+	update(&vault)
+	if storeFlags(vault) == flags {
+		return nil
+	}
+
+	var keyring entity.Keyring
+	for i := range vault.KeySlots {
+		keySlot := &vault.KeySlots[i]
+		if len(keySlot.StoreKey) == 0 {
+			continue
+		}
+		if keyring.Keys == nil {
+			keyring, err = s.keyring()
+			if err != nil {
+				return err
+			}
+		}
+		key := keyring.FindKey(keySlot.Id)
+		if key == nil || !key.Verified {
+			return fmt.Errorf("key slot %s is not unlocked, login again", keySlot.Id)
+		}
+		storeKey, _, err := unwrapStoreKey(*keySlot, key.Key, flags)
+		if err != nil {
+			return err
+		}
+		keySlot.StoreKey, err = kdf.WrapStoreKey(key.Key, keySlot.Id, storeKey, storeFlags(vault))
+		if err != nil {
+			return err
+		}
+	}
+	err = s.vaultRepository.Save(vault)
+	if err != nil {
+		return fmt.Errorf("save vault error: %v", err)
+	}
+	return nil
+}
+
+// keyring ключи, разблокированные в этом процессе, иначе из сессии
+func (s *KeyringService) keyring() (entity.Keyring, error) {
+	if s.unlocked != nil {
+		return *s.unlocked, nil
+	}
+	return s.secretRepository.GetKeyring()
+}
+
+func (s *KeyringService) CreateVault(masterPass string, storeKey []byte) (entity.Vault, entity.Keyring, error) {
+	keySlot, err := s.newKeySlot()
+	if err != nil {
+		return entity.Vault{}, entity.Keyring{}, err
+	}
+	key := s.keyDeriver.DeriveKey(masterPass, keySlot.Salt, keySlot.Kdf)
+	keySlot.KeyCheck = kdf.KeyCheck(key)
+
+	if storeKey == nil {
+		storeKey, err = kdf.NewStoreKey()
+		if err != nil {
+			return entity.Vault{}, entity.Keyring{}, err
+		}
+	}
+	keySlot.StoreKey, err = kdf.WrapStoreKey(key, keySlot.Id, storeKey, kdf.StoreFlags{})
+	if err != nil {
+		return entity.Vault{}, entity.Keyring{}, err
+	}
+
+	vault := entity.Vault{
+		CurrentKeyId: keySlot.Id,
+		KeySlots:     []entity.KeySlot{keySlot},
+	}
+	keyring := entity.Keyring{
+		CurrentKeyId: keySlot.Id,
+		Keys: []entity.VaultKey{
+			{Id: keySlot.Id, Kdf: keySlot.Kdf, Key: key, Verified: true},
+		},
+		StoreKey: storeKey,
+	}
+	return vault, keyring, nil
+}
+
+// RekeyVault хранилище нового мастер-пароля: слот со следующим номером смены, ключом хранилища previousKeyring
+// и ключом прежнего текущего слота. Прежние слоты остаются, ими зашифрованы записи на сервере и других устройствах,
+// но ключ хранилища в них больше не хранится: иначе старый мастер-пароль по-прежнему открывал бы хранилище
+func (s *KeyringService) RekeyVault(masterPass string, previous entity.Vault, previousKeyring entity.Keyring) (entity.Vault, entity.Keyring, error) {
+	vault, keyring, err := s.CreateVault(masterPass, previousKeyring.StoreKey)
+	if err != nil {
+		return entity.Vault{}, entity.Keyring{}, err
+	}
+	// rekey перешифровывает все записи, старых форматов после него не остается.
+	// Ключ хранилища прежний, значит и запечатанное им хранилище
+	vault.LegacyMigrated = true
+	vault.StoreSealed = previous.StoreSealed
+	keyring.RejectLegacy = true
+
+	keySlot := vault.CurrentKeySlot()
+	keySlot.StoreKey, err = kdf.WrapStoreKey(keyring.CurrentKey().Key, keySlot.Id, keyring.StoreKey, storeFlags(vault))
+	if err != nil {
+		return entity.Vault{}, entity.Keyring{}, err
+	}
+	keySlot.Generation = previous.NextGeneration()
+	previousKey := previousKeyring.CurrentKey()
+	if previousKey != nil && previousKey.Verified {
+		keySlot.PreviousKeyId = previousKey.Id
+		keySlot.PreviousKey, err = kdf.WrapPreviousKey(keyring.CurrentKey().Key, keySlot.Id, previousKey.Key)
+		if err != nil {
+			return entity.Vault{}, entity.Keyring{}, err
+		}
+	}
+
+	for _, previousSlot := range previous.KeySlots {
+		previousSlot.StoreKey = nil
+		vault.KeySlots = append(vault.KeySlots, previousSlot)
+	}
+	for _, key := range previousKeyring.Keys {
+		if key.Verified {
+			keyring.Keys = append(keyring.Keys, key)
+		}
+	}
+	return vault, keyring, nil
+}
+
+// unlockStoreKey расшифровывает ключ хранилища текущим ключом. Если в текущем слоте ключа хранилища нет, а в другом
+// слоте с проверенным ключом есть (текущий слот создан rekey на другом устройстве), ключ хранилища переносится в текущий слот.
+// Проверенному слоту без ключа хранилища (хранилище создано до запечатывания) ключ создается и сохраняется.
+// Ключ, зашифрованный без отметок хранилища, шифруется заново вместе с ними. Для непроверенного слота ключа нет
+func (s *KeyringService) unlockStoreKey(vault *entity.Vault, keyring entity.Keyring) ([]byte, error) {
+	keySlot := vault.CurrentKeySlot()
+	key := keyring.CurrentKey()
+	if !key.Verified {
+		return nil, nil
+	}
+	if len(keySlot.StoreKey) > 0 {
+		storeKey, legacy, err := unwrapStoreKey(*keySlot, key.Key, storeFlags(*vault))
+		if err != nil || !legacy {
+			return storeKey, err
+		}
+		return s.saveStoreKey(vault, key.Key, storeKey)
+	}
+
+	storeKey, err := s.findStoreKey(*vault, keyring)
+	if err != nil {
+		return nil, err
+	}
+	if storeKey == nil {
+		if vault.StoreSealed {
+			return nil, errors.New("store key of sealed local vault is not found, master password of this device is required")
+		}
+		storeKey, err = kdf.NewStoreKey()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.saveStoreKey(vault, key.Key, storeKey)
+}
+
+// saveStoreKey сохраняет ключ хранилища в текущем слоте вместе с отметками хранилища
+func (s *KeyringService) saveStoreKey(vault *entity.Vault, key []byte, storeKey []byte) ([]byte, error) {
+	keySlot := vault.CurrentKeySlot()
+	var err error
+	keySlot.StoreKey, err = kdf.WrapStoreKey(key, keySlot.Id, storeKey, storeFlags(*vault))
+	if err != nil {
+		return nil, err
+	}
+	// в других слотах ключ хранилища больше не нужен, а прежний мастер-пароль не должен его открывать
+	for i := range vault.KeySlots {
+		if vault.KeySlots[i].Id != keySlot.Id {
+			vault.KeySlots[i].StoreKey = nil
+		}
+	}
+	err = s.vaultRepository.Save(*vault)
+	if err != nil {
+		return nil, fmt.Errorf("save vault error: %v", err)
+	}
+	return storeKey, nil
+}
+
+// findStoreKey ключ хранилища из слота, ключ которого есть в связке и проверен. nil, если такого слота нет
+func (s *KeyringService) findStoreKey(vault entity.Vault, keyring entity.Keyring) ([]byte, error) {
+	for _, keySlot := range vault.KeySlots {
+		if len(keySlot.StoreKey) == 0 {
+			continue
+		}
+		key := keyring.FindKey(keySlot.Id)
+		if key == nil || !key.Verified {
+			continue
+		}
+		storeKey, _, err := unwrapStoreKey(keySlot, key.Key, storeFlags(vault))
+		return storeKey, err
+	}
+	return nil, nil
+}
+
+// unwrapStoreKey legacy - ключ зашифрован до аутентификации отметок хранилища.
+// Проверенный ключ слота не расшифровывает ключ хранилища, только если отметки или ключ изменили в обход клиента
+func unwrapStoreKey(keySlot entity.KeySlot, key []byte, flags kdf.StoreFlags) ([]byte, bool, error) {
+	storeKey, err := kdf.UnwrapStoreKey(key, keySlot.Id, keySlot.StoreKey, flags)
+	if err == nil {
+		return storeKey, false, nil
+	}
+	storeKey, legacyErr := kdf.UnwrapLegacyStoreKey(key, keySlot.Id, keySlot.StoreKey)
+	if legacyErr != nil {
+		return nil, false, fmt.Errorf("%w: %w", sharedErrors.ErrVaultTampered, err)
+	}
+	return storeKey, true, nil
+}
+
+func storeFlags(vault entity.Vault) kdf.StoreFlags {
+	return kdf.StoreFlags{LegacyMigrated: vault.LegacyMigrated, StoreSealed: vault.StoreSealed}
+}
+
+// deriveKeyring мастер-пароль проверяется по текущему слоту. Ключи слотов, бывших текущими до rekey,
+// расшифровываются по цепочке из текущего слота. Другой слот, не прошедший проверку, получен с сервера
+// и создан другим мастер-паролем (rekey на другом устройстве без цепочки): его ключа в связке нет.
+// Слот с недопустимыми параметрами KDF пропускается, а если это текущий слот, хранилище не открывается
+func (s *KeyringService) deriveKeyring(vault entity.Vault, masterPass string) (entity.Keyring, error) {
+	keyring := entity.Keyring{
+		CurrentKeyId: vault.CurrentKeyId,
+		Keys:         make([]entity.VaultKey, 0, len(vault.KeySlots)),
+		RejectLegacy: vault.LegacyMigrated,
+	}
+
+	currentSlot := vault.CurrentKeySlot()
+	err := currentSlot.Kdf.Validate()
+	if err != nil {
+		return entity.Keyring{}, fmt.Errorf("key slot %s: %w", currentSlot.Id, err)
+	}
+	currentKey := s.keyDeriver.DeriveKey(masterPass, currentSlot.Salt, currentSlot.Kdf)
+	if len(currentSlot.KeyCheck) > 0 {
+		if !kdf.VerifyKeyCheck(currentKey, currentSlot.KeyCheck) {
+			return entity.Keyring{}, sharedErrors.ErrWrongMasterPassword
+		}
+		keyring.Keys = append(keyring.Keys, entity.VaultKey{Id: currentSlot.Id, Kdf: currentSlot.Kdf, Key: currentKey, Verified: true})
+		s.unwrapPreviousKeys(vault, *currentSlot, currentKey, &keyring)
+	} else {
+		keyring.Keys = append(keyring.Keys, entity.VaultKey{Id: currentSlot.Id, Kdf: currentSlot.Kdf, Key: currentKey})
+	}
+
+	for _, keySlot := range vault.KeySlots {
+		if keyring.FindKey(keySlot.Id) != nil || keySlot.Kdf.Validate() != nil {
+			continue
+		}
+		key := s.keyDeriver.DeriveKey(masterPass, keySlot.Salt, keySlot.Kdf)
+		verified := false
+		if len(keySlot.KeyCheck) > 0 {
+			if !kdf.VerifyKeyCheck(key, keySlot.KeyCheck) {
+				continue
+			}
+			verified = true
+		}
+		keyring.Keys = append(keyring.Keys, entity.VaultKey{
+			Id:       keySlot.Id,
+			Kdf:      keySlot.Kdf,
+			Key:      key,
+			Verified: verified,
+		})
+	}
+	return keyring, nil
+}
+
+// unwrapPreviousKeys добавляет в связку ключи слотов, бывших текущими до rekey. Цепочка обрывается на слоте,
+// которого нет в хранилище или ключ которого не совпал с KeyCheck
+func (s *KeyringService) unwrapPreviousKeys(vault entity.Vault, keySlot entity.KeySlot, key []byte, keyring *entity.Keyring) {
+	for keySlot.PreviousKeyId != "" && len(keySlot.PreviousKey) > 0 {
+		previousSlot := vault.FindKeySlot(keySlot.PreviousKeyId)
+		if previousSlot == nil || keyring.FindKey(previousSlot.Id) != nil {
+			return
+		}
+		previousKey, err := kdf.UnwrapPreviousKey(key, keySlot.Id, keySlot.PreviousKey)
+		if err != nil || !kdf.VerifyKeyCheck(previousKey, previousSlot.KeyCheck) {
+			return
+		}
+		keyring.Keys = append(keyring.Keys, entity.VaultKey{
+			Id:       previousSlot.Id,
+			Kdf:      previousSlot.Kdf,
+			Key:      previousKey,
+			Verified: true,
+		})
+		keySlot, key = *previousSlot, previousKey
+	}
+}
+
+func (s *KeyringService) newKeySlot() (entity.KeySlot, error) {
+	salt := make([]byte, kdf.SaltSize)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return entity.KeySlot{}, err
+	}
+	keyId := make([]byte, keyIdSize)
+	_, err = rand.Read(keyId)
+	if err != nil {
+		return entity.KeySlot{}, err
+	}
+	return entity.KeySlot{
+		Id:        hex.EncodeToString(keyId),
+		Salt:      salt,
+		Kdf:       s.kdfParams,
+		CreatedAt: time.Now(),
+	}, nil
+}
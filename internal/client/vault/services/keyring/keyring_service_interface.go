@@ -0,0 +1,26 @@
+package keyring
+
+import "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+
+//go:generate mockgen -source=keyring_service_interface.go -destination=mock_keyring_service/mock_keyring_service.go -package=mock_keyring_service
+type KeyringServiceInterface interface {
+	// Unlock получение ключей хранилища из мастер-пароля, проверка мастер-пароля по KeyCheck
+	Unlock(masterPass string) (entity.Keyring, error)
+	// CreateVault новое хранилище с новой солью, без сохранения. storeKey - ключ локального хранилища записей,
+	// который нужно перенести в новое хранилище; nil - создать новый
+	CreateVault(masterPass string, storeKey []byte) (entity.Vault, entity.Keyring, error)
+	// RekeyVault хранилище нового мастер-пароля на смену previous, без сохранения. Ключ локального хранилища записей
+	// переносится из previousKeyring, а ключ прежнего текущего слота шифруется ключом нового
+	RekeyVault(masterPass string, previous entity.Vault, previousKeyring entity.Keyring) (entity.Vault, entity.Keyring, error)
+	// StoreKey ключ локального хранилища записей: из ключей, разблокированных в этом процессе, иначе из сессии.
+	// ErrVaultLocked, если хранилище заблокировано. nil - хранилище без KeyCheck, не запечатывается до rekey
+	StoreKey() ([]byte, error)
+	// Lock забывает ключи, разблокированные в этом процессе. Ключи в сессии не меняются
+	Lock()
+	// StoreSealed локальное хранилище записей уже запечатывалось. ErrVaultTampered, если отметку изменили в обход клиента
+	StoreSealed() (bool, error)
+	// MarkStoreSealed отмечает, что локальное хранилище записей запечатано
+	MarkStoreSealed() error
+	// CompleteLegacyMigration отмечает, что записей старых форматов не осталось: после этого они не расшифровываются
+	CompleteLegacyMigration() error
+}
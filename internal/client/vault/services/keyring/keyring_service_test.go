@@ -0,0 +1,343 @@
+package keyring
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	entryEntity "github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/encoder"
+	"github.com/anoriar/gophkeeper/internal/client/shared/app/logger"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret"
+	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret/mock_secret_repository"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	vaultErrors "github.com/anoriar/gophkeeper/internal/client/vault/errors"
+	vaultRepository "github.com/anoriar/gophkeeper/internal/client/vault/repository/vault"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/vault/mock_vault_repository"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/vault_ext/mock_vault_ext_repository"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/vaultsync"
+)
+
+var testKdfParams = entity.KdfParams{Time: 1, Memory: 1024, Threads: 1}
+
+func TestKeyringService_Unlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	vaultRepositoryMock := mock_vault_repository.NewMockVaultRepositoryInterface(ctrl)
+	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
+
+	s := NewKeyringService(vaultRepositoryMock, secretRepositoryMock, testKdfParams)
+	vault, vaultKeyring, err := s.CreateVault("master", nil)
+	require.NoError(t, err)
+
+	legacyVault := entity.Vault{CurrentKeyId: vault.CurrentKeyId, KeySlots: []entity.KeySlot{vault.KeySlots[0]}}
+	legacyVault.KeySlots[0].KeyCheck = nil
+	legacyVault.KeySlots[0].StoreKey = nil
+
+	unsealedVault := entity.Vault{CurrentKeyId: vault.CurrentKeyId, KeySlots: []entity.KeySlot{vault.KeySlots[0]}}
+	unsealedVault.KeySlots[0].StoreKey = nil
+
+	otherVault, _, err := s.CreateVault("other master", nil)
+	require.NoError(t, err)
+	mergedVault := entity.Vault{CurrentKeyId: vault.CurrentKeyId, KeySlots: []entity.KeySlot{vault.KeySlots[0], otherVault.KeySlots[0]}}
+
+	invalidKdfVault := entity.Vault{CurrentKeyId: vault.CurrentKeyId, KeySlots: []entity.KeySlot{vault.KeySlots[0]}}
+	invalidKdfVault.KeySlots[0].Kdf.Time = 0
+
+	tests := []struct {
+		name          string
+		masterPass    string
+		mockBehaviour func()
+		wantVerified  bool
+		wantStoreKey  []byte
+		wantErr       error
+	}{
+		{
+			name:       "success",
+			masterPass: "master",
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().Get().Return(vault, nil)
+			},
+			wantVerified: true,
+			wantStoreKey: vaultKeyring.StoreKey,
+		},
+		{
+			name:       "wrong master password",
+			masterPass: "typo",
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().Get().Return(vault, nil)
+			},
+			wantErr: sharedErrors.ErrWrongMasterPassword,
+		},
+		{
+			name:       "slot of other master password from server",
+			masterPass: "master",
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().Get().Return(mergedVault, nil)
+			},
+			wantVerified: true,
+			wantStoreKey: vaultKeyring.StoreKey,
+		},
+		{
+			name:       "current slot with invalid kdf params",
+			masterPass: "master",
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().Get().Return(invalidKdfVault, nil)
+			},
+			wantErr: vaultErrors.ErrKdfParamsNotValid,
+		},
+		{
+			name:       "slot without key check",
+			masterPass: "typo",
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().Get().Return(legacyVault, nil)
+			},
+			wantVerified: false,
+		},
+		{
+			name:       "verified slot without store key",
+			masterPass: "master",
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().Get().Return(unsealedVault, nil)
+				vaultRepositoryMock.EXPECT().Save(gomock.Any()).DoAndReturn(func(vault entity.Vault) error {
+					assert.NotEmpty(t, vault.CurrentKeySlot().StoreKey)
+					return nil
+				})
+			},
+			wantVerified: true,
+		},
+		{
+			name:       "new vault",
+			masterPass: "master",
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().Get().Return(entity.Vault{}, vaultRepository.ErrVaultNotFound)
+				vaultRepositoryMock.EXPECT().Save(gomock.Any()).DoAndReturn(func(vault entity.Vault) error {
+					assert.NotEmpty(t, vault.CurrentKeySlot().KeyCheck)
+					assert.NotEmpty(t, vault.CurrentKeySlot().StoreKey)
+					return nil
+				})
+			},
+			wantVerified: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour()
+			keyring, err := s.Unlock(tt.masterPass)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVerified, keyring.CurrentKey().Verified)
+			assert.Equal(t, tt.wantVerified, keyring.StoreKey != nil)
+			if tt.wantStoreKey != nil {
+				assert.Equal(t, tt.wantStoreKey, keyring.StoreKey)
+			}
+			assert.NotNil(t, keyring.LegacyKey)
+		})
+	}
+}
+
+func TestKeyringService_StoreKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	vaultRepositoryMock := mock_vault_repository.NewMockVaultRepositoryInterface(ctrl)
+	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
+	s := NewKeyringService(vaultRepositoryMock, secretRepositoryMock, testKdfParams)
+
+	// до разблокировки в этом процессе ключ берется из сессии
+	secretRepositoryMock.EXPECT().GetKeyring().Return(entity.Keyring{}, secret.ErrVaultLocked)
+	_, err := s.StoreKey()
+	assert.ErrorIs(t, err, secret.ErrVaultLocked)
+
+	vault, keyring, err := s.CreateVault("master", nil)
+	require.NoError(t, err)
+	vaultRepositoryMock.EXPECT().Get().Return(vault, nil)
+	_, err = s.Unlock("master")
+	require.NoError(t, err)
+
+	storeKey, err := s.StoreKey()
+	require.NoError(t, err)
+	assert.Equal(t, keyring.StoreKey, storeKey)
+
+	// rekey переносит ключ хранилища в новый слот
+	_, newKeyring, err := s.CreateVault("new master", keyring.StoreKey)
+	require.NoError(t, err)
+	assert.Equal(t, keyring.StoreKey, newKeyring.StoreKey)
+}
+
+func TestKeyringService_CompleteLegacyMigration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	vaultRepositoryMock := mock_vault_repository.NewMockVaultRepositoryInterface(ctrl)
+	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
+	s := NewKeyringService(vaultRepositoryMock, secretRepositoryMock, testKdfParams)
+
+	vault, _, err := s.CreateVault("master", nil)
+	require.NoError(t, err)
+	vaultRepositoryMock.EXPECT().Get().Return(vault, nil)
+	keyring, err := s.Unlock("master")
+	require.NoError(t, err)
+	assert.False(t, keyring.RejectLegacy)
+
+	var migratedVault entity.Vault
+	vaultRepositoryMock.EXPECT().Get().Return(copyVault(vault), nil)
+	vaultRepositoryMock.EXPECT().Save(gomock.Any()).DoAndReturn(func(saved entity.Vault) error {
+		migratedVault = saved
+		return nil
+	})
+	require.NoError(t, s.CompleteLegacyMigration())
+	assert.True(t, migratedVault.LegacyMigrated)
+
+	// следующий login получает флаг из хранилища
+	vaultRepositoryMock.EXPECT().Get().Return(migratedVault, nil)
+	keyring, err = s.Unlock("master")
+	require.NoError(t, err)
+	assert.True(t, keyring.RejectLegacy)
+}
+
+// TestKeyringService_StoreFlagsTampered отметки, измененные в vault.json в обход клиента, не расшифровывают ключ хранилища
+func TestKeyringService_StoreFlagsTampered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	vaultRepositoryMock := mock_vault_repository.NewMockVaultRepositoryInterface(ctrl)
+	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
+	s := NewKeyringService(vaultRepositoryMock, secretRepositoryMock, testKdfParams)
+
+	vault, _, err := s.CreateVault("master", nil)
+	require.NoError(t, err)
+	vaultRepositoryMock.EXPECT().Get().Return(copyVault(vault), nil)
+	_, err = s.Unlock("master")
+	require.NoError(t, err)
+
+	var sealedVault entity.Vault
+	vaultRepositoryMock.EXPECT().Get().Return(copyVault(vault), nil)
+	vaultRepositoryMock.EXPECT().Save(gomock.Any()).DoAndReturn(func(saved entity.Vault) error {
+		sealedVault = saved
+		return nil
+	})
+	require.NoError(t, s.MarkStoreSealed())
+
+	vaultRepositoryMock.EXPECT().Get().Return(copyVault(sealedVault), nil)
+	sealed, err := s.StoreSealed()
+	require.NoError(t, err)
+	assert.True(t, sealed)
+
+	// сброшенная отметка позволила бы подложить незапечатанный файл
+	unsealedVault := copyVault(sealedVault)
+	unsealedVault.StoreSealed = false
+	vaultRepositoryMock.EXPECT().Get().Return(unsealedVault, nil)
+	_, err = s.StoreSealed()
+	assert.ErrorIs(t, err, sharedErrors.ErrVaultTampered)
+
+	// login тоже не принимает отметку, выставленную в обход клиента
+	forgedVault := copyVault(sealedVault)
+	forgedVault.LegacyMigrated = true
+	vaultRepositoryMock.EXPECT().Get().Return(forgedVault, nil)
+	_, err = s.Unlock("master")
+	assert.ErrorIs(t, err, sharedErrors.ErrVaultTampered)
+}
+
+func copyVault(vault entity.Vault) entity.Vault {
+	vault.KeySlots = append([]entity.KeySlot(nil), vault.KeySlots...)
+	return vault
+}
+
+// TestKeyringService_RekeyOnOtherDevice rekey на устройстве A, затем login и sync на устройстве B:
+// B берет новый слот текущим, открывает новым мастер-паролем свое запечатанное хранилище и записи, перешифрованные на A
+func TestKeyringService_RekeyOnOtherDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
+	vaultExtRepositoryMock := mock_vault_ext_repository.NewMockVaultExtRepositoryInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+	encryptor, err := encoder.NewAeadDataEncryptor("aes-256-gcm")
+	require.NoError(t, err)
+
+	var serverVault entity.Vault
+	vaultExtRepositoryMock.EXPECT().Get(gomock.Any(), "token").DoAndReturn(func(ctx context.Context, token string) (entity.Vault, error) {
+		return serverVault, nil
+	}).AnyTimes()
+	vaultExtRepositoryMock.EXPECT().Save(gomock.Any(), "token", gomock.Any()).DoAndReturn(func(ctx context.Context, token string, vault entity.Vault) error {
+		serverVault = vault
+		return nil
+	}).AnyTimes()
+
+	newDevice := func() (*vaultRepository.VaultRepository, *KeyringService, *vaultsync.VaultSyncService) {
+		repository, err := vaultRepository.NewVaultRepository(filepath.Join(t.TempDir(), "vault.json"))
+		require.NoError(t, err)
+		return repository,
+			NewKeyringService(repository, secretRepositoryMock, testKdfParams),
+			vaultsync.NewVaultSyncService(repository, vaultExtRepositoryMock, loggerMock)
+	}
+
+	// A создает хранилище и отправляет слот на сервер
+	vaultRepositoryA, keyringServiceA, vaultSyncServiceA := newDevice()
+	keyringA, err := keyringServiceA.Unlock("old master")
+	require.NoError(t, err)
+	require.NoError(t, vaultSyncServiceA.Push(ctx, "token"))
+
+	// B входит старым мастер-паролем, его хранилище запечатано ключом, который знает только B
+	vaultRepositoryB, keyringServiceB, vaultSyncServiceB := newDevice()
+	require.NoError(t, vaultSyncServiceB.Pull(ctx, "token"))
+	keyringB, err := keyringServiceB.Unlock("old master")
+	require.NoError(t, err)
+	require.NotEmpty(t, keyringB.StoreKey)
+	require.NoError(t, keyringServiceB.MarkStoreSealed())
+	oldEntry, err := encoder.EncryptEntry(encryptor, entryEntity.Entry{Id: "2", EntryType: enum.Login, Data: []byte("from b")}, keyringB)
+	require.NoError(t, err)
+
+	// A меняет мастер-пароль, перешифровывает запись и отправляет новый слот
+	oldVaultA, err := vaultRepositoryA.Get()
+	require.NoError(t, err)
+	newVaultA, newKeyringA, err := keyringServiceA.RekeyVault("new master", oldVaultA, keyringA)
+	require.NoError(t, err)
+	require.NoError(t, vaultRepositoryA.Save(newVaultA))
+	require.NoError(t, vaultSyncServiceA.Push(ctx, "token"))
+	entry, err := encoder.EncryptEntry(encryptor, entryEntity.Entry{Id: "1", EntryType: enum.Login, Data: []byte("secret")}, newKeyringA)
+	require.NoError(t, err)
+
+	// login на B: sync слотов, затем новый мастер-пароль
+	require.NoError(t, vaultSyncServiceB.Pull(ctx, "token"))
+	_, err = keyringServiceB.Unlock("old master")
+	assert.ErrorIs(t, err, sharedErrors.ErrWrongMasterPassword)
+	newKeyringB, err := keyringServiceB.Unlock("new master")
+	require.NoError(t, err)
+	assert.Equal(t, newVaultA.CurrentKeyId, newKeyringB.CurrentKeyId)
+	assert.Equal(t, keyringB.StoreKey, newKeyringB.StoreKey)
+
+	// detail на B: запись A и запись B, зашифрованная до rekey
+	decrypted, err := encoder.DecryptEntry(encryptor, entry, newKeyringB)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), decrypted.Data)
+	decrypted, err = encoder.DecryptEntry(encryptor, oldEntry, newKeyringB)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("from b"), decrypted.Data)
+
+	// старый мастер-пароль больше не открывает ключ хранилища B
+	vaultB, err := vaultRepositoryB.Get()
+	require.NoError(t, err)
+	for _, keySlot := range vaultB.KeySlots {
+		if keySlot.Id != vaultB.CurrentKeyId {
+			assert.Empty(t, keySlot.StoreKey)
+		}
+	}
+
+	// push с B не возвращает текущим прежний слот
+	require.NoError(t, vaultSyncServiceB.Push(ctx, "token"))
+	assert.Equal(t, newVaultA.CurrentKeyId, serverVault.CurrentKeyId)
+}
@@ -0,0 +1,152 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: keyring_service_interface.go
+
+// Package mock_keyring_service is a generated GoMock package.
+package mock_keyring_service
+
+import (
+	reflect "reflect"
+
+	entity "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockKeyringServiceInterface is a mock of KeyringServiceInterface interface.
+type MockKeyringServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyringServiceInterfaceMockRecorder
+}
+
+// MockKeyringServiceInterfaceMockRecorder is the mock recorder for MockKeyringServiceInterface.
+type MockKeyringServiceInterfaceMockRecorder struct {
+	mock *MockKeyringServiceInterface
+}
+
+// NewMockKeyringServiceInterface creates a new mock instance.
+func NewMockKeyringServiceInterface(ctrl *gomock.Controller) *MockKeyringServiceInterface {
+	mock := &MockKeyringServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockKeyringServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyringServiceInterface) EXPECT() *MockKeyringServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CompleteLegacyMigration mocks base method.
+func (m *MockKeyringServiceInterface) CompleteLegacyMigration() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteLegacyMigration")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteLegacyMigration indicates an expected call of CompleteLegacyMigration.
+func (mr *MockKeyringServiceInterfaceMockRecorder) CompleteLegacyMigration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteLegacyMigration", reflect.TypeOf((*MockKeyringServiceInterface)(nil).CompleteLegacyMigration))
+}
+
+// CreateVault mocks base method.
+func (m *MockKeyringServiceInterface) CreateVault(masterPass string, storeKey []byte) (entity.Vault, entity.Keyring, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVault", masterPass, storeKey)
+	ret0, _ := ret[0].(entity.Vault)
+	ret1, _ := ret[1].(entity.Keyring)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateVault indicates an expected call of CreateVault.
+func (mr *MockKeyringServiceInterfaceMockRecorder) CreateVault(masterPass, storeKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVault", reflect.TypeOf((*MockKeyringServiceInterface)(nil).CreateVault), masterPass, storeKey)
+}
+
+// Lock mocks base method.
+func (m *MockKeyringServiceInterface) Lock() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Lock")
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockKeyringServiceInterfaceMockRecorder) Lock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockKeyringServiceInterface)(nil).Lock))
+}
+
+// MarkStoreSealed mocks base method.
+func (m *MockKeyringServiceInterface) MarkStoreSealed() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkStoreSealed")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkStoreSealed indicates an expected call of MarkStoreSealed.
+func (mr *MockKeyringServiceInterfaceMockRecorder) MarkStoreSealed() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkStoreSealed", reflect.TypeOf((*MockKeyringServiceInterface)(nil).MarkStoreSealed))
+}
+
+// RekeyVault mocks base method.
+func (m *MockKeyringServiceInterface) RekeyVault(masterPass string, previous entity.Vault, previousKeyring entity.Keyring) (entity.Vault, entity.Keyring, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RekeyVault", masterPass, previous, previousKeyring)
+	ret0, _ := ret[0].(entity.Vault)
+	ret1, _ := ret[1].(entity.Keyring)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RekeyVault indicates an expected call of RekeyVault.
+func (mr *MockKeyringServiceInterfaceMockRecorder) RekeyVault(masterPass, previous, previousKeyring interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RekeyVault", reflect.TypeOf((*MockKeyringServiceInterface)(nil).RekeyVault), masterPass, previous, previousKeyring)
+}
+
+// StoreKey mocks base method.
+func (m *MockKeyringServiceInterface) StoreKey() ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreKey")
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreKey indicates an expected call of StoreKey.
+func (mr *MockKeyringServiceInterfaceMockRecorder) StoreKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreKey", reflect.TypeOf((*MockKeyringServiceInterface)(nil).StoreKey))
+}
+
+// StoreSealed mocks base method.
+func (m *MockKeyringServiceInterface) StoreSealed() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreSealed")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreSealed indicates an expected call of StoreSealed.
+func (mr *MockKeyringServiceInterfaceMockRecorder) StoreSealed() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreSealed", reflect.TypeOf((*MockKeyringServiceInterface)(nil).StoreSealed))
+}
+
+// Unlock mocks base method.
+func (m *MockKeyringServiceInterface) Unlock(masterPass string) (entity.Keyring, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", masterPass)
+	ret0, _ := ret[0].(entity.Keyring)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockKeyringServiceInterfaceMockRecorder) Unlock(masterPass interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockKeyringServiceInterface)(nil).Unlock), masterPass)
+}
@@ -0,0 +1,39 @@
+package migration
+
+import "context"
+
+// Migration переводит локальные файлы хранилища с версии формата Version-1 на Version
+type Migration struct {
+	Version     int
+	Description string
+	Migrate     func(ctx context.Context) error
+}
+
+// NewMigrations миграции по порядку версий. Последняя версия - текущий формат файлов клиента.
+// migrateEntryStore переносит записи в bolt хранилище и запечатывает его; nil, если миграции только проверяются через Pending.
+// Миграция, которой нужен ключ заблокированного хранилища, возвращает ErrVaultLocked и продолжается после login
+func NewMigrations(migrateEntryStore func(ctx context.Context) error) []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Description: "add manifest with local files format version",
+			// формат файлов не меняется, манифест только фиксирует версию
+			Migrate: func(ctx context.Context) error {
+				return nil
+			},
+		},
+		{
+			Version:     2,
+			Description: "entries carry encrypted title",
+			// старые записи остаются без названия; версия не дает старому клиенту потерять названия при перезаписи
+			Migrate: func(ctx context.Context) error {
+				return nil
+			},
+		},
+		{
+			Version:     3,
+			Description: "entries moved from JSON-lines files to sealed bolt store",
+			Migrate:     migrateEntryStore,
+		},
+	}
+}
@@ -0,0 +1,266 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	vaultErrors "github.com/anoriar/gophkeeper/internal/client/vault/errors"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/manifest"
+)
+
+// skippedFileSuffixes блокировки и недописанные временные файлы в копию не попадают
+var skippedFileSuffixes = []string{".lock", ".tmp", ".sealing"}
+
+type MigrationService struct {
+	manifestRepository manifest.ManifestRepositoryInterface
+	migrations         []Migration
+	// dataDirName - каталог данных профиля, пути файлов в копии считаются от него
+	dataDirName string
+	// vaultPaths - файлы и каталоги хранилища, которые копируются перед миграцией
+	vaultPaths []string
+	// backupDirName - каталог, в котором для каждой миграции создается своя копия
+	backupDirName string
+	now           func() time.Time
+	logger        *zap.Logger
+}
+
+func NewMigrationService(
+	manifestRepository manifest.ManifestRepositoryInterface,
+	migrations []Migration,
+	dataDirName string,
+	vaultPaths []string,
+	backupDirName string,
+	logger *zap.Logger,
+) *MigrationService {
+	return &MigrationService{
+		manifestRepository: manifestRepository,
+		migrations:         migrations,
+		dataDirName:        dataDirName,
+		vaultPaths:         vaultPaths,
+		backupDirName:      backupDirName,
+		now:                time.Now,
+		logger:             logger,
+	}
+}
+
+func (s *MigrationService) Migrate(ctx context.Context) error {
+	unlock, err := s.manifestRepository.Lock(ctx)
+	if err != nil {
+		if errors.Is(err, sharedErrors.ErrVaultBusy) {
+			return err
+		}
+		s.logger.Error("lock manifest error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	defer func() {
+		err := unlock()
+		if err != nil {
+			s.logger.Error("unlock manifest error", zap.String("error", err.Error()))
+		}
+	}()
+
+	currentVersion := s.currentVersion()
+	vaultManifest, err := s.manifestRepository.Get()
+	if err != nil {
+		if !errors.Is(err, manifest.ErrManifestNotFound) {
+			s.logger.Error("get manifest error", zap.String("error", err.Error()))
+			return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+		}
+		exists, err := s.vaultExists()
+		if err != nil {
+			s.logger.Error("check vault files error", zap.String("error", err.Error()))
+			return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+		}
+		// новое хранилище сразу создается в текущем формате
+		if !exists {
+			return s.saveManifest(currentVersion)
+		}
+	}
+
+	if vaultManifest.FormatVersion > currentVersion {
+		return fmt.Errorf("%w: files version %d, supported %d", vaultErrors.ErrFormatVersionNotSupported, vaultManifest.FormatVersion, currentVersion)
+	}
+	if vaultManifest.FormatVersion == currentVersion {
+		return nil
+	}
+
+	backupDirName, err := s.backup(vaultManifest.FormatVersion)
+	if err != nil {
+		s.logger.Error("backup vault files error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	s.logger.Info("vault files copied before migration", zap.String("backup", backupDirName))
+
+	for _, migration := range s.migrations {
+		if migration.Version <= vaultManifest.FormatVersion {
+			continue
+		}
+		err = migration.Migrate(ctx)
+		if err != nil {
+			// файлы остаются в предыдущей версии, миграция продолжится после login
+			if errors.Is(err, secret.ErrVaultLocked) {
+				s.logger.Warn("vault files migration needs unlocked vault, it continues after login", zap.Int("version", migration.Version))
+				return nil
+			}
+			s.logger.Error("migration error",
+				zap.Int("version", migration.Version),
+				zap.String("backup", backupDirName),
+				zap.String("error", err.Error()),
+			)
+			return fmt.Errorf("%w: migration to version %d: %w", sharedErrors.ErrInternalError, migration.Version, err)
+		}
+		// версия сохраняется после каждой миграции: после сбоя выполняются только оставшиеся
+		err = s.saveManifest(migration.Version)
+		if err != nil {
+			return err
+		}
+		s.logger.Info("vault files migrated", zap.Int("version", migration.Version), zap.String("description", migration.Description))
+	}
+	return nil
+}
+
+func (s *MigrationService) Pending() (bool, error) {
+	vaultManifest, err := s.manifestRepository.Get()
+	if err != nil {
+		if !errors.Is(err, manifest.ErrManifestNotFound) {
+			return false, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+		}
+		// без манифеста файлы записаны версией до миграций, если они есть
+		exists, err := s.vaultExists()
+		if err != nil {
+			return false, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+		}
+		return exists, nil
+	}
+	currentVersion := s.currentVersion()
+	if vaultManifest.FormatVersion > currentVersion {
+		return false, fmt.Errorf("%w: files version %d, supported %d", vaultErrors.ErrFormatVersionNotSupported, vaultManifest.FormatVersion, currentVersion)
+	}
+	return vaultManifest.FormatVersion < currentVersion, nil
+}
+
+func (s *MigrationService) ClearBackups(ctx context.Context) error {
+	// копия не удаляется, пока другой процесс делает ее перед миграцией
+	unlock, err := s.manifestRepository.Lock(ctx)
+	if err != nil {
+		if errors.Is(err, sharedErrors.ErrVaultBusy) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	defer func() {
+		err := unlock()
+		if err != nil {
+			s.logger.Error("unlock manifest error", zap.String("error", err.Error()))
+		}
+	}()
+
+	err = os.RemoveAll(s.backupDirName)
+	if err != nil {
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	return nil
+}
+
+func (s *MigrationService) currentVersion() int {
+	if len(s.migrations) == 0 {
+		return 0
+	}
+	return s.migrations[len(s.migrations)-1].Version
+}
+
+func (s *MigrationService) saveManifest(version int) error {
+	err := s.manifestRepository.Save(entity.Manifest{FormatVersion: version, UpdatedAt: s.now().UTC()})
+	if err != nil {
+		s.logger.Error("save manifest error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	return nil
+}
+
+func (s *MigrationService) vaultExists() (bool, error) {
+	for _, vaultPath := range s.vaultPaths {
+		_, err := os.Stat(vaultPath)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// backup копирует файлы хранилища в отдельный каталог. Записи в файлах зашифрованы, ключ для копии не нужен
+func (s *MigrationService) backup(fromVersion int) (string, error) {
+	backupDirName := filepath.Join(s.backupDirName, fmt.Sprintf("format-v%d-%s", fromVersion, s.now().UTC().Format("20060102T150405Z")))
+	for _, vaultPath := range s.vaultPaths {
+		err := filepath.WalkDir(vaultPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() || !d.Type().IsRegular() || isSkippedFile(path) {
+				return nil
+			}
+			relativePath, err := filepath.Rel(s.dataDirName, path)
+			if err != nil {
+				return err
+			}
+			return copyFile(path, filepath.Join(backupDirName, relativePath))
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return backupDirName, nil
+}
+
+func isSkippedFile(fileName string) bool {
+	for _, suffix := range skippedFileSuffixes {
+		if strings.HasSuffix(fileName, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func copyFile(srcFileName string, dstFileName string) error {
+	err := os.MkdirAll(filepath.Dir(dstFileName), 0700)
+	if err != nil {
+		return err
+	}
+	src, err := os.Open(srcFileName)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(dstFileName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	if err == nil {
+		err = dst.Sync()
+	}
+	closeErr := dst.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
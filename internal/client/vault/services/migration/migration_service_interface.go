@@ -0,0 +1,16 @@
+package migration
+
+import "context"
+
+//go:generate mockgen -source=migration_service_interface.go -destination=mock_migration_service/mock_migration_service.go -package=mock_migration_service
+type MigrationServiceInterface interface {
+	// Migrate переводит локальные файлы на текущую версию формата, перед миграцией файлы копируются.
+	// Миграции, которым нужен ключ заблокированного хранилища, откладываются до вызова после login.
+	// ErrFormatVersionNotSupported, если файлы записаны более новой версией клиента
+	Migrate(ctx context.Context) error
+	// Pending файлы есть и записаны в формате старше текущего. Файлы не меняются
+	Pending() (bool, error)
+	// ClearBackups удаляет копии файлов, сделанные перед миграциями. В копиях остаются записи старых форматов,
+	// поэтому они удаляются, когда login перевел все записи на текущий формат
+	ClearBackups(ctx context.Context) error
+}
@@ -0,0 +1,301 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anoriar/gophkeeper/internal/client/shared/app/logger"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	vaultErrors "github.com/anoriar/gophkeeper/internal/client/vault/errors"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/manifest"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/manifest/mock_manifest_repository"
+)
+
+var testMigrationNow = time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+func TestMigrationService_Migrate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manifestRepositoryMock := mock_manifest_repository.NewMockManifestRepositoryInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	var applied []int
+	migrationErr := errors.New("migration error")
+	newMigrations := func(failVersion int, failErr error) []Migration {
+		migrations := make([]Migration, 0, 3)
+		for version := 1; version <= 3; version++ {
+			version := version
+			migrations = append(migrations, Migration{
+				Version: version,
+				Migrate: func(ctx context.Context) error {
+					if version == failVersion {
+						return failErr
+					}
+					applied = append(applied, version)
+					return nil
+				},
+			})
+		}
+		return migrations
+	}
+	unlock := func() error { return nil }
+
+	tests := []struct {
+		name          string
+		vaultExists   bool
+		failVersion   int
+		failErr       error
+		mockBehaviour func()
+		wantApplied   []int
+		wantBackup    bool
+		wantErr       error
+	}{
+		{
+			name: "success new vault without migrations",
+			mockBehaviour: func() {
+				manifestRepositoryMock.EXPECT().Lock(gomock.Any()).Return(unlock, nil)
+				manifestRepositoryMock.EXPECT().Get().Return(entity.Manifest{}, manifest.ErrManifestNotFound)
+				manifestRepositoryMock.EXPECT().Save(entity.Manifest{FormatVersion: 3, UpdatedAt: testMigrationNow}).Return(nil)
+			},
+		},
+		{
+			name:        "success vault without manifest migrated from version 0",
+			vaultExists: true,
+			mockBehaviour: func() {
+				manifestRepositoryMock.EXPECT().Lock(gomock.Any()).Return(unlock, nil)
+				manifestRepositoryMock.EXPECT().Get().Return(entity.Manifest{}, manifest.ErrManifestNotFound)
+				for version := 1; version <= 3; version++ {
+					manifestRepositoryMock.EXPECT().Save(entity.Manifest{FormatVersion: version, UpdatedAt: testMigrationNow}).Return(nil)
+				}
+			},
+			wantApplied: []int{1, 2, 3},
+			wantBackup:  true,
+		},
+		{
+			name:        "success only pending migrations",
+			vaultExists: true,
+			mockBehaviour: func() {
+				manifestRepositoryMock.EXPECT().Lock(gomock.Any()).Return(unlock, nil)
+				manifestRepositoryMock.EXPECT().Get().Return(entity.Manifest{FormatVersion: 2}, nil)
+				manifestRepositoryMock.EXPECT().Save(entity.Manifest{FormatVersion: 3, UpdatedAt: testMigrationNow}).Return(nil)
+			},
+			wantApplied: []int{3},
+			wantBackup:  true,
+		},
+		{
+			name:        "success current version",
+			vaultExists: true,
+			mockBehaviour: func() {
+				manifestRepositoryMock.EXPECT().Lock(gomock.Any()).Return(unlock, nil)
+				manifestRepositoryMock.EXPECT().Get().Return(entity.Manifest{FormatVersion: 3}, nil)
+			},
+		},
+		{
+			name:        "newer version error",
+			vaultExists: true,
+			mockBehaviour: func() {
+				manifestRepositoryMock.EXPECT().Lock(gomock.Any()).Return(unlock, nil)
+				manifestRepositoryMock.EXPECT().Get().Return(entity.Manifest{FormatVersion: 4}, nil)
+			},
+			wantErr: vaultErrors.ErrFormatVersionNotSupported,
+		},
+		{
+			name:        "migration error keeps completed version",
+			vaultExists: true,
+			failVersion: 2,
+			failErr:     migrationErr,
+			mockBehaviour: func() {
+				manifestRepositoryMock.EXPECT().Lock(gomock.Any()).Return(unlock, nil)
+				manifestRepositoryMock.EXPECT().Get().Return(entity.Manifest{}, manifest.ErrManifestNotFound)
+				manifestRepositoryMock.EXPECT().Save(entity.Manifest{FormatVersion: 1, UpdatedAt: testMigrationNow}).Return(nil)
+			},
+			wantApplied: []int{1},
+			wantBackup:  true,
+			wantErr:     migrationErr,
+		},
+		{
+			name:        "migration with locked vault continues after login",
+			vaultExists: true,
+			failVersion: 3,
+			failErr:     secret.ErrVaultLocked,
+			mockBehaviour: func() {
+				manifestRepositoryMock.EXPECT().Lock(gomock.Any()).Return(unlock, nil)
+				manifestRepositoryMock.EXPECT().Get().Return(entity.Manifest{FormatVersion: 1}, nil)
+				manifestRepositoryMock.EXPECT().Save(entity.Manifest{FormatVersion: 2, UpdatedAt: testMigrationNow}).Return(nil)
+			},
+			wantApplied: []int{2},
+			wantBackup:  true,
+		},
+		{
+			name: "vault busy error",
+			mockBehaviour: func() {
+				manifestRepositoryMock.EXPECT().Lock(gomock.Any()).Return(nil, sharedErrors.ErrVaultBusy)
+			},
+			wantErr: sharedErrors.ErrVaultBusy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataDirName := t.TempDir()
+			entriesDirName := filepath.Join(dataDirName, "entries")
+			vaultFileName := filepath.Join(dataDirName, "secret", "vault.json")
+			backupDirName := filepath.Join(dataDirName, "backups")
+			if tt.vaultExists {
+				require.NoError(t, os.MkdirAll(entriesDirName, 0700))
+				require.NoError(t, os.WriteFile(filepath.Join(entriesDirName, "logins"), []byte("entries"), 0600))
+				require.NoError(t, os.WriteFile(filepath.Join(entriesDirName, "logins.lock"), nil, 0600))
+			}
+			applied = nil
+
+			tt.mockBehaviour()
+			s := NewMigrationService(manifestRepositoryMock, newMigrations(tt.failVersion, tt.failErr), dataDirName, []string{entriesDirName, vaultFileName}, backupDirName, loggerMock)
+			s.now = func() time.Time { return testMigrationNow }
+			err := s.Migrate(context.Background())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantApplied, applied)
+
+			backups, _ := filepath.Glob(filepath.Join(backupDirName, "format-v*", "entries", "*"))
+			if !tt.wantBackup {
+				assert.Empty(t, backups)
+				return
+			}
+			// копируются только файлы данных, без блокировок
+			require.Len(t, backups, 1)
+			content, err := os.ReadFile(backups[0])
+			require.NoError(t, err)
+			assert.Equal(t, "entries", string(content))
+		})
+	}
+}
+
+func TestMigrationService_Pending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manifestRepositoryMock := mock_manifest_repository.NewMockManifestRepositoryInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	migrations := []Migration{{Version: 1}, {Version: 2}}
+
+	tests := []struct {
+		name          string
+		vaultExists   bool
+		mockBehaviour func()
+		want          bool
+		wantErr       error
+	}{
+		{
+			name: "new vault",
+			mockBehaviour: func() {
+				manifestRepositoryMock.EXPECT().Get().Return(entity.Manifest{}, manifest.ErrManifestNotFound)
+			},
+			want: false,
+		},
+		{
+			name:        "vault without manifest",
+			vaultExists: true,
+			mockBehaviour: func() {
+				manifestRepositoryMock.EXPECT().Get().Return(entity.Manifest{}, manifest.ErrManifestNotFound)
+			},
+			want: true,
+		},
+		{
+			name: "old version",
+			mockBehaviour: func() {
+				manifestRepositoryMock.EXPECT().Get().Return(entity.Manifest{FormatVersion: 1}, nil)
+			},
+			want: true,
+		},
+		{
+			name: "current version",
+			mockBehaviour: func() {
+				manifestRepositoryMock.EXPECT().Get().Return(entity.Manifest{FormatVersion: 2}, nil)
+			},
+			want: false,
+		},
+		{
+			name: "newer version error",
+			mockBehaviour: func() {
+				manifestRepositoryMock.EXPECT().Get().Return(entity.Manifest{FormatVersion: 3}, nil)
+			},
+			wantErr: vaultErrors.ErrFormatVersionNotSupported,
+		},
+		{
+			name: "get manifest error",
+			mockBehaviour: func() {
+				manifestRepositoryMock.EXPECT().Get().Return(entity.Manifest{}, errors.New("error"))
+			},
+			wantErr: sharedErrors.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataDirName := t.TempDir()
+			entriesDirName := filepath.Join(dataDirName, "entries")
+			if tt.vaultExists {
+				require.NoError(t, os.MkdirAll(entriesDirName, 0700))
+			}
+
+			tt.mockBehaviour()
+			s := NewMigrationService(manifestRepositoryMock, migrations, dataDirName, []string{entriesDirName}, filepath.Join(dataDirName, "backups"), loggerMock)
+			got, err := s.Pending()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMigrationService_ClearBackups(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manifestRepositoryMock := mock_manifest_repository.NewMockManifestRepositoryInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	dataDirName := t.TempDir()
+	entriesDirName := filepath.Join(dataDirName, "entries")
+	backupDirName := filepath.Join(dataDirName, "backups")
+	require.NoError(t, os.MkdirAll(entriesDirName, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(entriesDirName, "logins"), []byte("entries"), 0600))
+
+	manifestRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
+	manifestRepositoryMock.EXPECT().Get().Return(entity.Manifest{}, manifest.ErrManifestNotFound)
+	manifestRepositoryMock.EXPECT().Save(gomock.Any()).Return(nil)
+	s := NewMigrationService(manifestRepositoryMock, []Migration{{Version: 1, Migrate: func(ctx context.Context) error { return nil }}}, dataDirName, []string{entriesDirName}, backupDirName, loggerMock)
+	require.NoError(t, s.Migrate(context.Background()))
+	backups, _ := filepath.Glob(filepath.Join(backupDirName, "format-v*"))
+	require.Len(t, backups, 1)
+
+	manifestRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
+	require.NoError(t, s.ClearBackups(context.Background()))
+	_, err = os.Stat(backupDirName)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	// файлы хранилища не затрагиваются
+	_, err = os.Stat(filepath.Join(entriesDirName, "logins"))
+	assert.NoError(t, err)
+
+	manifestRepositoryMock.EXPECT().Lock(gomock.Any()).Return(nil, sharedErrors.ErrVaultBusy)
+	assert.ErrorIs(t, s.ClearBackups(context.Background()), sharedErrors.ErrVaultBusy)
+}
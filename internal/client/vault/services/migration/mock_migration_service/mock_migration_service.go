@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: migration_service_interface.go
+
+// Package mock_migration_service is a generated GoMock package.
+package mock_migration_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMigrationServiceInterface is a mock of MigrationServiceInterface interface.
+type MockMigrationServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockMigrationServiceInterfaceMockRecorder
+}
+
+// MockMigrationServiceInterfaceMockRecorder is the mock recorder for MockMigrationServiceInterface.
+type MockMigrationServiceInterfaceMockRecorder struct {
+	mock *MockMigrationServiceInterface
+}
+
+// NewMockMigrationServiceInterface creates a new mock instance.
+func NewMockMigrationServiceInterface(ctrl *gomock.Controller) *MockMigrationServiceInterface {
+	mock := &MockMigrationServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockMigrationServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMigrationServiceInterface) EXPECT() *MockMigrationServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ClearBackups mocks base method.
+func (m *MockMigrationServiceInterface) ClearBackups(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearBackups", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearBackups indicates an expected call of ClearBackups.
+func (mr *MockMigrationServiceInterfaceMockRecorder) ClearBackups(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearBackups", reflect.TypeOf((*MockMigrationServiceInterface)(nil).ClearBackups), ctx)
+}
+
+// Migrate mocks base method.
+func (m *MockMigrationServiceInterface) Migrate(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Migrate", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Migrate indicates an expected call of Migrate.
+func (mr *MockMigrationServiceInterfaceMockRecorder) Migrate(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Migrate", reflect.TypeOf((*MockMigrationServiceInterface)(nil).Migrate), ctx)
+}
+
+// Pending mocks base method.
+func (m *MockMigrationServiceInterface) Pending() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pending")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pending indicates an expected call of Pending.
+func (mr *MockMigrationServiceInterfaceMockRecorder) Pending() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pending", reflect.TypeOf((*MockMigrationServiceInterface)(nil).Pending))
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: reencrypt_service_interface.go
+
+// Package mock_reencrypt_service is a generated GoMock package.
+package mock_reencrypt_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReencryptServiceInterface is a mock of ReencryptServiceInterface interface.
+type MockReencryptServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockReencryptServiceInterfaceMockRecorder
+}
+
+// MockReencryptServiceInterfaceMockRecorder is the mock recorder for MockReencryptServiceInterface.
+type MockReencryptServiceInterfaceMockRecorder struct {
+	mock *MockReencryptServiceInterface
+}
+
+// NewMockReencryptServiceInterface creates a new mock instance.
+func NewMockReencryptServiceInterface(ctrl *gomock.Controller) *MockReencryptServiceInterface {
+	mock := &MockReencryptServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockReencryptServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReencryptServiceInterface) EXPECT() *MockReencryptServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Reencrypt mocks base method.
+func (m *MockReencryptServiceInterface) Reencrypt(ctx context.Context, keyring entity.Keyring) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reencrypt", ctx, keyring)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reencrypt indicates an expected call of Reencrypt.
+func (mr *MockReencryptServiceInterfaceMockRecorder) Reencrypt(ctx, keyring interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reencrypt", reflect.TypeOf((*MockReencryptServiceInterface)(nil).Reencrypt), ctx, keyring)
+}
@@ -0,0 +1,94 @@
+package reencrypt
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	entryRepository "github.com/anoriar/gophkeeper/internal/client/entry/repository/entry"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/encoder"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+type ReencryptService struct {
+	entryRepositories []entryRepository.EntryRepositoryInterface
+	encoder           encoder.DataEncryptorInterface
+	logger            *zap.Logger
+}
+
+func NewReencryptService(
+	entryRepositories []entryRepository.EntryRepositoryInterface,
+	encoder encoder.DataEncryptorInterface,
+	logger *zap.Logger,
+) *ReencryptService {
+	return &ReencryptService{
+		entryRepositories: entryRepositories,
+		encoder:           encoder,
+		logger:            logger,
+	}
+}
+
+// Reencrypt записи, которые не расшифровываются ключами keyring, остаются как есть: их id возвращаются,
+// остальные записи перешифровываются
+func (s *ReencryptService) Reencrypt(ctx context.Context, keyring entity.Keyring) ([]string, error) {
+	var skipped []string
+	for _, repository := range s.entryRepositories {
+		repositorySkipped, err := s.reencryptRepository(ctx, repository, keyring)
+		skipped = append(skipped, repositorySkipped...)
+		if err != nil {
+			return skipped, err
+		}
+	}
+	return skipped, nil
+}
+
+func (s *ReencryptService) reencryptRepository(ctx context.Context, repository entryRepository.EntryRepositoryInterface, keyring entity.Keyring) (skipped []string, err error) {
+	unlock, err := repository.Lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("lock entries error: %w", err)
+	}
+	defer func() {
+		unlockErr := unlock()
+		if err == nil && unlockErr != nil {
+			err = fmt.Errorf("unlock entries error: %w", unlockErr)
+		}
+	}()
+
+	entries, err := repository.GetList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get entries error: %w", err)
+	}
+
+	reencrypted := 0
+	for i := range entries {
+		if !encoder.EntryNeedsReencrypt(s.encoder, entries[i], keyring) {
+			continue
+		}
+		decrypted, err := encoder.DecryptEntry(s.encoder, entries[i], keyring)
+		if err != nil {
+			s.logger.Warn("entry is not reencrypted", zap.String("id", entries[i].Id), zap.String("error", err.Error()))
+			skipped = append(skipped, entries[i].Id)
+			continue
+		}
+		encrypted, err := encoder.EncryptEntry(s.encoder, decrypted, keyring)
+		if err != nil {
+			return skipped, fmt.Errorf("encrypt entry %s error: %w", entries[i].Id, err)
+		}
+		// новый шифротекст должен уйти на сервер при следующей синхронизации
+		encrypted.UpdatedAt = time.Now()
+		entries[i] = encrypted
+		reencrypted++
+	}
+
+	if reencrypted == 0 {
+		return skipped, nil
+	}
+	err = repository.Rewrite(ctx, entries)
+	if err != nil {
+		return skipped, fmt.Errorf("rewrite entries error: %w", err)
+	}
+	s.logger.Info("entries reencrypted", zap.Int("count", reencrypted))
+	return skipped, nil
+}
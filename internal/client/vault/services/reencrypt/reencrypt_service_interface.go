@@ -0,0 +1,14 @@
+package reencrypt
+
+import (
+	"context"
+
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+//go:generate mockgen -source=reencrypt_service_interface.go -destination=mock_reencrypt_service/mock_reencrypt_service.go -package=mock_reencrypt_service
+type ReencryptServiceInterface interface {
+	// Reencrypt перешифровывает текущим ключом записи, зашифрованные другим ключом или в устаревшем формате.
+	// Возвращает id записей, которые не удалось расшифровать: они остаются как есть
+	Reencrypt(ctx context.Context, keyring entity.Keyring) ([]string, error)
+}
@@ -0,0 +1,145 @@
+package reencrypt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	entryRepository "github.com/anoriar/gophkeeper/internal/client/entry/repository/entry"
+	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry/mock_entry_repository"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/encoder"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/encoder/mock_data_encryptor"
+	"github.com/anoriar/gophkeeper/internal/client/shared/app/logger"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	vaultEntity "github.com/anoriar/gophkeeper/internal/client/vault/entity"
+)
+
+func TestReencryptService_Reencrypt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	encryptorMock := mock_data_encryptor.NewMockDataEncryptorInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	keyring := vaultEntity.Keyring{CurrentKeyId: "0102030405060708"}
+	updatedAt := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+	legacyAd := encoder.AssociatedData{EntryId: "1", EntryType: enum.Login}
+
+	tests := []struct {
+		name          string
+		mockBehaviour func()
+		wantSkipped   []string
+		wantErr       error
+	}{
+		{
+			name: "success",
+			mockBehaviour: func() {
+				entries := []entity.Entry{
+					{Id: "1", EntryType: enum.Login, UpdatedAt: updatedAt, Data: []byte("legacy")},
+					{Id: "2", EntryType: enum.Login, UpdatedAt: updatedAt, Data: []byte("actual")},
+				}
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
+				entryRepositoryMock.EXPECT().GetList(gomock.Any()).Return(entries, nil)
+				encryptorMock.EXPECT().NeedsReencrypt([]byte("legacy"), keyring).Return(true)
+				encryptorMock.EXPECT().NeedsReencrypt([]byte("actual"), keyring).Return(false)
+				encryptorMock.EXPECT().Decrypt([]byte("legacy"), legacyAd, keyring).Return([]byte("data"), nil)
+				encryptorMock.EXPECT().Encrypt([]byte("data"), legacyAd, keyring).Return([]byte("reencrypted"), nil)
+				entryRepositoryMock.EXPECT().Rewrite(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entries []entity.Entry) error {
+					assert.Equal(t, []byte("reencrypted"), entries[0].Data)
+					assert.True(t, entries[0].UpdatedAt.After(updatedAt))
+					assert.Equal(t, []byte("actual"), entries[1].Data)
+					assert.Equal(t, updatedAt, entries[1].UpdatedAt)
+					return nil
+				})
+			},
+		},
+		{
+			name: "plain meta",
+			mockBehaviour: func() {
+				entries := []entity.Entry{
+					{Id: "1", EntryType: enum.Login, UpdatedAt: updatedAt, Data: []byte("actual"), PlainMeta: []byte("{}")},
+				}
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
+				entryRepositoryMock.EXPECT().GetList(gomock.Any()).Return(entries, nil)
+				encryptorMock.EXPECT().Decrypt([]byte("actual"), legacyAd, keyring).Return([]byte("data"), nil)
+				encryptorMock.EXPECT().Encrypt([]byte("data"), legacyAd, keyring).Return([]byte("reencrypted"), nil)
+				encryptorMock.EXPECT().Encrypt([]byte("{}"), encoder.NewMetaAssociatedData(entries[0]), keyring).Return([]byte("encrypted meta"), nil)
+				entryRepositoryMock.EXPECT().Rewrite(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entries []entity.Entry) error {
+					assert.Equal(t, []byte("encrypted meta"), entries[0].Meta)
+					assert.Nil(t, entries[0].PlainMeta)
+					return nil
+				})
+			},
+		},
+		{
+			name: "nothing to reencrypt",
+			mockBehaviour: func() {
+				entries := []entity.Entry{{Id: "2", EntryType: enum.Login, UpdatedAt: updatedAt, Data: []byte("actual")}}
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
+				entryRepositoryMock.EXPECT().GetList(gomock.Any()).Return(entries, nil)
+				encryptorMock.EXPECT().NeedsReencrypt([]byte("actual"), keyring).Return(false)
+			},
+		},
+		{
+			name: "undecryptable entry skipped",
+			mockBehaviour: func() {
+				entries := []entity.Entry{
+					{Id: "1", EntryType: enum.Login, UpdatedAt: updatedAt, Data: []byte("legacy")},
+					{Id: "3", EntryType: enum.Login, UpdatedAt: updatedAt, Data: []byte("foreign")},
+				}
+				foreignAd := encoder.AssociatedData{EntryId: "3", EntryType: enum.Login}
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
+				entryRepositoryMock.EXPECT().GetList(gomock.Any()).Return(entries, nil)
+				encryptorMock.EXPECT().NeedsReencrypt([]byte("legacy"), keyring).Return(true)
+				encryptorMock.EXPECT().NeedsReencrypt([]byte("foreign"), keyring).Return(true)
+				encryptorMock.EXPECT().Decrypt([]byte("legacy"), legacyAd, keyring).Return([]byte("data"), nil)
+				encryptorMock.EXPECT().Encrypt([]byte("data"), legacyAd, keyring).Return([]byte("reencrypted"), nil)
+				encryptorMock.EXPECT().Decrypt([]byte("foreign"), foreignAd, keyring).Return(nil, sharedErrors.ErrWrongMasterPassword)
+				entryRepositoryMock.EXPECT().Rewrite(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entries []entity.Entry) error {
+					assert.Equal(t, []byte("reencrypted"), entries[0].Data)
+					assert.Equal(t, []byte("foreign"), entries[1].Data)
+					return nil
+				})
+			},
+			wantSkipped: []string{"3"},
+		},
+		{
+			name: "rewrite error",
+			mockBehaviour: func() {
+				entries := []entity.Entry{{Id: "1", EntryType: enum.Login, UpdatedAt: updatedAt, Data: []byte("legacy")}}
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
+				entryRepositoryMock.EXPECT().GetList(gomock.Any()).Return(entries, nil)
+				encryptorMock.EXPECT().NeedsReencrypt([]byte("legacy"), keyring).Return(true)
+				encryptorMock.EXPECT().Decrypt([]byte("legacy"), legacyAd, keyring).Return([]byte("data"), nil)
+				encryptorMock.EXPECT().Encrypt([]byte("data"), legacyAd, keyring).Return([]byte("reencrypted"), nil)
+				entryRepositoryMock.EXPECT().Rewrite(gomock.Any(), gomock.Any()).Return(sharedErrors.ErrInternalError)
+			},
+			wantErr: sharedErrors.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour()
+			s := NewReencryptService(
+				[]entryRepository.EntryRepositoryInterface{entryRepositoryMock},
+				encryptorMock,
+				loggerMock,
+			)
+			skipped, err := s.Reencrypt(context.Background(), keyring)
+			assert.Equal(t, tt.wantSkipped, skipped)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: rekey_service_interface.go
+
+// Package mock_rekey_service is a generated GoMock package.
+package mock_rekey_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	command "github.com/anoriar/gophkeeper/internal/client/vault/dto/command"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRekeyServiceInterface is a mock of RekeyServiceInterface interface.
+type MockRekeyServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRekeyServiceInterfaceMockRecorder
+}
+
+// MockRekeyServiceInterfaceMockRecorder is the mock recorder for MockRekeyServiceInterface.
+type MockRekeyServiceInterfaceMockRecorder struct {
+	mock *MockRekeyServiceInterface
+}
+
+// NewMockRekeyServiceInterface creates a new mock instance.
+func NewMockRekeyServiceInterface(ctrl *gomock.Controller) *MockRekeyServiceInterface {
+	mock := &MockRekeyServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockRekeyServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRekeyServiceInterface) EXPECT() *MockRekeyServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Recover mocks base method.
+func (m *MockRekeyServiceInterface) Recover(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recover", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recover indicates an expected call of Recover.
+func (mr *MockRekeyServiceInterfaceMockRecorder) Recover(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recover", reflect.TypeOf((*MockRekeyServiceInterface)(nil).Recover), ctx)
+}
+
+// Rekey mocks base method.
+func (m *MockRekeyServiceInterface) Rekey(ctx context.Context, command command.RekeyCommand) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rekey", ctx, command)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rekey indicates an expected call of Rekey.
+func (mr *MockRekeyServiceInterfaceMockRecorder) Rekey(ctx, command interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rekey", reflect.TypeOf((*MockRekeyServiceInterface)(nil).Rekey), ctx, command)
+}
@@ -0,0 +1,351 @@
+package rekey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	entryEntity "github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	entryRepository "github.com/anoriar/gophkeeper/internal/client/entry/repository/entry"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/encoder"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret"
+	"github.com/anoriar/gophkeeper/internal/client/vault/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/rollback"
+	vaultRepository "github.com/anoriar/gophkeeper/internal/client/vault/repository/vault"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/keyring"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/vaultsync"
+)
+
+type RekeyService struct {
+	entryRepositories map[enum.EntryType]entryRepository.EntryRepositoryInterface
+	// historyRepositories ревизии записей перешифровываются вместе с записями
+	historyRepositories map[enum.EntryType]entryRepository.EntryHistoryRepositoryInterface
+	// entryStore запечатывается ключом нового хранилища, если было создано без ключа
+	entryStore         entryRepository.EntryStoreInterface
+	vaultRepository    vaultRepository.VaultRepositoryInterface
+	rollbackRepository rollback.RollbackRepositoryInterface
+	secretRepository   secret.SecretRepositoryInterface
+	keyringService     keyring.KeyringServiceInterface
+	vaultSyncService   vaultsync.VaultSyncServiceInterface
+	encoder            encoder.DataEncryptorInterface
+	logger             *zap.Logger
+}
+
+func NewRekeyService(
+	entryRepositories map[enum.EntryType]entryRepository.EntryRepositoryInterface,
+	historyRepositories map[enum.EntryType]entryRepository.EntryHistoryRepositoryInterface,
+	entryStore entryRepository.EntryStoreInterface,
+	vaultRepository vaultRepository.VaultRepositoryInterface,
+	rollbackRepository rollback.RollbackRepositoryInterface,
+	secretRepository secret.SecretRepositoryInterface,
+	keyringService keyring.KeyringServiceInterface,
+	vaultSyncService vaultsync.VaultSyncServiceInterface,
+	encoder encoder.DataEncryptorInterface,
+	logger *zap.Logger,
+) *RekeyService {
+	return &RekeyService{
+		entryRepositories:   entryRepositories,
+		historyRepositories: historyRepositories,
+		entryStore:          entryStore,
+		vaultRepository:     vaultRepository,
+		rollbackRepository:  rollbackRepository,
+		secretRepository:    secretRepository,
+		keyringService:      keyringService,
+		vaultSyncService:    vaultSyncService,
+		encoder:             encoder,
+		logger:              logger,
+	}
+}
+
+func (s *RekeyService) Rekey(ctx context.Context, command command.RekeyCommand) error {
+	// другие процессы клиента не должны писать записи между чтением и перезаписью хранилища
+	unlock, err := s.lockEntryRepositories(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	oldKeyring, err := s.unlockOld(command.OldMasterPassword)
+	if err != nil {
+		return err
+	}
+	recovered, err := s.Recover(ctx)
+	if err != nil {
+		return err
+	}
+	if recovered {
+		// хранилище восстановлено из отката, ключи старого пароля нужно получить заново
+		oldKeyring, err = s.unlockOld(command.OldMasterPassword)
+		if err != nil {
+			return err
+		}
+	}
+
+	oldVault, err := s.vaultRepository.Get()
+	if err != nil {
+		s.logger.Error("get vault error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	// ключ локального хранилища не меняется: его запечатывание не зависит от того, успеет ли rekey перезаписать записи.
+	// Другие устройства берут новый слот текущим после sync и открывают им свое хранилище по цепочке ключей
+	newVault, newKeyring, err := s.keyringService.RekeyVault(command.NewMasterPassword, oldVault, oldKeyring)
+	if err != nil {
+		s.logger.Error("create vault error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+
+	// все записи расшифровываются до первой записи на диск: неверный старый пароль ничего не ломает
+	rollbackState := entity.RekeyRollback{
+		Vault:   oldVault,
+		Entries: make(map[enum.EntryType][]entryEntity.Entry, len(s.entryRepositories)),
+		History: make(map[enum.EntryType][]entryEntity.Entry, len(s.historyRepositories)),
+	}
+	reencryptedEntries := make(map[enum.EntryType][]entryEntity.Entry, len(s.entryRepositories))
+	reencryptedHistory := make(map[enum.EntryType][]entryEntity.Entry, len(s.historyRepositories))
+	for _, entryType := range enum.AllEntryTypes {
+		repository, ok := s.entryRepositories[entryType]
+		if !ok {
+			continue
+		}
+		entries, err := repository.GetList(ctx)
+		if err != nil {
+			s.logger.Error("get entries error", zap.String("error", err.Error()))
+			return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+		}
+		rollbackState.Entries[entryType] = entries
+
+		reencryptedEntries[entryType], err = s.reencryptEntries(entries, oldKeyring, newKeyring)
+		if err != nil {
+			return err
+		}
+
+		historyRepository, ok := s.historyRepositories[entryType]
+		if !ok {
+			continue
+		}
+		revisions, err := historyRepository.GetAll(ctx)
+		if err != nil {
+			s.logger.Error("get history error", zap.String("error", err.Error()))
+			return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+		}
+		rollbackState.History[entryType] = revisions
+
+		reencryptedHistory[entryType], err = s.reencryptRevisions(revisions, oldKeyring, newKeyring)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.rollbackRepository.Save(rollbackState)
+	if err != nil {
+		s.logger.Error("save rollback error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+
+	err = s.apply(ctx, newVault, reencryptedEntries, reencryptedHistory)
+	if err != nil {
+		s.logger.Error("rekey error", zap.String("error", err.Error()))
+		_, recoverErr := s.Recover(ctx)
+		if recoverErr != nil {
+			s.logger.Error("rekey rollback error", zap.String("error", recoverErr.Error()))
+		}
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+
+	err = s.rollbackRepository.Delete()
+	if err != nil {
+		s.logger.Error("delete rollback error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+
+	err = s.secretRepository.SaveKeyring(newKeyring)
+	if err != nil {
+		s.logger.Error("save keyring error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	// ключи старого мастер-пароля больше не нужны: хранилище записей берет ключ нового из сессии.
+	// Хранилище, созданное без ключа (слот без KeyCheck), запечатывается им сразу после перешифрования
+	s.keyringService.Lock()
+	err = s.entryStore.Seal()
+	if err != nil {
+		s.logger.Error("seal entry store error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+
+	s.pushVault(ctx)
+	return nil
+}
+
+// pushVault новый слот нужен другим устройствам, чтобы расшифровать перешифрованные записи после sync.
+// Без login или без связи с сервером слот отправится при следующем login
+func (s *RekeyService) pushVault(ctx context.Context) {
+	token, err := s.secretRepository.GetAuthToken()
+	if err != nil {
+		s.logger.Warn("new key slot is not sent to server", zap.String("error", err.Error()))
+		return
+	}
+	err = s.vaultSyncService.Push(ctx, token)
+	if err != nil {
+		s.logger.Warn("new key slot is not sent to server", zap.String("error", err.Error()))
+	}
+}
+
+func (s *RekeyService) Recover(ctx context.Context) (bool, error) {
+	rollbackState, err := s.rollbackRepository.Get()
+	if err != nil {
+		if errors.Is(err, rollback.ErrRollbackNotFound) {
+			return false, nil
+		}
+		s.logger.Error("get rollback error", zap.String("error", err.Error()))
+		return false, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+
+	// записи восстанавливаются в запечатанное хранилище, для этого нужен его ключ
+	_, err = s.keyringService.StoreKey()
+	if err != nil {
+		if errors.Is(err, secret.ErrVaultLocked) {
+			s.logger.Warn("previous rekey was interrupted, vault will be restored after login")
+			return false, nil
+		}
+		s.logger.Error("get store key error", zap.String("error", err.Error()))
+		return false, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+
+	s.logger.Warn("previous rekey was interrupted, restoring vault")
+	err = s.apply(ctx, rollbackState.Vault, rollbackState.Entries, rollbackState.History)
+	if err != nil {
+		s.logger.Error("restore vault error", zap.String("error", err.Error()))
+		return false, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+
+	err = s.rollbackRepository.Delete()
+	if err != nil {
+		s.logger.Error("delete rollback error", zap.String("error", err.Error()))
+		return false, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	return true, nil
+}
+
+func (s *RekeyService) unlockOld(oldMasterPassword string) (entity.Keyring, error) {
+	keyring, err := s.keyringService.Unlock(oldMasterPassword)
+	if err != nil {
+		if errors.Is(err, sharedErrors.ErrWrongMasterPassword) {
+			return entity.Keyring{}, fmt.Errorf("old master password: %w", err)
+		}
+		s.logger.Error("unlock vault error", zap.String("error", err.Error()))
+		return entity.Keyring{}, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+	}
+	return keyring, nil
+}
+
+func (s *RekeyService) lockEntryRepositories(ctx context.Context) (func(), error) {
+	unlocks := make([]func() error, 0, len(s.entryRepositories))
+	unlockAll := func() {
+		for _, unlock := range unlocks {
+			err := unlock()
+			if err != nil {
+				s.logger.Error("unlock entries error", zap.String("error", err.Error()))
+			}
+		}
+	}
+	for _, entryType := range enum.AllEntryTypes {
+		repository, ok := s.entryRepositories[entryType]
+		if !ok {
+			continue
+		}
+		unlock, err := repository.Lock(ctx)
+		if err != nil {
+			unlockAll()
+			if errors.Is(err, sharedErrors.ErrVaultBusy) {
+				return nil, err
+			}
+			s.logger.Error("lock entries error", zap.String("error", err.Error()))
+			return nil, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+		}
+		unlocks = append(unlocks, unlock)
+	}
+	return unlockAll, nil
+}
+
+func (s *RekeyService) reencryptEntries(entries []entryEntity.Entry, oldKeyring entity.Keyring, newKeyring entity.Keyring) ([]entryEntity.Entry, error) {
+	reencrypted := make([]entryEntity.Entry, 0, len(entries))
+	for _, entry := range entries {
+		decrypted, err := encoder.DecryptEntry(s.encoder, entry, oldKeyring)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt entry %s error, check old master password: %w", entry.Id, err)
+		}
+		encrypted, err := encoder.EncryptEntry(s.encoder, decrypted, newKeyring)
+		if err != nil {
+			s.logger.Error("encrypt entry error", zap.String("error", err.Error()))
+			return nil, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+		}
+		// новые шифротексты должны уйти на сервер при следующей синхронизации
+		encrypted.UpdatedAt = time.Now()
+		reencrypted = append(reencrypted, encrypted)
+	}
+	return reencrypted, nil
+}
+
+// reencryptRevisions ревизии не синхронизируются, поэтому время изменения у них прежнее.
+// Ревизию, которую не расшифровать ключами старого пароля, прочитать уже нельзя: она не переносится
+func (s *RekeyService) reencryptRevisions(revisions []entryEntity.Entry, oldKeyring entity.Keyring, newKeyring entity.Keyring) ([]entryEntity.Entry, error) {
+	reencrypted := make([]entryEntity.Entry, 0, len(revisions))
+	for _, revision := range revisions {
+		decrypted, err := encoder.DecryptEntry(s.encoder, revision, oldKeyring)
+		if err != nil {
+			s.logger.Warn("revision is not readable and is dropped", zap.String("id", revision.Id), zap.String("error", err.Error()))
+			continue
+		}
+		encrypted, err := encoder.EncryptEntry(s.encoder, decrypted, newKeyring)
+		if err != nil {
+			s.logger.Error("encrypt revision error", zap.String("error", err.Error()))
+			return nil, fmt.Errorf("%w: %w", sharedErrors.ErrInternalError, err)
+		}
+		reencrypted = append(reencrypted, encrypted)
+	}
+	return reencrypted, nil
+}
+
+// apply ревизии типа, которого нет в history, не трогаются: так восстанавливается откат без ревизий
+func (s *RekeyService) apply(
+	ctx context.Context,
+	vault entity.Vault,
+	entries map[enum.EntryType][]entryEntity.Entry,
+	history map[enum.EntryType][]entryEntity.Entry,
+) error {
+	err := s.vaultRepository.Save(vault)
+	if err != nil {
+		return fmt.Errorf("save vault error: %w", err)
+	}
+	for _, entryType := range enum.AllEntryTypes {
+		typeEntries, ok := entries[entryType]
+		if !ok {
+			continue
+		}
+		err = s.entryRepositories[entryType].Rewrite(ctx, typeEntries)
+		if err != nil {
+			return fmt.Errorf("rewrite %s entries error: %w", entryType, err)
+		}
+	}
+	for _, entryType := range enum.AllEntryTypes {
+		revisions, ok := history[entryType]
+		if !ok {
+			continue
+		}
+		historyRepository, ok := s.historyRepositories[entryType]
+		if !ok {
+			continue
+		}
+		err = historyRepository.Rewrite(ctx, revisions)
+		if err != nil {
+			return fmt.Errorf("rewrite %s history error: %w", entryType, err)
+		}
+	}
+	return nil
+}
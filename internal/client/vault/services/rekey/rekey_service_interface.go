@@ -0,0 +1,16 @@
+package rekey
+
+import (
+	"context"
+
+	"github.com/anoriar/gophkeeper/internal/client/vault/dto/command"
+)
+
+//go:generate mockgen -source=rekey_service_interface.go -destination=mock_rekey_service/mock_rekey_service.go -package=mock_rekey_service
+type RekeyServiceInterface interface {
+	// Rekey смена мастер-пароля: все записи перешифровываются ключом из нового пароля
+	Rekey(ctx context.Context, command command.RekeyCommand) error
+	// Recover откат смены мастер-пароля, прерванной до завершения. true - хранилище восстановлено из отката.
+	// Если хранилище заблокировано, откат откладывается до login
+	Recover(ctx context.Context) (bool, error)
+}
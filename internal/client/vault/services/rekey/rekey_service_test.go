@@ -0,0 +1,249 @@
+package rekey
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	entryEntity "github.com/anoriar/gophkeeper/internal/client/entry/entity"
+	"github.com/anoriar/gophkeeper/internal/client/entry/enum"
+	entryRepository "github.com/anoriar/gophkeeper/internal/client/entry/repository/entry"
+	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry/mock_entry_history_repository"
+	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry/mock_entry_repository"
+	"github.com/anoriar/gophkeeper/internal/client/entry/repository/entry/mock_entry_store"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/encoder"
+	"github.com/anoriar/gophkeeper/internal/client/entry/services/encoder/mock_data_encryptor"
+	"github.com/anoriar/gophkeeper/internal/client/shared/app/logger"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/client/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret"
+	"github.com/anoriar/gophkeeper/internal/client/user/repository/secret/mock_secret_repository"
+	"github.com/anoriar/gophkeeper/internal/client/vault/dto/command"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/rollback"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/rollback/mock_rollback_repository"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/vault/mock_vault_repository"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/keyring/mock_keyring_service"
+	"github.com/anoriar/gophkeeper/internal/client/vault/services/vaultsync/mock_vault_sync_service"
+)
+
+func TestRekeyService_Rekey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	entryStoreMock := mock_entry_store.NewMockEntryStoreInterface(ctrl)
+	historyRepositoryMock := mock_entry_history_repository.NewMockEntryHistoryRepositoryInterface(ctrl)
+	vaultRepositoryMock := mock_vault_repository.NewMockVaultRepositoryInterface(ctrl)
+	rollbackRepositoryMock := mock_rollback_repository.NewMockRollbackRepositoryInterface(ctrl)
+	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
+	keyringServiceMock := mock_keyring_service.NewMockKeyringServiceInterface(ctrl)
+	vaultSyncServiceMock := mock_vault_sync_service.NewMockVaultSyncServiceInterface(ctrl)
+	encryptorMock := mock_data_encryptor.NewMockDataEncryptorInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	cmd := command.RekeyCommand{OldMasterPassword: "old", NewMasterPassword: "new"}
+	oldVault := entity.Vault{CurrentKeyId: "0102030405060708"}
+	// RekeyVault отмечает, что старых форматов в новом хранилище нет
+	newVault := entity.Vault{CurrentKeyId: "0807060504030201", LegacyMigrated: true}
+	oldKeyring := entity.Keyring{CurrentKeyId: oldVault.CurrentKeyId, StoreKey: []byte("store key")}
+	newKeyring := entity.Keyring{CurrentKeyId: newVault.CurrentKeyId, StoreKey: []byte("store key"), RejectLegacy: true}
+	entries := []entryEntity.Entry{{Id: "1", EntryType: enum.Login, Data: []byte("old encrypted")}}
+	ad := encoder.AssociatedData{EntryId: "1", EntryType: enum.Login}
+	revisionTime := time.Date(2024, time.April, 1, 12, 0, 0, 0, time.UTC)
+	revisions := []entryEntity.Entry{
+		{Id: "1", EntryType: enum.Login, UpdatedAt: revisionTime, Data: []byte("old revision")},
+		{Id: "1", EntryType: enum.Login, UpdatedAt: revisionTime, Data: []byte("unreadable revision")},
+	}
+	expectedRollback := entity.RekeyRollback{
+		Vault:   oldVault,
+		Entries: map[enum.EntryType][]entryEntity.Entry{enum.Login: entries},
+		History: map[enum.EntryType][]entryEntity.Entry{enum.Login: revisions},
+	}
+	writeErr := errors.New("write error")
+	expectRevisionsReencrypted := func() {
+		historyRepositoryMock.EXPECT().GetAll(gomock.Any()).Return(revisions, nil)
+		encryptorMock.EXPECT().Decrypt([]byte("old revision"), ad, oldKeyring).Return([]byte("revision data"), nil)
+		encryptorMock.EXPECT().Encrypt([]byte("revision data"), ad, newKeyring).Return([]byte("new revision"), nil)
+		encryptorMock.EXPECT().Decrypt([]byte("unreadable revision"), ad, oldKeyring).Return(nil, errors.New("decrypt error"))
+	}
+
+	tests := []struct {
+		name          string
+		mockBehaviour func()
+		wantErr       error
+	}{
+		{
+			name: "success",
+			mockBehaviour: func() {
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
+				keyringServiceMock.EXPECT().Unlock("old").Return(oldKeyring, nil)
+				rollbackRepositoryMock.EXPECT().Get().Return(entity.RekeyRollback{}, rollback.ErrRollbackNotFound)
+				vaultRepositoryMock.EXPECT().Get().Return(oldVault, nil)
+				keyringServiceMock.EXPECT().RekeyVault("new", oldVault, oldKeyring).Return(newVault, newKeyring, nil)
+				entryRepositoryMock.EXPECT().GetList(gomock.Any()).Return(entries, nil)
+				encryptorMock.EXPECT().Decrypt([]byte("old encrypted"), ad, oldKeyring).Return([]byte("data"), nil)
+				encryptorMock.EXPECT().Encrypt([]byte("data"), ad, newKeyring).Return([]byte("new encrypted"), nil)
+				expectRevisionsReencrypted()
+				rollbackRepositoryMock.EXPECT().Save(expectedRollback).Return(nil)
+				vaultRepositoryMock.EXPECT().Save(newVault).Return(nil)
+				entryRepositoryMock.EXPECT().Rewrite(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entries []entryEntity.Entry) error {
+					assert.Equal(t, []byte("new encrypted"), entries[0].Data)
+					assert.False(t, entries[0].UpdatedAt.IsZero())
+					return nil
+				})
+				// ревизия, которую не расшифровать, не переносится, а время изменения ревизий не меняется
+				historyRepositoryMock.EXPECT().Rewrite(gomock.Any(), []entryEntity.Entry{
+					{Id: "1", EntryType: enum.Login, UpdatedAt: revisionTime, Data: []byte("new revision")},
+				}).Return(nil)
+				rollbackRepositoryMock.EXPECT().Delete().Return(nil)
+				secretRepositoryMock.EXPECT().SaveKeyring(newKeyring).Return(nil)
+				keyringServiceMock.EXPECT().Lock()
+				entryStoreMock.EXPECT().Seal().Return(nil)
+				secretRepositoryMock.EXPECT().GetAuthToken().Return("token", nil)
+				vaultSyncServiceMock.EXPECT().Push(gomock.Any(), "token").Return(nil)
+			},
+		},
+		{
+			name: "wrong old master password",
+			mockBehaviour: func() {
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
+				keyringServiceMock.EXPECT().Unlock("old").Return(oldKeyring, nil)
+				rollbackRepositoryMock.EXPECT().Get().Return(entity.RekeyRollback{}, rollback.ErrRollbackNotFound)
+				vaultRepositoryMock.EXPECT().Get().Return(oldVault, nil)
+				keyringServiceMock.EXPECT().RekeyVault("new", oldVault, oldKeyring).Return(newVault, newKeyring, nil)
+				entryRepositoryMock.EXPECT().GetList(gomock.Any()).Return(entries, nil)
+				encryptorMock.EXPECT().Decrypt([]byte("old encrypted"), ad, oldKeyring).Return(nil, writeErr)
+			},
+			wantErr: writeErr,
+		},
+		{
+			name: "apply error restores vault",
+			mockBehaviour: func() {
+				entryRepositoryMock.EXPECT().Lock(gomock.Any()).Return(func() error { return nil }, nil)
+				keyringServiceMock.EXPECT().Unlock("old").Return(oldKeyring, nil)
+				rollbackRepositoryMock.EXPECT().Get().Return(entity.RekeyRollback{}, rollback.ErrRollbackNotFound)
+				vaultRepositoryMock.EXPECT().Get().Return(oldVault, nil)
+				keyringServiceMock.EXPECT().RekeyVault("new", oldVault, oldKeyring).Return(newVault, newKeyring, nil)
+				entryRepositoryMock.EXPECT().GetList(gomock.Any()).Return(entries, nil)
+				encryptorMock.EXPECT().Decrypt([]byte("old encrypted"), ad, oldKeyring).Return([]byte("data"), nil)
+				encryptorMock.EXPECT().Encrypt([]byte("data"), ad, newKeyring).Return([]byte("new encrypted"), nil)
+				expectRevisionsReencrypted()
+				rollbackRepositoryMock.EXPECT().Save(expectedRollback).Return(nil)
+				vaultRepositoryMock.EXPECT().Save(newVault).Return(nil)
+				entryRepositoryMock.EXPECT().Rewrite(gomock.Any(), gomock.Any()).Return(writeErr)
+
+				rollbackRepositoryMock.EXPECT().Get().Return(expectedRollback, nil)
+				keyringServiceMock.EXPECT().StoreKey().Return(oldKeyring.StoreKey, nil)
+				vaultRepositoryMock.EXPECT().Save(oldVault).Return(nil)
+				entryRepositoryMock.EXPECT().Rewrite(gomock.Any(), entries).Return(nil)
+				historyRepositoryMock.EXPECT().Rewrite(gomock.Any(), revisions).Return(nil)
+				rollbackRepositoryMock.EXPECT().Delete().Return(nil)
+			},
+			wantErr: sharedErrors.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour()
+			s := NewRekeyService(
+				map[enum.EntryType]entryRepository.EntryRepositoryInterface{enum.Login: entryRepositoryMock},
+				map[enum.EntryType]entryRepository.EntryHistoryRepositoryInterface{enum.Login: historyRepositoryMock},
+				entryStoreMock,
+				vaultRepositoryMock,
+				rollbackRepositoryMock,
+				secretRepositoryMock,
+				keyringServiceMock,
+				vaultSyncServiceMock,
+				encryptorMock,
+				loggerMock,
+			)
+			err := s.Rekey(context.Background(), cmd)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRekeyService_Recover(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entryRepositoryMock := mock_entry_repository.NewMockEntryRepositoryInterface(ctrl)
+	entryStoreMock := mock_entry_store.NewMockEntryStoreInterface(ctrl)
+	historyRepositoryMock := mock_entry_history_repository.NewMockEntryHistoryRepositoryInterface(ctrl)
+	vaultRepositoryMock := mock_vault_repository.NewMockVaultRepositoryInterface(ctrl)
+	rollbackRepositoryMock := mock_rollback_repository.NewMockRollbackRepositoryInterface(ctrl)
+	secretRepositoryMock := mock_secret_repository.NewMockSecretRepositoryInterface(ctrl)
+	keyringServiceMock := mock_keyring_service.NewMockKeyringServiceInterface(ctrl)
+	vaultSyncServiceMock := mock_vault_sync_service.NewMockVaultSyncServiceInterface(ctrl)
+	encryptorMock := mock_data_encryptor.NewMockDataEncryptorInterface(ctrl)
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+
+	oldVault := entity.Vault{CurrentKeyId: "0102030405060708"}
+	entries := []entryEntity.Entry{{Id: "1", EntryType: enum.Login, Data: []byte("old encrypted")}}
+	rollbackState := entity.RekeyRollback{
+		Vault:   oldVault,
+		Entries: map[enum.EntryType][]entryEntity.Entry{enum.Login: entries},
+	}
+
+	tests := []struct {
+		name          string
+		mockBehaviour func()
+		wantRecovered bool
+	}{
+		{
+			name: "no rollback",
+			mockBehaviour: func() {
+				rollbackRepositoryMock.EXPECT().Get().Return(entity.RekeyRollback{}, rollback.ErrRollbackNotFound)
+			},
+		},
+		{
+			name: "vault locked postpones rollback",
+			mockBehaviour: func() {
+				rollbackRepositoryMock.EXPECT().Get().Return(rollbackState, nil)
+				keyringServiceMock.EXPECT().StoreKey().Return(nil, secret.ErrVaultLocked)
+			},
+		},
+		{
+			name: "rollback restored",
+			mockBehaviour: func() {
+				rollbackRepositoryMock.EXPECT().Get().Return(rollbackState, nil)
+				keyringServiceMock.EXPECT().StoreKey().Return([]byte("store key"), nil)
+				vaultRepositoryMock.EXPECT().Save(oldVault).Return(nil)
+				entryRepositoryMock.EXPECT().Rewrite(gomock.Any(), entries).Return(nil)
+				rollbackRepositoryMock.EXPECT().Delete().Return(nil)
+			},
+			wantRecovered: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour()
+			s := NewRekeyService(
+				map[enum.EntryType]entryRepository.EntryRepositoryInterface{enum.Login: entryRepositoryMock},
+				map[enum.EntryType]entryRepository.EntryHistoryRepositoryInterface{enum.Login: historyRepositoryMock},
+				entryStoreMock,
+				vaultRepositoryMock,
+				rollbackRepositoryMock,
+				secretRepositoryMock,
+				keyringServiceMock,
+				vaultSyncServiceMock,
+				encryptorMock,
+				loggerMock,
+			)
+			recovered, err := s.Recover(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRecovered, recovered)
+		})
+	}
+}
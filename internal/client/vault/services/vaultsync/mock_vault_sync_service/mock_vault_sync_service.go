@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vault_sync_service_interface.go
+
+// Package mock_vault_sync_service is a generated GoMock package.
+package mock_vault_sync_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockVaultSyncServiceInterface is a mock of VaultSyncServiceInterface interface.
+type MockVaultSyncServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockVaultSyncServiceInterfaceMockRecorder
+}
+
+// MockVaultSyncServiceInterfaceMockRecorder is the mock recorder for MockVaultSyncServiceInterface.
+type MockVaultSyncServiceInterfaceMockRecorder struct {
+	mock *MockVaultSyncServiceInterface
+}
+
+// NewMockVaultSyncServiceInterface creates a new mock instance.
+func NewMockVaultSyncServiceInterface(ctrl *gomock.Controller) *MockVaultSyncServiceInterface {
+	mock := &MockVaultSyncServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockVaultSyncServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVaultSyncServiceInterface) EXPECT() *MockVaultSyncServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Pull mocks base method.
+func (m *MockVaultSyncServiceInterface) Pull(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pull", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pull indicates an expected call of Pull.
+func (mr *MockVaultSyncServiceInterfaceMockRecorder) Pull(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pull", reflect.TypeOf((*MockVaultSyncServiceInterface)(nil).Pull), ctx, token)
+}
+
+// Push mocks base method.
+func (m *MockVaultSyncServiceInterface) Push(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Push", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Push indicates an expected call of Push.
+func (mr *MockVaultSyncServiceInterfaceMockRecorder) Push(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Push", reflect.TypeOf((*MockVaultSyncServiceInterface)(nil).Push), ctx, token)
+}
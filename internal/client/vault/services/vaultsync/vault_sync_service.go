@@ -0,0 +1,79 @@
+package vaultsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	vaultRepository "github.com/anoriar/gophkeeper/internal/client/vault/repository/vault"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/vault_ext"
+)
+
+// VaultSyncService синхронизация слотов ключей (соль, параметры KDF, keyCheck, зашифрованный ключ хранилища) с сервером.
+// Ключей в открытом виде в слотах нет, без мастер-пароля сервер их не получит
+type VaultSyncService struct {
+	vaultRepository    vaultRepository.VaultRepositoryInterface
+	vaultExtRepository vault_ext.VaultExtRepositoryInterface
+	logger             *zap.Logger
+}
+
+func NewVaultSyncService(
+	vaultRepository vaultRepository.VaultRepositoryInterface,
+	vaultExtRepository vault_ext.VaultExtRepositoryInterface,
+	logger *zap.Logger,
+) *VaultSyncService {
+	return &VaultSyncService{
+		vaultRepository:    vaultRepository,
+		vaultExtRepository: vaultExtRepository,
+		logger:             logger,
+	}
+}
+
+func (s *VaultSyncService) Pull(ctx context.Context, token string) error {
+	_, err := s.pull(ctx, token)
+	return err
+}
+
+func (s *VaultSyncService) Push(ctx context.Context, token string) error {
+	vault, err := s.pull(ctx, token)
+	if err != nil {
+		return err
+	}
+	if vault.CurrentKeySlot() == nil {
+		return nil
+	}
+	err = s.vaultExtRepository.Save(ctx, token, vault)
+	if err != nil {
+		return fmt.Errorf("save remote vault error: %w", err)
+	}
+	return nil
+}
+
+// pull объединенное хранилище. Сервер без слотов ключей не ошибка: они появятся там после Push
+func (s *VaultSyncService) pull(ctx context.Context, token string) (entity.Vault, error) {
+	vault, err := s.vaultRepository.Get()
+	if err != nil && !errors.Is(err, vaultRepository.ErrVaultNotFound) {
+		return entity.Vault{}, fmt.Errorf("get vault error: %w", err)
+	}
+
+	remoteVault, err := s.vaultExtRepository.Get(ctx, token)
+	if err != nil {
+		if errors.Is(err, vault_ext.ErrRemoteVaultNotFound) {
+			return vault, nil
+		}
+		return entity.Vault{}, fmt.Errorf("get remote vault error: %w", err)
+	}
+
+	if !vault.Merge(remoteVault) {
+		return vault, nil
+	}
+	err = s.vaultRepository.Save(vault)
+	if err != nil {
+		return entity.Vault{}, fmt.Errorf("save vault error: %w", err)
+	}
+	s.logger.Info("key slots received from server", zap.Int("count", len(vault.KeySlots)))
+	return vault, nil
+}
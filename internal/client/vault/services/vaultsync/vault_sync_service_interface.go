@@ -0,0 +1,12 @@
+package vaultsync
+
+import "context"
+
+//go:generate mockgen -source=vault_sync_service_interface.go -destination=mock_vault_sync_service/mock_vault_sync_service.go -package=mock_vault_sync_service
+type VaultSyncServiceInterface interface {
+	// Pull добавляет в локальное хранилище слоты ключей с сервера. На новом устройстве или после потери
+	// локальных файлов так восстанавливаются соли, без которых записи с сервера не расшифровать
+	Pull(ctx context.Context, token string) error
+	// Push объединяет слоты с сервера с локальными и отправляет результат на сервер
+	Push(ctx context.Context, token string) error
+}
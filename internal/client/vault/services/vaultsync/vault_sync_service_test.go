@@ -0,0 +1,143 @@
+package vaultsync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anoriar/gophkeeper/internal/client/shared/app/logger"
+	"github.com/anoriar/gophkeeper/internal/client/vault/entity"
+	vaultRepository "github.com/anoriar/gophkeeper/internal/client/vault/repository/vault"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/vault/mock_vault_repository"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/vault_ext"
+	"github.com/anoriar/gophkeeper/internal/client/vault/repository/vault_ext/mock_vault_ext_repository"
+)
+
+func TestVaultSyncService_Pull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+	vaultRepositoryMock := mock_vault_repository.NewMockVaultRepositoryInterface(ctrl)
+	vaultExtRepositoryMock := mock_vault_ext_repository.NewMockVaultExtRepositoryInterface(ctrl)
+
+	kdfParams := entity.KdfParams{Time: 1, Memory: 64, Threads: 1}
+	localSlot := entity.KeySlot{Id: "0101010101010101", Salt: []byte("local salt"), Kdf: kdfParams, KeyCheck: []byte("local check"), StoreKey: []byte("local store key")}
+	remoteSlot := entity.KeySlot{Id: "0202020202020202", Salt: []byte("remote salt"), Kdf: kdfParams, KeyCheck: []byte("remote check"), StoreKey: []byte("remote store key")}
+	// слот с Threads: 0 уронил бы argon2 при получении ключа
+	invalidSlot := entity.KeySlot{Id: "0303030303030303", Salt: []byte("invalid salt"), Kdf: entity.KdfParams{Time: 1, Memory: 64}}
+	// слот rekey на другом устройстве
+	rekeyedSlot := entity.KeySlot{Id: "0404040404040404", Salt: []byte("rekeyed salt"), Kdf: kdfParams, KeyCheck: []byte("rekeyed check"), StoreKey: []byte("rekeyed store key"), Generation: 1}
+	localVault := entity.Vault{CurrentKeyId: localSlot.Id, KeySlots: []entity.KeySlot{localSlot}}
+	remoteVault := entity.Vault{CurrentKeyId: remoteSlot.Id, KeySlots: []entity.KeySlot{remoteSlot, localSlot}}
+	rekeyedVault := entity.Vault{CurrentKeyId: rekeyedSlot.Id, KeySlots: []entity.KeySlot{rekeyedSlot, localSlot}}
+
+	tests := []struct {
+		name          string
+		mockBehaviour func()
+		want          *entity.Vault
+	}{
+		{
+			name: "new device takes remote vault",
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().Get().Return(entity.Vault{}, vaultRepository.ErrVaultNotFound)
+				vaultExtRepositoryMock.EXPECT().Get(gomock.Any(), "token").Return(remoteVault, nil)
+			},
+			want: &remoteVault,
+		},
+		{
+			name: "remote slots added without store key",
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().Get().Return(localVault, nil)
+				vaultExtRepositoryMock.EXPECT().Get(gomock.Any(), "token").Return(remoteVault, nil)
+			},
+			want: &entity.Vault{
+				CurrentKeyId: localSlot.Id,
+				KeySlots: []entity.KeySlot{
+					localSlot,
+					{Id: remoteSlot.Id, Salt: remoteSlot.Salt, Kdf: kdfParams, KeyCheck: remoteSlot.KeyCheck},
+				},
+			},
+		},
+		{
+			name: "newer remote current slot adopted",
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().Get().Return(localVault, nil)
+				vaultExtRepositoryMock.EXPECT().Get(gomock.Any(), "token").Return(rekeyedVault, nil)
+			},
+			want: &entity.Vault{
+				CurrentKeyId: rekeyedSlot.Id,
+				KeySlots: []entity.KeySlot{
+					localSlot,
+					{Id: rekeyedSlot.Id, Salt: rekeyedSlot.Salt, Kdf: kdfParams, KeyCheck: rekeyedSlot.KeyCheck, Generation: 1},
+				},
+			},
+		},
+		{
+			name: "remote slot with invalid kdf params skipped",
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().Get().Return(localVault, nil)
+				vaultExtRepositoryMock.EXPECT().Get(gomock.Any(), "token").Return(entity.Vault{
+					CurrentKeyId: invalidSlot.Id,
+					KeySlots:     []entity.KeySlot{invalidSlot, localSlot},
+				}, nil)
+			},
+		},
+		{
+			name: "nothing new on server",
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().Get().Return(localVault, nil)
+				vaultExtRepositoryMock.EXPECT().Get(gomock.Any(), "token").Return(localVault, nil)
+			},
+		},
+		{
+			name: "no vault on server",
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().Get().Return(localVault, nil)
+				vaultExtRepositoryMock.EXPECT().Get(gomock.Any(), "token").Return(entity.Vault{}, vault_ext.ErrRemoteVaultNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour()
+			if tt.want != nil {
+				vaultRepositoryMock.EXPECT().Save(*tt.want).Return(nil)
+			}
+
+			s := NewVaultSyncService(vaultRepositoryMock, vaultExtRepositoryMock, loggerMock)
+			err := s.Pull(context.Background(), "token")
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestVaultSyncService_Push(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+	vaultRepositoryMock := mock_vault_repository.NewMockVaultRepositoryInterface(ctrl)
+	vaultExtRepositoryMock := mock_vault_ext_repository.NewMockVaultExtRepositoryInterface(ctrl)
+
+	kdfParams := entity.KdfParams{Time: 1, Memory: 64, Threads: 1}
+	localSlot := entity.KeySlot{Id: "0101010101010101", Salt: []byte("local salt"), Kdf: kdfParams}
+	remoteSlot := entity.KeySlot{Id: "0202020202020202", Salt: []byte("remote salt"), Kdf: kdfParams}
+	localVault := entity.Vault{CurrentKeyId: localSlot.Id, KeySlots: []entity.KeySlot{localSlot}}
+	remoteVault := entity.Vault{CurrentKeyId: remoteSlot.Id, KeySlots: []entity.KeySlot{remoteSlot}}
+	mergedVault := entity.Vault{CurrentKeyId: localSlot.Id, KeySlots: []entity.KeySlot{localSlot, remoteSlot}}
+
+	vaultRepositoryMock.EXPECT().Get().Return(localVault, nil)
+	vaultExtRepositoryMock.EXPECT().Get(gomock.Any(), "token").Return(remoteVault, nil)
+	vaultRepositoryMock.EXPECT().Save(mergedVault).Return(nil)
+	// слоты других устройств не теряются при отправке
+	vaultExtRepositoryMock.EXPECT().Save(gomock.Any(), "token", mergedVault).Return(nil)
+
+	s := NewVaultSyncService(vaultRepositoryMock, vaultExtRepositoryMock, loggerMock)
+	assert.NoError(t, s.Push(context.Background(), "token"))
+}
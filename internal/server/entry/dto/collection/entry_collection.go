@@ -29,3 +29,14 @@ func (c *EntryCollection) FindByOriginalId(id string) *entity.Entry {
 	}
 	return nil
 }
+
+// WithoutDeleted записи, которых нет в корзине
+func (c *EntryCollection) WithoutDeleted() *EntryCollection {
+	entries := make([]entity.Entry, 0, len(c.Entries))
+	for _, entry := range c.Entries {
+		if entry.DeletedAt == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return NewEntryCollection(entries)
+}
@@ -12,6 +12,8 @@ type SyncRequest struct {
 	Items    []SyncRequestItem `json:"items"`
 	SyncType enum.EntryType
 	UserID   string
+	// WithTrash клиент понимает записи в корзине. Старым клиентам они не отправляются: те показали бы их как обычные
+	WithTrash bool `json:"withTrash"`
 }
 
 func (c *SyncRequest) Contains(id string) bool {
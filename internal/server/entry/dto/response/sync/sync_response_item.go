@@ -10,8 +10,10 @@ type SyncResponseItem struct {
 	UpdatedAt  time.Time       `json:"updatedAt"`
 	Data       string          `json:"data"`
 	Meta       json.RawMessage `json:"meta"`
+	// IsDeleted запись в корзине: ее еще можно восстановить
+	IsDeleted bool `json:"isDeleted"`
 }
 
-func NewSyncResponseItem(originalId string, updatedAt time.Time, data string, meta json.RawMessage) *SyncResponseItem {
-	return &SyncResponseItem{OriginalId: originalId, UpdatedAt: updatedAt, Data: data, Meta: meta}
+func NewSyncResponseItem(originalId string, updatedAt time.Time, data string, meta json.RawMessage, isDeleted bool) *SyncResponseItem {
+	return &SyncResponseItem{OriginalId: originalId, UpdatedAt: updatedAt, Data: data, Meta: meta, IsDeleted: isDeleted}
 }
@@ -16,6 +16,8 @@ type Entry struct {
 	UpdatedAt  time.Time       `db:"updated_at"`
 	Data       []byte          `db:"data"`
 	Meta       json.RawMessage `db:"meta"`
+	// DeletedAt время удаления записи. Удаленная запись хранится в корзине до истечения TRASH_RETENTION
+	DeletedAt *time.Time `db:"deleted_at"`
 }
 
 func NewEntry(id string, originalId string, userId string, entryType enum.EntryType, updatedAt time.Time, data []byte, meta json.RawMessage) *Entry {
@@ -38,6 +40,14 @@ func (e Entry) Equals(other Entry) (bool, error) {
 		e.EntryType == other.EntryType &&
 		bytes.Equal(e.Data, other.Data) &&
 		e.UpdatedAt == other.UpdatedAt &&
-		bytes.Equal(meta, otherMeta), nil
+		bytes.Equal(meta, otherMeta) &&
+		equalTimes(e.DeletedAt, other.DeletedAt), nil
 
 }
+
+func equalTimes(t1 *time.Time, t2 *time.Time) bool {
+	if t1 == nil || t2 == nil {
+		return t1 == t2
+	}
+	return t1.Equal(*t2)
+}
@@ -27,5 +27,5 @@ func (f *SyncResponseFactory) CreateSyncResponse(entryCollection collection.Entr
 }
 
 func (f *SyncResponseFactory) CreateSyncResponseItem(entry entity.Entry) sync.SyncResponseItem {
-	return *sync.NewSyncResponseItem(entry.OriginalId, entry.UpdatedAt, base64.StdEncoding.EncodeToString(entry.Data), entry.Meta)
+	return *sync.NewSyncResponseItem(entry.OriginalId, entry.UpdatedAt, base64.StdEncoding.EncodeToString(entry.Data), entry.Meta, entry.DeletedAt != nil)
 }
@@ -65,6 +65,8 @@ func TestSyncResponseFactory_CreateSyncResponse(t *testing.T) {
 }
 
 func TestSyncResponseFactory_CreateSyncResponseItem(t *testing.T) {
+	deletedAt := time.Date(2024, time.March, 11, 12, 0, 0, 0, time.UTC)
+
 	type args struct {
 		entry entity.Entry
 	}
@@ -93,6 +95,28 @@ func TestSyncResponseFactory_CreateSyncResponseItem(t *testing.T) {
 				Meta:       []byte(`{"key1": "value1", "key2": "value2"}`),
 			},
 		},
+		{
+			name: "success deleted entry",
+			args: args{
+				entry: entity.Entry{
+					Id:         "7f8b3f0d-321e-440e-9cb3-e9d80d6a9db2",
+					OriginalId: "54493f7e-b64f-4831-8b38-691768a86d83",
+					UserId:     "b632eb93-0c31-4d6c-8fb9-282f3fb7e54e",
+					EntryType:  enum.Login,
+					UpdatedAt:  time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+					Data:       []byte("data"),
+					Meta:       []byte(`{"key1": "value1"}`),
+					DeletedAt:  &deletedAt,
+				},
+			},
+			want: sync.SyncResponseItem{
+				OriginalId: "54493f7e-b64f-4831-8b38-691768a86d83",
+				UpdatedAt:  time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+				Data:       base64.StdEncoding.EncodeToString([]byte("data")),
+				Meta:       []byte(`{"key1": "value1"}`),
+				IsDeleted:  true,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
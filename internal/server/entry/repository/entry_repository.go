@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/anoriar/gophkeeper/internal/server/entry/enum"
 
@@ -56,13 +57,13 @@ func (e *EntryRepository) AddEntries(ctx context.Context, entries []entity.Entry
 		return fmt.Errorf("%w: %v", errors2.ErrInternalError, err)
 	}
 
-	stmt, err := txx.PreparexContext(ctx, "INSERT INTO entries (id, type, user_id, updated_at, data, meta, original_id) VALUES ($1, $2, $3, $4, $5, $6, $7)")
+	stmt, err := txx.PreparexContext(ctx, "INSERT INTO entries (id, type, user_id, updated_at, data, meta, original_id, deleted_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)")
 	if err != nil {
 		return fmt.Errorf("%w: %v", errors2.ErrInternalError, err)
 	}
 
 	for _, entry := range entries {
-		_, err := stmt.ExecContext(ctx, entry.Id, entry.EntryType, entry.UserId, entry.UpdatedAt, entry.Data, entry.Meta, entry.OriginalId)
+		_, err := stmt.ExecContext(ctx, entry.Id, entry.EntryType, entry.UserId, entry.UpdatedAt, entry.Data, entry.Meta, entry.OriginalId, entry.DeletedAt)
 		if err != nil {
 			var pgErr *pgconn.PgError
 			if errors.As(err, &pgErr) && pgerrcode.UniqueViolation == pgErr.Code {
@@ -81,13 +82,13 @@ func (e *EntryRepository) UpdateEntries(ctx context.Context, entries []entity.En
 		return fmt.Errorf("%w: %v", errors2.ErrInternalError, err)
 	}
 
-	stmt, err := txx.PreparexContext(ctx, "UPDATE entries SET type = $1, user_id = $2, updated_at = $3, data = $4, meta = $5, original_id = $6 WHERE id = $7")
+	stmt, err := txx.PreparexContext(ctx, "UPDATE entries SET type = $1, user_id = $2, updated_at = $3, data = $4, meta = $5, original_id = $6, deleted_at = $7 WHERE id = $8")
 	if err != nil {
 		return fmt.Errorf("%w: %v", errors2.ErrInternalError, err)
 	}
 
 	for _, entry := range entries {
-		_, err := stmt.ExecContext(ctx, entry.EntryType, entry.UserId, entry.UpdatedAt, entry.Data, entry.Meta, entry.OriginalId, entry.Id)
+		_, err := stmt.ExecContext(ctx, entry.EntryType, entry.UserId, entry.UpdatedAt, entry.Data, entry.Meta, entry.OriginalId, entry.DeletedAt, entry.Id)
 		if err != nil {
 			return err
 		}
@@ -99,27 +100,16 @@ func (e *EntryRepository) UpdateEntries(ctx context.Context, entries []entity.En
 	return nil
 }
 
-func (e *EntryRepository) DeleteEntries(ctx context.Context, entriesIds []string) error {
-	txx, err := e.getTxFromContextOrBeginNew(ctx)
+func (e *EntryRepository) DeleteExpiredEntries(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result, err := e.db.Conn.ExecContext(ctx, "DELETE FROM entries WHERE deleted_at IS NOT NULL AND deleted_at < $1", deletedBefore)
 	if err != nil {
-		return fmt.Errorf("%w: %v", errors2.ErrInternalError, err)
+		return 0, fmt.Errorf("%w: %v", errors2.ErrInternalError, err)
 	}
-
-	stmt, err := txx.PreparexContext(ctx, "DELETE FROM entries WHERE id = $1")
+	deleted, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("%w: %v", errors2.ErrInternalError, err)
+		return 0, fmt.Errorf("%w: %v", errors2.ErrInternalError, err)
 	}
-	for _, entryId := range entriesIds {
-		_, err := stmt.ExecContext(ctx, entryId)
-		if err != nil {
-			return err
-		}
-		if err != nil {
-			return fmt.Errorf("%w: %v", errors2.ErrInternalError, err)
-		}
-	}
-
-	return nil
+	return deleted, nil
 }
 
 func (e *EntryRepository) getTxFromContextOrBeginNew(ctx context.Context) (*sqlx.Tx, error) {
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/anoriar/gophkeeper/internal/server/entry/enum"
 
@@ -14,5 +15,6 @@ type EntryRepositoryInterface interface {
 	GetEntriesByUserIDAndType(ctx context.Context, userID string, entryType enum.EntryType) (collection.EntryCollection, error)
 	AddEntries(ctx context.Context, entries []entity.Entry) error
 	UpdateEntries(ctx context.Context, entries []entity.Entry) error
-	DeleteEntries(ctx context.Context, entriesIds []string) error
+	// DeleteExpiredEntries окончательно удаляет записи, попавшие в корзину раньше deletedBefore. Возвращает число удаленных
+	DeleteExpiredEntries(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
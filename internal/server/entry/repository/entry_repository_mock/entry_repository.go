@@ -7,12 +7,12 @@ package entry_repository_mock
 import (
 	context "context"
 	reflect "reflect"
-
-	gomock "github.com/golang/mock/gomock"
+	time "time"
 
 	collection "github.com/anoriar/gophkeeper/internal/server/entry/dto/collection"
 	entity "github.com/anoriar/gophkeeper/internal/server/entry/entity"
 	enum "github.com/anoriar/gophkeeper/internal/server/entry/enum"
+	gomock "github.com/golang/mock/gomock"
 )
 
 // MockEntryRepositoryInterface is a mock of EntryRepositoryInterface interface.
@@ -52,18 +52,19 @@ func (mr *MockEntryRepositoryInterfaceMockRecorder) AddEntries(ctx, entries inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEntries", reflect.TypeOf((*MockEntryRepositoryInterface)(nil).AddEntries), ctx, entries)
 }
 
-// DeleteEntries mocks base method.
-func (m *MockEntryRepositoryInterface) DeleteEntries(ctx context.Context, entriesIds []string) error {
+// DeleteExpiredEntries mocks base method.
+func (m *MockEntryRepositoryInterface) DeleteExpiredEntries(ctx context.Context, deletedBefore time.Time) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteEntries", ctx, entriesIds)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "DeleteExpiredEntries", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteEntries indicates an expected call of DeleteEntries.
-func (mr *MockEntryRepositoryInterfaceMockRecorder) DeleteEntries(ctx, entriesIds interface{}) *gomock.Call {
+// DeleteExpiredEntries indicates an expected call of DeleteExpiredEntries.
+func (mr *MockEntryRepositoryInterfaceMockRecorder) DeleteExpiredEntries(ctx, deletedBefore interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntries", reflect.TypeOf((*MockEntryRepositoryInterface)(nil).DeleteEntries), ctx, entriesIds)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredEntries", reflect.TypeOf((*MockEntryRepositoryInterface)(nil).DeleteExpiredEntries), ctx, deletedBefore)
 }
 
 // GetEntriesByUserIDAndType mocks base method.
@@ -0,0 +1,61 @@
+package purge
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/anoriar/gophkeeper/internal/server/entry/repository"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/server/shared/errors"
+)
+
+type PurgeService struct {
+	entryRepository repository.EntryRepositoryInterface
+	trashRetention  time.Duration
+	now             func() time.Time
+	logger          *zap.Logger
+}
+
+func NewPurgeService(
+	entryRepository repository.EntryRepositoryInterface,
+	trashRetention time.Duration,
+	logger *zap.Logger,
+) *PurgeService {
+	return &PurgeService{
+		entryRepository: entryRepository,
+		trashRetention:  trashRetention,
+		now:             time.Now,
+		logger:          logger,
+	}
+}
+
+func (s *PurgeService) Purge(ctx context.Context) error {
+	deleted, err := s.entryRepository.DeleteExpiredEntries(ctx, s.now().Add(-s.trashRetention))
+	if err != nil {
+		s.logger.Error("purge trash error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %v", sharedErrors.ErrInternalError, err)
+	}
+	s.logger.Info("entries deleted", zap.Int64("count", deleted))
+	return nil
+}
+
+// Run первая очистка выполняется сразу: сервер мог долго не работать
+func (s *PurgeService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// ошибка уже залогирована, следующая попытка - на следующем тике
+		_ = s.Purge(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// select выбирает случайно, если тик и отмена пришли одновременно
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
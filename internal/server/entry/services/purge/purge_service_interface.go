@@ -0,0 +1,13 @@
+package purge
+
+import (
+	"context"
+	"time"
+)
+
+type PurgeServiceInterface interface {
+	// Purge окончательно удаляет записи, которые лежат в корзине дольше TRASH_RETENTION
+	Purge(ctx context.Context) error
+	// Run запускает Purge раз в interval, пока не отменен ctx
+	Run(ctx context.Context, interval time.Duration)
+}
@@ -0,0 +1,91 @@
+package purge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anoriar/gophkeeper/internal/server/entry/repository/entry_repository_mock"
+	"github.com/anoriar/gophkeeper/internal/server/shared/app/logger"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/server/shared/errors"
+)
+
+const testTrashRetention = 30 * 24 * time.Hour
+
+var testPurgeNow = time.Date(2024, time.April, 1, 12, 0, 0, 0, time.UTC)
+
+func TestPurgeService_Purge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+	entryRepositoryMock := entry_repository_mock.NewMockEntryRepositoryInterface(ctrl)
+
+	tests := []struct {
+		name          string
+		mockBehaviour func()
+		err           error
+	}{
+		{
+			name: "success",
+			mockBehaviour: func() {
+				entryRepositoryMock.EXPECT().DeleteExpiredEntries(gomock.Any(), testPurgeNow.Add(-testTrashRetention)).Return(int64(2), nil)
+			},
+		},
+		{
+			name: "delete error",
+			mockBehaviour: func() {
+				entryRepositoryMock.EXPECT().DeleteExpiredEntries(gomock.Any(), testPurgeNow.Add(-testTrashRetention)).Return(int64(0), errors.New("error"))
+			},
+			err: sharedErrors.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour()
+			s := NewPurgeService(entryRepositoryMock, testTrashRetention, loggerMock)
+			s.now = func() time.Time { return testPurgeNow }
+			err := s.Purge(context.Background())
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPurgeService_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+	entryRepositoryMock := entry_repository_mock.NewMockEntryRepositoryInterface(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	// первая очистка при запуске, вторая - по тику; после нее Run останавливается
+	entryRepositoryMock.EXPECT().DeleteExpiredEntries(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+	entryRepositoryMock.EXPECT().DeleteExpiredEntries(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, time.Time) (int64, error) {
+		cancel()
+		return 0, nil
+	})
+
+	s := NewPurgeService(entryRepositoryMock, testTrashRetention, loggerMock)
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not stop after context cancel")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	sharedErrors "github.com/anoriar/gophkeeper/internal/server/shared/errors"
 
@@ -31,6 +32,7 @@ type SyncService struct {
 	syncResponseFactory  *syncResponseFactory.SyncResponseFactory
 	syncRequestValidator *validator.SyncRequestValidator
 	db                   db.DatabaseInterface
+	now                  func() time.Time
 	logger               *zap.Logger
 }
 
@@ -43,6 +45,7 @@ func NewSyncService(
 	return &SyncService{
 		entryRepository:      entryRepository,
 		db:                   db,
+		now:                  time.Now,
 		logger:               logger,
 		entryFactory:         factory.NewEntryFactory(uuidGen),
 		syncResponseFactory:  syncResponseFactory.NewSyncResponseFactory(),
@@ -64,9 +67,8 @@ func (s SyncService) Sync(ctx context.Context, request sync.SyncRequest) (syncRe
 
 	newEntries := s.getNewItems(request, userEntries)
 	updatedEntries := s.getUpdatedItems(request, userEntries)
-	deletedIds := s.getDeletedIds(request, userEntries)
 
-	err = s.executeSync(ctx, newEntries, updatedEntries, deletedIds)
+	err = s.executeSync(ctx, newEntries, updatedEntries)
 	if err != nil {
 		if errors.Is(err, sharedErrors.ErrConflict) {
 			s.logger.Error("execute sync error", zap.String("error", err.Error()))
@@ -81,11 +83,14 @@ func (s SyncService) Sync(ctx context.Context, request sync.SyncRequest) (syncRe
 		s.logger.Error("get actual entries error", zap.String("error", err.Error()))
 		return syncResponsePkg.SyncResponse{}, fmt.Errorf("%w: %v", sharedErrors.ErrInternalError, err)
 	}
+	if !request.WithTrash {
+		actualEntries = *actualEntries.WithoutDeleted()
+	}
 	response := s.syncResponseFactory.CreateSyncResponse(actualEntries, request.SyncType)
 	return response, nil
 }
 
-func (s SyncService) executeSync(ctx context.Context, newEntries []entity.Entry, updatedEntries []entity.Entry, deletedIds []string) error {
+func (s SyncService) executeSync(ctx context.Context, newEntries []entity.Entry, updatedEntries []entity.Entry) error {
 	txx, err := s.db.BeginTransaction(ctx)
 	if err != nil {
 		return fmt.Errorf("create transaction error: %v", err)
@@ -107,59 +112,56 @@ func (s SyncService) executeSync(ctx context.Context, newEntries []entity.Entry,
 		}
 	}
 
-	if len(deletedIds) > 0 {
-		err = s.entryRepository.DeleteEntries(ctx, deletedIds)
-		if err != nil {
-			return fmt.Errorf("delete entries error: %v", err)
-		}
-	}
-
 	err = txx.Commit()
 	if err != nil {
 		return fmt.Errorf("commit transaction error: %v", err)
 	}
 	s.logger.Info("entries added", zap.Int("count", len(newEntries)))
 	s.logger.Info("entries updated", zap.Int("count", len(updatedEntries)))
-	s.logger.Info("entries deleted", zap.Int("count", len(deletedIds)))
 
 	return nil
 }
 
+// getNewItems записи, которых еще нет на сервере. Удаленные на клиенте записи сразу попадают в корзину
 func (s SyncService) getNewItems(request sync.SyncRequest, userEntries collection.EntryCollection) []entity.Entry {
 	newEntries := make([]entity.Entry, 0, len(request.Items))
 	for _, requestItem := range request.Items {
-		if requestItem.IsDeleted != true && !userEntries.Contains(requestItem.OriginalId) {
+		if !userEntries.Contains(requestItem.OriginalId) {
 			item := s.entryFactory.CreateNewEntryFromRequestItem(requestItem, request.UserID, request.SyncType)
+			if requestItem.IsDeleted {
+				deletedAt := s.now()
+				item.DeletedAt = &deletedAt
+			}
 			newEntries = append(newEntries, item)
 		}
 	}
 	return newEntries
 }
 
+// getUpdatedItems записи, измененные на клиенте позже, чем на сервере. Удаление, восстановление и правка записи
+// в корзине - тоже изменения. Старые клиенты не меняют дату при удалении, поэтому удаление с той же датой тоже учитывается
 func (s SyncService) getUpdatedItems(request sync.SyncRequest, userEntries collection.EntryCollection) []entity.Entry {
 	updatedEntries := make([]entity.Entry, 0, len(request.Items))
 	for _, requestItem := range request.Items {
-		if requestItem.OriginalId == "" || requestItem.IsDeleted == true {
+		if requestItem.OriginalId == "" {
 			continue
 		}
 		userEntry := userEntries.FindByOriginalId(requestItem.OriginalId)
-		if userEntry != nil {
-			if requestItem.UpdatedAt.After(userEntry.UpdatedAt) {
-				item := s.entryFactory.CreateEntryFromRequestItem(userEntry.Id, requestItem, request.UserID, request.SyncType)
-				updatedEntries = append(updatedEntries, item)
+		if userEntry == nil {
+			continue
+		}
+		isNewlyDeleted := requestItem.IsDeleted && userEntry.DeletedAt == nil
+		if requestItem.UpdatedAt.After(userEntry.UpdatedAt) || (isNewlyDeleted && requestItem.UpdatedAt.Equal(userEntry.UpdatedAt)) {
+			item := s.entryFactory.CreateEntryFromRequestItem(userEntry.Id, requestItem, request.UserID, request.SyncType)
+			if requestItem.IsDeleted {
+				item.DeletedAt = userEntry.DeletedAt
+				if isNewlyDeleted {
+					deletedAt := s.now()
+					item.DeletedAt = &deletedAt
+				}
 			}
+			updatedEntries = append(updatedEntries, item)
 		}
 	}
 	return updatedEntries
 }
-
-func (s SyncService) getDeletedIds(request sync.SyncRequest, userEntries collection.EntryCollection) []string {
-	deletedIds := make([]string, 0, len(request.Items))
-	for _, requestItem := range request.Items {
-		deletedEntry := userEntries.FindByOriginalId(requestItem.OriginalId)
-		if requestItem.IsDeleted == true && deletedEntry != nil {
-			deletedIds = append(deletedIds, deletedEntry.Id)
-		}
-	}
-	return deletedIds
-}
@@ -25,6 +25,8 @@ import (
 	"github.com/anoriar/gophkeeper/internal/server/shared/services/uuid/mock_uuid_generator"
 )
 
+var testSyncNow = time.Date(2024, time.April, 1, 12, 0, 0, 0, time.UTC)
+
 func TestSyncService_Sync(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -54,12 +56,13 @@ func TestSyncService_Sync(t *testing.T) {
 		err           error
 	}{
 		{
-			name: "success add && update && delete items",
+			name: "success add && update && trash items",
 			args: args{
 				ctx: context.Background(),
 				request: syncRequestPkg.SyncRequest{
-					SyncType: enum.Login,
-					UserID:   "b632eb93-0c31-4d6c-8fb9-282f3fb7e54e",
+					SyncType:  enum.Login,
+					UserID:    "b632eb93-0c31-4d6c-8fb9-282f3fb7e54e",
+					WithTrash: true,
 					Items: []syncRequestPkg.SyncRequestItem{
 						{
 							OriginalId: "0bc6c22e-d8b5-4057-9d28-eb5b7a233364",
@@ -132,6 +135,16 @@ func TestSyncService_Sync(t *testing.T) {
 							Data:       updateItemData,
 							Meta:       []byte(`{"key1": "value1", "key2": "value2"}`),
 						},
+						{
+							Id:         "ffffc574-5eb0-4b3a-87af-93f2322f594e",
+							EntryType:  enum.Login,
+							UserId:     "b632eb93-0c31-4d6c-8fb9-282f3fb7e54e",
+							OriginalId: "3453c579-9db6-4089-8ca3-1635a9887316",
+							UpdatedAt:  time.Date(2023, time.February, 10, 12, 0, 0, 0, time.UTC),
+							Data:       deleteItemData,
+							Meta:       []byte(`{"key1": "value1", "key2": "value2"}`),
+							DeletedAt:  &testSyncNow,
+						},
 					}}, nil)
 
 				gomock.InOrder(
@@ -167,9 +180,16 @@ func TestSyncService_Sync(t *testing.T) {
 						Data:       updateItemData,
 						Meta:       []byte(`{"key1": "value1", "key2": "value2"}`),
 					},
-				})
-				entryRepositoryMock.EXPECT().DeleteEntries(gomock.Any(), []string{
-					"ffffc574-5eb0-4b3a-87af-93f2322f594e",
+					{
+						Id:         "ffffc574-5eb0-4b3a-87af-93f2322f594e",
+						EntryType:  enum.Login,
+						UserId:     "b632eb93-0c31-4d6c-8fb9-282f3fb7e54e",
+						OriginalId: "3453c579-9db6-4089-8ca3-1635a9887316",
+						UpdatedAt:  time.Date(2023, time.February, 10, 12, 0, 0, 0, time.UTC),
+						Data:       deleteItemData,
+						Meta:       []byte(`{"key1": "value1", "key2": "value2"}`),
+						DeletedAt:  &testSyncNow,
+					},
 				})
 				tx.EXPECT().Commit().Return(nil)
 			},
@@ -187,6 +207,72 @@ func TestSyncService_Sync(t *testing.T) {
 						Data:       "MX1mUs+puMP3FNlWITgzf5vS2JmcsVu/AivvxURLiQaPQJIxeVbF5/zGUBNVWuW5kzWhHKAi4E+gtoQ8Y9k=",
 						Meta:       []byte(`{"key1": "value1", "key2": "value2"}`),
 					},
+					{
+						OriginalId: "3453c579-9db6-4089-8ca3-1635a9887316",
+						UpdatedAt:  time.Date(2023, time.February, 10, 12, 0, 0, 0, time.UTC),
+						Data:       "RKj38DKdE4z6qO0okC259mPyzENyiTd8UwQ7n3lIrpVLhmcgKkumi5fkygtxK8MaYv+Yy6wenhVIwDExfaU=",
+						Meta:       []byte(`{"key1": "value1", "key2": "value2"}`),
+						IsDeleted:  true,
+					},
+				},
+				SyncType: enum.Login,
+			},
+		},
+		{
+			name: "old client does not get trashed items",
+			args: args{
+				ctx: context.Background(),
+				request: syncRequestPkg.SyncRequest{
+					SyncType: enum.Login,
+					UserID:   "b632eb93-0c31-4d6c-8fb9-282f3fb7e54e",
+					Items: []syncRequestPkg.SyncRequestItem{
+						{
+							OriginalId: "3da6111c-6316-4993-aeff-74a2c3f345f9",
+							UpdatedAt:  time.Date(2024, time.February, 10, 12, 0, 0, 0, time.UTC),
+							Data:       updateItemData,
+							Meta:       []byte(`{"key1": "value1", "key2": "value2"}`),
+							IsDeleted:  false,
+						},
+					},
+				},
+			},
+			mockBehaviour: func() {
+				tx := mock.NewMockDBTransactionInterface(ctrl)
+				userEntries := collection.EntryCollection{Entries: []entity.Entry{
+					{
+						Id:         "05dfdb32-3674-4381-be02-091e5e17080c",
+						EntryType:  enum.Login,
+						UserId:     "b632eb93-0c31-4d6c-8fb9-282f3fb7e54e",
+						OriginalId: "3da6111c-6316-4993-aeff-74a2c3f345f9",
+						UpdatedAt:  time.Date(2024, time.February, 10, 12, 0, 0, 0, time.UTC),
+						Data:       updateItemData,
+						Meta:       []byte(`{"key1": "value1", "key2": "value2"}`),
+					},
+					{
+						Id:         "ffffc574-5eb0-4b3a-87af-93f2322f594e",
+						EntryType:  enum.Login,
+						UserId:     "b632eb93-0c31-4d6c-8fb9-282f3fb7e54e",
+						OriginalId: "3453c579-9db6-4089-8ca3-1635a9887316",
+						UpdatedAt:  time.Date(2023, time.February, 10, 12, 0, 0, 0, time.UTC),
+						Data:       deleteItemData,
+						Meta:       []byte(`{"key1": "value1", "key2": "value2"}`),
+						DeletedAt:  &testSyncNow,
+					},
+				}}
+				entryRepositoryMock.EXPECT().GetEntriesByUserIDAndType(gomock.Any(), "b632eb93-0c31-4d6c-8fb9-282f3fb7e54e", enum.Login).
+					Return(userEntries, nil).Times(2)
+				dbMock.EXPECT().BeginTransaction(gomock.Any()).Return(tx, nil)
+				tx.EXPECT().Rollback().Return(nil)
+				tx.EXPECT().Commit().Return(nil)
+			},
+			want: syncResponsePkg.SyncResponse{
+				Items: []syncResponsePkg.SyncResponseItem{
+					{
+						OriginalId: "3da6111c-6316-4993-aeff-74a2c3f345f9",
+						UpdatedAt:  time.Date(2024, time.February, 10, 12, 0, 0, 0, time.UTC),
+						Data:       "MX1mUs+puMP3FNlWITgzf5vS2JmcsVu/AivvxURLiQaPQJIxeVbF5/zGUBNVWuW5kzWhHKAi4E+gtoQ8Y9k=",
+						Meta:       []byte(`{"key1": "value1", "key2": "value2"}`),
+					},
 				},
 				SyncType: enum.Login,
 			},
@@ -365,6 +451,7 @@ func TestSyncService_Sync(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehaviour()
 			s := NewSyncService(entryRepositoryMock, uuidGenMock, dbMock, loggerMock)
+			s.now = func() time.Time { return testSyncNow }
 			got, err := s.Sync(tt.args.ctx, tt.args.request)
 			if tt.err != nil {
 				if !errors.Is(err, tt.err) {
@@ -392,7 +479,6 @@ func TestSyncService_executeSync(t *testing.T) {
 		ctx            context.Context
 		newEntries     []entity.Entry
 		updatedEntries []entity.Entry
-		deletedIds     []string
 	}
 	tests := []struct {
 		name          string
@@ -426,9 +512,6 @@ func TestSyncService_executeSync(t *testing.T) {
 						Meta:       []byte(""),
 					},
 				},
-				deletedIds: []string{
-					"1ecbfa8b-4697-4803-903c-856d40047bf6",
-				},
 			},
 			mockBehaviour: func() {
 				tx := mock.NewMockDBTransactionInterface(ctrl)
@@ -457,9 +540,6 @@ func TestSyncService_executeSync(t *testing.T) {
 						Meta:       []byte(""),
 					},
 				}).Return(nil)
-				entryRepositoryMock.EXPECT().DeleteEntries(gomock.Any(), []string{
-					"1ecbfa8b-4697-4803-903c-856d40047bf6",
-				}).Return(nil)
 				tx.EXPECT().Commit().Return(nil)
 			},
 			wantErr: false,
@@ -532,22 +612,6 @@ func TestSyncService_executeSync(t *testing.T) {
 			},
 			wantErr: true,
 		},
-		{
-			name: "delete entries error",
-			args: args{
-				ctx:            context.Background(),
-				newEntries:     []entity.Entry{},
-				updatedEntries: []entity.Entry{},
-				deletedIds:     []string{"nfs8dfjh234yfvc"},
-			},
-			mockBehaviour: func() {
-				tx := mock.NewMockDBTransactionInterface(ctrl)
-				dbMock.EXPECT().BeginTransaction(gomock.Any()).Return(tx, nil)
-				tx.EXPECT().Rollback().Return(nil)
-				entryRepositoryMock.EXPECT().DeleteEntries(gomock.Any(), []string{"nfs8dfjh234yfvc"}).Return(errors.New("error"))
-			},
-			wantErr: true,
-		},
 		{
 			name: "commit error",
 			args: args{
@@ -566,98 +630,14 @@ func TestSyncService_executeSync(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehaviour()
 			s := NewSyncService(entryRepositoryMock, uuidGenMock, dbMock, loggerMock)
-			if err := s.executeSync(tt.args.ctx, tt.args.newEntries, tt.args.updatedEntries, tt.args.deletedIds); (err != nil) != tt.wantErr {
+			s.now = func() time.Time { return testSyncNow }
+			if err := s.executeSync(tt.args.ctx, tt.args.newEntries, tt.args.updatedEntries); (err != nil) != tt.wantErr {
 				t.Errorf("executeSync() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
-func TestSyncService_getDeletedIds(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	uuidGenMock := mock_uuid_generator.NewMockUUIDGeneratorInterface(ctrl)
-	loggerMock, err := logger.Initialize("info")
-	require.NoError(t, err)
-	dbMock := mock.NewMockDatabaseInterface(ctrl)
-	entryRepositoryMock := entry_repository_mock.NewMockEntryRepositoryInterface(ctrl)
-
-	type args struct {
-		request     syncRequestPkg.SyncRequest
-		userEntries collection.EntryCollection
-	}
-	tests := []struct {
-		name string
-		args args
-		want []string
-	}{
-		{
-			name: "success",
-			args: args{
-				request: syncRequestPkg.SyncRequest{
-					SyncType: enum.Login,
-					UserID:   "b632eb93-0c31-4d6c-8fb9-282f3fb7e54e",
-					Items: []syncRequestPkg.SyncRequestItem{
-						{
-							OriginalId: "0bc6c22e-d8b5-4057-9d28-eb5b7a233364",
-							UpdatedAt:  time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
-							Data:       []byte{},
-							Meta:       []byte(""),
-							IsDeleted:  true,
-						},
-						{
-							OriginalId: "3da6111c-6316-4993-aeff-74a2c3f345f9",
-							UpdatedAt:  time.Date(2024, time.February, 10, 12, 0, 0, 0, time.UTC),
-							Data:       []byte{},
-							Meta:       []byte(""),
-							IsDeleted:  false,
-						},
-						{
-							OriginalId: "3453c579-9db6-4089-8ca3-1635a9887316",
-							UpdatedAt:  time.Date(2023, time.February, 10, 12, 0, 0, 0, time.UTC),
-							Data:       []byte{},
-							Meta:       []byte(""),
-							IsDeleted:  true,
-						},
-					},
-				},
-				userEntries: collection.EntryCollection{Entries: []entity.Entry{
-					{
-						Id:         "05dfdb32-3674-4381-be02-091e5e17080c",
-						EntryType:  enum.Login,
-						UserId:     "b632eb93-0c31-4d6c-8fb9-282f3fb7e54e",
-						OriginalId: "3da6111c-6316-4993-aeff-74a2c3f345f9",
-						UpdatedAt:  time.Date(2023, time.February, 10, 12, 0, 0, 0, time.UTC),
-						Data:       []byte{},
-						Meta:       []byte(""),
-					},
-					{
-						Id:         "ffffc574-5eb0-4b3a-87af-93f2322f594e",
-						EntryType:  enum.Login,
-						UserId:     "b632eb93-0c31-4d6c-8fb9-282f3fb7e54e",
-						OriginalId: "3453c579-9db6-4089-8ca3-1635a9887316",
-						UpdatedAt:  time.Date(2023, time.February, 10, 12, 0, 0, 0, time.UTC),
-						Data:       []byte{},
-						Meta:       []byte(""),
-					},
-				}},
-			},
-			want: []string{
-				"ffffc574-5eb0-4b3a-87af-93f2322f594e",
-			},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			s := NewSyncService(entryRepositoryMock, uuidGenMock, dbMock, loggerMock)
-			if got := s.getDeletedIds(tt.args.request, tt.args.userEntries); !assert.Equal(t, got, tt.want) {
-				t.Errorf("getDeletedIds() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
 func TestSyncService_getNewItems(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -748,7 +728,7 @@ func TestSyncService_getNewItems(t *testing.T) {
 			},
 		},
 		{
-			name: "success try add isDeleted item",
+			name: "success add isDeleted item to trash",
 			args: args{
 				request: syncRequestPkg.SyncRequest{
 					SyncType: enum.Login,
@@ -765,14 +745,28 @@ func TestSyncService_getNewItems(t *testing.T) {
 				},
 				userEntries: collection.EntryCollection{Entries: []entity.Entry{}},
 			},
-			mockBehaviour: func() {},
-			want:          []entity.Entry{},
+			mockBehaviour: func() {
+				uuidGenMock.EXPECT().NewString().Return("dfd0a1b2-ca49-4715-9900-504f06cc9e5b")
+			},
+			want: []entity.Entry{
+				{
+					Id:         "dfd0a1b2-ca49-4715-9900-504f06cc9e5b",
+					EntryType:  enum.Login,
+					UserId:     "b632eb93-0c31-4d6c-8fb9-282f3fb7e54e",
+					OriginalId: "0bc6c22e-d8b5-4057-9d28-eb5b7a233364",
+					UpdatedAt:  time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+					Data:       []byte{},
+					Meta:       []byte(""),
+					DeletedAt:  &testSyncNow,
+				},
+			},
 		},
 	}
 	for _, tt := range tests {
 		tt.mockBehaviour()
 		t.Run(tt.name, func(t *testing.T) {
 			s := NewSyncService(entryRepositoryMock, uuidGenMock, dbMock, loggerMock)
+			s.now = func() time.Time { return testSyncNow }
 			if got := s.getNewItems(tt.args.request, tt.args.userEntries); !assert.Equal(t, got, tt.want) {
 				t.Errorf("getNewItems() = %v, want %v", got, tt.want)
 			}
@@ -801,7 +795,7 @@ func TestSyncService_getUpdatedItems(t *testing.T) {
 		want          []entity.Entry
 	}{
 		{
-			name: "success edit 1 item from 1 to add 1 to edit 1 to delete",
+			name: "success edit 1 item and trash 1 item from 1 to add 1 to edit 1 to delete",
 			args: args{
 				request: syncRequestPkg.SyncRequest{
 					SyncType: enum.Login,
@@ -862,10 +856,20 @@ func TestSyncService_getUpdatedItems(t *testing.T) {
 					Data:       []byte{},
 					Meta:       []byte(""),
 				},
+				{
+					Id:         "6b80f318-fac0-4ba7-889c-cbc2e2fc6223",
+					EntryType:  enum.Login,
+					UserId:     "b632eb93-0c31-4d6c-8fb9-282f3fb7e54e",
+					OriginalId: "3453c579-9db6-4089-8ca3-1635a9887316",
+					UpdatedAt:  time.Date(2023, time.February, 10, 12, 0, 0, 0, time.UTC),
+					Data:       []byte{},
+					Meta:       []byte(""),
+					DeletedAt:  &testSyncNow,
+				},
 			},
 		},
 		{
-			name: "success try to edit isDeleted item",
+			name: "success move item to trash",
 			args: args{
 				request: syncRequestPkg.SyncRequest{
 					SyncType: enum.Login,
@@ -893,7 +897,18 @@ func TestSyncService_getUpdatedItems(t *testing.T) {
 				}},
 			},
 			mockBehaviour: func() {},
-			want:          []entity.Entry{},
+			want: []entity.Entry{
+				{
+					Id:         "05dfdb32-3674-4381-be02-091e5e17080c",
+					EntryType:  enum.Login,
+					UserId:     "b632eb93-0c31-4d6c-8fb9-282f3fb7e54e",
+					OriginalId: "3da6111c-6316-4993-aeff-74a2c3f345f9",
+					UpdatedAt:  time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+					Data:       []byte{},
+					Meta:       []byte(""),
+					DeletedAt:  &testSyncNow,
+				},
+			},
 		},
 		{
 			name: "success try to edit item with older date",
@@ -931,6 +946,7 @@ func TestSyncService_getUpdatedItems(t *testing.T) {
 		tt.mockBehaviour()
 		t.Run(tt.name, func(t *testing.T) {
 			s := NewSyncService(entryRepositoryMock, uuidGenMock, dbMock, loggerMock)
+			s.now = func() time.Time { return testSyncNow }
 			if got := s.getUpdatedItems(tt.args.request, tt.args.userEntries); !assert.Equal(t, got, tt.want) {
 				t.Errorf("getUpdatedItems() = %v, want %v", got, tt.want)
 			}
@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE entries ADD COLUMN deleted_at timestamp(3) NULL;
+
+
+
+
+-- +goose Down
+ALTER TABLE entries DROP COLUMN deleted_at;
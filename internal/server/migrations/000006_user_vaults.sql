@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_vaults (
+     user_id VARCHAR(36) PRIMARY KEY,
+     data JSONB NOT NULL,
+     updated_at timestamp(3) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE user_vaults;
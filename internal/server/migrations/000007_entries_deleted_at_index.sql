@@ -0,0 +1,8 @@
+-- +goose Up
+CREATE INDEX entries_deleted_at_idx ON entries (deleted_at) WHERE deleted_at IS NOT NULL;
+
+
+
+
+-- +goose Down
+DROP INDEX entries_deleted_at_idx;
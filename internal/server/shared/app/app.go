@@ -6,6 +6,7 @@ import (
 	"github.com/anoriar/gophkeeper/internal/server/shared/services/uuid"
 
 	entryRepositoryPkg "github.com/anoriar/gophkeeper/internal/server/entry/repository"
+	"github.com/anoriar/gophkeeper/internal/server/entry/services/purge"
 	"github.com/anoriar/gophkeeper/internal/server/entry/services/sync"
 
 	dbPkg "github.com/anoriar/gophkeeper/internal/server/shared/app/db"
@@ -13,15 +14,19 @@ import (
 	"github.com/anoriar/gophkeeper/internal/server/shared/config"
 	userRepositoryPkg "github.com/anoriar/gophkeeper/internal/server/user/repository"
 	"github.com/anoriar/gophkeeper/internal/server/user/services/auth"
+	vaultRepositoryPkg "github.com/anoriar/gophkeeper/internal/server/vault/repository"
+	"github.com/anoriar/gophkeeper/internal/server/vault/services/vault"
 )
 
 // App missing godoc.
 type App struct {
-	Config      *config.Config
-	Logger      *zap.Logger
-	Database    dbPkg.DatabaseInterface
-	AuthService auth.AuthServiceInterface
-	SyncService sync.SyncServiceInterface
+	Config       *config.Config
+	Logger       *zap.Logger
+	Database     dbPkg.DatabaseInterface
+	AuthService  auth.AuthServiceInterface
+	SyncService  sync.SyncServiceInterface
+	PurgeService purge.PurgeServiceInterface
+	VaultService vault.VaultServiceInterface
 }
 
 // NewApp missing godoc.
@@ -47,13 +52,18 @@ func NewApp(cnf *config.Config) (*App, error) {
 
 	entryRepository := entryRepositoryPkg.NewEntryRepository(db)
 	syncService := sync.NewSyncService(entryRepository, uuid.NewUUIDGenerator(), db, logger)
+	purgeService := purge.NewPurgeService(entryRepository, cnf.TrashRetention, logger)
+
+	vaultService := vault.NewVaultService(vaultRepositoryPkg.NewVaultRepository(db), logger)
 
 	return &App{
-		Config:      cnf,
-		Logger:      logger,
-		Database:    db,
-		AuthService: authService,
-		SyncService: syncService,
+		Config:       cnf,
+		Logger:       logger,
+		Database:     db,
+		AuthService:  authService,
+		SyncService:  syncService,
+		PurgeService: purgeService,
+		VaultService: vaultService,
 	}, nil
 }
 
@@ -1,17 +1,30 @@
 package config
 
+import "time"
+
+const (
+	defaultTrashRetention     = 30 * 24 * time.Hour
+	defaultTrashPurgeInterval = time.Hour
+)
+
 // Config missing godoc.
 type Config struct {
 	RunAddress   string `env:"RUN_ADDRESS"`
 	LogLevel     string `env:"LOG_LEVEL"`
 	DatabaseURI  string `env:"DATABASE_URI"`
 	JwtSecretKey string `env:"JWT_SECRET_KEY"`
+	// TrashRetention - сколько хранить удаленные записи, пока их можно восстановить
+	TrashRetention time.Duration `env:"TRASH_RETENTION"`
+	// TrashPurgeInterval - как часто удалять записи, срок хранения которых в корзине истек
+	TrashPurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL"`
 }
 
 // NewConfig missing godoc.
 func NewConfig() *Config {
 	return &Config{
-		LogLevel:     "info",
-		JwtSecretKey: "secret-key",
+		LogLevel:           "info",
+		JwtSecretKey:       "secret-key",
+		TrashRetention:     defaultTrashRetention,
+		TrashPurgeInterval: defaultTrashPurgeInterval,
 	}
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/anoriar/gophkeeper/internal/server/shared/middleware/logger"
 	"github.com/anoriar/gophkeeper/internal/server/user/handlers/login"
 	"github.com/anoriar/gophkeeper/internal/server/user/handlers/register"
+	"github.com/anoriar/gophkeeper/internal/server/vault/handlers/vault"
 )
 
 // Router missing godoc.
@@ -18,6 +19,7 @@ type Router struct {
 	registerHandler    *register.RegisterHandler
 	loginHandler       *login.LoginHandler
 	syncHandler        *sync.SyncHandler
+	vaultHandler       *vault.VaultHandler
 	loggerMiddleware   *logger.LoggerMiddleware
 	authMiddleware     *auth.AuthMiddleware
 	compressMiddleware *compress.CompressMiddleware
@@ -29,6 +31,7 @@ func NewRouter(app *app.App) *Router {
 		registerHandler:    register.NewRegisterHandler(app.AuthService),
 		loginHandler:       login.NewLoginHandler(app.AuthService),
 		syncHandler:        sync.NewSyncHandler(app.SyncService, app.Logger),
+		vaultHandler:       vault.NewVaultHandler(app.VaultService, app.Logger),
 		loggerMiddleware:   logger.NewLoggerMiddleware(app.Logger),
 		authMiddleware:     auth.NewAuthMiddleware(app.AuthService),
 		compressMiddleware: compress.NewCompressMiddleware(),
@@ -45,6 +48,8 @@ func (r *Router) Route() chi.Router {
 	router.Post("/api/user/register", r.registerHandler.Register)
 	router.Post("/api/user/login", r.loginHandler.Login)
 	router.With(r.authMiddleware.Auth).Post("/api/entries/sync", r.syncHandler.Sync)
+	router.With(r.authMiddleware.Auth).Get("/api/vault", r.vaultHandler.Get)
+	router.With(r.authMiddleware.Auth).Put("/api/vault", r.vaultHandler.Save)
 
 	return router
 }
@@ -24,12 +24,12 @@ func RunServer(app *app.App, r *router.Router) error {
 	if err != nil {
 		return err
 	}
-	gracefulShutdown(srv)
+	gracefulShutdown(app, srv)
 
 	return nil
 }
 
-func gracefulShutdown(srv *http.Server) {
+func gracefulShutdown(app *app.App, srv *http.Server) {
 
 	// Create a context that will be canceled on signal reception
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
@@ -44,10 +44,18 @@ func gracefulShutdown(srv *http.Server) {
 
 	var wg sync.WaitGroup
 
-	wg.Add(1)
+	wg.Add(2)
 
+	// корзина очищается по расписанию, независимо от запросов синхронизации
 	go func() {
 		defer wg.Done()
+		app.PurgeService.Run(ctx, app.Config.TrashPurgeInterval)
+	}()
+
+	go func() {
+		defer wg.Done()
+		// сервер не запустился или остановлен: очистка корзины тоже останавливается
+		defer stop()
 
 		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
 			log.Printf("Error starting the server: %v\n", err)
@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Vault слоты ключей хранилища пользователя: соли, параметры KDF, keyCheck и зашифрованный ключ хранилища.
+// Сервер хранит их как есть, ключей в открытом виде в них нет
+type Vault struct {
+	UserId    string          `db:"user_id"`
+	Data      json.RawMessage `db:"data"`
+	UpdatedAt time.Time       `db:"updated_at"`
+}
@@ -0,0 +1,6 @@
+package errors
+
+import "github.com/pkg/errors"
+
+var ErrVaultNotValid = errors.New("vault not valid")
+var ErrVaultOutdated = errors.New("vault is outdated")
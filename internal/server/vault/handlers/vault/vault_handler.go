@@ -0,0 +1,91 @@
+package vault
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	customCtx "github.com/anoriar/gophkeeper/internal/server/shared/context"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/server/shared/errors"
+	vaultErrors "github.com/anoriar/gophkeeper/internal/server/vault/errors"
+	"github.com/anoriar/gophkeeper/internal/server/vault/services/vault"
+)
+
+// VaultHandler слоты ключей хранилища: другое устройство пользователя получает по ним ключи из мастер-пароля
+type VaultHandler struct {
+	vaultService vault.VaultServiceInterface
+	logger       *zap.Logger
+}
+
+func NewVaultHandler(vaultService vault.VaultServiceInterface, logger *zap.Logger) *VaultHandler {
+	return &VaultHandler{vaultService: vaultService, logger: logger}
+}
+
+func (vh *VaultHandler) Get(w http.ResponseWriter, req *http.Request) {
+	userID := getUserID(req)
+	if userID == "" {
+		http.Error(w, "user unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	data, err := vh.vaultService.GetVault(req.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sharedErrors.ErrNotFound) {
+			http.Error(w, "vault not found", http.StatusNotFound)
+			return
+		}
+		vh.logger.Error("internal server error", zap.String("error", err.Error()))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(data)
+	if err != nil {
+		vh.logger.Error("write response error", zap.String("error", err.Error()))
+	}
+}
+
+func (vh *VaultHandler) Save(w http.ResponseWriter, req *http.Request) {
+	userID := getUserID(req)
+	if userID == "" {
+		http.Error(w, "user unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	requestBody, err := io.ReadAll(req.Body)
+	if err != nil {
+		vh.logger.Error("internal server error", zap.String("error", err.Error()))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !json.Valid(requestBody) {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	err = vh.vaultService.SaveVault(req.Context(), userID, requestBody)
+	if err != nil {
+		if errors.Is(err, vaultErrors.ErrVaultNotValid) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, vaultErrors.ErrVaultOutdated) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		vh.logger.Error("internal server error", zap.String("error", err.Error()))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func getUserID(req *http.Request) string {
+	userID, _ := req.Context().Value(customCtx.UserIDContextKey).(string)
+	return userID
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/anoriar/gophkeeper/internal/server/shared/app/db"
+	errors2 "github.com/anoriar/gophkeeper/internal/server/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/server/vault/entity"
+)
+
+type VaultRepository struct {
+	db *db.Database
+}
+
+func NewVaultRepository(db *db.Database) *VaultRepository {
+	return &VaultRepository{db: db}
+}
+
+func (r *VaultRepository) GetVaultByUserID(ctx context.Context, userID string) (entity.Vault, error) {
+	var vault entity.Vault
+	err := r.db.Conn.QueryRowxContext(ctx, "SELECT user_id, data, updated_at FROM user_vaults WHERE user_id = $1", userID).StructScan(&vault)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Vault{}, fmt.Errorf("%w: %v", errors2.ErrNotFound, err)
+		}
+		return entity.Vault{}, fmt.Errorf("GetVaultByUserID: %w: %v", errors2.ErrInternalError, err)
+	}
+	return vault, nil
+}
+
+func (r *VaultRepository) SaveVault(ctx context.Context, vault entity.Vault) error {
+	_, err := r.db.Conn.ExecContext(
+		ctx,
+		"INSERT INTO user_vaults (user_id, data, updated_at) VALUES ($1, $2, $3) ON CONFLICT (user_id) DO UPDATE SET data = EXCLUDED.data, updated_at = EXCLUDED.updated_at",
+		vault.UserId,
+		vault.Data,
+		vault.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("SaveVault: %w: %v", errors2.ErrInternalError, err)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/anoriar/gophkeeper/internal/server/vault/entity"
+)
+
+//go:generate mockgen -source=vault_repository_interface.go -destination=vault_repository_mock/vault_repository.go -package=vault_repository_mock
+type VaultRepositoryInterface interface {
+	// GetVaultByUserID ErrNotFound, если пользователь еще не сохранял слоты ключей
+	GetVaultByUserID(ctx context.Context, userID string) (entity.Vault, error)
+	// SaveVault создает или заменяет слоты ключей пользователя
+	SaveVault(ctx context.Context, vault entity.Vault) error
+}
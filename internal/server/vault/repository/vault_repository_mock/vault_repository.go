@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vault_repository_interface.go
+
+// Package vault_repository_mock is a generated GoMock package.
+package vault_repository_mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/anoriar/gophkeeper/internal/server/vault/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockVaultRepositoryInterface is a mock of VaultRepositoryInterface interface.
+type MockVaultRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockVaultRepositoryInterfaceMockRecorder
+}
+
+// MockVaultRepositoryInterfaceMockRecorder is the mock recorder for MockVaultRepositoryInterface.
+type MockVaultRepositoryInterfaceMockRecorder struct {
+	mock *MockVaultRepositoryInterface
+}
+
+// NewMockVaultRepositoryInterface creates a new mock instance.
+func NewMockVaultRepositoryInterface(ctrl *gomock.Controller) *MockVaultRepositoryInterface {
+	mock := &MockVaultRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockVaultRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVaultRepositoryInterface) EXPECT() *MockVaultRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetVaultByUserID mocks base method.
+func (m *MockVaultRepositoryInterface) GetVaultByUserID(ctx context.Context, userID string) (entity.Vault, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVaultByUserID", ctx, userID)
+	ret0, _ := ret[0].(entity.Vault)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVaultByUserID indicates an expected call of GetVaultByUserID.
+func (mr *MockVaultRepositoryInterfaceMockRecorder) GetVaultByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVaultByUserID", reflect.TypeOf((*MockVaultRepositoryInterface)(nil).GetVaultByUserID), ctx, userID)
+}
+
+// SaveVault mocks base method.
+func (m *MockVaultRepositoryInterface) SaveVault(ctx context.Context, vault entity.Vault) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveVault", ctx, vault)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveVault indicates an expected call of SaveVault.
+func (mr *MockVaultRepositoryInterfaceMockRecorder) SaveVault(ctx, vault interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveVault", reflect.TypeOf((*MockVaultRepositoryInterface)(nil).SaveVault), ctx, vault)
+}
@@ -0,0 +1,114 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	sharedErrors "github.com/anoriar/gophkeeper/internal/server/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/server/vault/entity"
+	vaultErrors "github.com/anoriar/gophkeeper/internal/server/vault/errors"
+	"github.com/anoriar/gophkeeper/internal/server/vault/repository"
+)
+
+// Границы параметров Argon2id те же, что проверяет клиент: слот с другими параметрами клиенты не откроют
+const (
+	maxKdfTime    = 64
+	maxKdfMemory  = 1024 * 1024
+	maxKdfThreads = 64
+)
+
+type VaultService struct {
+	vaultRepository repository.VaultRepositoryInterface
+	now             func() time.Time
+	logger          *zap.Logger
+}
+
+func NewVaultService(vaultRepository repository.VaultRepositoryInterface, logger *zap.Logger) *VaultService {
+	return &VaultService{vaultRepository: vaultRepository, now: time.Now, logger: logger}
+}
+
+func (s *VaultService) GetVault(ctx context.Context, userID string) (json.RawMessage, error) {
+	vault, err := s.vaultRepository.GetVaultByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sharedErrors.ErrNotFound) {
+			return nil, err
+		}
+		s.logger.Error("get vault error", zap.String("error", err.Error()))
+		return nil, fmt.Errorf("%w: %v", sharedErrors.ErrInternalError, err)
+	}
+	return vault.Data, nil
+}
+
+func (s *VaultService) SaveVault(ctx context.Context, userID string, data json.RawMessage) error {
+	generation, err := validateVault(data)
+	if err != nil {
+		return err
+	}
+
+	// устройство, еще не получившее слот нового мастер-пароля, не должно вернуть текущим прежний слот
+	storedVault, err := s.vaultRepository.GetVaultByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, sharedErrors.ErrNotFound) {
+		s.logger.Error("get vault error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %v", sharedErrors.ErrInternalError, err)
+	}
+	if err == nil {
+		storedGeneration, err := validateVault(storedVault.Data)
+		if err == nil && generation < storedGeneration {
+			return fmt.Errorf("%w: current key slot generation %d is older than %d", vaultErrors.ErrVaultOutdated, generation, storedGeneration)
+		}
+	}
+
+	err = s.vaultRepository.SaveVault(ctx, entity.Vault{UserId: userID, Data: data, UpdatedAt: s.now()})
+	if err != nil {
+		s.logger.Error("save vault error", zap.String("error", err.Error()))
+		return fmt.Errorf("%w: %v", sharedErrors.ErrInternalError, err)
+	}
+	return nil
+}
+
+// validateVault ключи слотов сервер не разбирает, проверяется, что клиент сможет выбрать текущий слот
+// и что параметры KDF не уронят клиент и не займут всю его память. Возвращает номер смены мастер-пароля текущего слота
+func validateVault(data json.RawMessage) (uint64, error) {
+	var vault struct {
+		CurrentKeyId string `json:"currentKeyId"`
+		KeySlots     []struct {
+			Id         string `json:"id"`
+			Generation uint64 `json:"generation"`
+			Kdf        struct {
+				Time    uint32 `json:"time"`
+				Memory  uint32 `json:"memory"`
+				Threads uint32 `json:"threads"`
+			} `json:"kdf"`
+		} `json:"keySlots"`
+	}
+	err := json.Unmarshal(data, &vault)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", vaultErrors.ErrVaultNotValid, err)
+	}
+	currentFound := false
+	var generation uint64
+	for _, keySlot := range vault.KeySlots {
+		if keySlot.Id == "" {
+			return 0, fmt.Errorf("%w: key slot without id", vaultErrors.ErrVaultNotValid)
+		}
+		kdf := keySlot.Kdf
+		if kdf.Time < 1 || kdf.Time > maxKdfTime ||
+			kdf.Threads < 1 || kdf.Threads > maxKdfThreads ||
+			kdf.Memory < 8*kdf.Threads || kdf.Memory > maxKdfMemory {
+			return 0, fmt.Errorf("%w: key slot %q has invalid kdf params", vaultErrors.ErrVaultNotValid, keySlot.Id)
+		}
+		if keySlot.Id == vault.CurrentKeyId {
+			currentFound = true
+			generation = keySlot.Generation
+		}
+	}
+	if currentFound {
+		return generation, nil
+	}
+	return 0, fmt.Errorf("%w: current key slot %q not found", vaultErrors.ErrVaultNotValid, vault.CurrentKeyId)
+}
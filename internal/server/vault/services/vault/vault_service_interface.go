@@ -0,0 +1,14 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type VaultServiceInterface interface {
+	// GetVault ErrNotFound, если пользователь еще не сохранял слоты ключей
+	GetVault(ctx context.Context, userID string) (json.RawMessage, error)
+	// SaveVault ErrVaultNotValid, если в data нет текущего слота.
+	// ErrVaultOutdated, если номер смены мастер-пароля у текущего слота меньше, чем у сохраненного
+	SaveVault(ctx context.Context, userID string, data json.RawMessage) error
+}
@@ -0,0 +1,119 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anoriar/gophkeeper/internal/server/shared/app/logger"
+	sharedErrors "github.com/anoriar/gophkeeper/internal/server/shared/errors"
+	"github.com/anoriar/gophkeeper/internal/server/vault/entity"
+	vaultErrors "github.com/anoriar/gophkeeper/internal/server/vault/errors"
+	"github.com/anoriar/gophkeeper/internal/server/vault/repository/vault_repository_mock"
+)
+
+func TestVaultService_SaveVault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loggerMock, err := logger.Initialize("info")
+	require.NoError(t, err)
+	vaultRepositoryMock := vault_repository_mock.NewMockVaultRepositoryInterface(ctrl)
+
+	now := time.Date(2024, time.April, 1, 12, 0, 0, 0, time.UTC)
+	validData := json.RawMessage(`{"currentKeyId":"0102030405060708","keySlots":[{"id":"0102030405060708","salt":"c2FsdA==","kdf":{"time":3,"memory":65536,"threads":4}}]}`)
+
+	// rekey на другом устройстве: новый текущий слот со следующим номером смены мастер-пароля
+	rekeyedData := json.RawMessage(`{"currentKeyId":"0807060504030201","keySlots":[` +
+		`{"id":"0807060504030201","generation":1,"kdf":{"time":3,"memory":65536,"threads":4}},` +
+		`{"id":"0102030405060708","kdf":{"time":3,"memory":65536,"threads":4}}]}`)
+
+	tests := []struct {
+		name          string
+		data          json.RawMessage
+		mockBehaviour func()
+		wantErr       error
+	}{
+		{
+			name: "success",
+			data: validData,
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().GetVaultByUserID(gomock.Any(), "user").Return(entity.Vault{}, sharedErrors.ErrNotFound).Times(1)
+				vaultRepositoryMock.EXPECT().SaveVault(gomock.Any(), entity.Vault{
+					UserId:    "user",
+					Data:      validData,
+					UpdatedAt: now,
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "newer current slot",
+			data: rekeyedData,
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().GetVaultByUserID(gomock.Any(), "user").Return(entity.Vault{UserId: "user", Data: validData}, nil).Times(1)
+				vaultRepositoryMock.EXPECT().SaveVault(gomock.Any(), entity.Vault{
+					UserId:    "user",
+					Data:      rekeyedData,
+					UpdatedAt: now,
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "older current slot",
+			data: validData,
+			mockBehaviour: func() {
+				vaultRepositoryMock.EXPECT().GetVaultByUserID(gomock.Any(), "user").Return(entity.Vault{UserId: "user", Data: rekeyedData}, nil).Times(1)
+			},
+			wantErr: vaultErrors.ErrVaultOutdated,
+		},
+		{
+			name:          "current slot not found",
+			data:          json.RawMessage(`{"currentKeyId":"0102030405060708","keySlots":[{"id":"0807060504030201"}]}`),
+			mockBehaviour: func() {},
+			wantErr:       vaultErrors.ErrVaultNotValid,
+		},
+		{
+			name:          "slot without id",
+			data:          json.RawMessage(`{"currentKeyId":"","keySlots":[{"salt":"c2FsdA=="}]}`),
+			mockBehaviour: func() {},
+			wantErr:       vaultErrors.ErrVaultNotValid,
+		},
+		{
+			name:          "zero kdf threads",
+			data:          json.RawMessage(`{"currentKeyId":"0102030405060708","keySlots":[{"id":"0102030405060708","kdf":{"time":3,"memory":65536,"threads":0}}]}`),
+			mockBehaviour: func() {},
+			wantErr:       vaultErrors.ErrVaultNotValid,
+		},
+		{
+			name:          "kdf memory too large",
+			data:          json.RawMessage(`{"currentKeyId":"0102030405060708","keySlots":[{"id":"0102030405060708","kdf":{"time":3,"memory":4294967295,"threads":4}}]}`),
+			mockBehaviour: func() {},
+			wantErr:       vaultErrors.ErrVaultNotValid,
+		},
+		{
+			name:          "not an object",
+			data:          json.RawMessage(`[]`),
+			mockBehaviour: func() {},
+			wantErr:       vaultErrors.ErrVaultNotValid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour()
+			s := NewVaultService(vaultRepositoryMock, loggerMock)
+			s.now = func() time.Time { return now }
+
+			err := s.SaveVault(context.Background(), "user", tt.data)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}